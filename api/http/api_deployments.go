@@ -78,11 +78,16 @@ const (
 	ParamTenantID     = "tenant_id"
 	ParamName         = "name"
 	ParamTag          = "tag"
+	ParamStatus       = "status"
 	ParamDescription  = "description"
 	ParamPage         = "page"
 	ParamPerPage      = "per_page"
 	ParamSort         = "sort"
 	ParamID           = "id"
+	ParamFrom         = "from"
+	ParamTo           = "to"
+	ParamProvides     = "provides"
+	ParamSearch       = "search"
 )
 
 const Redacted = "REDACTED"
@@ -258,12 +263,15 @@ func getReleaseOrImageFilter(r *rest.Request, version listReleasesVersion,
 	filter := &model.ReleaseOrImageFilter{
 		Name:       q.Get(ParamName),
 		UpdateType: q.Get(ParamUpdateType),
+		Provides:   q[ParamProvides],
+		SearchText: q.Get(ParamSearch),
 	}
 	if version == listReleasesV1 {
 		filter.Description = q.Get(ParamDescription)
 		filter.DeviceType = q.Get(ParamDeviceType)
 	} else if version == listReleasesV2 {
 		filter.Tags = q[ParamTag]
+		filter.Status = q.Get(ParamStatus)
 	}
 
 	if paginated {
@@ -314,7 +322,7 @@ func (d *DeploymentsApiHandlers) GetLimit(w rest.ResponseWriter, r *rest.Request
 
 	d.view.RenderSuccessGet(w, limitResponse{
 		Limit: limit.Value,
-		Usage: 0, // TODO fill this when ready
+		Usage: limit.Usage,
 	})
 }
 
@@ -347,8 +355,12 @@ func (d *DeploymentsApiHandlers) GetImage(w rest.ResponseWriter, r *rest.Request
 func (d *DeploymentsApiHandlers) GetImages(w rest.ResponseWriter, r *rest.Request) {
 	l := requestlog.GetRequestLogger(r)
 
-	defer redactReleaseName(r)
+	defer redactReleaseFilter(r)
 	filter := getReleaseOrImageFilter(r, listReleasesV1, false)
+	if err := filter.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
 
 	list, _, err := d.app.ListImages(r.Context(), filter)
 	if err != nil {
@@ -362,8 +374,12 @@ func (d *DeploymentsApiHandlers) GetImages(w rest.ResponseWriter, r *rest.Reques
 func (d *DeploymentsApiHandlers) ListImages(w rest.ResponseWriter, r *rest.Request) {
 	l := requestlog.GetRequestLogger(r)
 
-	defer redactReleaseName(r)
+	defer redactReleaseFilter(r)
 	filter := getReleaseOrImageFilter(r, listReleasesV1, true)
+	if err := filter.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
 
 	list, totalCount, err := d.app.ListImages(r.Context(), filter)
 	if err != nil {
@@ -463,6 +479,8 @@ func (d *DeploymentsApiHandlers) CompleteUpload(w rest.ResponseWriter, r *rest.R
 		w.WriteHeader(http.StatusAccepted)
 	case app.ErrUploadNotFound:
 		d.view.RenderErrorNotFound(w, r, l)
+	case app.ErrStorageLimitExceeded:
+		d.view.RenderError(w, r, err, http.StatusRequestEntityTooLarge, l)
 	default:
 		l.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -719,10 +737,15 @@ func (d *DeploymentsApiHandlers) newImageWithContext(
 	default:
 		d.view.RenderInternalError(w, r, err, l)
 		return
-	case app.ErrModelArtifactNotUnique:
+	case app.ErrModelArtifactNotUnique, app.ErrReleaseAliasConflict,
+		app.ErrArtifactNotSigned, app.ErrArtifactSignatureInvalid:
 		l.Error(err.Error())
 		d.view.RenderError(w, r, cause, http.StatusUnprocessableEntity, l)
 		return
+	case app.ErrStorageLimitExceeded:
+		l.Error(err.Error())
+		d.view.RenderError(w, r, cause, http.StatusRequestEntityTooLarge, l)
+		return
 	case app.ErrModelParsingArtifactFailed:
 		l.Error(err.Error())
 		d.view.RenderError(w, r, formatArtifactUploadError(err), http.StatusBadRequest, l)
@@ -787,15 +810,20 @@ func (d *DeploymentsApiHandlers) GenerateImage(w rest.ResponseWriter, r *rest.Re
 		d.view.RenderInternalError(w, r, err, l)
 	case nil:
 		d.view.RenderSuccessPost(w, r, imgID)
-	case app.ErrModelArtifactNotUnique:
+	case app.ErrModelArtifactNotUnique, app.ErrReleaseAliasConflict,
+		app.ErrArtifactNotSigned, app.ErrArtifactSignatureInvalid:
 		l.Error(err.Error())
 		d.view.RenderError(w, r, cause, http.StatusUnprocessableEntity, l)
+	case app.ErrStorageLimitExceeded:
+		l.Error(err.Error())
+		d.view.RenderError(w, r, cause, http.StatusRequestEntityTooLarge, l)
 	case app.ErrModelParsingArtifactFailed:
 		l.Error(err.Error())
 		d.view.RenderError(w, r, formatArtifactUploadError(err), http.StatusBadRequest, l)
 	case app.ErrModelMissingInputMetadata, app.ErrModelMissingInputArtifact,
 		app.ErrModelInvalidMetadata, app.ErrModelMultipartUploadMsgMalformed,
-		io.ErrUnexpectedEOF, utils.ErrStreamTooLarge, ErrModelArtifactFileTooLarge:
+		io.ErrUnexpectedEOF, utils.ErrStreamTooLarge, ErrModelArtifactFileTooLarge,
+		app.ErrArtifactGeneratorType, app.ErrArtifactGeneratorArgs:
 		l.Error(err.Error())
 		d.view.RenderError(w, r, cause, http.StatusBadRequest, l)
 	}
@@ -864,6 +892,7 @@ func (d *DeploymentsApiHandlers) ParseMultipart(
 			// Assign the form-data payload to the artifact reader
 			// and return. The content is consumed elsewhere.
 			if size > 0 {
+				uploadMsg.Size = size
 				uploadMsg.ArtifactReader = utils.ReadExactly(part, size)
 			} else {
 				uploadMsg.ArtifactReader = utils.ReadAtMost(
@@ -1002,7 +1031,7 @@ func (d *DeploymentsApiHandlers) createDeployment(
 		// haeder
 		r.URL.Path = strings.TrimSuffix(r.URL.Path, "/group/"+constructor.Group)
 		d.view.RenderSuccessPost(w, r, id)
-	case app.ErrNoArtifact:
+	case app.ErrNoArtifact, app.ErrReleaseNotDeployable, app.ErrReleaseChannelNotFound:
 		d.view.RenderError(w, r, err, http.StatusUnprocessableEntity, l)
 	case app.ErrNoDevices:
 		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
@@ -1344,10 +1373,9 @@ func (d *DeploymentsApiHandlers) getDeploymentForDevice(
 	idata *identity.Identity,
 	request *model.DeploymentNextRequest,
 ) {
-	ctx := r.Context()
 	l := requestlog.GetRequestLogger(r)
 
-	deployment, err := d.app.GetDeploymentForDeviceWithCurrent(ctx, idata.Subject, request)
+	deployment, err := d.deploymentForDevice(r.Context(), r, idata, request)
 	if err != nil {
 		if err == app.ErrConflictingRequestData {
 			d.view.RenderError(w, r, err, http.StatusConflict, l)
@@ -1360,16 +1388,31 @@ func (d *DeploymentsApiHandlers) getDeploymentForDevice(
 	if deployment == nil {
 		d.view.RenderNoUpdateForDevice(w)
 		return
+	}
+
+	d.view.RenderSuccessGet(w, deployment)
+}
+
+// deploymentForDevice returns the deployment instructions for the device
+// identified by idata, generating the download link for configuration
+// deployments.
+func (d *DeploymentsApiHandlers) deploymentForDevice(
+	ctx context.Context,
+	r *rest.Request,
+	idata *identity.Identity,
+	request *model.DeploymentNextRequest,
+) (*model.DeploymentInstructions, error) {
+	deployment, err := d.app.GetDeploymentForDeviceWithCurrent(ctx, idata.Subject, request)
+	if err != nil || deployment == nil {
+		return nil, err
 	} else if deployment.Type == model.DeploymentTypeConfiguration {
 		// Generate pre-signed URL
 		var hostName string = d.config.PresignHostname
 		if hostName == "" {
 			if hostName = r.Header.Get(hdrForwardedHost); hostName == "" {
-				d.view.RenderInternalError(w, r,
-					errors.New("presign.hostname not configured; "+
-						"unable to generate download link "+
-						" for configuration deployment"), l)
-				return
+				return nil, errors.New("presign.hostname not configured; " +
+					"unable to generate download link " +
+					" for configuration deployment")
 			}
 		}
 		req, _ := http.NewRequest(
@@ -1395,7 +1438,7 @@ func (d *DeploymentsApiHandlers) getDeploymentForDevice(
 		}
 	}
 
-	d.view.RenderSuccessGet(w, deployment)
+	return deployment, nil
 }
 
 func (d *DeploymentsApiHandlers) PutDeploymentStatusForDevice(
@@ -1429,7 +1472,8 @@ func (d *DeploymentsApiHandlers) PutDeploymentStatusForDevice(
 			SubState: report.SubState,
 		}); err != nil {
 
-		if err == app.ErrDeploymentAborted || err == app.ErrDeviceDecommissioned {
+		if err == app.ErrDeploymentAborted || err == app.ErrDeviceDecommissioned ||
+			err == app.ErrInvalidStatusTransition {
 			d.view.RenderError(w, r, err, http.StatusConflict, l)
 		} else if err == app.ErrStorageNotFound {
 			d.view.RenderErrorNotFound(w, r, l)
@@ -1647,6 +1691,35 @@ func (d *DeploymentsApiHandlers) LookupDeployment(w rest.ResponseWriter, r *rest
 	d.view.RenderSuccessGet(w, deps[:len])
 }
 
+// GetDeploymentLinkForDevice generates a new download link for the artifact
+// of an ongoing deployment of the device, e.g. once the link sent with the
+// deployment instructions expired in the middle of the download.
+func (d *DeploymentsApiHandlers) GetDeploymentLinkForDevice(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := requestlog.GetRequestLogger(r)
+
+	idata := identity.FromContext(ctx)
+	if idata == nil {
+		d.view.RenderError(w, r, ErrMissingIdentity, http.StatusBadRequest, l)
+		return
+	}
+
+	link, err := d.app.GetDeviceDeploymentDownloadLink(ctx, r.PathParam("id"), idata.Subject)
+	switch err {
+	case nil:
+		d.view.RenderSuccessGet(w, link)
+	case app.ErrStorageNotFound, app.ErrNoArtifact:
+		d.view.RenderErrorNotFound(w, r, l)
+	case app.ErrDeviceDeploymentFinished, app.ErrDeploymentAborted:
+		d.view.RenderError(w, r, err, http.StatusConflict, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
 func (d *DeploymentsApiHandlers) PutDeploymentLogForDevice(w rest.ResponseWriter, r *rest.Request) {
 	ctx := r.Context()
 	l := requestlog.GetRequestLogger(r)
@@ -1705,6 +1778,35 @@ func (d *DeploymentsApiHandlers) GetDeploymentLogForDevice(w rest.ResponseWriter
 	d.view.RenderDeploymentLog(w, *depl)
 }
 
+func (d *DeploymentsApiHandlers) GetDeploymentHistoryForDevice(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := requestlog.GetRequestLogger(r)
+
+	did := r.PathParam("id")
+	devid := r.PathParam("devid")
+
+	if !govalidator.IsUUID(did) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+
+	history, err := d.app.GetDeviceDeploymentHistory(ctx, did, devid)
+	if err != nil {
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+
+	if history == nil {
+		d.view.RenderErrorNotFound(w, r, l)
+		return
+	}
+
+	d.view.RenderSuccessGet(w, history)
+}
+
 func (d *DeploymentsApiHandlers) AbortDeviceDeployments(w rest.ResponseWriter, r *rest.Request) {
 	ctx := r.Context()
 	l := requestlog.GetRequestLogger(r)
@@ -1943,3 +2045,60 @@ func (d *DeploymentsApiHandlers) PutTenantStorageSettingsHandler(
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (d *DeploymentsApiHandlers) GetTenantSettingsHandler(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	l := requestlog.GetRequestLogger(r)
+
+	tenantID := r.PathParam("tenant")
+
+	ctx := identity.WithContext(
+		r.Context(),
+		&identity.Identity{Tenant: tenantID},
+	)
+
+	settings, err := d.app.GetTenantSettings(ctx)
+	if err != nil {
+		rest_utils.RestErrWithLogInternal(w, r, l, err)
+		return
+	}
+
+	d.view.RenderSuccessGet(w, settings)
+}
+
+func (d *DeploymentsApiHandlers) PutTenantSettingsHandler(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	l := requestlog.GetRequestLogger(r)
+
+	defer r.Body.Close()
+
+	tenantID := r.PathParam("tenant")
+
+	ctx := identity.WithContext(
+		r.Context(),
+		&identity.Identity{Tenant: tenantID},
+	)
+
+	var settings model.TenantSettings
+	if err := r.DecodeJsonPayload(&settings); err != nil {
+		rest_utils.RestErrWithLog(w, r, l, err, http.StatusBadRequest)
+		return
+	}
+	if err := settings.Validate(); err != nil {
+		rest_utils.RestErrWithLog(w, r, l,
+			errors.WithMessage(err, "invalid settings schema"), http.StatusBadRequest)
+		return
+	}
+
+	err := d.app.SetTenantSettings(ctx, &settings)
+	if err != nil {
+		rest_utils.RestErrWithLogInternal(w, r, l, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/asaskevich/govalidator"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+
+	"github.com/mendersoftware/deployments/app"
+)
+
+// artifactFileNameRegexp matches the names allowed for the payload files
+// by mender-artifact.
+var artifactFileNameRegexp = regexp.MustCompile(`^[\w\-.,]+$`)
+
+var ErrArtifactFileNameInvalid = errors.New("invalid artifact file name")
+
+// GetArtifactContents lists the payloads of an artifact with their files.
+func (d *DeploymentsApiHandlers) GetArtifactContents(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	id := r.PathParam("id")
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+
+	contents, err := d.app.GetArtifactContents(r.Context(), id)
+	switch {
+	case err == nil:
+		d.view.RenderSuccessGet(w, contents)
+	case errors.Is(err, app.ErrImageMetaNotFound):
+		d.view.RenderErrorNotFound(w, r, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
+// GetArtifactFile streams a payload file out of an artifact.
+func (d *DeploymentsApiHandlers) GetArtifactFile(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	id := r.PathParam("id")
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+	name := r.PathParam("name")
+	if !artifactFileNameRegexp.MatchString(name) {
+		d.view.RenderError(w, r, ErrArtifactFileNameInvalid, http.StatusBadRequest, l)
+		return
+	}
+
+	file, info, err := d.app.GetArtifactFile(r.Context(), id, name)
+	switch {
+	case err == nil:
+	case errors.Is(err, app.ErrImageMetaNotFound):
+		d.view.RenderErrorNotFound(w, r, l)
+		return
+	case errors.Is(err, app.ErrArtifactFileNotFound):
+		d.view.RenderError(w, r, err, http.StatusNotFound, l)
+		return
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+	defer file.Close()
+
+	rw := w.(http.ResponseWriter)
+	hdr := rw.Header()
+	hdr.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, info.Name))
+	hdr.Set("Content-Type", "application/octet-stream")
+	hdr.Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	rw.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(rw, file); err != nil {
+		// The response is already sent.
+		l.Errorf("failed to stream artifact file %s: %s", name, err)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/google/uuid"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestGetArtifactContents(t *testing.T) {
+	artifactID := uuid.NewString()
+
+	testCases := map[string]struct {
+		id string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         artifactID,
+			callApp:    true,
+			httpStatus: http.StatusOK,
+		},
+		"error, invalid id": {
+			id:         "foo",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, not found": {
+			id:         artifactID,
+			callApp:    true,
+			err:        app.ErrImageMetaNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			id:         artifactID,
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var contents *model.ArtifactContents
+				if tc.err == nil {
+					contents = &model.ArtifactContents{ID: tc.id}
+				}
+				app.On("GetArtifactContents", contextMatcher(), tc.id).
+					Return(contents, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsIdContents,
+				rest.Get,
+				d.GetArtifactContents,
+			)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementArtifactsIdContents, "#id", tc.id, 1),
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusOK {
+				recorded.BodyIs(`{"id":"` + tc.id + `","name":"","payloads":null}`)
+			}
+		})
+	}
+}
+
+func TestGetArtifactFile(t *testing.T) {
+	artifactID := uuid.NewString()
+	const content = "application archive"
+
+	testCases := map[string]struct {
+		id   string
+		name string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         artifactID,
+			name:       "app.tar",
+			callApp:    true,
+			httpStatus: http.StatusOK,
+		},
+		"error, invalid id": {
+			id:         "foo",
+			name:       "app.tar",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, invalid name": {
+			id:         artifactID,
+			name:       "app%20tar",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, artifact not found": {
+			id:         artifactID,
+			name:       "app.tar",
+			callApp:    true,
+			err:        app.ErrImageMetaNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, file not found": {
+			id:         artifactID,
+			name:       "app.tar",
+			callApp:    true,
+			err:        app.ErrArtifactFileNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			id:         artifactID,
+			name:       "app.tar",
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var (
+					file io.ReadCloser
+					info *model.UpdateFile
+				)
+				if tc.err == nil {
+					file = io.NopCloser(strings.NewReader(content))
+					info = &model.UpdateFile{Name: tc.name, Size: int64(len(content))}
+				}
+				app.On("GetArtifactFile", contextMatcher(), tc.id, tc.name).
+					Return(file, info, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsIdFile,
+				rest.Get,
+				d.GetArtifactFile,
+			)
+			url := strings.Replace(ApiUrlManagementArtifactsIdFile, "#id", tc.id, 1)
+			url = strings.Replace(url, "#name", tc.name, 1)
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost"+url, nil)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusOK {
+				recorded.BodyIs(content)
+				recorded.HeaderIs("Content-Disposition",
+					`attachment; filename="`+tc.name+`"`)
+				recorded.HeaderIs("Content-Length", "19")
+			}
+		})
+	}
+}
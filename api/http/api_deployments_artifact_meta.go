@@ -0,0 +1,71 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/asaskevich/govalidator"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/go-lib-micro/requestid"
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+
+	"github.com/mendersoftware/deployments/app"
+	"github.com/mendersoftware/deployments/model"
+)
+
+// EditArtifactMeta adds, overrides or removes the provides, depends and
+// clears provides of an artifact and responds with the edited artifact.
+func (d *DeploymentsApiHandlers) EditArtifactMeta(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	id := r.PathParam("id")
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+	var edit model.ArtifactMetaEdit
+	if err := r.DecodeJsonPayload(&edit); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+	if err := edit.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+
+	image, err := d.app.EditArtifactMeta(r.Context(), id, &edit)
+	var cErr *model.ConflictError
+	switch {
+	case err == nil:
+		d.view.RenderSuccessGet(w, image)
+	case errors.As(err, &cErr):
+		w.WriteHeader(http.StatusConflict)
+		_ = cErr.WithRequestID(requestid.FromContext(r.Context()))
+		if err = w.WriteJson(cErr); err != nil {
+			l.Error(err)
+		} else {
+			l.Error(cErr.Error())
+		}
+	case errors.Is(err, app.ErrImageMetaNotFound):
+		d.view.RenderErrorNotFound(w, r, l)
+	case errors.Is(err, app.ErrArtifactMetaInActiveDeployment):
+		d.view.RenderError(w, r, ErrArtifactUsedInActiveDeployment, http.StatusConflict, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
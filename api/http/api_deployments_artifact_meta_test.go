@@ -0,0 +1,138 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestEditArtifactMeta(t *testing.T) {
+	artifactID := uuid.NewString()
+	body := `{"artifact_provides": {"rootfs-image.version": "2.0", "old": null},
+		"artifact_depends": {"artifact_name": ["release-0"]}}`
+
+	testCases := map[string]struct {
+		id   string
+		body string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         artifactID,
+			body:       body,
+			callApp:    true,
+			httpStatus: http.StatusOK,
+		},
+		"error, invalid id": {
+			id:         "foo",
+			body:       body,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, empty edit": {
+			id:         artifactID,
+			body:       `{}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, device type": {
+			id:         artifactID,
+			body:       `{"artifact_depends": {"device_type": "rpi4"}}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, not found": {
+			id:         artifactID,
+			body:       body,
+			callApp:    true,
+			err:        app.ErrImageMetaNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, conflicting depends": {
+			id:         artifactID,
+			body:       body,
+			callApp:    true,
+			err:        model.NewConflictError(errors.New("conflicting depends")),
+			httpStatus: http.StatusConflict,
+		},
+		"error, used in active deployment": {
+			id:         artifactID,
+			body:       body,
+			callApp:    true,
+			err:        app.ErrArtifactMetaInActiveDeployment,
+			httpStatus: http.StatusConflict,
+		},
+		"error, internal": {
+			id:         artifactID,
+			body:       body,
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var image *model.Image
+				if tc.err == nil {
+					image = &model.Image{Id: tc.id}
+				}
+				app.On("EditArtifactMeta",
+					contextMatcher(),
+					tc.id,
+					mock.MatchedBy(func(edit *model.ArtifactMetaEdit) bool {
+						old, ok := edit.Provides["old"]
+						return ok && old == nil &&
+							*edit.Provides["rootfs-image.version"] == "2.0"
+					}),
+				).Return(image, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsIdMetadata,
+				rest.Patch,
+				d.EditArtifactMeta,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPatch,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementArtifactsIdMetadata, "#id", tc.id, 1),
+				strings.NewReader(tc.body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
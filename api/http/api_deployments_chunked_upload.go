@@ -0,0 +1,174 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/asaskevich/govalidator"
+	"github.com/mendersoftware/go-lib-micro/config"
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/app"
+	dconfig "github.com/mendersoftware/deployments/config"
+)
+
+const (
+	hdrUploadOffset  = "Upload-Offset"
+	hdrUploadLength  = "Upload-Length"
+	hdrTusResumable  = "Tus-Resumable"
+	tusResumableV100 = "1.0.0"
+
+	ChunkedUploadContentType = "application/offset+octet-stream"
+)
+
+var (
+	ErrUploadLengthInvalid = errors.New("missing or invalid " + hdrUploadLength + " header")
+	ErrUploadOffsetInvalid = errors.New("missing or invalid " + hdrUploadOffset + " header")
+)
+
+func parseUploadHeader(r *rest.Request, name string) (int64, bool) {
+	value, err := strconv.ParseInt(r.Header.Get(name), 10, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// CreateChunkedUpload starts a resumable upload of an artifact of the
+// length given in the Upload-Length header; the Location header points to
+// the upload receiving the chunks.
+func (d *DeploymentsApiHandlers) CreateChunkedUpload(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	w.Header().Set(hdrTusResumable, tusResumableV100)
+	length, ok := parseUploadHeader(r, hdrUploadLength)
+	if !ok || length == 0 {
+		d.view.RenderError(w, r, ErrUploadLengthInvalid, http.StatusBadRequest, l)
+		return
+	}
+
+	expireSeconds := config.Config.GetInt(dconfig.SettingsStorageChunkedUploadExpireSeconds)
+	link, err := d.app.CreateChunkedUpload(
+		r.Context(),
+		length,
+		time.Duration(expireSeconds)*time.Second,
+	)
+	switch errors.Cause(err) {
+	case nil:
+		w.Header().Set(hdrUploadOffset, "0")
+		d.view.RenderSuccessPost(w, r, link.ArtifactID)
+	case app.ErrStorageLimitExceeded:
+		d.view.RenderError(w, r, err, http.StatusRequestEntityTooLarge, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
+// GetChunkedUploadOffset reports the offset and length of a chunked upload
+// in the Upload-Offset and Upload-Length headers.
+func (d *DeploymentsApiHandlers) GetChunkedUploadOffset(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	w.Header().Set(hdrTusResumable, tusResumableV100)
+	id := r.PathParam(ParamID)
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+
+	link, err := d.app.GetChunkedUpload(r.Context(), id)
+	switch errors.Cause(err) {
+	case nil:
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set(hdrUploadOffset, strconv.FormatInt(link.Chunked.Offset, 10))
+		w.Header().Set(hdrUploadLength, strconv.FormatInt(link.Chunked.Length, 10))
+		w.WriteHeader(http.StatusOK)
+	case app.ErrChunkedUploadNotFound:
+		d.view.RenderError(w, r, err, http.StatusNotFound, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
+// UploadChunk stores the request body as the chunk of a chunked upload
+// starting at the offset given in the Upload-Offset header.
+func (d *DeploymentsApiHandlers) UploadChunk(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	w.Header().Set(hdrTusResumable, tusResumableV100)
+	id := r.PathParam(ParamID)
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+	offset, ok := parseUploadHeader(r, hdrUploadOffset)
+	if !ok {
+		d.view.RenderError(w, r, ErrUploadOffsetInvalid, http.StatusBadRequest, l)
+		return
+	}
+	if r.ContentLength <= 0 {
+		d.view.RenderError(w, r, app.ErrChunkSize, http.StatusBadRequest, l)
+		return
+	}
+	defer r.Body.Close()
+
+	newOffset, err := d.app.UploadChunk(r.Context(), id, offset, r.Body, r.ContentLength)
+	switch errors.Cause(err) {
+	case nil:
+		w.Header().Set(hdrUploadOffset, strconv.FormatInt(newOffset, 10))
+		w.WriteHeader(http.StatusNoContent)
+	case app.ErrChunkedUploadNotFound:
+		d.view.RenderError(w, r, err, http.StatusNotFound, l)
+	case app.ErrChunkOffsetMismatch:
+		w.Header().Set(hdrUploadOffset, strconv.FormatInt(newOffset, 10))
+		d.view.RenderError(w, r, err, http.StatusConflict, l)
+	case app.ErrChunkSize:
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
+// CompleteChunkedUpload starts processing the artifact of a fully received
+// chunked upload.
+func (d *DeploymentsApiHandlers) CompleteChunkedUpload(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	w.Header().Set(hdrTusResumable, tusResumableV100)
+	id := r.PathParam(ParamID)
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+
+	err := d.app.CompleteChunkedUpload(r.Context(), id)
+	switch errors.Cause(err) {
+	case nil:
+		w.WriteHeader(http.StatusAccepted)
+	case app.ErrChunkedUploadNotFound, app.ErrUploadNotFound:
+		d.view.RenderError(w, r, err, http.StatusNotFound, l)
+	case app.ErrChunkedUploadIncomplete:
+		d.view.RenderError(w, r, err, http.StatusConflict, l)
+	case app.ErrStorageLimitExceeded:
+		d.view.RenderError(w, r, err, http.StatusRequestEntityTooLarge, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestCreateChunkedUpload(t *testing.T) {
+	uploadID := uuid.NewString()
+
+	testCases := map[string]struct {
+		length string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			length:     "1024",
+			callApp:    true,
+			httpStatus: http.StatusCreated,
+		},
+		"error, missing length": {
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, invalid length": {
+			length:     "-1",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, storage limit": {
+			length:     "1024",
+			callApp:    true,
+			err:        app.ErrStorageLimitExceeded,
+			httpStatus: http.StatusRequestEntityTooLarge,
+		},
+		"error, internal": {
+			length:     "1024",
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var link *model.UploadLink
+				if tc.err == nil {
+					link = &model.UploadLink{ArtifactID: uploadID}
+				}
+				app.On("CreateChunkedUpload",
+					contextMatcher(),
+					int64(1024),
+					mock.AnythingOfType("time.Duration"),
+				).Return(link, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsUploads,
+				rest.Post,
+				d.CreateChunkedUpload,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"http://localhost"+ApiUrlManagementArtifactsUploads,
+				nil,
+			)
+			if tc.length != "" {
+				req.Header.Set(hdrUploadLength, tc.length)
+			}
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			recorded.HeaderIs(hdrTusResumable, tusResumableV100)
+			if tc.httpStatus == http.StatusCreated {
+				location := recorded.Recorder.Header().Get("Location")
+				assert.True(t, strings.HasSuffix(location, "/artifacts/uploads/"+uploadID))
+				recorded.HeaderIs(hdrUploadOffset, "0")
+			}
+		})
+	}
+}
+
+func TestGetChunkedUploadOffset(t *testing.T) {
+	uploadID := uuid.NewString()
+
+	testCases := map[string]struct {
+		id string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         uploadID,
+			callApp:    true,
+			httpStatus: http.StatusOK,
+		},
+		"error, invalid id": {
+			id:         "foo",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, not found": {
+			id:         uploadID,
+			callApp:    true,
+			err:        app.ErrChunkedUploadNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var link *model.UploadLink
+				if tc.err == nil {
+					link = &model.UploadLink{
+						ArtifactID: uploadID,
+						Chunked: &model.ChunkedUpload{
+							Length: 1024,
+							Offset: 512,
+						},
+					}
+				}
+				app.On("GetChunkedUpload", contextMatcher(), tc.id).
+					Return(link, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsUploadsId,
+				rest.Head,
+				d.GetChunkedUploadOffset,
+			)
+			req, _ := http.NewRequest(
+				http.MethodHead,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementArtifactsUploadsId, "#id", tc.id, 1),
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusOK {
+				recorded.HeaderIs(hdrUploadOffset, "512")
+				recorded.HeaderIs(hdrUploadLength, "1024")
+			}
+		})
+	}
+}
+
+func TestUploadChunk(t *testing.T) {
+	uploadID := uuid.NewString()
+
+	testCases := map[string]struct {
+		offset string
+		body   string
+
+		callApp   bool
+		newOffset int64
+		err       error
+
+		httpStatus int
+	}{
+		"ok": {
+			offset:     "512",
+			body:       "chunk",
+			callApp:    true,
+			newOffset:  517,
+			httpStatus: http.StatusNoContent,
+		},
+		"error, missing offset": {
+			body:       "chunk",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, empty chunk": {
+			offset:     "512",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, offset mismatch": {
+			offset:     "512",
+			body:       "chunk",
+			callApp:    true,
+			newOffset:  1024,
+			err:        app.ErrChunkOffsetMismatch,
+			httpStatus: http.StatusConflict,
+		},
+		"error, chunk size": {
+			offset:     "512",
+			body:       "chunk",
+			callApp:    true,
+			newOffset:  512,
+			err:        app.ErrChunkSize,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, not found": {
+			offset:     "512",
+			body:       "chunk",
+			callApp:    true,
+			err:        app.ErrChunkedUploadNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			offset:     "512",
+			body:       "chunk",
+			callApp:    true,
+			newOffset:  512,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				app.On("UploadChunk",
+					contextMatcher(),
+					uploadID,
+					int64(512),
+					mock.Anything,
+					int64(len(tc.body)),
+				).Return(tc.newOffset, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsUploadsId,
+				rest.Patch,
+				d.UploadChunk,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPatch,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementArtifactsUploadsId, "#id", uploadID, 1),
+				strings.NewReader(tc.body),
+			)
+			req.Header.Set("Content-Type", ChunkedUploadContentType)
+			if tc.offset != "" {
+				req.Header.Set(hdrUploadOffset, tc.offset)
+			}
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			switch tc.httpStatus {
+			case http.StatusNoContent, http.StatusConflict:
+				recorded.HeaderIs(hdrUploadOffset, strconv.FormatInt(tc.newOffset, 10))
+			}
+		})
+	}
+}
+
+func TestCompleteChunkedUpload(t *testing.T) {
+	uploadID := uuid.NewString()
+
+	testCases := map[string]struct {
+		err error
+
+		httpStatus int
+	}{
+		"ok": {
+			httpStatus: http.StatusAccepted,
+		},
+		"error, not found": {
+			err:        app.ErrChunkedUploadNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, incomplete": {
+			err:        app.ErrChunkedUploadIncomplete,
+			httpStatus: http.StatusConflict,
+		},
+		"error, storage limit": {
+			err:        app.ErrStorageLimitExceeded,
+			httpStatus: http.StatusRequestEntityTooLarge,
+		},
+		"error, internal": {
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			app.On("CompleteChunkedUpload", contextMatcher(), uploadID).
+				Return(tc.err)
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsUploadsComplete,
+				rest.Post,
+				d.CompleteChunkedUpload,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementArtifactsUploadsComplete, "#id", uploadID, 1),
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/asaskevich/govalidator"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/mendersoftware/go-lib-micro/requestid"
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+
+	"github.com/mendersoftware/deployments/app"
+	"github.com/mendersoftware/deployments/model"
+)
+
+// tenantIDFromPath maps the "default" tenant of single-tenant setups to the
+// empty tenant ID.
+func tenantIDFromPath(tenantID string) string {
+	if tenantID == "default" {
+		return ""
+	}
+	return tenantID
+}
+
+// CopyArtifactToTenantHandler copies an artifact of the tenant in the path,
+// with its metadata and file, to the tenant in the request body and
+// responds with the copy.
+func (d *DeploymentsApiHandlers) CopyArtifactToTenantHandler(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	l := requestlog.GetRequestLogger(r)
+
+	id := r.PathParam("id")
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+	var req model.CopyArtifactRequest
+	if err := r.DecodeJsonPayload(&req); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+
+	ctx := r.Context()
+	if tenantID := tenantIDFromPath(r.PathParam("tenant")); tenantID != "" {
+		ctx = identity.WithContext(ctx, &identity.Identity{Tenant: tenantID})
+	}
+	image, err := d.app.CopyArtifact(ctx, id, tenantIDFromPath(req.TenantID))
+	if err == nil {
+		w.WriteHeader(http.StatusCreated)
+		_ = w.WriteJson(image)
+		return
+	}
+	var cErr *model.ConflictError
+	if errors.As(err, &cErr) {
+		w.WriteHeader(http.StatusConflict)
+		_ = cErr.WithRequestID(requestid.FromContext(ctx))
+		err = w.WriteJson(cErr)
+		if err != nil {
+			l.Error(err)
+		} else {
+			l.Error(cErr.Error())
+		}
+		return
+	}
+	switch cause := errors.Cause(err); cause {
+	case app.ErrImageMetaNotFound:
+		d.view.RenderError(w, r, cause, http.StatusNotFound, l)
+	case app.ErrCopyArtifactSameTenant:
+		d.view.RenderError(w, r, cause, http.StatusBadRequest, l)
+	case app.ErrModelArtifactNotUnique, app.ErrReleaseAliasConflict,
+		app.ErrArtifactNotSigned, app.ErrArtifactSignatureInvalid:
+		d.view.RenderError(w, r, cause, http.StatusUnprocessableEntity, l)
+	case app.ErrStorageLimitExceeded:
+		d.view.RenderError(w, r, cause, http.StatusRequestEntityTooLarge, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
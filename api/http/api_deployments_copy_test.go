@@ -0,0 +1,156 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestCopyArtifactToTenant(t *testing.T) {
+	artifactID := uuid.NewString()
+
+	testCases := map[string]struct {
+		id   string
+		body string
+
+		callApp  bool
+		tenantID string
+		err      error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         artifactID,
+			body:       `{"tenant_id": "customer"}`,
+			callApp:    true,
+			tenantID:   "customer",
+			httpStatus: http.StatusCreated,
+		},
+		"ok, default tenant": {
+			id:         artifactID,
+			body:       `{"tenant_id": "default"}`,
+			callApp:    true,
+			httpStatus: http.StatusCreated,
+		},
+		"error, invalid id": {
+			id:         "foo",
+			body:       `{"tenant_id": "customer"}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, missing tenant": {
+			id:         artifactID,
+			body:       `{}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, artifact not found": {
+			id:         artifactID,
+			body:       `{"tenant_id": "customer"}`,
+			callApp:    true,
+			tenantID:   "customer",
+			err:        app.ErrImageMetaNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, same tenant": {
+			id:         artifactID,
+			body:       `{"tenant_id": "golden"}`,
+			callApp:    true,
+			tenantID:   "golden",
+			err:        app.ErrCopyArtifactSameTenant,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, artifact not unique": {
+			id:         artifactID,
+			body:       `{"tenant_id": "customer"}`,
+			callApp:    true,
+			tenantID:   "customer",
+			err:        app.ErrModelArtifactNotUnique,
+			httpStatus: http.StatusUnprocessableEntity,
+		},
+		"error, conflicting artifact": {
+			id:         artifactID,
+			body:       `{"tenant_id": "customer"}`,
+			callApp:    true,
+			tenantID:   "customer",
+			err:        model.NewConflictError(errors.New(app.ErrMsgArtifactConflict)),
+			httpStatus: http.StatusConflict,
+		},
+		"error, internal": {
+			id:         artifactID,
+			body:       `{"tenant_id": "customer"}`,
+			callApp:    true,
+			tenantID:   "customer",
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var image *model.Image
+				if tc.err == nil {
+					image = &model.Image{Id: uuid.NewString()}
+				}
+				app.On("CopyArtifact",
+					mock.MatchedBy(func(ctx context.Context) bool {
+						idty := identity.FromContext(ctx)
+						return idty != nil && idty.Tenant == "golden"
+					}),
+					tc.id,
+					tc.tenantID,
+				).Return(image, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlInternalTenantArtifactCopy,
+				rest.Post,
+				d.CopyArtifactToTenantHandler,
+			)
+			url := strings.NewReplacer(
+				"#tenant", "golden",
+				"#id", tc.id,
+			).Replace(ApiUrlInternalTenantArtifactCopy)
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"http://localhost"+url,
+				strings.NewReader(tc.body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
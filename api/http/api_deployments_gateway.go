@@ -0,0 +1,203 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+
+	"github.com/mendersoftware/deployments/app"
+	"github.com/mendersoftware/deployments/model"
+)
+
+// gatewayDevices returns the set of the given devices connected through the
+// gateway authenticated with the request.
+func (d *DeploymentsApiHandlers) gatewayDevices(
+	ctx context.Context,
+	idata *identity.Identity,
+	deviceIDs []string,
+) (map[string]struct{}, error) {
+	devices, err := d.app.GetGatewayDevices(ctx, idata.Subject, deviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(devices))
+	for _, deviceID := range devices {
+		set[deviceID] = struct{}{}
+	}
+	return set, nil
+}
+
+// gatewayDeviceContext returns the context used to act on behalf of a device
+// connected through the gateway.
+func gatewayDeviceContext(
+	ctx context.Context,
+	idata *identity.Identity,
+	deviceID string,
+) (context.Context, *identity.Identity) {
+	deviceIdentity := &identity.Identity{
+		Subject:  deviceID,
+		Tenant:   idata.Tenant,
+		Plan:     idata.Plan,
+		IsDevice: true,
+	}
+	return identity.WithContext(ctx, deviceIdentity), deviceIdentity
+}
+
+// GetDeploymentsForGatewayDevices checks for deployments on behalf of the
+// devices connected through the gateway.
+func (d *DeploymentsApiHandlers) GetDeploymentsForGatewayDevices(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := requestlog.GetRequestLogger(r)
+
+	idata := identity.FromContext(ctx)
+	if idata == nil {
+		d.view.RenderError(w, r, ErrMissingIdentity, http.StatusBadRequest, l)
+		return
+	}
+
+	var req model.GatewayDeploymentsNextRequest
+	if err := r.DecodeJsonPayload(&req); err != nil {
+		d.view.RenderError(w, r,
+			errors.Wrap(err, "invalid schema"),
+			http.StatusBadRequest, l)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+
+	devices, err := d.gatewayDevices(ctx, idata, req.DeviceIDs())
+	if err != nil {
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+
+	results := make([]model.GatewayDeploymentNext, len(req.Devices))
+	for i := range req.Devices {
+		device := &req.Devices[i]
+		result := &results[i]
+		result.DeviceID = device.DeviceID
+		if _, ok := devices[device.DeviceID]; !ok {
+			result.Status = http.StatusForbidden
+			result.Error = app.ErrNotGatewayDevice.Error()
+			continue
+		}
+
+		deviceCtx, deviceIdentity := gatewayDeviceContext(ctx, idata, device.DeviceID)
+		deployment, err := d.deploymentForDevice(deviceCtx, r, deviceIdentity,
+			&model.DeploymentNextRequest{
+				DeviceProvides: &device.InstalledDeviceDeployment,
+			})
+		switch {
+		case err == app.ErrConflictingRequestData:
+			result.Status = http.StatusConflict
+			result.Error = err.Error()
+		case err != nil:
+			l.Errorf("failed to check deployments for device %s: %s",
+				device.DeviceID, err.Error())
+			result.Status = http.StatusInternalServerError
+			result.Error = ErrInternal.Error()
+		case deployment == nil:
+			result.Status = http.StatusNoContent
+		default:
+			result.Status = http.StatusOK
+			result.Deployment = deployment
+		}
+	}
+
+	d.view.RenderSuccessGet(w, results)
+}
+
+// PostDeploymentStatusesForGatewayDevices reports deployment statuses on
+// behalf of the devices connected through the gateway.
+func (d *DeploymentsApiHandlers) PostDeploymentStatusesForGatewayDevices(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := requestlog.GetRequestLogger(r)
+
+	idata := identity.FromContext(ctx)
+	if idata == nil {
+		d.view.RenderError(w, r, ErrMissingIdentity, http.StatusBadRequest, l)
+		return
+	}
+
+	var req model.GatewayStatusReportsRequest
+	if err := r.DecodeJsonPayload(&req); err != nil {
+		d.view.RenderError(w, r,
+			errors.Wrap(err, "invalid schema"),
+			http.StatusBadRequest, l)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+
+	devices, err := d.gatewayDevices(ctx, idata, req.DeviceIDs())
+	if err != nil {
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+
+	results := make([]model.GatewayStatusReportResult, len(req.Statuses))
+	for i, report := range req.Statuses {
+		result := &results[i]
+		result.DeviceID = report.DeviceID
+		result.DeploymentID = report.DeploymentID
+		if _, ok := devices[report.DeviceID]; !ok {
+			result.Status = http.StatusForbidden
+			result.Error = app.ErrNotGatewayDevice.Error()
+			continue
+		}
+
+		deviceCtx, _ := gatewayDeviceContext(ctx, idata, report.DeviceID)
+		err := d.app.UpdateDeviceDeploymentStatus(deviceCtx,
+			report.DeploymentID, report.DeviceID, model.DeviceDeploymentState{
+				Status:   report.Status,
+				SubState: report.SubState,
+			})
+		switch err {
+		case nil:
+			result.Status = http.StatusNoContent
+		case app.ErrDeploymentAborted, app.ErrDeviceDecommissioned,
+			app.ErrInvalidStatusTransition:
+			result.Status = http.StatusConflict
+			result.Error = err.Error()
+		case app.ErrStorageNotFound:
+			result.Status = http.StatusNotFound
+			result.Error = err.Error()
+		default:
+			l.Errorf("failed to update the deployment status for device %s: %s",
+				report.DeviceID, err.Error())
+			result.Status = http.StatusInternalServerError
+			result.Error = ErrInternal.Error()
+		}
+	}
+
+	d.view.RenderSuccessGet(w, results)
+}
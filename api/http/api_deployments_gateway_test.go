@@ -0,0 +1,375 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/google/uuid"
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func gatewayDeviceContextMatcher(tenantID, deviceID string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		id := identity.FromContext(ctx)
+		return id != nil && id.IsDevice &&
+			id.Tenant == tenantID && id.Subject == deviceID
+	})
+}
+
+func TestGetDeploymentsForGatewayDevices(t *testing.T) {
+	const (
+		tenantID  = "tenant"
+		gatewayID = "gateway"
+	)
+	deploymentID := uuid.NewSHA1(uuid.NameSpaceURL, []byte("deployment")).String()
+	provides := func(deviceID string) model.GatewayDeviceProvides {
+		return model.GatewayDeviceProvides{
+			DeviceID: deviceID,
+			InstalledDeviceDeployment: model.InstalledDeviceDeployment{
+				ArtifactName: "release-1",
+				DeviceType:   "sensor",
+			},
+		}
+	}
+	instructions := &model.DeploymentInstructions{
+		ID: deploymentID,
+		Artifact: model.ArtifactDeploymentInstructions{
+			ArtifactName:          "release-2",
+			DeviceTypesCompatible: []string{"sensor"},
+		},
+	}
+
+	testCases := map[string]struct {
+		body *model.GatewayDeploymentsNextRequest
+		app  func(t *testing.T) *mapp.App
+
+		status  int
+		results []model.GatewayDeploymentNext
+	}{
+		"ok": {
+			body: &model.GatewayDeploymentsNextRequest{
+				Devices: []model.GatewayDeviceProvides{
+					provides("child-1"),
+					provides("child-2"),
+					provides("child-3"),
+					provides("other"),
+				},
+			},
+			app: func(t *testing.T) *mapp.App {
+				a := new(mapp.App)
+				a.On("GetGatewayDevices", contextMatcher(), gatewayID,
+					[]string{"child-1", "child-2", "child-3", "other"}).
+					Return([]string{"child-1", "child-2", "child-3"}, nil)
+				for deviceID, ret := range map[string]struct {
+					instructions *model.DeploymentInstructions
+					err          error
+				}{
+					"child-1": {instructions: instructions},
+					"child-2": {},
+					"child-3": {err: app.ErrConflictingRequestData},
+				} {
+					p := provides(deviceID)
+					a.On("GetDeploymentForDeviceWithCurrent",
+						gatewayDeviceContextMatcher(tenantID, deviceID),
+						deviceID,
+						&model.DeploymentNextRequest{
+							DeviceProvides: &p.InstalledDeviceDeployment,
+						},
+					).Return(ret.instructions, ret.err)
+				}
+				return a
+			},
+
+			status: http.StatusOK,
+			results: []model.GatewayDeploymentNext{{
+				DeviceID:   "child-1",
+				Status:     http.StatusOK,
+				Deployment: instructions,
+			}, {
+				DeviceID: "child-2",
+				Status:   http.StatusNoContent,
+			}, {
+				DeviceID: "child-3",
+				Status:   http.StatusConflict,
+				Error:    app.ErrConflictingRequestData.Error(),
+			}, {
+				DeviceID: "other",
+				Status:   http.StatusForbidden,
+				Error:    app.ErrNotGatewayDevice.Error(),
+			}},
+		},
+		"ok, internal error for a device": {
+			body: &model.GatewayDeploymentsNextRequest{
+				Devices: []model.GatewayDeviceProvides{provides("child-1")},
+			},
+			app: func(t *testing.T) *mapp.App {
+				a := new(mapp.App)
+				a.On("GetGatewayDevices", contextMatcher(), gatewayID,
+					[]string{"child-1"}).
+					Return([]string{"child-1"}, nil)
+				a.On("GetDeploymentForDeviceWithCurrent",
+					contextMatcher(), "child-1", mock.Anything,
+				).Return(nil, errors.New("mongo: connection refused"))
+				return a
+			},
+
+			status: http.StatusOK,
+			results: []model.GatewayDeploymentNext{{
+				DeviceID: "child-1",
+				Status:   http.StatusInternalServerError,
+				Error:    ErrInternal.Error(),
+			}},
+		},
+		"error, invalid request": {
+			body: &model.GatewayDeploymentsNextRequest{},
+			app: func(t *testing.T) *mapp.App {
+				return new(mapp.App)
+			},
+
+			status: http.StatusBadRequest,
+		},
+		"error, inventory": {
+			body: &model.GatewayDeploymentsNextRequest{
+				Devices: []model.GatewayDeviceProvides{provides("child-1")},
+			},
+			app: func(t *testing.T) *mapp.App {
+				a := new(mapp.App)
+				a.On("GetGatewayDevices", contextMatcher(), gatewayID,
+					[]string{"child-1"}).
+					Return(nil, errors.New("inventory unavailable"))
+				return a
+			},
+
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a := tc.app(t)
+			defer a.AssertExpectations(t)
+
+			d := NewDeploymentsApiHandlers(nil, new(view.RESTView), a)
+			api := setUpRestTest(
+				ApiUrlDevicesGatewayDeploymentsNext,
+				rest.Post,
+				d.GetDeploymentsForGatewayDevices,
+			)
+			b, _ := json.Marshal(tc.body)
+			req, _ := http.NewRequestWithContext(
+				identity.WithContext(context.Background(), &identity.Identity{
+					Subject:  gatewayID,
+					Tenant:   tenantID,
+					IsDevice: true,
+				}),
+				http.MethodPost,
+				"http://localhost"+ApiUrlDevicesGatewayDeploymentsNext,
+				strings.NewReader(string(b)),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.status)
+			if tc.status == http.StatusOK {
+				var results []model.GatewayDeploymentNext
+				err := json.Unmarshal(recorded.Recorder.Body.Bytes(), &results)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.results, results)
+			}
+		})
+	}
+}
+
+func TestPostDeploymentStatusesForGatewayDevices(t *testing.T) {
+	const (
+		tenantID  = "tenant"
+		gatewayID = "gateway"
+	)
+	deploymentID := uuid.NewSHA1(uuid.NameSpaceURL, []byte("deployment")).String()
+	report := func(deviceID string, status model.DeviceDeploymentStatus) model.GatewayStatusReport {
+		return model.GatewayStatusReport{
+			DeviceID:     deviceID,
+			DeploymentID: deploymentID,
+			Status:       status,
+		}
+	}
+
+	testCases := map[string]struct {
+		body *model.GatewayStatusReportsRequest
+		app  func(t *testing.T) *mapp.App
+
+		status  int
+		results []model.GatewayStatusReportResult
+	}{
+		"ok": {
+			body: &model.GatewayStatusReportsRequest{
+				Statuses: []model.GatewayStatusReport{
+					report("child-1", model.DeviceDeploymentStatusInstalling),
+					report("child-2", model.DeviceDeploymentStatusSuccess),
+					report("child-3", model.DeviceDeploymentStatusDownloading),
+					report("child-4", model.DeviceDeploymentStatusFailure),
+					report("child-5", model.DeviceDeploymentStatusFailure),
+					report("other", model.DeviceDeploymentStatusSuccess),
+				},
+			},
+			app: func(t *testing.T) *mapp.App {
+				a := new(mapp.App)
+				a.On("GetGatewayDevices", contextMatcher(), gatewayID,
+					[]string{"child-1", "child-2", "child-3", "child-4", "child-5", "other"}).
+					Return([]string{"child-1", "child-2", "child-3", "child-4", "child-5"}, nil)
+				for deviceID, ret := range map[string]struct {
+					status model.DeviceDeploymentStatus
+					err    error
+				}{
+					"child-1": {status: model.DeviceDeploymentStatusInstalling},
+					"child-2": {
+						status: model.DeviceDeploymentStatusSuccess,
+						err:    app.ErrDeploymentAborted,
+					},
+					"child-3": {
+						status: model.DeviceDeploymentStatusDownloading,
+						err:    app.ErrInvalidStatusTransition,
+					},
+					"child-4": {
+						status: model.DeviceDeploymentStatusFailure,
+						err:    app.ErrStorageNotFound,
+					},
+					"child-5": {
+						status: model.DeviceDeploymentStatusFailure,
+						err:    errors.New("mongo: connection refused"),
+					},
+				} {
+					a.On("UpdateDeviceDeploymentStatus",
+						gatewayDeviceContextMatcher(tenantID, deviceID),
+						deploymentID,
+						deviceID,
+						model.DeviceDeploymentState{Status: ret.status},
+					).Return(ret.err)
+				}
+				return a
+			},
+
+			status: http.StatusOK,
+			results: []model.GatewayStatusReportResult{{
+				DeviceID:     "child-1",
+				DeploymentID: deploymentID,
+				Status:       http.StatusNoContent,
+			}, {
+				DeviceID:     "child-2",
+				DeploymentID: deploymentID,
+				Status:       http.StatusConflict,
+				Error:        app.ErrDeploymentAborted.Error(),
+			}, {
+				DeviceID:     "child-3",
+				DeploymentID: deploymentID,
+				Status:       http.StatusConflict,
+				Error:        app.ErrInvalidStatusTransition.Error(),
+			}, {
+				DeviceID:     "child-4",
+				DeploymentID: deploymentID,
+				Status:       http.StatusNotFound,
+				Error:        app.ErrStorageNotFound.Error(),
+			}, {
+				DeviceID:     "child-5",
+				DeploymentID: deploymentID,
+				Status:       http.StatusInternalServerError,
+				Error:        ErrInternal.Error(),
+			}, {
+				DeviceID:     "other",
+				DeploymentID: deploymentID,
+				Status:       http.StatusForbidden,
+				Error:        app.ErrNotGatewayDevice.Error(),
+			}},
+		},
+		"error, invalid request": {
+			body: &model.GatewayStatusReportsRequest{
+				Statuses: []model.GatewayStatusReport{
+					report("child-1", model.DeviceDeploymentStatusAborted),
+				},
+			},
+			app: func(t *testing.T) *mapp.App {
+				return new(mapp.App)
+			},
+
+			status: http.StatusBadRequest,
+		},
+		"error, inventory": {
+			body: &model.GatewayStatusReportsRequest{
+				Statuses: []model.GatewayStatusReport{
+					report("child-1", model.DeviceDeploymentStatusInstalling),
+				},
+			},
+			app: func(t *testing.T) *mapp.App {
+				a := new(mapp.App)
+				a.On("GetGatewayDevices", contextMatcher(), gatewayID,
+					[]string{"child-1"}).
+					Return(nil, errors.New("inventory unavailable"))
+				return a
+			},
+
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a := tc.app(t)
+			defer a.AssertExpectations(t)
+
+			d := NewDeploymentsApiHandlers(nil, new(view.RESTView), a)
+			api := setUpRestTest(
+				ApiUrlDevicesGatewayDeploymentsStatus,
+				rest.Post,
+				d.PostDeploymentStatusesForGatewayDevices,
+			)
+			b, _ := json.Marshal(tc.body)
+			req, _ := http.NewRequestWithContext(
+				identity.WithContext(context.Background(), &identity.Identity{
+					Subject:  gatewayID,
+					Tenant:   tenantID,
+					IsDevice: true,
+				}),
+				http.MethodPost,
+				"http://localhost"+ApiUrlDevicesGatewayDeploymentsStatus,
+				strings.NewReader(string(b)),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.status)
+			if tc.status == http.StatusOK {
+				var results []model.GatewayStatusReportResult
+				err := json.Unmarshal(recorded.Recorder.Body.Bytes(), &results)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.results, results)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/asaskevich/govalidator"
+
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+
+	"github.com/mendersoftware/deployments/app"
+	"github.com/mendersoftware/deployments/model"
+)
+
+// ImportArtifact starts importing an artifact from a remote URL; the
+// Location header points to the job tracking the import.
+func (d *DeploymentsApiHandlers) ImportArtifact(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	var req model.ImportArtifactRequest
+	if err := r.DecodeJsonPayload(&req); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+
+	job, err := d.app.ImportArtifact(r.Context(), &req)
+	switch err {
+	case nil:
+		d.view.RenderSuccessPost(w, r, job.ID)
+	case app.ErrStorageLimitExceeded:
+		d.view.RenderError(w, r, err, http.StatusRequestEntityTooLarge, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
+func (d *DeploymentsApiHandlers) GetImportJob(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	id := r.PathParam("id")
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+
+	job, err := d.app.GetImportJob(r.Context(), id)
+	switch err {
+	case nil:
+		d.view.RenderSuccessGet(w, job)
+	case app.ErrImportJobNotFound:
+		d.view.RenderError(w, r, err, http.StatusNotFound, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
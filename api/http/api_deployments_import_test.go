@@ -0,0 +1,186 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestImportArtifact(t *testing.T) {
+	jobID := uuid.NewString()
+	body := `{"url": "https://artifacts.example.com/release-1.mender",
+		"headers": {"Authorization": "Bearer token"}}`
+
+	testCases := map[string]struct {
+		body string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			body:       body,
+			callApp:    true,
+			httpStatus: http.StatusCreated,
+		},
+		"error, missing url": {
+			body:       `{"checksum": ""}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, unsupported scheme": {
+			body:       `{"url": "ftp://artifacts.example.com/release-1.mender"}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, malformed body": {
+			body:       `{"url": `,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, storage limit": {
+			body:       body,
+			callApp:    true,
+			err:        app.ErrStorageLimitExceeded,
+			httpStatus: http.StatusRequestEntityTooLarge,
+		},
+		"error, internal": {
+			body:       body,
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var job *model.ImportJob
+				if tc.err == nil {
+					job = &model.ImportJob{ID: jobID}
+				}
+				app.On("ImportArtifact",
+					contextMatcher(),
+					&model.ImportArtifactRequest{
+						URL:     "https://artifacts.example.com/release-1.mender",
+						Headers: map[string]string{"Authorization": "Bearer token"},
+					},
+				).Return(job, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsImport,
+				rest.Post,
+				d.ImportArtifact,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"http://localhost"+ApiUrlManagementArtifactsImport,
+				strings.NewReader(tc.body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusCreated {
+				location := recorded.Recorder.Header().Get("Location")
+				assert.True(t, strings.HasSuffix(location, "/artifacts/import/"+jobID))
+			}
+		})
+	}
+}
+
+func TestGetImportJob(t *testing.T) {
+	jobID := uuid.NewString()
+
+	testCases := map[string]struct {
+		id string
+
+		callApp bool
+		job     *model.ImportJob
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         jobID,
+			callApp:    true,
+			job:        &model.ImportJob{ID: jobID, Status: model.ImportJobStatusCompleted},
+			httpStatus: http.StatusOK,
+		},
+		"error, invalid id": {
+			id:         "foo",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, not found": {
+			id:         jobID,
+			callApp:    true,
+			err:        app.ErrImportJobNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			id:         jobID,
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				app.On("GetImportJob", contextMatcher(), tc.id).
+					Return(tc.job, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsImportId,
+				rest.Get,
+				d.GetImportJob,
+			)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementArtifactsImportId, "#id", tc.id, 1),
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusOK {
+				assert.Contains(t, recorded.Recorder.Body.String(), `"status":"completed"`)
+			}
+		})
+	}
+}
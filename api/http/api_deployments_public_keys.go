@@ -0,0 +1,105 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/asaskevich/govalidator"
+
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+
+	"github.com/mendersoftware/deployments/app"
+	"github.com/mendersoftware/deployments/model"
+)
+
+// AddPublicKey adds a public key trusted to sign the tenant's artifacts
+func (d *DeploymentsApiHandlers) AddPublicKey(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	var constructor model.PublicKeyConstructor
+	if err := r.DecodeJsonPayload(&constructor); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+	if err := constructor.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+
+	key, err := d.app.AddPublicKey(r.Context(), &constructor)
+	switch err {
+	case nil:
+		d.view.RenderSuccessPost(w, r, key.ID)
+	case model.ErrPublicKeyInvalidPEM, model.ErrPublicKeyType:
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+	case app.ErrPublicKeyConflict:
+		d.view.RenderError(w, r, err, http.StatusConflict, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
+func (d *DeploymentsApiHandlers) ListPublicKeys(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	keys, err := d.app.ListPublicKeys(r.Context())
+	if err != nil {
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+	d.view.RenderSuccessGet(w, keys)
+}
+
+func (d *DeploymentsApiHandlers) GetPublicKey(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	id := r.PathParam("id")
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+
+	key, err := d.app.GetPublicKey(r.Context(), id)
+	switch err {
+	case nil:
+		d.view.RenderSuccessGet(w, key)
+	case app.ErrPublicKeyNotFound:
+		d.view.RenderErrorNotFound(w, r, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
+
+func (d *DeploymentsApiHandlers) DeletePublicKey(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	id := r.PathParam("id")
+	if !govalidator.IsUUID(id) {
+		d.view.RenderError(w, r, ErrIDNotUUID, http.StatusBadRequest, l)
+		return
+	}
+
+	err := d.app.DeletePublicKey(r.Context(), id)
+	switch err {
+	case nil:
+		d.view.RenderSuccessDelete(w)
+	case app.ErrPublicKeyNotFound:
+		d.view.RenderErrorNotFound(w, r, l)
+	default:
+		d.view.RenderInternalError(w, r, err, l)
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestAddPublicKey(t *testing.T) {
+	keyID := uuid.NewString()
+
+	testCases := map[string]struct {
+		body string
+
+		callApp bool
+		key     *model.PublicKey
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			body:       `{"name": "release key", "key": "pem"}`,
+			callApp:    true,
+			key:        &model.PublicKey{ID: keyID},
+			httpStatus: http.StatusCreated,
+		},
+		"error, missing name": {
+			body:       `{"key": "pem"}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, malformed body": {
+			body:       `{"key": `,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, invalid key": {
+			body:       `{"name": "release key", "key": "pem"}`,
+			callApp:    true,
+			err:        model.ErrPublicKeyInvalidPEM,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, already trusted": {
+			body:       `{"name": "release key", "key": "pem"}`,
+			callApp:    true,
+			err:        app.ErrPublicKeyConflict,
+			httpStatus: http.StatusConflict,
+		},
+		"error, internal": {
+			body:       `{"name": "release key", "key": "pem"}`,
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				app.On("AddPublicKey",
+					contextMatcher(),
+					&model.PublicKeyConstructor{Name: "release key", Key: "pem"},
+				).Return(tc.key, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsKeys,
+				rest.Post,
+				d.AddPublicKey,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"http://localhost"+ApiUrlManagementArtifactsKeys,
+				strings.NewReader(tc.body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusCreated {
+				location := recorded.Recorder.Header().Get("Location")
+				assert.True(t, strings.HasSuffix(location, "/artifacts/keys/"+keyID))
+			}
+		})
+	}
+}
+
+func TestListPublicKeys(t *testing.T) {
+	keys := []model.PublicKey{{
+		ID:          uuid.NewString(),
+		Name:        "release key",
+		Algorithm:   model.PublicKeyAlgorithmECDSA,
+		Fingerprint: "fingerprint",
+	}}
+
+	testCases := map[string]struct {
+		keys []model.PublicKey
+		err  error
+
+		httpStatus int
+	}{
+		"ok": {
+			keys:       keys,
+			httpStatus: http.StatusOK,
+		},
+		"error, internal": {
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			app.On("ListPublicKeys", contextMatcher()).Return(tc.keys, tc.err)
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsKeys,
+				rest.Get,
+				d.ListPublicKeys,
+			)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"http://localhost"+ApiUrlManagementArtifactsKeys,
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusOK {
+				var actual []model.PublicKey
+				err := json.Unmarshal(recorded.Recorder.Body.Bytes(), &actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.keys, actual)
+			}
+		})
+	}
+}
+
+func TestGetPublicKey(t *testing.T) {
+	keyID := uuid.NewString()
+
+	testCases := map[string]struct {
+		id string
+
+		key *model.PublicKey
+		err error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         keyID,
+			key:        &model.PublicKey{ID: keyID, Name: "release key"},
+			httpStatus: http.StatusOK,
+		},
+		"error, invalid id": {
+			id:         "key",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, not found": {
+			id:         keyID,
+			err:        app.ErrPublicKeyNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			id:         keyID,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.id == keyID {
+				app.On("GetPublicKey", contextMatcher(), keyID).
+					Return(tc.key, tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsKeysId,
+				rest.Get,
+				d.GetPublicKey,
+			)
+			url := strings.Replace(ApiUrlManagementArtifactsKeysId, "#id", tc.id, 1)
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost"+url, nil)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.httpStatus == http.StatusOK {
+				actual := &model.PublicKey{}
+				err := json.Unmarshal(recorded.Recorder.Body.Bytes(), actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.key.ID, actual.ID)
+				assert.Equal(t, tc.key.Name, actual.Name)
+			}
+		})
+	}
+}
+
+func TestDeletePublicKey(t *testing.T) {
+	keyID := uuid.NewString()
+
+	testCases := map[string]struct {
+		id  string
+		err error
+
+		httpStatus int
+	}{
+		"ok": {
+			id:         keyID,
+			httpStatus: http.StatusNoContent,
+		},
+		"error, invalid id": {
+			id:         "key",
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, not found": {
+			id:         keyID,
+			err:        app.ErrPublicKeyNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			id:         keyID,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.id == keyID {
+				app.On("DeletePublicKey", contextMatcher(), keyID).Return(tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsKeysId,
+				rest.Delete,
+				d.DeletePublicKey,
+			)
+			url := strings.Replace(ApiUrlManagementArtifactsKeysId, "#id", tc.id, 1)
+			req, _ := http.NewRequest(http.MethodDelete, "http://localhost"+url, nil)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Northern.tech AS
+//
+//	Licensed under the Apache License, Version 2.0 (the "License");
+//	you may not use this file except in compliance with the License.
+//	You may obtain a copy of the License at
+//
+//	    http://www.apache.org/licenses/LICENSE-2.0
+//
+//	Unless required by applicable law or agreed to in writing, software
+//	distributed under the License is distributed on an "AS IS" BASIS,
+//	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//	See the License for the specific language governing permissions and
+//	limitations under the License.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/mendersoftware/go-lib-micro/rest_utils"
+
+	"github.com/mendersoftware/deployments/app"
+	"github.com/mendersoftware/deployments/model"
+)
+
+func (d *DeploymentsApiHandlers) ListReleaseChannels(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	channels, err := d.app.ListReleaseChannels(ctx)
+	if err != nil {
+		rest_utils.RestErrWithLog(w, r, l, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = w.WriteJson(channels)
+	if err != nil {
+		l.Errorf("failed to serialize JSON response: %s", err.Error())
+	}
+}
+
+func (d *DeploymentsApiHandlers) GetReleaseChannel(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	channel, err := d.app.GetReleaseChannel(ctx, r.PathParam(ParamName))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, app.ErrReleaseChannelNotFound) {
+			status = http.StatusNotFound
+		}
+		rest_utils.RestErrWithLog(w, r, l, err, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = w.WriteJson(channel)
+	if err != nil {
+		l.Errorf("failed to serialize JSON response: %s", err.Error())
+	}
+}
+
+func (d *DeploymentsApiHandlers) PromoteRelease(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	channelName := r.PathParam(ParamName)
+
+	var promotion model.ReleasePromotion
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(&promotion); err != nil {
+		rest_utils.RestErrWithLog(w, r, l,
+			errors.WithMessage(err,
+				"malformed JSON in request body"),
+			http.StatusBadRequest)
+		return
+	}
+	if err := promotion.Validate(channelName); err != nil {
+		rest_utils.RestErrWithLog(w, r, l,
+			errors.WithMessage(err,
+				"invalid request body"),
+			http.StatusBadRequest)
+		return
+	}
+
+	channel, err := d.app.PromoteRelease(ctx, channelName, promotion)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, app.ErrReleaseChannelNotFound) ||
+			errors.Is(err, app.ErrReleaseNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, app.ErrReleaseNotDeployable) {
+			status = http.StatusUnprocessableEntity
+		}
+		rest_utils.RestErrWithLog(w, r, l, err, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = w.WriteJson(channel)
+	if err != nil {
+		l.Errorf("failed to serialize JSON response: %s", err.Error())
+	}
+}
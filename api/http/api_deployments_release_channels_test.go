@@ -0,0 +1,228 @@
+// Copyright 2023 Northern.tech AS
+//
+//	Licensed under the Apache License, Version 2.0 (the "License");
+//	you may not use this file except in compliance with the License.
+//	You may obtain a copy of the License at
+//
+//	    http://www.apache.org/licenses/LICENSE-2.0
+//
+//	Unless required by applicable law or agreed to in writing, software
+//	distributed under the License is distributed on an "AS IS" BASIS,
+//	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//	See the License for the specific language governing permissions and
+//	limitations under the License.
+
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/app"
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestListReleaseChannels(t *testing.T) {
+	testCases := map[string]struct {
+		channels []model.ReleaseChannel
+		err      error
+
+		httpStatus int
+		body       string
+	}{
+		"ok": {
+			channels: []model.ReleaseChannel{{
+				Name:        "stable",
+				ReleaseName: "release-1",
+			}},
+			httpStatus: http.StatusOK,
+			body: `[{"name":"stable","release_name":"release-1",` +
+				`"modified":"0001-01-01T00:00:00Z"}]`,
+		},
+		"error, internal": {
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			app.On("ListReleaseChannels", contextMatcher()).
+				Return(tc.channels, tc.err)
+
+			d := NewDeploymentsApiHandlers(nil, new(view.RESTView), app)
+			api := setUpRestTest(
+				ApiUrlManagementV2ReleaseChannels,
+				rest.Get,
+				d.ListReleaseChannels,
+			)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"http://localhost"+ApiUrlManagementV2ReleaseChannels,
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.body != "" {
+				recorded.BodyIs(tc.body)
+			}
+		})
+	}
+}
+
+func TestGetReleaseChannel(t *testing.T) {
+	testCases := map[string]struct {
+		err error
+
+		httpStatus int
+	}{
+		"ok": {
+			httpStatus: http.StatusOK,
+		},
+		"error, not found": {
+			err:        app.ErrReleaseChannelNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			var channel *model.ReleaseChannel
+			if tc.err == nil {
+				channel = &model.ReleaseChannel{
+					Name:        "stable",
+					ReleaseName: "release-1",
+				}
+			}
+			app.On("GetReleaseChannel", contextMatcher(), "stable").
+				Return(channel, tc.err)
+
+			d := NewDeploymentsApiHandlers(nil, new(view.RESTView), app)
+			api := setUpRestTest(
+				ApiUrlManagementV2ReleaseChannelsName,
+				rest.Get,
+				d.GetReleaseChannel,
+			)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementV2ReleaseChannelsName, "#name", "stable", 1),
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
+
+func TestPromoteRelease(t *testing.T) {
+	testCases := map[string]struct {
+		channel string
+		body    string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			channel:    "stable",
+			body:       `{"from_channel": "beta"}`,
+			callApp:    true,
+			httpStatus: http.StatusOK,
+		},
+		"error, malformed body": {
+			channel:    "stable",
+			body:       `{"from_channel": `,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, invalid promotion": {
+			channel:    "stable",
+			body:       `{"from_channel": "beta", "release_name": "release-1"}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, invalid channel": {
+			channel:    "stable%20eu",
+			body:       `{"release_name": "release-1"}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, channel not found": {
+			channel:    "stable",
+			body:       `{"from_channel": "beta"}`,
+			callApp:    true,
+			err:        app.ErrReleaseChannelNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, release not found": {
+			channel:    "stable",
+			body:       `{"release_name": "release-1"}`,
+			callApp:    true,
+			err:        app.ErrReleaseNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, release not deployable": {
+			channel:    "stable",
+			body:       `{"release_name": "release-1"}`,
+			callApp:    true,
+			err:        app.ErrReleaseNotDeployable,
+			httpStatus: http.StatusUnprocessableEntity,
+		},
+		"error, internal": {
+			channel:    "stable",
+			body:       `{"release_name": "release-1"}`,
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				var channel *model.ReleaseChannel
+				if tc.err == nil {
+					channel = &model.ReleaseChannel{Name: tc.channel}
+				}
+				app.On("PromoteRelease", contextMatcher(), tc.channel,
+					mock.AnythingOfType("model.ReleasePromotion")).
+					Return(channel, tc.err)
+			}
+
+			d := NewDeploymentsApiHandlers(nil, new(view.RESTView), app)
+			api := setUpRestTest(
+				ApiUrlManagementV2ReleaseChannelPromote,
+				rest.Post,
+				d.PromoteRelease,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"http://localhost"+strings.Replace(
+					ApiUrlManagementV2ReleaseChannelPromote, "#name", tc.channel, 1),
+				strings.NewReader(tc.body),
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/mendersoftware/go-lib-micro/requestid"
 	"github.com/mendersoftware/go-lib-micro/requestlog"
 	"github.com/mendersoftware/go-lib-micro/rest_utils"
 
@@ -37,10 +38,16 @@ const (
 	listReleasesV2
 )
 
-func redactReleaseName(r *rest.Request) {
+func redactReleaseFilter(r *rest.Request) {
 	q := r.URL.Query()
-	if q.Get(ParamName) != "" {
-		q.Set(ParamName, Redacted)
+	redacted := false
+	for _, param := range []string{ParamName, ParamSearch} {
+		if q.Get(param) != "" {
+			q.Set(param, Redacted)
+			redacted = true
+		}
+	}
+	if redacted {
 		r.URL.RawQuery = q.Encode()
 	}
 }
@@ -48,8 +55,12 @@ func redactReleaseName(r *rest.Request) {
 func (d *DeploymentsApiHandlers) GetReleases(w rest.ResponseWriter, r *rest.Request) {
 	l := requestlog.GetRequestLogger(r)
 
-	defer redactReleaseName(r)
+	defer redactReleaseFilter(r)
 	filter := getReleaseOrImageFilter(r, listReleasesV1, false)
+	if err := filter.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
 	releases, _, err := d.store.GetReleases(r.Context(), filter)
 	if err != nil {
 		d.view.RenderInternalError(w, r, err, l)
@@ -63,8 +74,12 @@ func (d *DeploymentsApiHandlers) listReleases(w rest.ResponseWriter, r *rest.Req
 	version listReleasesVersion) {
 	l := requestlog.GetRequestLogger(r)
 
-	defer redactReleaseName(r)
+	defer redactReleaseFilter(r)
 	filter := getReleaseOrImageFilter(r, version, true)
+	if err := filter.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
 	releases, totalCount, err := d.store.GetReleases(r.Context(), filter)
 	if err != nil {
 		d.view.RenderInternalError(w, r, err, l)
@@ -126,7 +141,9 @@ func (d *DeploymentsApiHandlers) PatchRelease(w rest.ResponseWriter, r *rest.Req
 		status := http.StatusInternalServerError
 		if errors.Is(err, app.ErrReleaseNotFound) {
 			status = http.StatusNotFound
-		} else if errors.Is(err, model.ErrTooManyUniqueTags) {
+		} else if errors.Is(err, model.ErrTooManyUniqueTags) ||
+			errors.Is(err, app.ErrReleaseStatusTransition) ||
+			errors.Is(err, app.ErrReleaseAliasConflict) {
 			status = http.StatusConflict
 		}
 		rest_utils.RestErrWithLog(w, r, l, err, status)
@@ -182,6 +199,54 @@ func (d *DeploymentsApiHandlers) PutReleaseTags(
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (d *DeploymentsApiHandlers) PatchReleaseTags(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	releaseName := r.PathParam(ParamName)
+	if releaseName == "" {
+		err := errors.New("path parameter 'release_name' cannot be empty")
+		rest_utils.RestErrWithLog(w, r, l, err, http.StatusNotFound)
+		return
+	}
+
+	var patch model.TagsPatch
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(&patch); err != nil {
+		rest_utils.RestErrWithLog(w, r, l,
+			errors.WithMessage(err,
+				"malformed JSON in request body"),
+			http.StatusBadRequest)
+		return
+	}
+	if err := patch.Validate(); err != nil {
+		rest_utils.RestErrWithLog(w, r, l,
+			errors.WithMessage(err,
+				"invalid request body"),
+			http.StatusBadRequest)
+		return
+	}
+
+	err := d.app.PatchReleaseTags(ctx, releaseName, patch)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, app.ErrReleaseNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, model.ErrTooManyTags) ||
+			errors.Is(err, model.ErrTooManyUniqueTags) ||
+			errors.Is(err, app.ErrReleaseTagsChanged) {
+			status = http.StatusConflict
+		}
+		rest_utils.RestErrWithLog(w, r, l, err, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (d *DeploymentsApiHandlers) GetReleaseTagKeys(
 	w rest.ResponseWriter,
 	r *rest.Request,
@@ -221,3 +286,203 @@ func (d *DeploymentsApiHandlers) GetReleasesUpdateTypes(
 		l.Errorf("failed to serialize JSON response: %s", err.Error())
 	}
 }
+
+// CompareReleases reports the differences between the artifacts of two
+// releases given by the from and to query parameters.
+func (d *DeploymentsApiHandlers) CompareReleases(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	query := r.URL.Query()
+	from, to := query.Get(ParamFrom), query.Get(ParamTo)
+	if from == "" || to == "" {
+		err := errors.New("query parameters 'from' and 'to' are required")
+		rest_utils.RestErrWithLog(w, r, l, err, http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := d.app.CompareReleases(ctx, from, to)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, app.ErrReleaseNotFound) {
+			status = http.StatusNotFound
+		}
+		rest_utils.RestErrWithLog(w, r, l, err, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = w.WriteJson(comparison)
+	if err != nil {
+		l.Errorf("failed to serialize JSON response: %s", err.Error())
+	}
+}
+
+// GetReleaseStatistics reports the number of devices running a release and
+// the outcome of its deployments.
+func (d *DeploymentsApiHandlers) GetReleaseStatistics(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	statistics, err := d.app.GetReleaseStatistics(ctx, r.PathParam(ParamName))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, app.ErrReleaseNotFound) {
+			status = http.StatusNotFound
+		}
+		rest_utils.RestErrWithLog(w, r, l, err, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = w.WriteJson(statistics)
+	if err != nil {
+		l.Errorf("failed to serialize JSON response: %s", err.Error())
+	}
+}
+
+func (d *DeploymentsApiHandlers) GetReleaseNotesHistory(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	page, perPage, err := rest_utils.ParsePagination(r)
+	if err == nil && perPage > MaximumPerPage {
+		err = errors.New(rest_utils.MsgQueryParmLimit(ParamPerPage))
+	}
+	if err != nil {
+		rest_utils.RestErrWithLog(w, r, l, err, http.StatusBadRequest)
+		return
+	}
+
+	skip := int((page - 1) * perPage)
+	revisions, totalCount, err := d.app.GetReleaseNotesHistory(ctx,
+		r.PathParam(ParamName), skip, int(perPage))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, app.ErrReleaseNotFound) {
+			status = http.StatusNotFound
+		}
+		rest_utils.RestErrWithLog(w, r, l, err, status)
+		return
+	}
+
+	w.Header().Add(hdrTotalCount, strconv.Itoa(totalCount))
+	hasNext := totalCount > skip+len(revisions)
+	for _, link := range rest_utils.MakePageLinkHdrs(r, page, perPage, hasNext) {
+		w.Header().Add("Link", link)
+	}
+	w.WriteHeader(http.StatusOK)
+	err = w.WriteJson(revisions)
+	if err != nil {
+		l.Errorf("failed to serialize JSON response: %s", err.Error())
+	}
+}
+
+// GetReleaseNotesHTML responds with the notes of the release rendered to
+// sanitized HTML.
+func (d *DeploymentsApiHandlers) GetReleaseNotesHTML(
+	w rest.ResponseWriter,
+	r *rest.Request,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	html, err := d.app.RenderReleaseNotes(ctx, r.PathParam(ParamName))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, app.ErrReleaseNotFound) {
+			status = http.StatusNotFound
+		}
+		rest_utils.RestErrWithLog(w, r, l, err, status)
+		return
+	}
+
+	rw := w.(http.ResponseWriter)
+	hdr := rw.Header()
+	hdr.Set("Content-Type", "text/html; charset=utf-8")
+	hdr.Set("Content-Length", strconv.Itoa(len(html)))
+	rw.WriteHeader(http.StatusOK)
+	if _, err = rw.Write(html); err != nil {
+		// The response is already sent.
+		l.Errorf("failed to write the release notes: %s", err.Error())
+	}
+}
+
+// deleteReleasesError is the response to a failed deletion of releases,
+// reporting the deletion of every artifact of the releases.
+type deleteReleasesError struct {
+	Error     string                          `json:"error"`
+	Artifacts []model.ReleaseArtifactDeletion `json:"artifacts,omitempty"`
+	RequestID string                          `json:"request_id,omitempty"`
+}
+
+// DeleteRelease deletes a release with all its artifacts.
+func (d *DeploymentsApiHandlers) DeleteRelease(w rest.ResponseWriter, r *rest.Request) {
+	d.deleteReleases(w, r, []string{r.PathParam(ParamName)})
+}
+
+// DeleteReleases deletes the releases given by the name query parameters
+// with all their artifacts.
+func (d *DeploymentsApiHandlers) DeleteReleases(w rest.ResponseWriter, r *rest.Request) {
+	d.deleteReleases(w, r, r.URL.Query()[ParamName])
+}
+
+func (d *DeploymentsApiHandlers) deleteReleases(
+	w rest.ResponseWriter,
+	r *rest.Request,
+	releaseNames []string,
+) {
+	ctx := r.Context()
+	l := log.FromContext(ctx)
+
+	if len(releaseNames) == 0 {
+		rest_utils.RestErrWithLog(w, r, l,
+			errors.New("at least one release name is required"),
+			http.StatusBadRequest)
+		return
+	}
+	for _, name := range releaseNames {
+		if name == "" {
+			rest_utils.RestErrWithLog(w, r, l,
+				errors.New("release name cannot be empty"),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	artifacts, err := d.app.DeleteReleases(ctx, releaseNames)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case errors.Is(err, app.ErrReleaseNotFound):
+		rest_utils.RestErrWithLog(w, r, l, err, http.StatusNotFound)
+		return
+	case errors.Is(err, app.ErrReleaseInActiveDeployment),
+		errors.Is(err, app.ErrReleaseInChannel):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, app.ErrReleaseDeleteFailed):
+		l.Error(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		rest_utils.RestErrWithLog(w, r, l, err, http.StatusInternalServerError)
+		return
+	}
+	err = w.WriteJson(deleteReleasesError{
+		Error:     err.Error(),
+		Artifacts: artifacts,
+		RequestID: requestid.FromContext(ctx),
+	})
+	if err != nil {
+		l.Error(err)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ant0ine/go-json-rest/rest"
 	"github.com/ant0ine/go-json-rest/rest/test"
@@ -231,6 +232,29 @@ func TestGetReleasesFilter(t *testing.T) {
 				Tags: []string{"foo", "bar"},
 			},
 		},
+		"ok, v2, status": {
+			queryString: "status=draft",
+			version:     listReleasesV2,
+			filter: &dmodel.ReleaseOrImageFilter{
+				Status: "draft",
+			},
+		},
+		"ok, v1, status ignored": {
+			queryString: "status=draft",
+			version:     listReleasesV1,
+			filter:      &dmodel.ReleaseOrImageFilter{},
+		},
+		"ok, provides, search": {
+			queryString: "provides=rootfs-image.version%3D3.*&provides=app&search=security",
+			version:     listReleasesV2,
+			paginated:   true,
+			filter: &dmodel.ReleaseOrImageFilter{
+				Provides:   []string{"rootfs-image.version=3.*", "app"},
+				SearchText: "security",
+				Page:       1,
+				PerPage:    DefaultPerPage,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -472,6 +496,34 @@ func TestListReleasesV2(t *testing.T) {
 	}
 }
 
+func TestListReleasesInvalidFilter(t *testing.T) {
+	testCases := map[string]string{
+		"provides without key":     "provides=%3D3.*",
+		"relevance without search": "sort=relevance:desc",
+	}
+
+	for name, query := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := &store_mocks.DataStore{}
+			defer store.AssertExpectations(t)
+
+			restView := new(view.RESTView)
+			app := app.NewDeployments(store, nil, 0, false)
+			c := NewDeploymentsApiHandlers(store, restView, app)
+
+			api := deployments_testing.SetUpTestApi(
+				"/api/management/v2/deployments/releases", rest.Get, c.ListReleasesV2)
+			req := test.MakeSimpleRequest("GET",
+				"http://1.2.3.4/api/management/v2/deployments/releases?"+query,
+				nil)
+			req.Header.Add(requestid.RequestIdHeader, "test")
+
+			recorded := test.RunRequest(t, api, req)
+			recorded.CodeIs(http.StatusBadRequest)
+		})
+	}
+}
+
 func TestPutReleaseTags(t *testing.T) {
 	t.Parallel()
 
@@ -707,6 +759,170 @@ func TestPutReleaseTags(t *testing.T) {
 	}
 }
 
+func TestPatchReleaseTags(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(name string, body []byte) *http.Request {
+		req, _ := http.NewRequest(
+			http.MethodPatch,
+			fmt.Sprintf("http://localhost:1234%s",
+				strings.ReplaceAll(ApiUrlManagementV2ReleaseTags, "#name", name)),
+			bytes.NewReader(body),
+		)
+		return req
+	}
+	patchBody := func(patch model.TagsPatch) []byte {
+		b, _ := json.Marshal(patch)
+		return b
+	}
+
+	type testCase struct {
+		Name string
+
+		App func(t *testing.T, self *testCase) *mapp.App
+		*http.Request
+
+		StatusCode int
+	}
+
+	testCases := []testCase{{
+		Name: "ok",
+
+		Request: newRequest("release-mc-release-face", patchBody(model.TagsPatch{
+			Add:    model.Tags{model.NewTag("env", "prod"), "beta"},
+			Remove: model.Tags{"old"},
+		})),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("PatchReleaseTags",
+				contextMatcher(),
+				"release-mc-release-face",
+				model.TagsPatch{
+					Add:    model.Tags{model.NewTag("env", "prod"), "beta"},
+					Remove: model.Tags{"old"},
+				}).
+				Return(nil)
+			return appie
+		},
+
+		StatusCode: http.StatusNoContent,
+	}, {
+		Name: "error/empty patch",
+
+		Request: newRequest("release-mc-release-face", []byte("{}")),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			return new(mapp.App)
+		},
+
+		StatusCode: http.StatusBadRequest,
+	}, {
+		Name: "error/malformed JSON",
+
+		Request: newRequest("release-mc-release-face", []byte("not json")),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			return new(mapp.App)
+		},
+
+		StatusCode: http.StatusBadRequest,
+	}, {
+		Name: "error/release not found",
+
+		Request: newRequest("release-mc-release-face", patchBody(model.TagsPatch{
+			Add: model.Tags{"one"},
+		})),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("PatchReleaseTags",
+				contextMatcher(),
+				"release-mc-release-face",
+				model.TagsPatch{Add: model.Tags{"one"}}).
+				Return(app.ErrReleaseNotFound)
+			return appie
+		},
+
+		StatusCode: http.StatusNotFound,
+	}, {
+		Name: "error/tags changed concurrently",
+
+		Request: newRequest("release-mc-release-face", patchBody(model.TagsPatch{
+			Add: model.Tags{"one"},
+		})),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("PatchReleaseTags",
+				contextMatcher(),
+				"release-mc-release-face",
+				model.TagsPatch{Add: model.Tags{"one"}}).
+				Return(app.ErrReleaseTagsChanged)
+			return appie
+		},
+
+		StatusCode: http.StatusConflict,
+	}, {
+		Name: "error/too many tags",
+
+		Request: newRequest("release-mc-release-face", patchBody(model.TagsPatch{
+			Add: model.Tags{"one"},
+		})),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("PatchReleaseTags",
+				contextMatcher(),
+				"release-mc-release-face",
+				model.TagsPatch{Add: model.Tags{"one"}}).
+				Return(model.ErrTooManyTags)
+			return appie
+		},
+
+		StatusCode: http.StatusConflict,
+	}, {
+		Name: "error/internal",
+
+		Request: newRequest("release-mc-release-face", patchBody(model.TagsPatch{
+			Add: model.Tags{"one"},
+		})),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("PatchReleaseTags",
+				contextMatcher(),
+				"release-mc-release-face",
+				model.TagsPatch{Add: model.Tags{"one"}}).
+				Return(errors.New("internal error"))
+			return appie
+		},
+
+		StatusCode: http.StatusInternalServerError,
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			appie := tc.App(t, &tc)
+			defer appie.AssertExpectations(t)
+
+			handlers := NewDeploymentsApiHandlers(nil, &view.RESTView{}, appie)
+			routes := ReleasesRoutes(handlers)
+			router, _ := rest.MakeRouter(routes...)
+			api := rest.NewApi()
+			api.SetApp(router)
+			handler := api.MakeHandler()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tc.Request)
+
+			rsp := w.Result()
+			assert.Equal(t, tc.StatusCode, rsp.StatusCode,
+				"unexpected status code from request")
+		})
+	}
+}
+
 func TestListReleaseTags(t *testing.T) {
 	t.Parallel()
 
@@ -964,6 +1180,107 @@ func TestPatchRelease(t *testing.T) {
 
 			StatusCode: http.StatusNoContent,
 		},
+		{
+			Name: "error/status transition",
+
+			Request: func() *http.Request {
+				data, _ := json.Marshal(model.ReleasePatch{
+					Status: model.ReleaseStatusDraft,
+				})
+				req, _ := http.NewRequest(
+					http.MethodPatch,
+					fmt.Sprintf("http://localhost:1234%s",
+						strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+							"#name", "release-mc-release-face"),
+					),
+					bytes.NewReader(data),
+				)
+				return req
+			}(),
+
+			App: func(t *testing.T, self *testCase) *mapp.App {
+				appie := new(mapp.App)
+				appie.On("UpdateRelease",
+					contextMatcher(),
+					"release-mc-release-face",
+					model.ReleasePatch{Status: model.ReleaseStatusDraft},
+				).Return(app.ErrReleaseStatusTransition)
+				return appie
+			},
+
+			StatusCode: http.StatusConflict,
+		},
+		{
+			Name: "error/alias in use",
+
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(
+					http.MethodPatch,
+					fmt.Sprintf("http://localhost:1234%s",
+						strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+							"#name", "release-mc-release-face"),
+					),
+					strings.NewReader(`{"alias": "Summer 2023"}`),
+				)
+				return req
+			}(),
+
+			App: func(t *testing.T, self *testCase) *mapp.App {
+				appie := new(mapp.App)
+				appie.On("UpdateRelease",
+					contextMatcher(),
+					"release-mc-release-face",
+					model.ReleasePatch{Alias: str2ptr("Summer 2023")},
+				).Return(app.ErrReleaseAliasConflict)
+				return appie
+			},
+
+			StatusCode: http.StatusConflict,
+		},
+		{
+			Name: "error/invalid alias",
+
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(
+					http.MethodPatch,
+					fmt.Sprintf("http://localhost:1234%s",
+						strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+							"#name", "release-mc-release-face"),
+					),
+					strings.NewReader(`{"alias": " Summer 2023"}`),
+				)
+				return req
+			}(),
+
+			App: func(t *testing.T, self *testCase) *mapp.App {
+				appie := new(mapp.App)
+				return appie
+			},
+
+			StatusCode: http.StatusBadRequest,
+		},
+		{
+			Name: "error/invalid status",
+
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(
+					http.MethodPatch,
+					fmt.Sprintf("http://localhost:1234%s",
+						strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+							"#name", "release-mc-release-face"),
+					),
+					strings.NewReader(`{"status": "archived"}`),
+				)
+				return req
+			}(),
+
+			App: func(t *testing.T, self *testCase) *mapp.App {
+				appie := new(mapp.App)
+				return appie
+			},
+
+			StatusCode: http.StatusBadRequest,
+		},
 		{
 			Name: "error/notes too long",
 
@@ -1038,3 +1355,533 @@ func TestPatchRelease(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteReleases(t *testing.T) {
+	t.Parallel()
+
+	artifacts := []model.ReleaseArtifactDeletion{{
+		ReleaseName:        "release-1",
+		ID:                 "artifact-1",
+		InActiveDeployment: true,
+	}}
+	testCases := map[string]struct {
+		url          string
+		releaseNames []string
+
+		artifacts []model.ReleaseArtifactDeletion
+		err       error
+
+		statusCode int
+		body       string
+	}{
+		"ok, release": {
+			url: strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+				"#name", "release-1"),
+			releaseNames: []string{"release-1"},
+			statusCode:   http.StatusNoContent,
+		},
+		"ok, bulk": {
+			url:          ApiUrlManagementV2Releases + "?name=release-1&name=release-2",
+			releaseNames: []string{"release-1", "release-2"},
+			statusCode:   http.StatusNoContent,
+		},
+		"error, no names": {
+			url:        ApiUrlManagementV2Releases,
+			statusCode: http.StatusBadRequest,
+		},
+		"error, empty name": {
+			url:        ApiUrlManagementV2Releases + "?name=release-1&name=",
+			statusCode: http.StatusBadRequest,
+		},
+		"error, not found": {
+			url: strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+				"#name", "release-1"),
+			releaseNames: []string{"release-1"},
+			err:          app.ErrReleaseNotFound,
+			statusCode:   http.StatusNotFound,
+		},
+		"error, in active deployment": {
+			url: strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+				"#name", "release-1"),
+			releaseNames: []string{"release-1"},
+			artifacts:    artifacts,
+			err:          app.ErrReleaseInActiveDeployment,
+			statusCode:   http.StatusConflict,
+			body: `{"error":"release used in active deployment","artifacts":[` +
+				`{"release_name":"release-1","id":"artifact-1",` +
+				`"device_types_compatible":null,"in_active_deployment":true,` +
+				`"deleted":false}],"request_id":"test"}`,
+		},
+		"error, in release channel": {
+			url: strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+				"#name", "release-1"),
+			releaseNames: []string{"release-1"},
+			artifacts: []model.ReleaseArtifactDeletion{{
+				ReleaseName: "release-1",
+				ID:          "artifact-1",
+				Channels:    []string{"stable"},
+			}},
+			err:        app.ErrReleaseInChannel,
+			statusCode: http.StatusConflict,
+			body: `{"error":"release promoted to a release channel","artifacts":[` +
+				`{"release_name":"release-1","id":"artifact-1",` +
+				`"device_types_compatible":null,"channels":["stable"],` +
+				`"deleted":false}],"request_id":"test"}`,
+		},
+		"error, delete failed": {
+			url: strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+				"#name", "release-1"),
+			releaseNames: []string{"release-1"},
+			artifacts: []model.ReleaseArtifactDeletion{{
+				ReleaseName: "release-1",
+				ID:          "artifact-1",
+				Error:       "storage error",
+			}},
+			err:        app.ErrReleaseDeleteFailed,
+			statusCode: http.StatusInternalServerError,
+			body: `{"error":"failed to delete some artifacts of the release",` +
+				`"artifacts":[{"release_name":"release-1","id":"artifact-1",` +
+				`"device_types_compatible":null,"deleted":false,` +
+				`"error":"storage error"}],"request_id":"test"}`,
+		},
+		"error, internal": {
+			url: strings.ReplaceAll(ApiUrlManagementV2ReleasesName,
+				"#name", "release-1"),
+			releaseNames: []string{"release-1"},
+			err:          errors.New("internal error"),
+			statusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for name := range testCases {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			appie := new(mapp.App)
+			defer appie.AssertExpectations(t)
+			if tc.releaseNames != nil {
+				appie.On("DeleteReleases", contextMatcher(), tc.releaseNames).
+					Return(tc.artifacts, tc.err)
+			}
+
+			handlers := NewDeploymentsApiHandlers(nil, &view.RESTView{}, appie)
+			routes := ReleasesRoutes(handlers)
+			router, _ := rest.MakeRouter(routes...)
+			api := rest.NewApi()
+			api.Use(&requestid.RequestIdMiddleware{})
+			api.SetApp(router)
+			req, _ := http.NewRequest(http.MethodDelete, "http://localhost:1234"+tc.url, nil)
+			req.Header.Set(requestid.RequestIdHeader, "test")
+			w := httptest.NewRecorder()
+			api.MakeHandler().ServeHTTP(w, req)
+
+			assert.Equal(t, tc.statusCode, w.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompareReleases(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(query string) *http.Request {
+		req, _ := http.NewRequest(
+			http.MethodGet,
+			"http://localhost:1234"+ApiUrlManagementV2ReleasesCompare+query,
+			nil,
+		)
+		return req
+	}
+	comparison := &model.ReleaseComparison{
+		From: "release-1",
+		To:   "release-2",
+		DeviceTypes: []model.DeviceTypeComparison{{
+			DeviceType:     "rpi4",
+			FromArtifactID: "1a",
+			ToArtifactID:   "2a",
+			Provides: model.MapDiff{
+				Changed: map[string]model.ValueChange{
+					"rootfs-image.version": {From: "1", To: "2"},
+				},
+			},
+			Files: []model.FileDiff{},
+		}},
+	}
+
+	type testCase struct {
+		Name string
+
+		App func(t *testing.T, self *testCase) *mapp.App
+		*http.Request
+
+		StatusCode int
+		Body       interface{}
+	}
+
+	testCases := []testCase{{
+		Name: "ok",
+
+		Request: newRequest("?from=release-1&to=release-2"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("CompareReleases",
+				contextMatcher(),
+				"release-1",
+				"release-2").
+				Return(comparison, nil)
+			return appie
+		},
+
+		StatusCode: http.StatusOK,
+		Body:       comparison,
+	}, {
+		Name: "error/missing to",
+
+		Request: newRequest("?from=release-1"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			return new(mapp.App)
+		},
+
+		StatusCode: http.StatusBadRequest,
+	}, {
+		Name: "error/release not found",
+
+		Request: newRequest("?from=release-1&to=release-2"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("CompareReleases",
+				contextMatcher(),
+				"release-1",
+				"release-2").
+				Return(nil, app.ErrReleaseNotFound)
+			return appie
+		},
+
+		StatusCode: http.StatusNotFound,
+	}, {
+		Name: "error/internal",
+
+		Request: newRequest("?from=release-1&to=release-2"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("CompareReleases",
+				contextMatcher(),
+				"release-1",
+				"release-2").
+				Return(nil, errors.New("internal error"))
+			return appie
+		},
+
+		StatusCode: http.StatusInternalServerError,
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			appie := tc.App(t, &tc)
+			defer appie.AssertExpectations(t)
+
+			handlers := NewDeploymentsApiHandlers(nil, &view.RESTView{}, appie)
+			routes := ReleasesRoutes(handlers)
+			router, _ := rest.MakeRouter(routes...)
+			api := rest.NewApi()
+			api.SetApp(router)
+			handler := api.MakeHandler()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tc.Request)
+
+			rsp := w.Result()
+			assert.Equal(t, tc.StatusCode, rsp.StatusCode,
+				"unexpected status code from request")
+			if tc.Body != nil {
+				b, _ := json.Marshal(tc.Body)
+				assert.JSONEq(t, string(b), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetReleaseStatistics(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(name string) *http.Request {
+		req, _ := http.NewRequest(
+			http.MethodGet,
+			"http://localhost:1234"+
+				strings.ReplaceAll(ApiUrlManagementV2ReleaseStatistics, "#name", name),
+			nil,
+		)
+		return req
+	}
+	statistics := model.NewReleaseStatistics("release-1", 2,
+		map[string]int{"rpi4": 3},
+		map[string]model.Stats{
+			"rpi4": {
+				model.DeviceDeploymentStatusSuccessStr: 3,
+				model.DeviceDeploymentStatusFailureStr: 1,
+			},
+		},
+	)
+
+	type testCase struct {
+		Name string
+
+		App func(t *testing.T, self *testCase) *mapp.App
+		*http.Request
+
+		StatusCode int
+		Body       interface{}
+	}
+
+	testCases := []testCase{{
+		Name: "ok",
+
+		Request: newRequest("release-1"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("GetReleaseStatistics", contextMatcher(), "release-1").
+				Return(statistics, nil)
+			return appie
+		},
+
+		StatusCode: http.StatusOK,
+		Body:       statistics,
+	}, {
+		Name: "error/release not found",
+
+		Request: newRequest("release-1"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("GetReleaseStatistics", contextMatcher(), "release-1").
+				Return(nil, app.ErrReleaseNotFound)
+			return appie
+		},
+
+		StatusCode: http.StatusNotFound,
+	}, {
+		Name: "error/internal",
+
+		Request: newRequest("release-1"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("GetReleaseStatistics", contextMatcher(), "release-1").
+				Return(nil, errors.New("internal error"))
+			return appie
+		},
+
+		StatusCode: http.StatusInternalServerError,
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			appie := tc.App(t, &tc)
+			defer appie.AssertExpectations(t)
+
+			handlers := NewDeploymentsApiHandlers(nil, &view.RESTView{}, appie)
+			routes := ReleasesRoutes(handlers)
+			router, _ := rest.MakeRouter(routes...)
+			api := rest.NewApi()
+			api.SetApp(router)
+			handler := api.MakeHandler()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tc.Request)
+
+			rsp := w.Result()
+			assert.Equal(t, tc.StatusCode, rsp.StatusCode,
+				"unexpected status code from request")
+			if tc.Body != nil {
+				b, _ := json.Marshal(tc.Body)
+				assert.JSONEq(t, string(b), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetReleaseNotesHistory(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(name, query string) *http.Request {
+		req, _ := http.NewRequest(
+			http.MethodGet,
+			"http://localhost:1234"+
+				strings.ReplaceAll(ApiUrlManagementV2ReleaseNotesHistory, "#name", name)+
+				query,
+			nil,
+		)
+		return req
+	}
+	revisions := []model.ReleaseNotesRevision{{
+		ReleaseName: "release-1",
+		Notes:       "# Release 1\n\nSecurity fixes",
+		UserID:      "user",
+		Modified:    time.Date(2023, 9, 22, 22, 0, 0, 0, time.UTC),
+	}}
+
+	type testCase struct {
+		Name string
+
+		App func(t *testing.T, self *testCase) *mapp.App
+		*http.Request
+
+		StatusCode int
+		TotalCount string
+		Body       interface{}
+	}
+
+	testCases := []testCase{{
+		Name: "ok",
+
+		Request: newRequest("release-1", "?page=3&per_page=10"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("GetReleaseNotesHistory", contextMatcher(), "release-1", 20, 10).
+				Return(revisions, 21, nil)
+			return appie
+		},
+
+		StatusCode: http.StatusOK,
+		TotalCount: "21",
+		Body:       revisions,
+	}, {
+		Name: "error/invalid pagination",
+
+		Request: newRequest("release-1", "?per_page=1000"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			return new(mapp.App)
+		},
+
+		StatusCode: http.StatusBadRequest,
+	}, {
+		Name: "error/release not found",
+
+		Request: newRequest("release-1", ""),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("GetReleaseNotesHistory", contextMatcher(), "release-1", 0, 20).
+				Return(nil, 0, app.ErrReleaseNotFound)
+			return appie
+		},
+
+		StatusCode: http.StatusNotFound,
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			appie := tc.App(t, &tc)
+			defer appie.AssertExpectations(t)
+
+			handlers := NewDeploymentsApiHandlers(nil, &view.RESTView{}, appie)
+			routes := ReleasesRoutes(handlers)
+			router, _ := rest.MakeRouter(routes...)
+			api := rest.NewApi()
+			api.SetApp(router)
+			handler := api.MakeHandler()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tc.Request)
+
+			rsp := w.Result()
+			assert.Equal(t, tc.StatusCode, rsp.StatusCode,
+				"unexpected status code from request")
+			if tc.TotalCount != "" {
+				assert.Equal(t, tc.TotalCount, rsp.Header.Get(hdrTotalCount))
+			}
+			if tc.Body != nil {
+				b, _ := json.Marshal(tc.Body)
+				assert.JSONEq(t, string(b), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetReleaseNotesHTML(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(name string) *http.Request {
+		req, _ := http.NewRequest(
+			http.MethodGet,
+			"http://localhost:1234"+
+				strings.ReplaceAll(ApiUrlManagementV2ReleaseNotes, "#name", name),
+			nil,
+		)
+		return req
+	}
+
+	type testCase struct {
+		Name string
+
+		App func(t *testing.T, self *testCase) *mapp.App
+		*http.Request
+
+		StatusCode int
+		Body       string
+	}
+
+	testCases := []testCase{{
+		Name: "ok",
+
+		Request: newRequest("release-1"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("RenderReleaseNotes", contextMatcher(), "release-1").
+				Return([]byte("<h1>Release 1</h1>\n"), nil)
+			return appie
+		},
+
+		StatusCode: http.StatusOK,
+		Body:       "<h1>Release 1</h1>\n",
+	}, {
+		Name: "error/release not found",
+
+		Request: newRequest("release-1"),
+
+		App: func(t *testing.T, self *testCase) *mapp.App {
+			appie := new(mapp.App)
+			appie.On("RenderReleaseNotes", contextMatcher(), "release-1").
+				Return(nil, app.ErrReleaseNotFound)
+			return appie
+		},
+
+		StatusCode: http.StatusNotFound,
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			appie := tc.App(t, &tc)
+			defer appie.AssertExpectations(t)
+
+			handlers := NewDeploymentsApiHandlers(nil, &view.RESTView{}, appie)
+			routes := ReleasesRoutes(handlers)
+			router, _ := rest.MakeRouter(routes...)
+			api := rest.NewApi()
+			api.SetApp(router)
+			handler := api.MakeHandler()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tc.Request)
+
+			rsp := w.Result()
+			assert.Equal(t, tc.StatusCode, rsp.StatusCode,
+				"unexpected status code from request")
+			if tc.Body != "" {
+				assert.Equal(t, "text/html; charset=utf-8", rsp.Header.Get("Content-Type"))
+				assert.Equal(t, tc.Body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ant0ine/go-json-rest/rest"
+
+	"github.com/mendersoftware/go-lib-micro/requestlog"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func (d *DeploymentsApiHandlers) GetRetentionPolicy(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	policy, err := d.app.GetRetentionPolicy(r.Context())
+	if err != nil {
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+	d.view.RenderSuccessGet(w, policy)
+}
+
+func (d *DeploymentsApiHandlers) SetRetentionPolicy(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	var policy model.RetentionPolicy
+	if err := r.DecodeJsonPayload(&policy); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		d.view.RenderError(w, r, err, http.StatusBadRequest, l)
+		return
+	}
+
+	if err := d.app.SetRetentionPolicy(r.Context(), &policy); err != nil {
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+	d.view.RenderSuccessPut(w)
+}
+
+// ListExpiredArtifacts lists the artifacts the retention policy would
+// delete now, without deleting them.
+func (d *DeploymentsApiHandlers) ListExpiredArtifacts(w rest.ResponseWriter, r *rest.Request) {
+	l := requestlog.GetRequestLogger(r)
+
+	expired, err := d.app.FindExpiredArtifacts(r.Context(), time.Now())
+	if err != nil {
+		d.view.RenderInternalError(w, r, err, l)
+		return
+	}
+	if expired == nil {
+		expired = []model.ExpiredArtifact{}
+	}
+	d.view.RenderSuccessGet(w, expired)
+}
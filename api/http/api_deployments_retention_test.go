@@ -0,0 +1,179 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	"github.com/ant0ine/go-json-rest/rest/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mapp "github.com/mendersoftware/deployments/app/mocks"
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/utils/restutil/view"
+)
+
+func TestSetRetentionPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		body string
+
+		callApp bool
+		err     error
+
+		httpStatus int
+	}{
+		"ok": {
+			body:       `{"keep_last_per_device_type": 3, "keep_tagged_releases": true}`,
+			callApp:    true,
+			httpStatus: http.StatusNoContent,
+		},
+		"error, negative count": {
+			body:       `{"keep_last_per_device_type": -1}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, malformed body": {
+			body:       `{"keep_last_per_device_type": `,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, internal": {
+			body:       `{"keep_last_per_device_type": 3, "keep_tagged_releases": true}`,
+			callApp:    true,
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.callApp {
+				app.On("SetRetentionPolicy",
+					contextMatcher(),
+					&model.RetentionPolicy{
+						KeepLastPerDeviceType: 3,
+						KeepTaggedReleases:    true,
+					},
+				).Return(tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsRetention,
+				rest.Put,
+				d.SetRetentionPolicy,
+			)
+			req, _ := http.NewRequest(
+				http.MethodPut,
+				"http://localhost"+ApiUrlManagementArtifactsRetention,
+				strings.NewReader(tc.body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
+
+func TestGetRetentionPolicy(t *testing.T) {
+	app := &mapp.App{}
+	defer app.AssertExpectations(t)
+	app.On("GetRetentionPolicy", contextMatcher()).
+		Return(&model.RetentionPolicy{DeleteUnusedAfterDays: 90}, nil)
+
+	restView := new(view.RESTView)
+	d := NewDeploymentsApiHandlers(nil, restView, app)
+	api := setUpRestTest(
+		ApiUrlManagementArtifactsRetention,
+		rest.Get,
+		d.GetRetentionPolicy,
+	)
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		"http://localhost"+ApiUrlManagementArtifactsRetention,
+		nil,
+	)
+
+	recorded := test.RunRequest(t, api.MakeHandler(), req)
+	recorded.CodeIs(http.StatusOK)
+	assert.JSONEq(t, `{"delete_unused_after_days": 90}`, recorded.Recorder.Body.String())
+}
+
+func TestListExpiredArtifacts(t *testing.T) {
+	testCases := map[string]struct {
+		expired []model.ExpiredArtifact
+		err     error
+
+		httpStatus int
+		body       string
+	}{
+		"ok": {
+			expired: []model.ExpiredArtifact{{
+				ID:          "a1",
+				Name:        "release-1",
+				DeviceTypes: []string{"dt1"},
+				Size:        10,
+				Reasons:     []string{model.RetentionReasonUnused},
+			}},
+			httpStatus: http.StatusOK,
+			body: `[{"id": "a1", "name": "release-1", "device_types_compatible": ["dt1"],
+				"size": 10, "last_used": "0001-01-01T00:00:00Z",
+				"reasons": ["delete_unused_after_days"], "deleted": false}]`,
+		},
+		"ok, none": {
+			httpStatus: http.StatusOK,
+			body:       `[]`,
+		},
+		"error, internal": {
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			app.On("FindExpiredArtifacts", contextMatcher(), mock.AnythingOfType("time.Time")).
+				Return(tc.expired, tc.err)
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementArtifactsRetentionExpired,
+				rest.Get,
+				d.ListExpiredArtifacts,
+			)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"http://localhost"+ApiUrlManagementArtifactsRetentionExpired,
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, recorded.Recorder.Body.String())
+			}
+		})
+	}
+}
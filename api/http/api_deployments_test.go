@@ -514,6 +514,32 @@ func TestPostDeployment(t *testing.T) {
 			Err:   app.ErrNoDevices.Error(),
 			ReqId: "test",
 		},
+	}, {
+		Name: "error: app error: release not deployable",
+		InputBody: &model.DeploymentConstructor{
+			Name:         "foo",
+			ArtifactName: "bar",
+			AllDevices:   true,
+		},
+		AppError:     app.ErrReleaseNotDeployable,
+		ResponseCode: http.StatusUnprocessableEntity,
+		ResponseBody: rest_utils.ApiError{
+			Err:   app.ErrReleaseNotDeployable.Error(),
+			ReqId: "test",
+		},
+	}, {
+		Name: "error: app error: channel not found",
+		InputBody: &model.DeploymentConstructor{
+			Name:       "foo",
+			Channel:    "stable",
+			AllDevices: true,
+		},
+		AppError:     app.ErrReleaseChannelNotFound,
+		ResponseCode: http.StatusUnprocessableEntity,
+		ResponseBody: rest_utils.ApiError{
+			Err:   app.ErrReleaseChannelNotFound.Error(),
+			ReqId: "test",
+		},
 	}, {
 		Name: "error: conflict",
 		InputBody: &model.DeploymentConstructor{
@@ -2032,6 +2058,101 @@ func TestDeleteDeviceDeploymentsHistory(t *testing.T) {
 	}
 }
 
+func TestGetDeploymentHistoryForDevice(t *testing.T) {
+	t.Parallel()
+
+	deploymentID := uuid.NewString()
+	history := &model.DeviceDeploymentHistory{
+		DeploymentID: deploymentID,
+		DeviceID:     "1",
+		Status:       model.DeviceDeploymentStatusDownloading,
+		Phases: []model.DeviceDeploymentPhase{{
+			DeviceDeploymentStatusTransition: model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusDownloading,
+				Timestamp: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			Duration: 10,
+		}},
+		Durations: map[string]float64{
+			model.DeviceDeploymentStatusDownloadingStr: 10,
+		},
+		Duration: 10,
+	}
+
+	testCases := map[string]struct {
+		deploymentID string
+		deviceID     string
+
+		appHistory *model.DeviceDeploymentHistory
+		appErr     error
+
+		responseCode int
+		responseBody interface{}
+	}{
+		"ok": {
+			deploymentID: deploymentID,
+			deviceID:     "1",
+			appHistory:   history,
+			responseCode: http.StatusOK,
+			responseBody: history,
+		},
+		"ko, deployment ID not UUID": {
+			deploymentID: "foo",
+			deviceID:     "1",
+			responseCode: http.StatusBadRequest,
+		},
+		"ko, not found": {
+			deploymentID: deploymentID,
+			deviceID:     "1",
+			responseCode: http.StatusNotFound,
+		},
+		"ko, internal error": {
+			deploymentID: deploymentID,
+			deviceID:     "1",
+			appErr:       errors.New("internal error"),
+			responseCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.responseCode != http.StatusBadRequest {
+				app.On("GetDeviceDeploymentHistory",
+					mock.MatchedBy(func(ctx context.Context) bool {
+						return true
+					}),
+					tc.deploymentID,
+					tc.deviceID,
+				).Return(tc.appHistory, tc.appErr)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlManagementDeploymentsDeviceStatusHistory,
+				rest.Get,
+				d.GetDeploymentHistoryForDevice,
+			)
+			url := "http://localhost" + ApiUrlManagementDeploymentsDeviceStatusHistory
+			url = strings.Replace(url, "#id", tc.deploymentID, 1)
+			url = strings.Replace(url, "#devid", tc.deviceID, 1)
+			req := test.MakeSimpleRequest("GET", url, nil)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.responseCode)
+			if tc.responseBody != nil {
+				body, _ := json.Marshal(tc.responseBody)
+				assert.JSONEq(t, string(body), recorded.Recorder.Body.String())
+			}
+		})
+	}
+}
+
 func TestGetDeploymentsStats(t *testing.T) {
 	t.Parallel()
 
@@ -2579,3 +2700,295 @@ func TestListDeviceDeploymentsByIDsInternal(t *testing.T) {
 		})
 	}
 }
+
+func TestPutDeploymentStatusForDevice(t *testing.T) {
+	deviceID := uuid.NewSHA1(uuid.NameSpaceOID, []byte("device")).String()
+	deploymentID := uuid.NewSHA1(uuid.NameSpaceURL, []byte("deployment")).String()
+
+	testCases := map[string]struct {
+		err        error
+		httpStatus int
+	}{
+		"ok": {
+			httpStatus: http.StatusNoContent,
+		},
+		"error, invalid status transition": {
+			err:        app.ErrInvalidStatusTransition,
+			httpStatus: http.StatusConflict,
+		},
+		"error, deployment aborted": {
+			err:        app.ErrDeploymentAborted,
+			httpStatus: http.StatusConflict,
+		},
+		"error, not found": {
+			err:        app.ErrStorageNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			app.On("UpdateDeviceDeploymentStatus",
+				contextMatcher(),
+				deploymentID,
+				deviceID,
+				model.DeviceDeploymentState{
+					Status: model.DeviceDeploymentStatusInstalling,
+				},
+			).Return(tc.err)
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlDevicesDeploymentStatus,
+				rest.Put,
+				d.PutDeploymentStatusForDevice,
+			)
+			url := strings.Replace(ApiUrlDevicesDeploymentStatus, "#id", deploymentID, 1)
+			req, _ := http.NewRequestWithContext(
+				identity.WithContext(context.Background(), &identity.Identity{
+					Subject:  deviceID,
+					IsDevice: true,
+				}),
+				http.MethodPut,
+				"http://localhost"+url,
+				strings.NewReader(`{"status": "installing"}`),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
+
+func TestGetDeploymentLinkForDevice(t *testing.T) {
+	deviceID := uuid.NewSHA1(uuid.NameSpaceOID, []byte("device")).String()
+	deploymentID := uuid.NewSHA1(uuid.NameSpaceURL, []byte("deployment")).String()
+
+	testCases := map[string]struct {
+		link       *model.Link
+		err        error
+		httpStatus int
+	}{
+		"ok": {
+			link: &model.Link{
+				Uri:    "https://example.com/artifact",
+				Expire: time.Now().Add(time.Hour).UTC().Round(time.Second),
+			},
+			httpStatus: http.StatusOK,
+		},
+		"error, deployment finished": {
+			err:        app.ErrDeviceDeploymentFinished,
+			httpStatus: http.StatusConflict,
+		},
+		"error, release revoked": {
+			err:        app.ErrDeploymentAborted,
+			httpStatus: http.StatusConflict,
+		},
+		"error, not found": {
+			err:        app.ErrStorageNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, no artifact": {
+			err:        app.ErrNoArtifact,
+			httpStatus: http.StatusNotFound,
+		},
+		"error, internal": {
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			app.On("GetDeviceDeploymentDownloadLink",
+				contextMatcher(),
+				deploymentID,
+				deviceID,
+			).Return(tc.link, tc.err)
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlDevicesDeploymentsLink,
+				rest.Get,
+				d.GetDeploymentLinkForDevice,
+			)
+			url := strings.Replace(ApiUrlDevicesDeploymentsLink, "#id", deploymentID, 1)
+			req, _ := http.NewRequestWithContext(
+				identity.WithContext(context.Background(), &identity.Identity{
+					Subject:  deviceID,
+					IsDevice: true,
+				}),
+				http.MethodGet,
+				"http://localhost"+url,
+				nil,
+			)
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+
+			if tc.httpStatus == http.StatusOK {
+				link := &model.Link{}
+				err := json.Unmarshal(recorded.Recorder.Body.Bytes(), link)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.link.Uri, link.Uri)
+				assert.True(t, tc.link.Expire.Equal(link.Expire))
+			}
+		})
+	}
+}
+
+func TestGetTenantSettings(t *testing.T) {
+	testCases := map[string]struct {
+		tenantID   string
+		settings   *model.TenantSettings
+		err        error
+		httpStatus int
+	}{
+		"ok": {
+			tenantID: "tenant1",
+			settings: &model.TenantSettings{
+				StatusTransitions: model.StatusTransitionsModeEnforce,
+			},
+			httpStatus: http.StatusOK,
+		},
+		"error": {
+			tenantID:   "tenant1",
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			app.On("GetTenantSettings",
+				mock.MatchedBy(func(ctx context.Context) bool {
+					id := identity.FromContext(ctx)
+					return id != nil && id.Tenant == tc.tenantID
+				}),
+			).Return(tc.settings, tc.err)
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlInternalTenantSettings,
+				rest.Get,
+				d.GetTenantSettingsHandler,
+			)
+			url := strings.Replace(ApiUrlInternalTenantSettings, "#tenant", tc.tenantID, -1)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"http://localhost"+url,
+				nil,
+			)
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+
+			if tc.httpStatus == http.StatusOK {
+				settings := &model.TenantSettings{}
+				err := json.Unmarshal(recorded.Recorder.Body.Bytes(), settings)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.settings, settings)
+			}
+		})
+	}
+}
+
+func TestPutTenantSettings(t *testing.T) {
+	testCases := map[string]struct {
+		tenantID   string
+		body       string
+		settings   *model.TenantSettings
+		err        error
+		httpStatus int
+	}{
+		"ok": {
+			tenantID: "tenant1",
+			body:     `{"status_transitions": "enforce"}`,
+			settings: &model.TenantSettings{
+				StatusTransitions: model.StatusTransitionsModeEnforce,
+			},
+			httpStatus: http.StatusNoContent,
+		},
+		"ok, signature policy": {
+			tenantID: "tenant1",
+			body:     `{"status_transitions": "warn", "require_signed_artifacts": true}`,
+			settings: &model.TenantSettings{
+				StatusTransitions:      model.StatusTransitionsModeWarn,
+				RequireSignedArtifacts: true,
+			},
+			httpStatus: http.StatusNoContent,
+		},
+		"ok, reset to default": {
+			tenantID:   "tenant1",
+			body:       `{}`,
+			settings:   &model.TenantSettings{},
+			httpStatus: http.StatusNoContent,
+		},
+		"error, invalid mode": {
+			tenantID:   "tenant1",
+			body:       `{"status_transitions": "ignore"}`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error, malformed body": {
+			tenantID:   "tenant1",
+			body:       `{`,
+			httpStatus: http.StatusBadRequest,
+		},
+		"error app err": {
+			tenantID: "tenant1",
+			body:     `{"status_transitions": "warn"}`,
+			settings: &model.TenantSettings{
+				StatusTransitions: model.StatusTransitionsModeWarn,
+			},
+			err:        errors.New("generic error"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &mapp.App{}
+			defer app.AssertExpectations(t)
+			if tc.settings != nil {
+				app.On("SetTenantSettings",
+					mock.MatchedBy(func(ctx context.Context) bool {
+						id := identity.FromContext(ctx)
+						return id != nil && id.Tenant == tc.tenantID
+					}),
+					tc.settings,
+				).Return(tc.err)
+			}
+
+			restView := new(view.RESTView)
+			d := NewDeploymentsApiHandlers(nil, restView, app)
+			api := setUpRestTest(
+				ApiUrlInternalTenantSettings,
+				rest.Put,
+				d.PutTenantSettingsHandler,
+			)
+			url := strings.Replace(ApiUrlInternalTenantSettings, "#tenant", tc.tenantID, -1)
+			req, _ := http.NewRequest(
+				http.MethodPut,
+				"http://localhost"+url,
+				strings.NewReader(tc.body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			recorded := test.RunRequest(t, api.MakeHandler(), req)
+			recorded.CodeIs(tc.httpStatus)
+		})
+	}
+}
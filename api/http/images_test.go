@@ -625,6 +625,45 @@ func TestPostArtifactsGenerate(t *testing.T) {
 			appGenerateImageResponse: "",
 			appGenerateImageError:    ErrModelArtifactFileTooLarge,
 		},
+		{
+			requestBodyObject: []h.Part{
+				{
+					FieldName:  "name",
+					FieldValue: "name",
+				},
+				{
+					FieldName:  "description",
+					FieldValue: "description",
+				},
+				{
+					FieldName:  "size",
+					FieldValue: strconv.Itoa(len(imageBody)),
+				},
+				{
+					FieldName:  "device_types_compatible",
+					FieldValue: "Beagle Bone",
+				},
+				{
+					FieldName:  "type",
+					FieldValue: "rootfs_image",
+				},
+				{
+					FieldName:  "args",
+					FieldValue: "args",
+				},
+				{
+					FieldName:   "file",
+					ContentType: "application/octet-stream",
+					ImageData:   imageBody,
+				},
+			},
+			requestContentType:       "multipart/form-data",
+			responseCode:             http.StatusBadRequest,
+			responseBody:             app.ErrArtifactGeneratorType.Error(),
+			appGenerateImage:         true,
+			appGenerateImageResponse: "",
+			appGenerateImageError:    app.ErrArtifactGeneratorType,
+		},
 		{
 			requestBodyObject: []h.Part{
 				{
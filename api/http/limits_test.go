@@ -51,10 +51,11 @@ func TestGetLimits(t *testing.T) {
 		{
 			name: "storage",
 			code: http.StatusOK,
-			body: `{"limit":200,"usage":0}`,
+			body: `{"limit":200,"usage":150}`,
 			limit: &model.Limit{
 				Name:  "storage",
 				Value: 200,
+				Usage: 150,
 			},
 		},
 		{
@@ -41,6 +41,22 @@ const (
 		"/#id/complete"
 	ApiUrlManagementArtifactsId         = ApiUrlManagement + "/artifacts/#id"
 	ApiUrlManagementArtifactsIdDownload = ApiUrlManagement + "/artifacts/#id/download"
+	ApiUrlManagementArtifactsIdMetadata = ApiUrlManagement + "/artifacts/#id/metadata"
+	ApiUrlManagementArtifactsIdContents = ApiUrlManagement + "/artifacts/#id/contents"
+	ApiUrlManagementArtifactsIdFile     = ApiUrlManagement + "/artifacts/#id/files/#name"
+
+	ApiUrlManagementArtifactsKeys   = ApiUrlManagement + "/artifacts/keys"
+	ApiUrlManagementArtifactsKeysId = ApiUrlManagement + "/artifacts/keys/#id"
+
+	ApiUrlManagementArtifactsImport   = ApiUrlManagement + "/artifacts/import"
+	ApiUrlManagementArtifactsImportId = ApiUrlManagement + "/artifacts/import/#id"
+
+	ApiUrlManagementArtifactsUploads         = ApiUrlManagement + "/artifacts/uploads"
+	ApiUrlManagementArtifactsUploadsId       = ApiUrlManagement + "/artifacts/uploads/#id"
+	ApiUrlManagementArtifactsUploadsComplete = ApiUrlManagement + "/artifacts/uploads/#id/complete"
+
+	ApiUrlManagementArtifactsRetention        = ApiUrlManagement + "/artifacts/retention"
+	ApiUrlManagementArtifactsRetentionExpired = ApiUrlManagement + "/artifacts/retention/expired"
 
 	ApiUrlManagementDeployments                   = ApiUrlManagement + "/deployments"
 	ApiUrlManagementMultipleDeploymentsStatistics = ApiUrlManagement +
@@ -53,6 +69,8 @@ const (
 	ApiUrlManagementDeploymentsDevicesList = ApiUrlManagement + "/deployments/#id/devices/list"
 	ApiUrlManagementDeploymentsLog         = ApiUrlManagement +
 		"/deployments/#id/devices/#devid/log"
+	ApiUrlManagementDeploymentsDeviceStatusHistory = ApiUrlManagement +
+		"/deployments/#id/devices/#devid/history"
 	ApiUrlManagementDeploymentsDeviceId      = ApiUrlManagement + "/deployments/devices/#id"
 	ApiUrlManagementDeploymentsDeviceHistory = ApiUrlManagement + "/deployments/devices/#id/history"
 	ApiUrlManagementDeploymentsDeviceList    = ApiUrlManagement + "/deployments/#id/device_list"
@@ -66,15 +84,27 @@ const (
 	ApiUrlManagementV2Releases              = ApiUrlManagementV2 + "/deployments/releases"
 	ApiUrlManagementV2ReleasesName          = ApiUrlManagementV2Releases + "/#name"
 	ApiUrlManagementV2ReleaseTags           = ApiUrlManagementV2Releases + "/#name/tags"
+	ApiUrlManagementV2ReleasesCompare       = ApiUrlManagementV2Releases + "/compare"
+	ApiUrlManagementV2ReleaseStatistics     = ApiUrlManagementV2Releases + "/#name/statistics"
+	ApiUrlManagementV2ReleaseNotes          = ApiUrlManagementV2Releases + "/#name/notes"
+	ApiUrlManagementV2ReleaseNotesHistory   = ApiUrlManagementV2ReleaseNotes + "/history"
 	ApiUrlManagementV2ReleaseAllTags        = ApiUrlManagementV2 + "/releases/all/tags"
 	ApiUrlManagementV2ReleaseAllUpdateTypes = ApiUrlManagementV2 + "/releases/all/types"
+	ApiUrlManagementV2ReleaseChannels       = ApiUrlManagementV2 + "/deployments/channels"
+	ApiUrlManagementV2ReleaseChannelsName   = ApiUrlManagementV2ReleaseChannels + "/#name"
+	ApiUrlManagementV2ReleaseChannelPromote = ApiUrlManagementV2ReleaseChannels +
+		"/#name/promote"
 
 	ApiUrlDevicesDeploymentsNext  = ApiUrlDevices + "/device/deployments/next"
 	ApiUrlDevicesDeploymentStatus = ApiUrlDevices + "/device/deployments/#id/status"
 	ApiUrlDevicesDeploymentsLog   = ApiUrlDevices + "/device/deployments/#id/log"
+	ApiUrlDevicesDeploymentsLink  = ApiUrlDevices + "/device/deployments/#id/link"
 	ApiUrlDevicesDownloadConfig   = ApiUrlDevices +
 		"/download/configuration/#deployment_id/#device_type/#device_id"
 
+	ApiUrlDevicesGatewayDeploymentsNext   = ApiUrlDevices + "/device/gateway/deployments/next"
+	ApiUrlDevicesGatewayDeploymentsStatus = ApiUrlDevices + "/device/gateway/deployments/status"
+
 	ApiUrlInternalAlive                    = ApiUrlInternal + "/alive"
 	ApiUrlInternalHealth                   = ApiUrlInternal + "/health"
 	ApiUrlInternalTenants                  = ApiUrlInternal + "/tenants"
@@ -83,8 +113,11 @@ const (
 	ApiUrlInternalTenantDeploymentsDevice  = ApiUrlInternal +
 		"/tenants/#tenant/deployments/devices/#id"
 	ApiUrlInternalTenantArtifacts       = ApiUrlInternal + "/tenants/#tenant/artifacts"
+	ApiUrlInternalTenantArtifactCopy    = ApiUrlInternal + "/tenants/#tenant/artifacts/#id/copy"
 	ApiUrlInternalTenantStorageSettings = ApiUrlInternal +
 		"/tenants/#tenant/storage/settings"
+	ApiUrlInternalTenantSettings = ApiUrlInternal +
+		"/tenants/#tenant/settings"
 	ApiUrlInternalDeviceConfigurationDeployments = ApiUrlInternal +
 		"/tenants/#tenant/configuration/deployments/#deployment_id/devices/#device_id"
 	ApiUrlInternalDeviceDeploymentLastStatusDeployments = ApiUrlInternal +
@@ -136,8 +169,28 @@ func NewImagesResourceRoutes(controller *DeploymentsApiHandlers, cfg *Config) []
 		rest.Get(ApiUrlManagementArtifactsId, controller.GetImage),
 		rest.Delete(ApiUrlManagementArtifactsId, controller.DeleteImage),
 		rest.Put(ApiUrlManagementArtifactsId, controller.EditImage),
+		rest.Patch(ApiUrlManagementArtifactsIdMetadata, controller.EditArtifactMeta),
 
 		rest.Get(ApiUrlManagementArtifactsIdDownload, controller.DownloadLink),
+		rest.Get(ApiUrlManagementArtifactsIdContents, controller.GetArtifactContents),
+		rest.Get(ApiUrlManagementArtifactsIdFile, controller.GetArtifactFile),
+
+		rest.Post(ApiUrlManagementArtifactsKeys, controller.AddPublicKey),
+		rest.Get(ApiUrlManagementArtifactsKeys, controller.ListPublicKeys),
+		rest.Get(ApiUrlManagementArtifactsKeysId, controller.GetPublicKey),
+		rest.Delete(ApiUrlManagementArtifactsKeysId, controller.DeletePublicKey),
+
+		rest.Post(ApiUrlManagementArtifactsImport, controller.ImportArtifact),
+		rest.Get(ApiUrlManagementArtifactsImportId, controller.GetImportJob),
+
+		rest.Post(ApiUrlManagementArtifactsUploads, controller.CreateChunkedUpload),
+		rest.Head(ApiUrlManagementArtifactsUploadsId, controller.GetChunkedUploadOffset),
+		rest.Patch(ApiUrlManagementArtifactsUploadsId, controller.UploadChunk),
+		rest.Post(ApiUrlManagementArtifactsUploadsComplete, controller.CompleteChunkedUpload),
+
+		rest.Get(ApiUrlManagementArtifactsRetention, controller.GetRetentionPolicy),
+		rest.Put(ApiUrlManagementArtifactsRetention, controller.SetRetentionPolicy),
+		rest.Get(ApiUrlManagementArtifactsRetentionExpired, controller.ListExpiredArtifacts),
 	}
 	if cfg.EnableDirectUpload {
 		log.NewEmpty().Infof(
@@ -184,6 +237,8 @@ func NewDeploymentsResourceRoutes(controller *DeploymentsApiHandlers) []*rest.Ro
 			controller.GetDevicesListForDeployment),
 		rest.Get(ApiUrlManagementDeploymentsLog,
 			controller.GetDeploymentLogForDevice),
+		rest.Get(ApiUrlManagementDeploymentsDeviceStatusHistory,
+			controller.GetDeploymentHistoryForDevice),
 		rest.Delete(ApiUrlManagementDeploymentsDeviceId,
 			controller.AbortDeviceDeployments),
 		rest.Delete(ApiUrlManagementDeploymentsDeviceHistory,
@@ -208,6 +263,12 @@ func NewDeploymentsResourceRoutes(controller *DeploymentsApiHandlers) []*rest.Ro
 			controller.PutDeploymentStatusForDevice),
 		rest.Put(ApiUrlDevicesDeploymentsLog,
 			controller.PutDeploymentLogForDevice),
+		rest.Get(ApiUrlDevicesDeploymentsLink,
+			controller.GetDeploymentLinkForDevice),
+		rest.Post(ApiUrlDevicesGatewayDeploymentsNext,
+			controller.GetDeploymentsForGatewayDevices),
+		rest.Post(ApiUrlDevicesGatewayDeploymentsStatus,
+			controller.PostDeploymentStatusesForGatewayDevices),
 		rest.Get(ApiUrlDevicesDownloadConfig,
 			controller.DownloadConfiguration),
 
@@ -244,10 +305,15 @@ func TenantRoutes(controller *DeploymentsApiHandlers) []*rest.Route {
 		rest.Delete(ApiUrlInternalTenantDeploymentsDevice,
 			controller.AbortDeviceDeploymentsInternal),
 		rest.Post(ApiUrlInternalTenantArtifacts, controller.NewImageForTenantHandler),
+		rest.Post(ApiUrlInternalTenantArtifactCopy, controller.CopyArtifactToTenantHandler),
 
 		// per-tenant storage settings
 		rest.Get(ApiUrlInternalTenantStorageSettings, controller.GetTenantStorageSettingsHandler),
 		rest.Put(ApiUrlInternalTenantStorageSettings, controller.PutTenantStorageSettingsHandler),
+
+		// per-tenant deployment settings
+		rest.Get(ApiUrlInternalTenantSettings, controller.GetTenantSettingsHandler),
+		rest.Put(ApiUrlInternalTenantSettings, controller.PutTenantSettingsHandler),
 	}
 }
 
@@ -260,10 +326,20 @@ func ReleasesRoutes(controller *DeploymentsApiHandlers) []*rest.Route {
 		rest.Get(ApiUrlManagementReleases, controller.GetReleases),
 		rest.Get(ApiUrlManagementReleasesList, controller.ListReleases),
 		rest.Get(ApiUrlManagementV2Releases, controller.ListReleasesV2),
+		rest.Get(ApiUrlManagementV2ReleasesCompare, controller.CompareReleases),
+		rest.Get(ApiUrlManagementV2ReleaseStatistics, controller.GetReleaseStatistics),
+		rest.Get(ApiUrlManagementV2ReleaseNotes, controller.GetReleaseNotesHTML),
+		rest.Get(ApiUrlManagementV2ReleaseNotesHistory, controller.GetReleaseNotesHistory),
 		rest.Put(ApiUrlManagementV2ReleaseTags, controller.PutReleaseTags),
+		rest.Patch(ApiUrlManagementV2ReleaseTags, controller.PatchReleaseTags),
 		rest.Get(ApiUrlManagementV2ReleaseAllTags, controller.GetReleaseTagKeys),
 		rest.Get(ApiUrlManagementV2ReleaseAllUpdateTypes, controller.GetReleasesUpdateTypes),
 		rest.Patch(ApiUrlManagementV2ReleasesName, controller.PatchRelease),
+		rest.Delete(ApiUrlManagementV2ReleasesName, controller.DeleteRelease),
+		rest.Delete(ApiUrlManagementV2Releases, controller.DeleteReleases),
+		rest.Get(ApiUrlManagementV2ReleaseChannels, controller.ListReleaseChannels),
+		rest.Get(ApiUrlManagementV2ReleaseChannelsName, controller.GetReleaseChannel),
+		rest.Post(ApiUrlManagementV2ReleaseChannelPromote, controller.PromoteRelease),
 	}
 }
 
@@ -17,8 +17,11 @@ package app
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
+	"net/http"
 	"path"
 	"reflect"
 	"strings"
@@ -79,27 +82,41 @@ var (
 	ErrModelImageInActiveDeployment     = errors.New(
 		"Image is used in active deployment and cannot be removed",
 	)
+	ErrArtifactMetaInActiveDeployment = errors.New(
+		"Image is used in active deployment and its metadata cannot be edited",
+	)
 	ErrModelImageUsedInAnyDeployment = errors.New("Image has already been used in deployment")
 	ErrModelParsingArtifactFailed    = errors.New("Cannot parse artifact file")
 	ErrUploadNotFound                = errors.New("artifact object not found")
 	ErrEmptyArtifact                 = errors.New("artifact cannot be nil")
+	ErrStorageLimitExceeded          = errors.New(
+		"Storage limit exceeded; remove unused artifacts to free up space",
+	)
+	ErrArtifactChecksumMismatch = errors.New(
+		"artifact checksum does not match the expected checksum",
+	)
+	ErrArtifactSizeMismatch = errors.New(
+		"artifact size does not match the size in the metadata",
+	)
 
 	ErrMsgArtifactConflict = "An artifact with the same name has conflicting dependencies"
 
 	// deployments
-	ErrModelMissingInput       = errors.New("Missing input deployment data")
-	ErrModelInvalidDeviceID    = errors.New("Invalid device ID")
-	ErrModelDeploymentNotFound = errors.New("Deployment not found")
-	ErrModelInternal           = errors.New("Internal error")
-	ErrStorageInvalidLog       = errors.New("Invalid deployment log")
-	ErrStorageNotFound         = errors.New("Not found")
-	ErrDeploymentAborted       = errors.New("Deployment aborted")
-	ErrDeviceDecommissioned    = errors.New("Device decommissioned")
-	ErrNoArtifact              = errors.New("No artifact for the deployment")
-	ErrNoDevices               = errors.New("No devices for the deployment")
-	ErrDuplicateDeployment     = errors.New("Deployment with given ID already exists")
-	ErrInvalidDeploymentID     = errors.New("Deployment ID must be a valid UUID")
-	ErrConflictingRequestData  = errors.New("Device provided conflicting request data")
+	ErrModelMissingInput        = errors.New("Missing input deployment data")
+	ErrModelInvalidDeviceID     = errors.New("Invalid device ID")
+	ErrModelDeploymentNotFound  = errors.New("Deployment not found")
+	ErrModelInternal            = errors.New("Internal error")
+	ErrStorageInvalidLog        = errors.New("Invalid deployment log")
+	ErrStorageNotFound          = errors.New("Not found")
+	ErrDeploymentAborted        = errors.New("Deployment aborted")
+	ErrDeviceDecommissioned     = errors.New("Device decommissioned")
+	ErrNoArtifact               = errors.New("No artifact for the deployment")
+	ErrNoDevices                = errors.New("No devices for the deployment")
+	ErrDuplicateDeployment      = errors.New("Deployment with given ID already exists")
+	ErrInvalidDeploymentID      = errors.New("Deployment ID must be a valid UUID")
+	ErrConflictingRequestData   = errors.New("Device provided conflicting request data")
+	ErrInvalidStatusTransition  = errors.New("Invalid device deployment status transition")
+	ErrDeviceDeploymentFinished = errors.New("Device deployment already finished")
 )
 
 //deployments
@@ -115,6 +132,34 @@ type App interface {
 	GetStorageSettings(ctx context.Context) (*model.StorageSettings, error)
 	SetStorageSettings(ctx context.Context, storageSettings *model.StorageSettings) error
 
+	// Tenant Settings
+	GetTenantSettings(ctx context.Context) (*model.TenantSettings, error)
+	SetTenantSettings(ctx context.Context, settings *model.TenantSettings) error
+
+	// Public keys trusted to sign artifacts
+	AddPublicKey(
+		ctx context.Context,
+		constructor *model.PublicKeyConstructor,
+	) (*model.PublicKey, error)
+	ListPublicKeys(ctx context.Context) ([]model.PublicKey, error)
+	GetPublicKey(ctx context.Context, id string) (*model.PublicKey, error)
+	DeletePublicKey(ctx context.Context, id string) error
+
+	// Artifact import from remote URLs
+	ImportArtifact(
+		ctx context.Context,
+		req *model.ImportArtifactRequest,
+	) (*model.ImportJob, error)
+	GetImportJob(ctx context.Context, id string) (*model.ImportJob, error)
+
+	// Artifact copy between tenants
+	CopyArtifact(ctx context.Context, id string, tenantID string) (*model.Image, error)
+
+	// Artifact retention policy
+	GetRetentionPolicy(ctx context.Context) (*model.RetentionPolicy, error)
+	SetRetentionPolicy(ctx context.Context, policy *model.RetentionPolicy) error
+	FindExpiredArtifacts(ctx context.Context, now time.Time) ([]model.ExpiredArtifact, error)
+
 	// images
 	ListImages(
 		ctx context.Context,
@@ -133,6 +178,20 @@ type App interface {
 		skipVerify bool,
 		metadata *model.DirectUploadMetadata,
 	) error
+	CreateChunkedUpload(
+		ctx context.Context,
+		length int64,
+		expire time.Duration,
+	) (*model.UploadLink, error)
+	GetChunkedUpload(ctx context.Context, id string) (*model.UploadLink, error)
+	UploadChunk(
+		ctx context.Context,
+		id string,
+		offset int64,
+		chunk io.Reader,
+		size int64,
+	) (int64, error)
+	CompleteChunkedUpload(ctx context.Context, id string) error
 	GetImage(ctx context.Context, id string) (*model.Image, error)
 	DeleteImage(ctx context.Context, imageID string) error
 	CreateImage(ctx context.Context,
@@ -146,6 +205,17 @@ type App interface {
 	) (io.Reader, error)
 	EditImage(ctx context.Context, id string,
 		constructorData *model.ImageMeta) (bool, error)
+	EditArtifactMeta(
+		ctx context.Context,
+		id string,
+		edit *model.ArtifactMetaEdit,
+	) (*model.Image, error)
+	GetArtifactContents(ctx context.Context, id string) (*model.ArtifactContents, error)
+	GetArtifactFile(
+		ctx context.Context,
+		id string,
+		name string,
+	) (io.ReadCloser, *model.UpdateFile, error)
 
 	// deployments
 	CreateDeployment(ctx context.Context,
@@ -162,6 +232,10 @@ type App interface {
 		deviceID string) (bool, error)
 	UpdateDeviceDeploymentStatus(ctx context.Context, deploymentID string,
 		deviceID string, state model.DeviceDeploymentState) error
+	GetGatewayDevices(ctx context.Context, gatewayID string,
+		deviceIDs []string) ([]string, error)
+	GetDeviceDeploymentDownloadLink(ctx context.Context, deploymentID string,
+		deviceID string) (*model.Link, error)
 	GetDeviceStatusesForDeployment(ctx context.Context,
 		deploymentID string) ([]model.DeviceDeployment, error)
 	GetDevicesListForDeployment(ctx context.Context,
@@ -174,6 +248,8 @@ type App interface {
 		deploymentID string, logs []model.LogMessage) error
 	GetDeviceDeploymentLog(ctx context.Context,
 		deviceID, deploymentID string) (*model.DeploymentLog, error)
+	GetDeviceDeploymentHistory(ctx context.Context,
+		deploymentID, deviceID string) (*model.DeviceDeploymentHistory, error)
 	AbortDeviceDeployments(ctx context.Context, deviceID string) error
 	DeleteDeviceDeploymentsHistory(ctx context.Context, deviceId string) error
 	DecommissionDevice(ctx context.Context, deviceID string) error
@@ -194,7 +270,30 @@ type App interface {
 
 	// releases
 	ReplaceReleaseTags(ctx context.Context, releaseName string, tags model.Tags) error
+	PatchReleaseTags(ctx context.Context, releaseName string, patch model.TagsPatch) error
 	UpdateRelease(ctx context.Context, releaseName string, release model.ReleasePatch) error
+	GetReleaseNotesHistory(
+		ctx context.Context,
+		releaseName string,
+		skip, limit int,
+	) ([]model.ReleaseNotesRevision, int, error)
+	RenderReleaseNotes(ctx context.Context, releaseName string) ([]byte, error)
+	DeleteReleases(
+		ctx context.Context,
+		releaseNames []string,
+	) ([]model.ReleaseArtifactDeletion, error)
+	ListReleaseChannels(ctx context.Context) ([]model.ReleaseChannel, error)
+	GetReleaseChannel(ctx context.Context, name string) (*model.ReleaseChannel, error)
+	PromoteRelease(
+		ctx context.Context,
+		channel string,
+		promotion model.ReleasePromotion,
+	) (*model.ReleaseChannel, error)
+	GetReleaseStatistics(ctx context.Context, releaseName string) (*model.ReleaseStatistics, error)
+	CompareReleases(
+		ctx context.Context,
+		from, to string,
+	) (*model.ReleaseComparison, error)
 	ListReleaseTags(ctx context.Context) (model.Tags, error)
 	GetReleasesUpdateTypes(ctx context.Context) ([]string, error)
 }
@@ -205,6 +304,17 @@ type Deployments struct {
 	workflowsClient workflows.Client
 	inventoryClient inventory.Client
 	reportingClient reporting.Client
+
+	// statusTransitions is the default handling of illegal device
+	// deployment status transitions for tenants without own settings
+	statusTransitions model.StatusTransitionsMode
+
+	// importClient fetches the artifacts imported from remote URLs
+	importClient *http.Client
+
+	// builtinGenerator generates the artifacts in-process instead of
+	// through the workflows service
+	builtinGenerator bool
 }
 
 // Compile-time check
@@ -217,10 +327,12 @@ func NewDeployments(
 	withAuditLogs bool,
 ) *Deployments {
 	return &Deployments{
-		db:              storage,
-		objectStorage:   objectStorage,
-		workflowsClient: workflows.NewClient(),
-		inventoryClient: inventory.NewClient(),
+		db:                storage,
+		objectStorage:     objectStorage,
+		workflowsClient:   workflows.NewClient(),
+		inventoryClient:   inventory.NewClient(),
+		statusTransitions: model.StatusTransitionsModeWarn,
+		importClient:      newImportClient(),
 	}
 }
 
@@ -286,17 +398,104 @@ func (d *Deployments) contextWithStorageSettings(
 func (d *Deployments) GetLimit(ctx context.Context, name string) (*model.Limit, error) {
 	limit, err := d.db.GetLimit(ctx, name)
 	if err == mongo.ErrLimitNotFound {
-		return &model.Limit{
+		limit = &model.Limit{
 			Name:  name,
 			Value: 0,
-		}, nil
-
+		}
 	} else if err != nil {
 		return nil, errors.Wrap(err, "failed to obtain limit from storage")
 	}
+	if name == model.LimitStorage {
+		limit.Usage, err = d.storageUsage(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return limit, nil
 }
 
+func (d *Deployments) storageUsage(ctx context.Context) (uint64, error) {
+	usage, err := d.db.GetStorageUsage(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to obtain storage usage")
+	} else if usage < 0 {
+		return 0, nil
+	}
+	return uint64(usage), nil
+}
+
+// storageLimit returns the storage limit in bytes; 0 means no limit
+func (d *Deployments) storageLimit(ctx context.Context) (uint64, error) {
+	limit, err := d.db.GetLimit(ctx, model.LimitStorage)
+	if err == mongo.ErrLimitNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Wrap(err, "failed to obtain limit from storage")
+	}
+	return limit.Value, nil
+}
+
+// checkStorageLimit fails when storing size more bytes would exceed the
+// storage limit; it only checks the limit, see reserveStorage.
+func (d *Deployments) checkStorageLimit(ctx context.Context, size int64) error {
+	limit, err := d.storageLimit(ctx)
+	if err != nil || limit == 0 {
+		return err
+	}
+	usage, err := d.storageUsage(ctx)
+	if err != nil {
+		return err
+	}
+	if usage >= limit || uint64(size) > limit-usage {
+		return ErrStorageLimitExceeded
+	}
+	return nil
+}
+
+// checkUploadStorageLimit checks the artifact uploaded to the given path
+// fits in the storage limit.
+func (d *Deployments) checkUploadStorageLimit(ctx context.Context, path string) error {
+	limit, err := d.storageLimit(ctx)
+	if err != nil || limit == 0 {
+		return err
+	}
+	info, err := d.objectStorage.StatObject(ctx, path)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return ErrUploadNotFound
+	} else if err != nil {
+		return err
+	}
+	var size int64
+	if info.Size != nil {
+		size = *info.Size
+	}
+	return d.checkStorageLimit(ctx, size)
+}
+
+// reserveStorage adds the size of a new artifact to the storage usage,
+// failing when it would exceed the storage limit.
+func (d *Deployments) reserveStorage(ctx context.Context, size int64) error {
+	limit, err := d.storageLimit(ctx)
+	if err != nil {
+		return err
+	}
+	err = d.db.IncrementStorageUsage(ctx, size, limit)
+	if err == store.ErrLimitExceeded {
+		return ErrStorageLimitExceeded
+	} else if err != nil {
+		return errors.Wrap(err, "failed to update storage usage")
+	}
+	return nil
+}
+
+// releaseStorage subtracts the size of a removed artifact from the storage
+// usage.
+func (d *Deployments) releaseStorage(ctx context.Context, size int64) {
+	if err := d.db.IncrementStorageUsage(ctx, -size, 0); err != nil {
+		log.FromContext(ctx).Errorf("failed to update storage usage: %s", err)
+	}
+}
+
 func (d *Deployments) ProvisionTenant(ctx context.Context, tenant_id string) error {
 	if err := d.db.ProvisionTenant(ctx, tenant_id); err != nil {
 		return errors.Wrap(err, "failed to provision tenant")
@@ -310,6 +509,10 @@ func (d *Deployments) ProvisionTenant(ctx context.Context, tenant_id string) err
 // Returns image ID and nil on success.
 func (d *Deployments) CreateImage(ctx context.Context,
 	multipartUploadMsg *model.MultipartUploadMsg) (string, error) {
+	// do not store artifacts of tenants already over the limit
+	if err := d.checkStorageLimit(ctx, multipartUploadMsg.Size); err != nil {
+		return "", err
+	}
 	return d.handleArtifact(ctx, multipartUploadMsg, false, nil)
 }
 
@@ -349,11 +552,18 @@ func (d *Deployments) handleArtifact(ctx context.Context,
 	if err != nil {
 		return "", err
 	}
+	verifier, err := d.newSignatureVerifier(ctx)
+	if err != nil {
+		return "", err
+	}
 
 	// create pipe
 	pR, pW := io.Pipe()
 
-	artifactReader := utils.CountReads(multipartUploadMsg.ArtifactReader)
+	checksum := sha256.New()
+	artifactReader := utils.CountReads(
+		io.TeeReader(multipartUploadMsg.ArtifactReader, checksum),
+	)
 
 	tee := io.TeeReader(artifactReader, pW)
 
@@ -387,12 +597,32 @@ func (d *Deployments) handleArtifact(ctx context.Context,
 		return err
 	}()
 
+	cleanup := func() {
+		// Try to remove the storage from s3.
+		if errDelete := d.objectStorage.DeleteObject(
+			ctx, model.ImagePathFromContext(ctx, artifactID),
+		); errDelete != nil {
+			l.Errorf(
+				"failed to clean up artifact storage after failure: %s",
+				errDelete,
+			)
+		}
+	}
+
 	// parse artifact
 	// artifact library reads all the data from the given reader
-	metaArtifactConstructor, err := getMetaFromArchive(&tee, skipVerify)
+	metaArtifactConstructor, err := getMetaFromArchive(&tee, skipVerify, verifier)
 	if err != nil {
 		_ = pW.CloseWithError(err)
 		<-ch
+		if skipVerify {
+			// the artifact was uploaded directly to its final path
+			cleanup()
+		}
+		switch cause := errors.Cause(err); cause {
+		case ErrArtifactNotSigned, ErrArtifactSignatureInvalid:
+			return artifactID, cause
+		}
 		return artifactID, errors.Wrap(ErrModelParsingArtifactFailed, err.Error())
 	}
 	validMetadata := false
@@ -407,19 +637,23 @@ func (d *Deployments) handleArtifact(ctx context.Context,
 	}
 	// validate artifact metadata
 	if err = metaArtifactConstructor.Validate(); err != nil {
+		_ = pW.CloseWithError(ErrModelInvalidMetadata)
+		<-ch
+		if skipVerify {
+			cleanup()
+		}
 		return artifactID, ErrModelInvalidMetadata
 	}
 
-	if !skipVerify {
-		// read the rest of the data,
-		// just in case the artifact library did not read all the data from the reader
-		_, err = io.Copy(io.Discard, tee)
-		if err != nil {
-			// CloseWithError will cause the reading end to abort upload.
-			_ = pW.CloseWithError(err)
-			<-ch
-			return artifactID, err
-		}
+	// read the rest of the data, to compute the checksum of the whole
+	// artifact and in case the artifact library did not read all the data
+	// from the reader
+	_, err = io.Copy(io.Discard, tee)
+	if err != nil {
+		// CloseWithError will cause the reading end to abort upload.
+		_ = pW.CloseWithError(err)
+		<-ch
+		return artifactID, err
 	}
 
 	// close the pipe
@@ -430,9 +664,12 @@ func (d *Deployments) handleArtifact(ctx context.Context,
 		return artifactID, uploadResponseErr
 	}
 
+	// the size is the one read, the storage limit cannot be bypassed by
+	// declaring a smaller size in the metadata
 	size := artifactReader.Count()
-	if skipVerify && validMetadata {
-		size = metadata.Size
+	if skipVerify && validMetadata && metadata.Size > 0 && metadata.Size != size {
+		cleanup()
+		return artifactID, ErrArtifactSizeMismatch
 	}
 	image := model.NewImage(
 		artifactID,
@@ -440,18 +677,34 @@ func (d *Deployments) handleArtifact(ctx context.Context,
 		metaArtifactConstructor,
 		size,
 	)
+	image.SigningKeyID = verifier.keyID
+
+	// the whole artifact went through the reader
+	image.Checksum = hex.EncodeToString(checksum.Sum(nil))
+	if multipartUploadMsg.Checksum != "" &&
+		!strings.EqualFold(multipartUploadMsg.Checksum, image.Checksum) {
+		cleanup()
+		return artifactID, ErrArtifactChecksumMismatch
+	}
+	if err = d.checkReleaseAlias(ctx, image.ArtifactMeta.Name); err != nil {
+		cleanup()
+		return artifactID, err
+	}
+	if err = d.reserveStorage(ctx, image.Size); err != nil {
+		cleanup()
+		return artifactID, err
+	}
+	d.shareArtifactObject(ctx, image)
 
 	// save image structure in the system
 	if err = d.db.InsertImage(ctx, image); err != nil {
-		// Try to remove the storage from s3.
-		if errDelete := d.objectStorage.DeleteObject(
-			ctx, model.ImagePathFromContext(ctx, artifactID),
-		); errDelete != nil {
+		if errDelete := d.deleteArtifactFile(ctx, image); errDelete != nil {
 			l.Errorf(
 				"failed to clean up artifact storage after failure: %s",
 				errDelete,
 			)
 		}
+		d.releaseStorage(ctx, image.Size)
 		if idxErr, ok := err.(*model.ConflictError); ok {
 			return artifactID, idxErr
 		}
@@ -489,7 +742,8 @@ func validUpdates(constructorUpdates []model.Update, metadataUpdates []model.Upd
 
 // GenerateImage parses raw data and uploads it to the file storage - in parallel,
 // creates image structure in the system, and starts the workflow to generate the
-// artifact from them.
+// artifact from them; with the built-in generator, the artifact is generated
+// and stored right away.
 // Returns image ID and nil on success.
 func (d *Deployments) GenerateImage(ctx context.Context,
 	multipartGenerateImageMsg *model.MultipartGenerateImageMsg) (string, error) {
@@ -497,6 +751,23 @@ func (d *Deployments) GenerateImage(ctx context.Context,
 	if multipartGenerateImageMsg == nil {
 		return "", ErrModelMultipartUploadMsgMalformed
 	}
+	// generated artifacts are not signed
+	settings, err := d.db.GetTenantSettings(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get tenant settings")
+	} else if settings != nil && settings.RequireSignedArtifacts {
+		return "", ErrArtifactNotSigned
+	}
+	if err = d.checkReleaseAlias(ctx, multipartGenerateImageMsg.Name); err != nil {
+		return "", err
+	}
+	// the size of the generated artifact is not known yet
+	if err = d.checkStorageLimit(ctx, 0); err != nil {
+		return "", err
+	}
+	if d.builtinGenerator {
+		return d.generateArtifact(ctx, multipartGenerateImageMsg)
+	}
 
 	imgPath, err := d.handleRawFile(ctx, multipartGenerateImageMsg)
 	if err != nil {
@@ -677,8 +948,7 @@ func (d *Deployments) DeleteImage(ctx context.Context, imageID string) error {
 	if err != nil {
 		return err
 	}
-	imagePath := model.ImagePathFromContext(ctx, imageID)
-	if err := d.objectStorage.DeleteObject(ctx, imagePath); err != nil {
+	if err := d.deleteArtifactFile(ctx, found); err != nil {
 		return errors.Wrap(err, "Deleting image file")
 	}
 
@@ -686,6 +956,7 @@ func (d *Deployments) DeleteImage(ctx context.Context, imageID string) error {
 	if err := d.db.DeleteImage(ctx, imageID); err != nil {
 		return errors.Wrap(err, "Deleting image metadata")
 	}
+	d.releaseStorage(ctx, found.Size)
 
 	// update release
 	if err := d.updateRelease(ctx, nil, found); err != nil {
@@ -771,7 +1042,7 @@ func (d *Deployments) DownloadLink(ctx context.Context, imageID string,
 	if err != nil {
 		return nil, err
 	}
-	imagePath := model.ImagePathFromContext(ctx, imageID)
+	imagePath := image.ObjectPathFromContext(ctx)
 	_, err = d.objectStorage.StatObject(ctx, imagePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "Searching for image file")
@@ -899,18 +1170,14 @@ func (d *Deployments) CompleteUpload(
 
 	settings, _ := storage.SettingsFromContext(ctx)
 	ctxAsync = storage.SettingsWithContext(ctxAsync, settings)
-	var artifactReader io.ReadCloser
-	if skipVerify {
-		artifactReader, err = d.objectStorage.GetObject(
-			ctxAsync,
-			model.ImagePathFromContext(ctx, intentID),
-		)
-	} else {
-		artifactReader, err = d.objectStorage.GetObject(
-			ctxAsync,
-			model.ImagePathFromContext(ctx, intentID)+fileSuffixTmp,
-		)
+	artifactPath := model.ImagePathFromContext(ctx, intentID)
+	if !skipVerify {
+		artifactPath += fileSuffixTmp
+	}
+	if err = d.checkUploadStorageLimit(ctx, artifactPath); err != nil {
+		return err
 	}
+	artifactReader, err := d.objectStorage.GetObject(ctxAsync, artifactPath)
 	if err != nil {
 		if errors.Is(err, storage.ErrObjectNotFound) {
 			return ErrUploadNotFound
@@ -960,20 +1227,27 @@ func getUpdateFiles(uFiles []*handlers.DataFile) ([]model.UpdateFile, error) {
 	return files, nil
 }
 
-func getMetaFromArchive(r *io.Reader, skipVerify bool) (*model.ArtifactMeta, error) {
+func getMetaFromArchive(
+	r *io.Reader,
+	skipVerify bool,
+	verifier *signatureVerifier,
+) (*model.ArtifactMeta, error) {
 	metaArtifact := model.NewArtifactMeta()
 
 	aReader := areader.NewReader(*r)
 
-	// There is no signature verification here.
-	// It is just simple check if artifact is signed or not.
+	// The signature covers the manifest, which holds the checksums of
+	// the rest of the artifact.
 	aReader.VerifySignatureCallback = func(message, sig []byte) error {
 		metaArtifact.Signed = true
-		return nil
+		return verifier.verify(message, sig)
 	}
 
 	var err error
-	if skipVerify {
+	// Reading the headers verifies the signature of the manifest, but
+	// not the payloads against the checksums of the manifest; signed
+	// artifacts are required to be intact, so they are read in full.
+	if skipVerify && !verifier.requireSigned {
 		err = aReader.ReadArtifactHeaders()
 		if err != nil {
 			return nil, errors.Wrap(err, "reading artifact error")
@@ -985,6 +1259,10 @@ func getMetaFromArchive(r *io.Reader, skipVerify bool) (*model.ArtifactMeta, err
 		}
 	}
 
+	if err = verifier.checkSigned(metaArtifact.Signed); err != nil {
+		return nil, err
+	}
+
 	metaArtifact.Info = getArtifactInfo(aReader.GetInfo())
 	metaArtifact.DeviceTypesCompatible = aReader.GetCompatibleDevices()
 
@@ -1005,7 +1283,24 @@ func getMetaFromArchive(r *io.Reader, skipVerify bool) (*model.ArtifactMeta, err
 		metaArtifact.ClearsProvides = aReader.MergeArtifactClearsProvides()
 	}
 
-	for _, p := range aReader.GetHandlers() {
+	metaArtifact.Updates, err = getUpdates(aReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return metaArtifact, nil
+}
+
+// getUpdates returns the payloads read by the artifact reader, in the order
+// of the payloads in the artifact.
+func getUpdates(aReader *areader.Reader) ([]model.Update, error) {
+	var updates []model.Update
+	installers := aReader.GetHandlers()
+	for i := 0; i < len(installers); i++ {
+		p, ok := installers[i]
+		if !ok {
+			continue
+		}
 		uFiles, err := getUpdateFiles(p.GetUpdateFiles())
 		if err != nil {
 			return nil, errors.Wrap(err, "Cannot get update files:")
@@ -1016,8 +1311,8 @@ func getMetaFromArchive(r *io.Reader, skipVerify bool) (*model.ArtifactMeta, err
 			return nil, errors.Wrap(err, "Cannot get update metadata")
 		}
 
-		metaArtifact.Updates = append(
-			metaArtifact.Updates,
+		updates = append(
+			updates,
 			model.Update{
 				TypeInfo: model.ArtifactUpdateTypeInfo{
 					Type: p.GetUpdateType(),
@@ -1026,8 +1321,7 @@ func getMetaFromArchive(r *io.Reader, skipVerify bool) (*model.ArtifactMeta, err
 				MetaData: uMetadata,
 			})
 	}
-
-	return metaArtifact, nil
+	return updates, nil
 }
 
 func getArtifactIDs(artifacts []*model.Image) []string {
@@ -1159,6 +1453,12 @@ func (d *Deployments) CreateDeployment(ctx context.Context,
 		return "", errors.Wrap(err, "Validating deployment")
 	}
 
+	if constructor.Channel != "" {
+		if err := d.resolveReleaseChannel(ctx, constructor); err != nil {
+			return "", err
+		}
+	}
+
 	if len(constructor.Group) > 0 || constructor.AllDevices {
 		constructor, err = d.updateDeploymentConstructor(ctx, constructor)
 		if err != nil {
@@ -1174,7 +1474,9 @@ func (d *Deployments) CreateDeployment(ctx context.Context,
 	// Assign artifacts to the deployment.
 	// When new artifact(s) with the artifact name same as the one in the deployment
 	// will be uploaded to the backend, it will also become part of this deployment.
-	artifacts, err := d.db.ImagesByName(ctx, deployment.ArtifactName)
+	// The artifact name may be the alias of the release as well.
+	var artifacts []*model.Image
+	deployment.ArtifactName, artifacts, err = d.imagesByReleaseName(ctx, deployment.ArtifactName)
 	if err != nil {
 		return "", errors.Wrap(err, "Finding artifact with given name")
 	}
@@ -1183,6 +1485,14 @@ func (d *Deployments) CreateDeployment(ctx context.Context,
 		return "", ErrNoArtifact
 	}
 
+	status, err := d.releaseStatus(ctx, deployment.ArtifactName)
+	if err != nil {
+		return "", err
+	}
+	if !status.IsDeployable() {
+		return "", ErrReleaseNotDeployable
+	}
+
 	deployment.Artifacts = getArtifactIDs(artifacts)
 	deployment.DeviceList = constructor.Devices
 	deployment.MaxDevices = len(constructor.Devices)
@@ -1404,6 +1714,7 @@ func (d *Deployments) createDeviceDeploymentWithStatus(
 	deviceDeployment.Status = status
 	deviceDeployment.Active = status.Active()
 	deviceDeployment.Created = deployment.Created
+	deviceDeployment.StatusHistory[0].Status = status
 
 	if err := d.setDeploymentDeviceCountIfUnset(ctx, deployment); err != nil {
 		return nil, err
@@ -1459,6 +1770,76 @@ func (d *Deployments) isDevicePartOfDeployment(
 	return false, nil
 }
 
+// GetDeviceDeploymentDownloadLink generates a new download link for the
+// artifact assigned to an ongoing device deployment, replacing a link which
+// expired while the device was downloading the artifact.
+func (d *Deployments) GetDeviceDeploymentDownloadLink(
+	ctx context.Context,
+	deploymentID string,
+	deviceID string,
+) (*model.Link, error) {
+	deviceDeployment, err := d.db.GetDeviceDeployment(ctx, deploymentID, deviceID, false)
+	if err == mongo.ErrStorageNotFound {
+		return nil, ErrStorageNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get the device deployment")
+	}
+	if !deviceDeployment.Status.Active() {
+		return nil, ErrDeviceDeploymentFinished
+	}
+	if deviceDeployment.Image == nil {
+		return nil, ErrNoArtifact
+	}
+	// stop serving releases revoked in the middle of the deployment
+	status, err := d.releaseStatus(ctx, deviceDeployment.Image.ArtifactMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	if status == model.ReleaseStatusRevoked {
+		if err = d.abortDeviceDeployment(ctx, deviceDeployment, "release revoked"); err != nil {
+			return nil, err
+		}
+		return nil, ErrDeploymentAborted
+	}
+	// stop serving artifacts found missing or corrupt after the assignment
+	intact, err := d.assignedArtifactIntact(ctx, deviceDeployment)
+	if err != nil {
+		return nil, err
+	}
+	if !intact {
+		err = d.abortDeviceDeployment(ctx, deviceDeployment, "artifact missing or corrupt")
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrDeploymentAborted
+	}
+
+	ctx, err = d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return d.updateDownloadLink(ctx, deviceDeployment.Image)
+}
+
+// updateDownloadLink generates the link devices download the artifact from.
+// The link supports HTTP Range requests, so devices can resume interrupted
+// downloads.
+func (d *Deployments) updateDownloadLink(
+	ctx context.Context,
+	image *model.Image,
+) (*model.Link, error) {
+	link, err := d.objectStorage.GetRequest(
+		ctx,
+		image.ObjectPathFromContext(ctx),
+		image.Name+model.ArtifactFileSuffix,
+		DefaultUpdateDownloadLinkExpire,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Generating download link for the device")
+	}
+	return link, nil
+}
+
 // GetDeploymentForDeviceWithCurrent returns deployment for the device
 func (d *Deployments) GetDeploymentForDeviceWithCurrent(ctx context.Context, deviceID string,
 	request *model.DeploymentNextRequest) (*model.DeploymentInstructions, error) {
@@ -1504,6 +1885,15 @@ func (d *Deployments) getDeploymentInstructions(
 		}, nil
 	}
 
+	// stop serving releases revoked in the middle of the deployment
+	status, err := d.releaseStatus(ctx, deployment.ArtifactName)
+	if err != nil {
+		return nil, err
+	}
+	if status == model.ReleaseStatusRevoked {
+		return nil, d.abortDeviceDeployment(ctx, deviceDeployment, "release revoked")
+	}
+
 	// assing artifact to the device deployment
 	// only if it was not assgined previously
 	if deviceDeployment.Image == nil {
@@ -1539,20 +1929,14 @@ func (d *Deployments) getDeploymentInstructions(
 		}
 	}
 
-	ctx, err := d.contextWithStorageSettings(ctx)
+	ctx, err = d.contextWithStorageSettings(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	imagePath := model.ImagePathFromContext(ctx, deviceDeployment.Image.Id)
-	link, err := d.objectStorage.GetRequest(
-		ctx,
-		imagePath,
-		deviceDeployment.Image.Name+model.ArtifactFileSuffix,
-		DefaultUpdateDownloadLinkExpire,
-	)
+	link, err := d.updateDownloadLink(ctx, deviceDeployment.Image)
 	if err != nil {
-		return nil, errors.Wrap(err, "Generating download link for the device")
+		return nil, err
 	}
 
 	instructions := &model.DeploymentInstructions{
@@ -1564,6 +1948,8 @@ func (d *Deployments) getDeploymentInstructions(
 			Source: *link,
 			DeviceTypesCompatible: deviceDeployment.Image.
 				ArtifactMeta.DeviceTypesCompatible,
+			Size:     deviceDeployment.Image.Size,
+			Checksum: deviceDeployment.Image.Checksum,
 		},
 	}
 
@@ -1642,9 +2028,27 @@ func (d *Deployments) UpdateDeviceDeploymentStatus(ctx context.Context, deployme
 		return ErrDeviceDecommissioned
 	}
 
-	// nothing to do
+	// nothing to do but recording a new substate in the history
 	if ddState.Status == currentStatus {
-		return nil
+		if ddState.SubState == "" || ddState.SubState == dd.SubState {
+			return nil
+		}
+		// keep the finish time of finished device deployments
+		ddState.FinishTime = nil
+		_, err = d.db.UpdateDeviceDeploymentStatus(ctx, deviceID, deploymentID, ddState)
+		return err
+	}
+
+	if !currentStatus.CanTransitionTo(ddState.Status) {
+		mode, err := d.statusTransitionsMode(ctx)
+		if err != nil {
+			return err
+		}
+		if mode == model.StatusTransitionsModeEnforce {
+			return ErrInvalidStatusTransition
+		}
+		l.Warnf("Invalid status transition from %s to %s for device %s deployment: %v",
+			currentStatus, ddState.Status, deviceID, deploymentID)
 	}
 
 	// update finish time
@@ -1903,6 +2307,21 @@ func (d *Deployments) GetDeviceDeploymentLog(ctx context.Context,
 		deviceID, deploymentID)
 }
 
+// GetDeviceDeploymentHistory returns the status transitions the device went
+// through in the deployment together with the time spent in each of them.
+func (d *Deployments) GetDeviceDeploymentHistory(ctx context.Context,
+	deploymentID, deviceID string) (*model.DeviceDeploymentHistory, error) {
+
+	dd, err := d.db.GetDeviceDeployment(ctx, deploymentID, deviceID, false)
+	if err == mongo.ErrStorageNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return model.NewDeviceDeploymentHistory(dd, time.Now()), nil
+}
+
 func (d *Deployments) HasDeploymentForDevice(ctx context.Context,
 	deploymentID string, deviceID string) (bool, error) {
 	return d.db.HasDeploymentForDevice(ctx, deploymentID, deviceID)
@@ -2093,6 +2512,64 @@ func (d *Deployments) SetStorageSettings(
 	return nil
 }
 
+// Tenant settings
+func (d *Deployments) GetTenantSettings(ctx context.Context) (*model.TenantSettings, error) {
+	settings, err := d.db.GetTenantSettings(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Searching for settings failed")
+	}
+	if settings == nil {
+		settings = &model.TenantSettings{}
+	}
+
+	return settings, nil
+}
+
+// SetTenantSettings replaces the tenant settings; settings left out of
+// the new settings fall back to their defaults.
+func (d *Deployments) SetTenantSettings(
+	ctx context.Context,
+	settings *model.TenantSettings,
+) error {
+	if err := d.db.SetTenantSettings(ctx, settings); err != nil {
+		return errors.Wrap(err, "Failed to save settings")
+	}
+
+	return nil
+}
+
+// statusTransitionsMode returns the handling of illegal device deployment
+// status transitions configured for the tenant, or the default one.
+func (d *Deployments) statusTransitionsMode(
+	ctx context.Context,
+) (model.StatusTransitionsMode, error) {
+	settings, err := d.db.GetTenantSettings(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get tenant settings")
+	}
+	if settings != nil && settings.StatusTransitions != "" {
+		return settings.StatusTransitions, nil
+	}
+	return d.statusTransitions, nil
+}
+
+func (d *Deployments) WithStatusTransitionsMode(mode model.StatusTransitionsMode) *Deployments {
+	d.statusTransitions = mode
+	return d
+}
+
+func (d *Deployments) WithImportClient(c *http.Client) *Deployments {
+	d.importClient = c
+	return d
+}
+
+// WithBuiltinArtifactGenerator makes GenerateImage build the artifacts
+// in-process, for the update modules the built-in generator supports.
+func (d *Deployments) WithBuiltinArtifactGenerator() *Deployments {
+	d.builtinGenerator = true
+	return d
+}
+
 func (d *Deployments) WithReporting(c reporting.Client) *Deployments {
 	d.reportingClient = c
 	return d
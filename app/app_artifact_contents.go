@@ -0,0 +1,223 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/mendersoftware/mender-artifact/areader"
+	"github.com/mendersoftware/mender-artifact/artifact"
+	"github.com/mendersoftware/mender-artifact/handlers"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+)
+
+var (
+	ErrArtifactFileNotFound   = errors.New("artifact file not found")
+	ErrArtifactObjectNotFound = errors.New("artifact object not found in the storage")
+
+	errArtifactFileStreamed = errors.New("artifact file streamed")
+)
+
+// GetArtifactContents lists the payloads of the artifact with their files.
+// The payloads recorded on upload are used if they list the size and
+// checksum of all the files; otherwise, the stored artifact is parsed.
+func (d *Deployments) GetArtifactContents(
+	ctx context.Context,
+	id string,
+) (*model.ArtifactContents, error) {
+	ctx, err := d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	image, err := d.db.FindImageByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "Searching for image with specified ID")
+	} else if image == nil || image.ArtifactMeta == nil {
+		return nil, ErrImageMetaNotFound
+	}
+	contents := &model.ArtifactContents{
+		ID:       image.Id,
+		Name:     image.ArtifactMeta.Name,
+		Info:     image.ArtifactMeta.Info,
+		Payloads: image.ArtifactMeta.Updates,
+	}
+	if model.UpdatesComplete(contents.Payloads) {
+		return contents, nil
+	}
+
+	obj, err := d.objectStorage.GetObject(ctx, image.ObjectPathFromContext(ctx))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, ErrArtifactObjectNotFound
+	} else if err != nil {
+		return nil, errors.WithMessage(err, "failed to get the artifact object")
+	}
+	defer obj.Close()
+
+	aReader := areader.NewReader(obj)
+	if err = aReader.ReadArtifact(); err != nil {
+		return nil, errors.Wrap(err, "reading artifact error")
+	}
+	contents.Payloads, err = getUpdates(aReader)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// GetArtifactFile streams the payload file with the given name out of the
+// stored artifact; the first payload containing the file is used. The
+// checksum of the file is verified while streaming: the returned reader
+// fails if it does not match.
+func (d *Deployments) GetArtifactFile(
+	ctx context.Context,
+	id string,
+	name string,
+) (io.ReadCloser, *model.UpdateFile, error) {
+	ctx, err := d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	image, err := d.db.FindImageByID(ctx, id)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Searching for image with specified ID")
+	} else if image == nil || image.ArtifactMeta == nil {
+		return nil, nil, ErrImageMetaNotFound
+	}
+	file := model.FindUpdateFile(image.ArtifactMeta.Updates, name)
+	if file == nil && len(image.ArtifactMeta.Updates) > 0 {
+		return nil, nil, ErrArtifactFileNotFound
+	}
+
+	obj, err := d.objectStorage.GetObject(ctx, image.ObjectPathFromContext(ctx))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, nil, ErrArtifactObjectNotFound
+	} else if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get the artifact object")
+	}
+
+	pR, pW := io.Pipe()
+	storer := &artifactFileStorer{
+		name:   name,
+		w:      pW,
+		result: make(chan artifactFileResult, 1),
+	}
+	go func() {
+		defer obj.Close()
+		err := readArtifactData(obj, storer)
+		if errors.Is(err, errArtifactFileStreamed) {
+			err = nil
+		} else if err == nil && !storer.found {
+			err = ErrArtifactFileNotFound
+		} else if err != nil {
+			log.FromContext(ctx).Errorf(
+				"failed to stream file %s of artifact %s: %s", name, id, err)
+		}
+		_ = pW.CloseWithError(err)
+		if !storer.found {
+			storer.result <- artifactFileResult{err: err}
+		}
+	}()
+
+	res := <-storer.result
+	if res.err != nil {
+		return nil, nil, res.err
+	}
+	modified := res.info.ModTime()
+	streamed := &model.UpdateFile{
+		Name: name,
+		Size: res.info.Size(),
+		Date: &modified,
+	}
+	if file != nil {
+		streamed.Checksum = file.Checksum
+	}
+	return pR, streamed, nil
+}
+
+// readArtifactData reads the artifact passing the files of all its
+// payloads to the given storer producer.
+func readArtifactData(r io.Reader, producer handlers.UpdateStorerProducer) error {
+	aReader := areader.NewReader(r)
+	if err := aReader.ReadArtifactHeaders(); err != nil {
+		return errors.Wrap(err, "reading artifact error")
+	}
+	for _, installer := range aReader.GetHandlers() {
+		installer.SetUpdateStorerProducer(producer)
+	}
+	return aReader.ReadArtifactData()
+}
+
+type artifactFileResult struct {
+	info os.FileInfo
+	err  error
+}
+
+// artifactFileStorer writes the payload file with the given name to the
+// pipe writer and discards the other files.
+type artifactFileStorer struct {
+	name   string
+	w      io.Writer
+	found  bool
+	result chan artifactFileResult
+}
+
+func (s *artifactFileStorer) NewUpdateStorer(
+	updateType *string,
+	payloadNum int,
+) (handlers.UpdateStorer, error) {
+	return s, nil
+}
+
+func (s *artifactFileStorer) Initialize(
+	artifactHeaders,
+	artifactAugmentedHeaders artifact.HeaderInfoer,
+	payloadHeaders handlers.ArtifactUpdateHeaders,
+) error {
+	return nil
+}
+
+func (s *artifactFileStorer) PrepareStoreUpdate() error {
+	return nil
+}
+
+func (s *artifactFileStorer) StoreUpdate(r io.Reader, info os.FileInfo) error {
+	if s.found || info.Name() != s.name {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+	s.found = true
+	s.result <- artifactFileResult{info: info}
+	if _, err := io.Copy(s.w, r); err != nil {
+		return err
+	}
+	// The reader verifies the checksum of the file once stored, verify
+	// it before stopping the read.
+	if verifier, ok := r.(interface{ Verify() error }); ok {
+		if err := verifier.Verify(); err != nil {
+			return errors.Wrap(err, "reader: error reading data")
+		}
+	}
+	return errArtifactFileStreamed
+}
+
+func (s *artifactFileStorer) FinishStoreUpdate() error {
+	return nil
+}
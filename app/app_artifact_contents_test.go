@@ -0,0 +1,291 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mendersoftware/mender-artifact/artifact"
+	"github.com/mendersoftware/mender-artifact/awriter"
+	"github.com/mendersoftware/mender-artifact/handlers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store/mocks"
+)
+
+var testArtifactFiles = map[string][]byte{
+	"app.tar":    []byte("application archive"),
+	"config.yml": []byte("key: value\n"),
+}
+
+// writeTestArtifactWithFiles writes an artifact with one payload holding
+// the given files.
+func writeTestArtifactWithFiles(t *testing.T, files map[string][]byte) []byte {
+	dir := t.TempDir()
+	var dataFiles []*handlers.DataFile
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, content, 0600))
+		dataFiles = append(dataFiles, &handlers.DataFile{Name: path})
+	}
+	updateType := "test-update"
+	update := handlers.NewModuleImage(updateType)
+	assert.NoError(t, update.SetUpdateFiles(dataFiles))
+
+	var buf bytes.Buffer
+	writer := awriter.NewWriter(&buf, artifact.NewCompressorNone())
+	err := writer.WriteArtifact(&awriter.WriteArtifactArgs{
+		Format:  "mender",
+		Version: 3,
+		Devices: []string{"device-type"},
+		Name:    "release-1",
+		Updates: &awriter.Updates{
+			Updates: []handlers.Composer{update},
+		},
+		Depends: &artifact.ArtifactDepends{
+			CompatibleDevices: []string{"device-type"},
+		},
+		Provides: &artifact.ArtifactProvides{
+			ArtifactName: "release-1",
+		},
+		TypeInfoV3: &artifact.TypeInfoV3{
+			Type: &updateType,
+		},
+	})
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestGetArtifactContents(t *testing.T) {
+	t.Parallel()
+
+	const imageID = "0b2a0e09-3b0c-4b55-9a4f-6a3c4bb0e7d1"
+	updateType := "test-update"
+	artifact := writeTestArtifactWithFiles(t, testArtifactFiles)
+	errInternal := errors.New("internal error")
+
+	completeUpdates := []model.Update{{
+		TypeInfo: model.ArtifactUpdateTypeInfo{Type: &updateType},
+		Files: []model.UpdateFile{
+			{Name: "app.tar", Checksum: "abc", Size: 19},
+		},
+	}}
+	testCases := map[string]struct {
+		image *model.Image
+		err   error
+
+		parse bool
+
+		contents *model.ArtifactContents
+		outErr   error
+	}{
+		"ok, stored payloads": {
+			image: &model.Image{
+				Id: imageID,
+				ArtifactMeta: &model.ArtifactMeta{
+					Name:    "release-1",
+					Updates: completeUpdates,
+				},
+			},
+			contents: &model.ArtifactContents{
+				ID:       imageID,
+				Name:     "release-1",
+				Payloads: completeUpdates,
+			},
+		},
+		"ok, parsed payloads": {
+			image: &model.Image{
+				Id: imageID,
+				ArtifactMeta: &model.ArtifactMeta{
+					Name: "release-1",
+					Updates: []model.Update{{
+						TypeInfo: model.ArtifactUpdateTypeInfo{Type: &updateType},
+						Files: []model.UpdateFile{
+							{Name: "app.tar"},
+							{Name: "config.yml"},
+						},
+					}},
+				},
+			},
+			parse: true,
+		},
+		"error, not found": {
+			outErr: ErrImageMetaNotFound,
+		},
+		"error, db": {
+			err:    errInternal,
+			outErr: errInternal,
+		},
+	}
+	for name := range testCases {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+
+			db.On("GetStorageSettings", ctx).Return(nil, nil)
+			db.On("FindImageByID", mock.Anything, imageID).Return(tc.image, tc.err)
+			if tc.parse {
+				objStore.On("GetObject", mock.Anything, imageID).
+					Return(io.NopCloser(bytes.NewReader(artifact)), nil)
+			}
+
+			ds := NewDeployments(db, objStore, 0, false)
+			contents, err := ds.GetArtifactContents(ctx, imageID)
+			if tc.outErr != nil {
+				assert.ErrorIs(t, err, tc.outErr)
+				return
+			}
+			assert.NoError(t, err)
+			if !tc.parse {
+				assert.Equal(t, tc.contents, contents)
+				return
+			}
+			if assert.Len(t, contents.Payloads, 1) {
+				payload := contents.Payloads[0]
+				assert.Equal(t, updateType, *payload.TypeInfo.Type)
+				if assert.Len(t, payload.Files, len(testArtifactFiles)) {
+					for _, f := range payload.Files {
+						assert.Equal(t, int64(len(testArtifactFiles[f.Name])), f.Size)
+						assert.Equal(t, sha256Hex(testArtifactFiles[f.Name]), f.Checksum)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetArtifactFile(t *testing.T) {
+	t.Parallel()
+
+	const imageID = "0b2a0e09-3b0c-4b55-9a4f-6a3c4bb0e7d1"
+	artifact := writeTestArtifactWithFiles(t, testArtifactFiles)
+	image := &model.Image{
+		Id: imageID,
+		ArtifactMeta: &model.ArtifactMeta{
+			Name: "release-1",
+			Updates: []model.Update{{
+				Files: []model.UpdateFile{
+					{Name: "app.tar", Checksum: "abc"},
+					{Name: "config.yml"},
+				},
+			}},
+		},
+	}
+
+	testCases := map[string]struct {
+		image    *model.Image
+		name     string
+		artifact []byte
+		objErr   error
+
+		content  []byte
+		checksum string
+		outErr   error
+	}{
+		"ok": {
+			image:    image,
+			name:     "app.tar",
+			artifact: artifact,
+			content:  testArtifactFiles["app.tar"],
+			checksum: "abc",
+		},
+		"ok, no stored payloads": {
+			image: &model.Image{
+				Id:           imageID,
+				ArtifactMeta: &model.ArtifactMeta{Name: "release-1"},
+			},
+			name:     "config.yml",
+			artifact: artifact,
+			content:  testArtifactFiles["config.yml"],
+		},
+		"error, file not in the stored payloads": {
+			image:  image,
+			name:   "other.bin",
+			outErr: ErrArtifactFileNotFound,
+		},
+		"error, file not in the artifact": {
+			image: &model.Image{
+				Id:           imageID,
+				ArtifactMeta: &model.ArtifactMeta{Name: "release-1"},
+			},
+			name:     "other.bin",
+			artifact: artifact,
+			outErr:   ErrArtifactFileNotFound,
+		},
+		"error, image not found": {
+			name:   "app.tar",
+			outErr: ErrImageMetaNotFound,
+		},
+		"error, object not found": {
+			image:  image,
+			name:   "app.tar",
+			objErr: storage.ErrObjectNotFound,
+			outErr: ErrArtifactObjectNotFound,
+		},
+	}
+	for name := range testCases {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+
+			db.On("GetStorageSettings", ctx).Return(nil, nil)
+			db.On("FindImageByID", mock.Anything, imageID).Return(tc.image, nil)
+			if tc.artifact != nil {
+				objStore.On("GetObject", mock.Anything, imageID).
+					Return(io.NopCloser(bytes.NewReader(tc.artifact)), nil)
+			} else if tc.objErr != nil {
+				objStore.On("GetObject", mock.Anything, imageID).
+					Return(nil, tc.objErr)
+			}
+
+			ds := NewDeployments(db, objStore, 0, false)
+			r, file, err := ds.GetArtifactFile(ctx, imageID, tc.name)
+			if tc.outErr != nil {
+				assert.ErrorIs(t, err, tc.outErr)
+				return
+			}
+			assert.NoError(t, err)
+			defer r.Close()
+			content, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.content, content)
+			assert.Equal(t, tc.name, file.Name)
+			assert.Equal(t, int64(len(tc.content)), file.Size)
+			assert.Equal(t, tc.checksum, file.Checksum)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+)
+
+// EditArtifactMeta adds, overrides or removes the provides, depends and
+// clears provides of the artifact and records the change in the history of
+// the artifact. The artifact file is left as is: the depends of edited
+// artifacts are enforced when assigning the artifact to devices, so
+// artifacts used in active deployments cannot be edited.
+func (d *Deployments) EditArtifactMeta(
+	ctx context.Context,
+	id string,
+	edit *model.ArtifactMetaEdit,
+) (*model.Image, error) {
+	if err := edit.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Validating artifact metadata")
+	}
+	image, err := d.db.FindImageByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "Searching for image with specified ID")
+	} else if image == nil {
+		return nil, ErrImageMetaNotFound
+	}
+
+	inUse, err := d.ImageUsedInActiveDeployment(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "Checking if image is used in active deployment")
+	} else if inUse {
+		return nil, ErrArtifactMetaInActiveDeployment
+	}
+
+	change := &model.ArtifactMetaChange{
+		Changed: time.Now(),
+		Before:  image.ArtifactMeta.Dependencies(),
+	}
+	if idty := identity.FromContext(ctx); idty != nil && idty.IsUser {
+		change.UserID = idty.Subject
+	}
+	edit.Apply(image.ArtifactMeta)
+	if err = image.ArtifactMeta.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Validating artifact metadata")
+	}
+	change.After = image.ArtifactMeta.Dependencies()
+
+	err = d.db.UpdateImageArtifactMeta(ctx, id, image.ArtifactMeta, change)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrImageMetaNotFound
+	} else if cErr, ok := err.(*model.ConflictError); ok {
+		return nil, cErr
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Updating artifact metadata")
+	}
+	image.SetModified(change.Changed)
+	image.ArtifactMetaHistory = append(image.ArtifactMetaHistory, *change)
+
+	err = d.db.UpdateReleaseArtifact(ctx, image, image.ArtifactMeta.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update the release")
+	}
+	return image, nil
+}
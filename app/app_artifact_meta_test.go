@@ -0,0 +1,254 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mocks"
+)
+
+func TestEditArtifactMeta(t *testing.T) {
+	t.Parallel()
+
+	const artifactID = "8a5b1f7c-1bde-4b6e-9a57-4b0c4c9b1e0a"
+	version := "2.0"
+	newImage := func() *model.Image {
+		return &model.Image{
+			Id: artifactID,
+			ArtifactMeta: &model.ArtifactMeta{
+				Name:                  "release-1",
+				DeviceTypesCompatible: []string{"rpi4"},
+				Provides: map[string]string{
+					"artifact_name":        "release-1",
+					"rootfs-image.version": "1.0",
+				},
+			},
+		}
+	}
+	edit := &model.ArtifactMetaEdit{
+		Provides: map[string]*string{"rootfs-image.version": &version},
+		Depends:  map[string]interface{}{"artifact_name": "release-0"},
+	}
+	conflict := model.NewConflictError(errors.New("conflicting depends"))
+
+	testCases := []struct {
+		Name string
+
+		Image       *model.Image
+		InUse       bool
+		UpdateError error
+
+		Error error
+	}{{
+		Name:  "ok",
+		Image: newImage(),
+	}, {
+		Name:  "error, artifact not found",
+		Error: ErrImageMetaNotFound,
+	}, {
+		Name:  "error, used in active deployment",
+		Image: newImage(),
+		InUse: true,
+		Error: ErrArtifactMetaInActiveDeployment,
+	}, {
+		Name:        "error, artifact deleted meanwhile",
+		Image:       newImage(),
+		UpdateError: store.ErrNotFound,
+		Error:       ErrImageMetaNotFound,
+	}, {
+		Name:        "error, conflicting depends",
+		Image:       newImage(),
+		UpdateError: conflict,
+		Error:       conflict,
+	}}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := identity.WithContext(context.Background(), &identity.Identity{
+				Subject: "user",
+				IsUser:  true,
+			})
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("FindImageByID", ctx, artifactID).Return(tc.Image, nil)
+			mockImageInActiveDeployment(db, map[string]bool{artifactID: tc.InUse})
+			if tc.Image != nil && !tc.InUse {
+				db.On("UpdateImageArtifactMeta", ctx, artifactID,
+					mock.MatchedBy(func(meta *model.ArtifactMeta) bool {
+						return meta.Provides["rootfs-image.version"] == "2.0" &&
+							meta.Depends["artifact_name"] == "release-0"
+					}),
+					mock.MatchedBy(func(change *model.ArtifactMetaChange) bool {
+						return change.UserID == "user" &&
+							change.Before.Provides["rootfs-image.version"] == "1.0" &&
+							change.After.Provides["rootfs-image.version"] == "2.0"
+					}),
+				).Return(tc.UpdateError)
+			}
+			if tc.Error == nil {
+				db.On("UpdateReleaseArtifact", ctx,
+					mock.AnythingOfType("*model.Image"), "release-1",
+				).Return(nil)
+			}
+
+			d := NewDeployments(db, nil, 0, false)
+			image, err := d.EditArtifactMeta(ctx, artifactID, edit)
+			if tc.Error != nil {
+				assert.ErrorIs(t, err, tc.Error)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Len(t, image.ArtifactMetaHistory, 1)
+				assert.Equal(t, "2.0", image.ArtifactMeta.Provides["rootfs-image.version"])
+			}
+		})
+	}
+}
+
+func TestAssignArtifactEditedDepends(t *testing.T) {
+	t.Parallel()
+
+	deployment := &model.Deployment{
+		Id:        "deployment",
+		Artifacts: []string{"artifact", "other-artifact"},
+	}
+	newImage := func(
+		id string,
+		depends map[string]interface{},
+		history []model.ArtifactMetaChange,
+	) *model.Image {
+		return &model.Image{
+			Id: id,
+			ArtifactMeta: &model.ArtifactMeta{
+				Name:                  "release-1",
+				DeviceTypesCompatible: []string{"rpi4"},
+				Depends:               depends,
+			},
+			ArtifactMetaHistory: history,
+		}
+	}
+	edited := []model.ArtifactMetaChange{{UserID: "user"}}
+	dependsRelease0 := map[string]interface{}{
+		"device_type":   []string{"rpi4"},
+		"artifact_name": "release-0",
+	}
+	dependsRootfs := map[string]interface{}{
+		"device_type":           []string{"rpi4"},
+		"rootfs-image.checksum": "checksum",
+	}
+
+	testCases := []struct {
+		Name string
+
+		Image        *model.Image
+		Candidates   []*model.Image
+		ArtifactName string
+		Provides     map[string]string
+
+		Assigned *model.Image
+	}{{
+		Name:         "ok, edited depends satisfied",
+		Image:        newImage("artifact", dependsRelease0, edited),
+		ArtifactName: "release-0",
+		Assigned:     newImage("artifact", dependsRelease0, edited),
+	}, {
+		Name:         "ok, depends not edited, checked by the device",
+		Image:        newImage("artifact", dependsRelease0, nil),
+		ArtifactName: "release-2",
+		Assigned:     newImage("artifact", dependsRelease0, nil),
+	}, {
+		Name:         "ok, another artifact satisfies the depends",
+		Image:        newImage("artifact", dependsRelease0, edited),
+		ArtifactName: "release-2",
+		Provides:     map[string]string{"rootfs-image.checksum": "checksum"},
+		Candidates: []*model.Image{
+			newImage("artifact", dependsRelease0, edited),
+			newImage("other-artifact", dependsRootfs, edited),
+		},
+		Assigned: newImage("other-artifact", dependsRootfs, edited),
+	}, {
+		Name:         "no artifact, edited depends not satisfied",
+		Image:        newImage("artifact", dependsRelease0, edited),
+		ArtifactName: "release-2",
+		Candidates: []*model.Image{
+			newImage("artifact", dependsRelease0, edited),
+		},
+	}, {
+		Name:         "no artifact, v1 client reporting no provides",
+		Image:        newImage("artifact", dependsRootfs, edited),
+		ArtifactName: "release-0",
+		Candidates: []*model.Image{
+			newImage("artifact", dependsRootfs, edited),
+			newImage("other-artifact", dependsRootfs, edited),
+		},
+	}}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			deviceDeployment := &model.DeviceDeployment{
+				Id:           "device-deployment",
+				DeploymentId: deployment.Id,
+				DeviceId:     "device",
+			}
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("ImageByIdsAndDeviceType", ctx, deployment.Artifacts, "rpi4").
+				Return(tc.Image, nil)
+			if tc.Candidates != nil {
+				db.On("ImagesByIdsAndDeviceType", ctx, deployment.Artifacts, "rpi4").
+					Return(tc.Candidates, nil)
+			}
+			if tc.Assigned != nil {
+				db.On("AssignArtifact", ctx, "device", deployment.Id, tc.Assigned).
+					Return(nil)
+			} else {
+				// the no-artifact status is set on the device deployment
+				db.On("GetDeviceDeployment", ctx, deployment.Id, "device", false).
+					Return(&model.DeviceDeployment{
+						Status: model.DeviceDeploymentStatusAborted,
+					}, nil)
+			}
+
+			d := NewDeployments(db, nil, 0, false)
+			err := d.assignArtifact(ctx, deployment, deviceDeployment,
+				&model.InstalledDeviceDeployment{
+					ArtifactName: tc.ArtifactName,
+					DeviceType:   "rpi4",
+					Provides:     tc.Provides,
+				})
+			if tc.Assigned != nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.Assigned, deviceDeployment.Image)
+			} else {
+				assert.ErrorIs(t, err, ErrDeploymentAborted)
+				assert.Nil(t, deviceDeployment.Image)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"path"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	"github.com/mendersoftware/deployments/store"
+)
+
+// artifactObjectID returns the ID of the artifact object storing the
+// artifacts with the given checksum; tenants with their own storage only
+// share objects between their own artifacts.
+func artifactObjectID(ctx context.Context, checksum string) string {
+	if settings, _ := storage.SettingsFromContext(ctx); settings != nil {
+		if idty := identity.FromContext(ctx); idty != nil && idty.Tenant != "" {
+			return idty.Tenant + "/" + checksum
+		}
+	}
+	return checksum
+}
+
+// artifactObjectPath returns the path of the file of the artifact object
+// with the given ID; it is addressed by content and not by the tenants
+// sharing it.
+func artifactObjectPath(id string) string {
+	return path.Join("objects", id)
+}
+
+// shareArtifactObject moves the file of the new image to the artifact
+// object with the same content, storing the file of the object first if
+// it does not exist yet. The image then points to the file of the object;
+// if the object cannot be shared the image keeps its own file.
+func (d *Deployments) shareArtifactObject(ctx context.Context, image *model.Image) {
+	if image.Checksum == "" {
+		return
+	}
+	l := log.FromContext(ctx)
+	imagePath := model.ImagePathFromContext(ctx, image.Id)
+	id := artifactObjectID(ctx, image.Checksum)
+	objectPath := artifactObjectPath(id)
+	if _, err := d.objectStorage.StatObject(ctx, objectPath); err != nil {
+		if err = d.copyObject(ctx, imagePath, objectPath); err != nil {
+			l.Warnf("failed to share the file of artifact %s: %s", image.Id, err)
+			return
+		}
+	}
+	obj, err := d.db.AddArtifactObjectReference(
+		ctx, id, objectPath, image.Size, image.Id,
+	)
+	if err != nil {
+		l.Warnf("failed to share the file of artifact %s: %s", image.Id, err)
+		return
+	}
+	image.ObjectID = id
+	image.ObjectPath = obj.Path
+	if err := d.objectStorage.DeleteObject(ctx, imagePath); err != nil {
+		l.Errorf("failed to delete the duplicate file of artifact %s: %s",
+			image.Id, err)
+	}
+}
+
+// copyObject copies the file at srcPath to dstPath in the storage.
+func (d *Deployments) copyObject(ctx context.Context, srcPath, dstPath string) error {
+	r, err := d.objectStorage.GetObject(ctx, srcPath)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get the object")
+	}
+	defer r.Close()
+	return errors.WithMessage(
+		d.objectStorage.PutObject(ctx, dstPath, r),
+		"failed to put the object",
+	)
+}
+
+// deleteArtifactFile deletes the file of the image, unless other images
+// share it.
+func (d *Deployments) deleteArtifactFile(ctx context.Context, image *model.Image) error {
+	path := image.ObjectPathFromContext(ctx)
+	if image.ObjectID != "" {
+		obj, err := d.db.RemoveArtifactObjectReference(ctx, image.ObjectID, image.Id)
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "failed to remove the artifact object reference")
+		} else if obj.RefCount > 0 {
+			return nil
+		}
+		err = d.db.DeleteArtifactObject(ctx, image.ObjectID)
+		if errors.Is(err, store.ErrNotFound) {
+			// shared again in the meantime
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "failed to delete the artifact object")
+		}
+		path = obj.Path
+	}
+	return d.objectStorage.DeleteObject(ctx, path)
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mocks"
+)
+
+func TestArtifactObjectID(t *testing.T) {
+	t.Parallel()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "tenant",
+	})
+	assert.Equal(t, "checksum", artifactObjectID(ctx, "checksum"))
+	ctx = storage.SettingsWithContext(ctx, &model.StorageSettings{Bucket: "bucket"})
+	assert.Equal(t, "tenant/checksum", artifactObjectID(ctx, "checksum"))
+}
+
+func TestShareArtifactObject(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name string
+
+		ObjectExists bool
+		CopyError    error
+		Object       *model.ArtifactObject
+		Error        error
+
+		ObjectID   string
+		ObjectPath string
+	}{{
+		Name: "ok, new object",
+
+		Object: &model.ArtifactObject{
+			ID:       "checksum",
+			Path:     "objects/checksum",
+			RefCount: 1,
+		},
+		ObjectID:   "checksum",
+		ObjectPath: "objects/checksum",
+	}, {
+		Name: "ok, shared object",
+
+		ObjectExists: true,
+		Object: &model.ArtifactObject{
+			ID:       "checksum",
+			Path:     "objects/checksum",
+			RefCount: 2,
+		},
+		ObjectID:   "checksum",
+		ObjectPath: "objects/checksum",
+	}, {
+		Name: "ok, shared object stored before the neutral paths",
+
+		ObjectExists: true,
+		Object: &model.ArtifactObject{
+			ID:       "checksum",
+			Path:     "other/image",
+			RefCount: 2,
+		},
+		ObjectID:   "checksum",
+		ObjectPath: "other/image",
+	}, {
+		Name: "error, copy fails and keeps own file",
+
+		CopyError: errors.New("internal error"),
+	}, {
+		Name: "error, keeps own file",
+
+		ObjectExists: true,
+		Error:        errors.New("internal error"),
+	}}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := identity.WithContext(context.Background(), &identity.Identity{
+				Tenant: "tenant",
+			})
+			image := &model.Image{Id: "image", Checksum: "checksum", Size: 10}
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+			if tc.ObjectExists {
+				objStore.On("StatObject", ctx, "objects/checksum").
+					Return(&storage.ObjectInfo{}, nil)
+			} else {
+				objStore.On("StatObject", ctx, "objects/checksum").
+					Return(nil, errors.New("not found"))
+				objStore.On("GetObject", ctx, "tenant/image").
+					Return(io.NopCloser(strings.NewReader("artifact")), nil)
+				objStore.On("PutObject", ctx, "objects/checksum", mock.Anything).
+					Return(tc.CopyError)
+			}
+			if tc.CopyError == nil {
+				db.On("AddArtifactObjectReference", ctx,
+					"checksum", "objects/checksum", int64(10), "image").
+					Return(tc.Object, tc.Error)
+			}
+			if tc.ObjectID != "" {
+				objStore.On("DeleteObject", ctx, "tenant/image").
+					Return(errors.New("ignored"))
+			}
+
+			d := NewDeployments(db, objStore, 0, false)
+			d.shareArtifactObject(ctx, image)
+			assert.Equal(t, tc.ObjectID, image.ObjectID)
+			assert.Equal(t, tc.ObjectPath, image.ObjectPath)
+		})
+	}
+}
+
+func TestDeleteArtifactFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name string
+
+		Image *model.Image
+
+		RemoveObject *model.ArtifactObject
+		RemoveError  error
+		DeleteError  error
+
+		DeletedPath string
+		Error       error
+	}{{
+		Name: "ok, own file",
+
+		Image:       &model.Image{Id: "image"},
+		DeletedPath: "tenant/image",
+	}, {
+		Name: "ok, still referenced",
+
+		Image: &model.Image{
+			Id:         "image",
+			ObjectID:   "checksum",
+			ObjectPath: "other/image",
+		},
+		RemoveObject: &model.ArtifactObject{
+			ID:       "checksum",
+			Path:     "other/image",
+			RefCount: 1,
+		},
+	}, {
+		Name: "ok, last reference",
+
+		Image: &model.Image{
+			Id:         "image",
+			ObjectID:   "checksum",
+			ObjectPath: "other/image",
+		},
+		RemoveObject: &model.ArtifactObject{ID: "checksum", Path: "other/image"},
+		DeletedPath:  "other/image",
+	}, {
+		Name: "ok, referenced again",
+
+		Image: &model.Image{Id: "image", ObjectID: "checksum"},
+		RemoveObject: &model.ArtifactObject{
+			ID:   "checksum",
+			Path: "tenant/image",
+		},
+		DeleteError: store.ErrNotFound,
+	}, {
+		Name: "ok, reference already removed",
+
+		Image:       &model.Image{Id: "image", ObjectID: "checksum"},
+		RemoveError: store.ErrNotFound,
+	}, {
+		Name: "error removing the reference",
+
+		Image:       &model.Image{Id: "image", ObjectID: "checksum"},
+		RemoveError: errors.New("internal error"),
+		Error:       errors.New("failed to remove the artifact object reference: internal error"),
+	}}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := identity.WithContext(context.Background(), &identity.Identity{
+				Tenant: "tenant",
+			})
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			if tc.Image.ObjectID != "" {
+				db.On("RemoveArtifactObjectReference", ctx, tc.Image.ObjectID, tc.Image.Id).
+					Return(tc.RemoveObject, tc.RemoveError)
+			}
+			if tc.RemoveObject != nil && tc.RemoveObject.RefCount == 0 {
+				db.On("DeleteArtifactObject", ctx, tc.Image.ObjectID).
+					Return(tc.DeleteError)
+			}
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+			if tc.DeletedPath != "" {
+				objStore.On("DeleteObject", ctx, tc.DeletedPath).Return(nil)
+			}
+
+			d := NewDeployments(db, objStore, 0, false)
+			err := d.deleteArtifactFile(ctx, tc.Image)
+			if tc.Error != nil {
+				assert.EqualError(t, err, tc.Error.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	"github.com/mendersoftware/deployments/store"
+)
+
+var (
+	ErrChunkedUploadNotFound   = errors.New("chunked upload not found")
+	ErrChunkOffsetMismatch     = errors.New("chunk offset does not match the upload offset")
+	ErrChunkSize               = errors.New("invalid chunk size")
+	ErrChunkedUploadIncomplete = errors.New("chunked upload is incomplete")
+)
+
+// chunkWriteTimeout bounds the time a chunk is written for, which is also
+// the time the upload is locked for by the chunk.
+const chunkWriteTimeout = 15 * time.Minute
+
+func chunkedUploadPath(ctx context.Context, id string) string {
+	return model.ImagePathFromContext(ctx, id) + fileSuffixTmp
+}
+
+// CreateChunkedUpload starts a resumable upload of an artifact of the given
+// length, received in chunks through UploadChunk.
+func (d *Deployments) CreateChunkedUpload(
+	ctx context.Context,
+	length int64,
+	expire time.Duration,
+) (*model.UploadLink, error) {
+	if length <= 0 {
+		return nil, ErrChunkSize
+	}
+	ctx, err := d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.checkStorageLimit(ctx, length); err != nil {
+		return nil, err
+	}
+
+	artifactID := uuid.New().String()
+	uploadID, err := d.objectStorage.InitChunkedUpload(
+		ctx, chunkedUploadPath(ctx, artifactID),
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "app: failed to start the chunked upload")
+	}
+	now := time.Now()
+	upLink := &model.UploadLink{
+		ArtifactID: artifactID,
+		Link: model.Link{
+			Expire: now.Add(expire),
+		},
+		IssuedAt:  now,
+		UpdatedTS: now,
+		Status:    model.LinkStatusPending,
+		Chunked: &model.ChunkedUpload{
+			UploadID: uploadID,
+			Length:   length,
+		},
+	}
+	err = d.db.InsertUploadIntent(ctx, upLink)
+	if err != nil {
+		return nil, errors.WithMessage(err, "app: error recording the upload intent")
+	}
+	return upLink, nil
+}
+
+// GetChunkedUpload returns a pending chunked upload which has not expired.
+func (d *Deployments) GetChunkedUpload(
+	ctx context.Context,
+	id string,
+) (*model.UploadLink, error) {
+	link, err := d.db.FindUploadLinkByID(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrChunkedUploadNotFound
+	} else if err != nil {
+		return nil, errors.WithMessage(err, "app: failed to get the upload intent")
+	}
+	if link.Chunked == nil ||
+		link.Status != model.LinkStatusPending ||
+		link.Expire.Before(time.Now()) {
+		return nil, ErrChunkedUploadNotFound
+	}
+	return link, nil
+}
+
+// UploadChunk stores the next chunk of a chunked upload, starting at the
+// given offset, and returns the offset of the upload after the chunk.
+func (d *Deployments) UploadChunk(
+	ctx context.Context,
+	id string,
+	offset int64,
+	chunk io.Reader,
+	size int64,
+) (int64, error) {
+	link, err := d.GetChunkedUpload(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	upload := link.Chunked
+	if offset != upload.Offset {
+		return upload.Offset, ErrChunkOffsetMismatch
+	}
+	// all chunks but the last one must be at least MinChunkSize
+	last := offset+size == upload.Length
+	if size <= 0 || size > storage.MaxChunkSize ||
+		offset+size > upload.Length ||
+		(size < storage.MinChunkSize && !last) ||
+		upload.Chunks >= storage.MaxChunks {
+		return upload.Offset, ErrChunkSize
+	}
+
+	ctx, err = d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return upload.Offset, err
+	}
+	// the chunk is stored as the part numbered after the chunks received
+	// so far: concurrent chunks at the same offset would write the same
+	// part, so only the chunk holding the lock of the upload is written
+	lock := model.ChunkLock{
+		ID:     uuid.New().String(),
+		Expire: time.Now().Add(chunkWriteTimeout),
+	}
+	err = d.db.LockUploadIntentChunk(ctx, id, offset, lock)
+	if errors.Is(err, store.ErrNotFound) {
+		return upload.Offset, ErrChunkOffsetMismatch
+	} else if err != nil {
+		return upload.Offset, errors.WithMessage(err,
+			"app: failed to lock the upload intent")
+	}
+	ctxWrite, cancel := context.WithDeadline(ctx, lock.Expire)
+	defer cancel()
+	err = d.objectStorage.PutChunk(
+		ctxWrite, chunkedUploadPath(ctx, id), upload.UploadID, upload.Chunks, chunk, size,
+	)
+	if err != nil {
+		if errUnlock := d.db.UnlockUploadIntentChunk(ctx, id, lock.ID); errUnlock != nil {
+			log.FromContext(ctx).
+				Warnf("failed to unlock the upload intent %s: %s", id, errUnlock)
+		}
+		return upload.Offset, errors.WithMessage(err, "app: failed to store the chunk")
+	}
+	err = d.db.UpdateUploadIntentChunk(ctx, id, lock.ID, offset, size)
+	if errors.Is(err, store.ErrNotFound) {
+		// the lock expired and another chunk was received at the same
+		// offset in the meantime
+		return upload.Offset, ErrChunkOffsetMismatch
+	} else if err != nil {
+		return upload.Offset, errors.WithMessage(err,
+			"app: failed to update the upload intent")
+	}
+	return offset + size, nil
+}
+
+// CompleteChunkedUpload assembles the chunks of a fully received chunked
+// upload and processes the artifact like CompleteUpload.
+func (d *Deployments) CompleteChunkedUpload(ctx context.Context, id string) error {
+	link, err := d.GetChunkedUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+	upload := link.Chunked
+	if upload.Offset < upload.Length {
+		return ErrChunkedUploadIncomplete
+	}
+	ctx, err = d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if err = d.checkStorageLimit(ctx, upload.Length); err != nil {
+		return err
+	}
+	err = d.objectStorage.CompleteChunkedUpload(
+		ctx, chunkedUploadPath(ctx, id), upload.UploadID, upload.Chunks,
+	)
+	if err != nil {
+		return errors.WithMessage(err, "app: failed to assemble the chunks")
+	}
+	return d.CompleteUpload(ctx, id, false, nil)
+}
@@ -0,0 +1,364 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mocks"
+	"github.com/mendersoftware/deployments/store/mongo"
+)
+
+const chunkedUploadID = "0b2a0e09-3b0c-4b55-9a4f-6a3c4bb0e7d1"
+
+func chunkedUploadLink(offset, length int64, chunks int) *model.UploadLink {
+	return &model.UploadLink{
+		ArtifactID: chunkedUploadID,
+		Link: model.Link{
+			Expire: time.Now().Add(time.Hour),
+		},
+		Status: model.LinkStatusPending,
+		Chunked: &model.ChunkedUpload{
+			UploadID: "upload",
+			Length:   length,
+			Offset:   offset,
+			Chunks:   chunks,
+		},
+	}
+}
+
+func TestCreateChunkedUpload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		db := &mocks.DataStore{}
+		defer db.AssertExpectations(t)
+		objStore := &fs_mocks.ObjectStorage{}
+		defer objStore.AssertExpectations(t)
+
+		db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+		db.On("GetLimit", mock.Anything, model.LimitStorage).
+			Return(nil, mongo.ErrLimitNotFound)
+		objStore.On("InitChunkedUpload", mock.Anything,
+			mock.MatchedBy(func(path string) bool {
+				return strings.HasSuffix(path, fileSuffixTmp)
+			})).Return("upload", nil)
+		db.On("InsertUploadIntent", mock.Anything,
+			mock.MatchedBy(func(link *model.UploadLink) bool {
+				return link.Status == model.LinkStatusPending &&
+					link.Chunked != nil &&
+					link.Chunked.UploadID == "upload" &&
+					link.Chunked.Length == 1024 &&
+					link.Chunked.Offset == 0
+			})).Return(nil)
+
+		d := NewDeployments(db, objStore, 0, false)
+		link, err := d.CreateChunkedUpload(context.Background(), 1024, time.Hour)
+		if assert.NoError(t, err) {
+			assert.WithinDuration(t, time.Now().Add(time.Hour), link.Expire, time.Minute)
+		}
+	})
+
+	t.Run("error, storage limit exceeded", func(t *testing.T) {
+		db := &mocks.DataStore{}
+		defer db.AssertExpectations(t)
+
+		db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+		db.On("GetLimit", mock.Anything, model.LimitStorage).
+			Return(&model.Limit{Name: model.LimitStorage, Value: 1000}, nil)
+		db.On("GetStorageUsage", mock.Anything).Return(int64(100), nil)
+
+		d := NewDeployments(db, nil, 0, false)
+		_, err := d.CreateChunkedUpload(context.Background(), 1024, time.Hour)
+		assert.ErrorIs(t, err, ErrStorageLimitExceeded)
+	})
+
+	t.Run("error, invalid length", func(t *testing.T) {
+		d := NewDeployments(nil, nil, 0, false)
+		_, err := d.CreateChunkedUpload(context.Background(), 0, time.Hour)
+		assert.ErrorIs(t, err, ErrChunkSize)
+	})
+}
+
+func TestGetChunkedUpload(t *testing.T) {
+	t.Parallel()
+
+	expired := chunkedUploadLink(0, 1024, 0)
+	expired.Expire = time.Now().Add(-time.Minute)
+	processing := chunkedUploadLink(1024, 1024, 1)
+	processing.Status = model.LinkStatusProcessing
+	direct := chunkedUploadLink(0, 1024, 0)
+	direct.Chunked = nil
+
+	testCases := []struct {
+		Name string
+
+		Link  *model.UploadLink
+		DBErr error
+
+		Error error
+	}{{
+		Name: "ok",
+		Link: chunkedUploadLink(0, 1024, 0),
+	}, {
+		Name:  "error, not found",
+		DBErr: store.ErrNotFound,
+		Error: ErrChunkedUploadNotFound,
+	}, {
+		Name:  "error, expired",
+		Link:  expired,
+		Error: ErrChunkedUploadNotFound,
+	}, {
+		Name:  "error, not pending",
+		Link:  processing,
+		Error: ErrChunkedUploadNotFound,
+	}, {
+		Name:  "error, not chunked",
+		Link:  direct,
+		Error: ErrChunkedUploadNotFound,
+	}}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("FindUploadLinkByID", mock.Anything, chunkedUploadID).
+				Return(tc.Link, tc.DBErr)
+
+			d := NewDeployments(db, nil, 0, false)
+			link, err := d.GetChunkedUpload(context.Background(), chunkedUploadID)
+			if tc.Error != nil {
+				assert.ErrorIs(t, err, tc.Error)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.Link, link)
+			}
+		})
+	}
+}
+
+func TestUploadChunk(t *testing.T) {
+	t.Parallel()
+
+	const length = 2*storage.MinChunkSize + 10
+
+	testCases := []struct {
+		Name string
+
+		Link   *model.UploadLink
+		Offset int64
+		Size   int64
+
+		Stored      bool
+		LockErr     error
+		PutChunkErr error
+		UpdateErr   error
+
+		NewOffset int64
+		Error     error
+	}{{
+		Name:      "ok, first chunk",
+		Link:      chunkedUploadLink(0, length, 0),
+		Offset:    0,
+		Size:      storage.MinChunkSize,
+		Stored:    true,
+		NewOffset: storage.MinChunkSize,
+	}, {
+		Name:      "ok, small last chunk",
+		Link:      chunkedUploadLink(2*storage.MinChunkSize, length, 2),
+		Offset:    2 * storage.MinChunkSize,
+		Size:      10,
+		Stored:    true,
+		NewOffset: length,
+	}, {
+		Name:      "error, offset mismatch",
+		Link:      chunkedUploadLink(storage.MinChunkSize, length, 1),
+		Offset:    0,
+		Size:      storage.MinChunkSize,
+		NewOffset: storage.MinChunkSize,
+		Error:     ErrChunkOffsetMismatch,
+	}, {
+		Name:      "error, small chunk",
+		Link:      chunkedUploadLink(0, length, 0),
+		Offset:    0,
+		Size:      10,
+		NewOffset: 0,
+		Error:     ErrChunkSize,
+	}, {
+		Name:      "error, chunk exceeds the length",
+		Link:      chunkedUploadLink(2*storage.MinChunkSize, length, 2),
+		Offset:    2 * storage.MinChunkSize,
+		Size:      storage.MinChunkSize,
+		NewOffset: 2 * storage.MinChunkSize,
+		Error:     ErrChunkSize,
+	}, {
+		Name:      "error, concurrent chunk",
+		Link:      chunkedUploadLink(0, length, 0),
+		Offset:    0,
+		Size:      storage.MinChunkSize,
+		Stored:    true,
+		UpdateErr: store.ErrNotFound,
+		NewOffset: 0,
+		Error:     ErrChunkOffsetMismatch,
+	}, {
+		Name:      "error, chunk being written",
+		Link:      chunkedUploadLink(0, length, 0),
+		Offset:    0,
+		Size:      storage.MinChunkSize,
+		LockErr:   store.ErrNotFound,
+		NewOffset: 0,
+		Error:     ErrChunkOffsetMismatch,
+	}, {
+		Name:        "error, storage",
+		Link:        chunkedUploadLink(0, length, 0),
+		Offset:      0,
+		Size:        storage.MinChunkSize,
+		Stored:      true,
+		PutChunkErr: errors.New("internal error"),
+		NewOffset:   0,
+		Error:       errors.New("app: failed to store the chunk: internal error"),
+	}}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+
+			db.On("FindUploadLinkByID", mock.Anything, chunkedUploadID).
+				Return(tc.Link, nil)
+			var lockID string
+			if tc.Stored || tc.LockErr != nil {
+				db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+				db.On("LockUploadIntentChunk", mock.Anything,
+					chunkedUploadID, tc.Offset,
+					mock.MatchedBy(func(lock model.ChunkLock) bool {
+						lockID = lock.ID
+						return lock.ID != "" && lock.Expire.After(time.Now())
+					}),
+				).Return(tc.LockErr)
+			}
+			if tc.Stored {
+				objStore.On("PutChunk",
+					mock.MatchedBy(func(ctx context.Context) bool {
+						_, ok := ctx.Deadline()
+						return ok
+					}),
+					chunkedUploadID+fileSuffixTmp,
+					"upload",
+					tc.Link.Chunked.Chunks,
+					mock.Anything,
+					tc.Size,
+				).Return(tc.PutChunkErr)
+				if tc.PutChunkErr == nil {
+					db.On("UpdateUploadIntentChunk", mock.Anything,
+						chunkedUploadID,
+						mock.MatchedBy(func(id string) bool { return id == lockID }),
+						tc.Offset, tc.Size,
+					).Return(tc.UpdateErr)
+				} else {
+					db.On("UnlockUploadIntentChunk", mock.Anything,
+						chunkedUploadID,
+						mock.MatchedBy(func(id string) bool { return id == lockID }),
+					).Return(nil)
+				}
+			}
+
+			d := NewDeployments(db, objStore, 0, false)
+			offset, err := d.UploadChunk(
+				context.Background(), chunkedUploadID, tc.Offset,
+				strings.NewReader("chunk"), tc.Size,
+			)
+			if tc.Error != nil {
+				assert.EqualError(t, err, tc.Error.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.NewOffset, offset)
+		})
+	}
+}
+
+func TestCompleteChunkedUpload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error, incomplete", func(t *testing.T) {
+		db := &mocks.DataStore{}
+		defer db.AssertExpectations(t)
+		db.On("FindUploadLinkByID", mock.Anything, chunkedUploadID).
+			Return(chunkedUploadLink(512, 1024, 1), nil)
+
+		d := NewDeployments(db, nil, 0, false)
+		err := d.CompleteChunkedUpload(context.Background(), chunkedUploadID)
+		assert.ErrorIs(t, err, ErrChunkedUploadIncomplete)
+	})
+
+	t.Run("error, assembled object not found", func(t *testing.T) {
+		db := &mocks.DataStore{}
+		defer db.AssertExpectations(t)
+		objStore := &fs_mocks.ObjectStorage{}
+		defer objStore.AssertExpectations(t)
+
+		db.On("FindUploadLinkByID", mock.Anything, chunkedUploadID).
+			Return(chunkedUploadLink(1024, 1024, 1), nil)
+		db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+		db.On("GetLimit", mock.Anything, model.LimitStorage).
+			Return(nil, mongo.ErrLimitNotFound)
+		objStore.On("CompleteChunkedUpload", mock.Anything,
+			chunkedUploadID+fileSuffixTmp, "upload", 1).
+			Return(nil)
+		objStore.On("GetObject", mock.Anything, chunkedUploadID+fileSuffixTmp).
+			Return(nil, storage.ErrObjectNotFound)
+
+		d := NewDeployments(db, objStore, 0, false)
+		err := d.CompleteChunkedUpload(context.Background(), chunkedUploadID)
+		assert.ErrorIs(t, err, ErrUploadNotFound)
+	})
+
+	t.Run("error, storage", func(t *testing.T) {
+		db := &mocks.DataStore{}
+		defer db.AssertExpectations(t)
+		objStore := &fs_mocks.ObjectStorage{}
+		defer objStore.AssertExpectations(t)
+
+		db.On("FindUploadLinkByID", mock.Anything, chunkedUploadID).
+			Return(chunkedUploadLink(1024, 1024, 1), nil)
+		db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+		db.On("GetLimit", mock.Anything, model.LimitStorage).
+			Return(nil, mongo.ErrLimitNotFound)
+		objStore.On("CompleteChunkedUpload", mock.Anything,
+			chunkedUploadID+fileSuffixTmp, "upload", 1).
+			Return(errors.New("internal error"))
+
+		d := NewDeployments(db, objStore, 0, false)
+		err := d.CompleteChunkedUpload(context.Background(), chunkedUploadID)
+		assert.EqualError(t, err, "app: failed to assemble the chunks: internal error")
+	})
+}
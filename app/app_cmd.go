@@ -19,11 +19,21 @@ import (
 	"path"
 	"time"
 
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/mendersoftware/go-lib-micro/log"
+	mstore "github.com/mendersoftware/go-lib-micro/store"
+	"github.com/pkg/errors"
+
 	"github.com/mendersoftware/deployments/model"
 	"github.com/mendersoftware/deployments/storage"
 	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mongo"
 )
 
+// DeviceDeploymentSubStateTimeout is the substate set on device deployments
+// failed for staying in an active status for too long.
+const DeviceDeploymentSubStateTimeout = "timeout"
+
 func (d *Deployments) cleanupExpiredLink(
 	ctx context.Context,
 	link model.UploadLink,
@@ -52,6 +62,14 @@ func (d *Deployments) cleanupExpiredLink(
 		if link.TenantID != "" {
 			objectPath = path.Join(link.TenantID, objectPath)
 		}
+		if link.Chunked != nil && link.Status == model.LinkStatusPending {
+			err = d.objectStorage.AbortChunkedUpload(
+				ctx, objectPath, link.Chunked.UploadID,
+			)
+			if err != nil && err != storage.ErrObjectNotFound {
+				break
+			}
+		}
 		err = d.objectStorage.DeleteObject(ctx, objectPath)
 		if err != nil && err != storage.ErrObjectNotFound {
 			break
@@ -71,6 +89,9 @@ func (d *Deployments) cleanupExpiredLink(
 	return err
 }
 
+// CleanupExpiredUploads removes the temporary objects of the expired upload
+// links and marks the interrupted artifact imports as failed, every
+// `interval`; a zero interval runs the cleanup once.
 func (d *Deployments) CleanupExpiredUploads(
 	ctx context.Context, interval, jitter time.Duration,
 ) error {
@@ -118,6 +139,121 @@ func (d *Deployments) CleanupExpiredUploads(
 		}
 		err = it.Close(ctx)
 		it = nil
+		if err != nil {
+			break
+		}
+		err = d.failStaleImportJobs(ctx, now)
+		if err != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+
+		case _, run = <-tc:
+		}
+	}
+	return err
+}
+
+func (d *Deployments) timeoutStuckDeviceDeployments(
+	ctx context.Context,
+	timeouts map[model.DeviceDeploymentStatus]time.Duration,
+	now time.Time,
+) error {
+	l := log.FromContext(ctx)
+	for _, status := range model.ActiveDeploymentStatuses() {
+		timeout := timeouts[status]
+		if timeout <= 0 {
+			continue
+		}
+		it, err := d.db.FindStuckDeviceDeployments(ctx, status, now.Add(-timeout))
+		if err != nil {
+			return errors.Wrapf(err,
+				"failed to find device deployments stuck in status %s", status)
+		}
+		for {
+			var (
+				next bool
+				dd   model.DeviceDeployment
+			)
+			next, err = it.Next(ctx)
+			if !next || err != nil {
+				break
+			}
+			if err = it.Decode(&dd); err != nil {
+				break
+			}
+			l.Infof("device %s stuck in status %s of deployment %s for more than %s; "+
+				"failing the deployment", dd.DeviceId, status, dd.DeploymentId, timeout)
+			errUpdate := d.UpdateDeviceDeploymentStatus(ctx, dd.DeploymentId, dd.DeviceId,
+				model.DeviceDeploymentState{
+					Status:   model.DeviceDeploymentStatusFailure,
+					SubState: DeviceDeploymentSubStateTimeout,
+				})
+			switch errUpdate {
+			case nil, ErrStorageNotFound, ErrDeploymentAborted, ErrDeviceDecommissioned:
+				// the device deployment might have finished in the meantime
+			default:
+				l.Errorf("failed to time out device %s in deployment %s: %s",
+					dd.DeviceId, dd.DeploymentId, errUpdate.Error())
+			}
+		}
+		if errClose := it.Close(ctx); err == nil {
+			err = errClose
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TimeoutStuckDeviceDeployments marks as failed the device deployments which
+// stayed in an active status for longer than the timeout configured for the
+// status; statuses without a timeout are left alone. The device deployments
+// of all the tenants are checked every `interval`, a zero interval runs the
+// check once.
+func (d *Deployments) TimeoutStuckDeviceDeployments(
+	ctx context.Context,
+	timeouts map[model.DeviceDeploymentStatus]time.Duration,
+	interval time.Duration,
+) error {
+	return runPeriodically(ctx, interval, func() error {
+		now := time.Now()
+		return d.forEachTenant(ctx, "",
+			func(ctx context.Context, _, _ string) error {
+				return d.timeoutStuckDeviceDeployments(ctx, timeouts, now)
+			})
+	})
+}
+
+// runPeriodically calls fn right away and then every `interval`, until fn
+// fails or ctx is done; a zero interval calls fn once.
+func runPeriodically(
+	ctx context.Context,
+	interval time.Duration,
+	fn func() error,
+) error {
+	var (
+		err error
+		tc  <-chan time.Time
+		run bool = true
+	)
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tc = ticker.C
+	} else {
+		c := make(chan time.Time)
+		close(c)
+		tc = c
+	}
+
+	for run && err == nil {
+		if err = fn(); err != nil {
+			break
+		}
 		select {
 		case <-ctx.Done():
 			err = ctx.Err()
@@ -127,3 +263,38 @@ func (d *Deployments) CleanupExpiredUploads(
 	}
 	return err
 }
+
+// forEachTenant calls fn with the context of each tenant, or of the given
+// tenant only, along with the ID of the tenant and the name of its DB; the
+// tenant ID is empty for the default DB. It stops at the first error.
+func (d *Deployments) forEachTenant(
+	ctx context.Context,
+	tenantID string,
+	fn func(ctx context.Context, tenantID, db string) error,
+) error {
+	var dbs []string
+	if tenantID != "" {
+		dbs = []string{mstore.DbNameForTenant(tenantID, mongo.DbName)}
+	} else {
+		var err error
+		dbs, err = d.db.GetTenantDbs()
+		if err != nil {
+			return errors.Wrap(err, "failed to retrieve tenant DBs")
+		} else if len(dbs) == 0 {
+			dbs = []string{mongo.DbName}
+		}
+	}
+	for _, db := range dbs {
+		tenantCtx := ctx
+		tenant := mstore.TenantFromDbName(db, mongo.DbName)
+		if tenant != "" {
+			tenantCtx = identity.WithContext(ctx, &identity.Identity{
+				Tenant: tenant,
+			})
+		}
+		if err := fn(tenantCtx, tenant, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,11 +21,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mendersoftware/go-lib-micro/identity"
+	ctxstore "github.com/mendersoftware/go-lib-micro/store"
+
 	"github.com/mendersoftware/deployments/model"
 	"github.com/mendersoftware/deployments/storage"
 	mstorage "github.com/mendersoftware/deployments/storage/mocks"
 	"github.com/mendersoftware/deployments/store"
 	mstore "github.com/mendersoftware/deployments/store/mocks"
+	"github.com/mendersoftware/deployments/store/mongo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -100,6 +104,18 @@ func TestCleanupExpiredUploads(t *testing.T) {
 			},
 			UpdatedTS: time.Now().Add(-time.Hour * 2),
 			Status:    model.LinkStatusPending,
+		}, {
+			ArtifactID: "0b2a0e09-3b0c-4b55-9a4f-6a3c4bb0e7d1",
+			Link: model.Link{
+				TenantID: "123456789012345678901234",
+				Expire:   time.Now().Add(-time.Hour * 12),
+			},
+			UpdatedTS: time.Now().Add(-time.Hour * 2),
+			Status:    model.LinkStatusPending,
+			Chunked: &model.ChunkedUpload{
+				UploadID: "upload",
+				Length:   1024,
+			},
 		}, {
 			ArtifactID: "1ea293ad-c94b-44b7-a137-af1dd9d6b126",
 			Link: model.Link{
@@ -141,6 +157,14 @@ func TestCleanupExpiredUploads(t *testing.T) {
 					statusNew = model.LinkStatusAborted | model.LinkStatusProcessedBit
 					errDelete = storage.ErrObjectNotFound
 				}
+				if link.Chunked != nil {
+					objectStore.On("AbortChunkedUpload",
+						ctx,
+						path.Join(link.TenantID, link.ArtifactID)+fileSuffixTmp,
+						link.Chunked.UploadID).
+						Return(nil).
+						Once()
+				}
 				objectStore.On("DeleteObject",
 					ctx,
 					path.Join(link.TenantID, link.ArtifactID)+fileSuffixTmp).
@@ -154,6 +178,21 @@ func TestCleanupExpiredUploads(t *testing.T) {
 			}
 		}
 
+		database.On("GetTenantDbs").
+			Return([]string{"deployment_service-123456789012345678901234"}, nil).
+			Once()
+		database.On("FailStaleImportJobs",
+			mock.MatchedBy(func(ctx context.Context) bool {
+				id := identity.FromContext(ctx)
+				return id != nil && id.Tenant == "123456789012345678901234"
+			}),
+			mock.MatchedBy(func(before time.Time) bool {
+				return time.Until(before) < -importJobStaleTime
+			}),
+			"import interrupted").
+			Return(int64(1), nil).
+			Once()
+
 		app := NewDeployments(database, objectStore, 0, false)
 
 		err := app.CleanupExpiredUploads(ctx, 0, jitter)
@@ -180,6 +219,11 @@ func TestCleanupExpiredUploads(t *testing.T) {
 			}).
 			Return(iterator, nil).
 			Once()
+		database.On("GetTenantDbs").Return(nil, nil).Once()
+		database.On("FailStaleImportJobs", ctx, mock.AnythingOfType("time.Time"),
+			"import interrupted").
+			Return(int64(0), nil).
+			Once()
 
 		app := NewDeployments(database, objectStore, 0, false)
 
@@ -261,4 +305,165 @@ func TestCleanupExpiredUploads(t *testing.T) {
 		err := app.CleanupExpiredUploads(ctx, 0, jitter)
 		assert.ErrorIs(t, err, errInternal)
 	})
+	t.Run("error/database fail stale import jobs", func(t *testing.T) {
+		ctx := context.Background()
+		database := new(mstore.DataStore)
+		objectStore := new(mstorage.ObjectStorage)
+		defer database.AssertExpectations(t)
+		defer objectStore.AssertExpectations(t)
+
+		errInternal := errors.New("internal error")
+		database.On("FindUploadLinks", ctx, mock.Anything).
+			Return(NewArrayIterator[model.UploadLink](nil), nil).
+			Once()
+		database.On("GetTenantDbs").Return(nil, nil).Once()
+		database.On("FailStaleImportJobs", ctx, mock.AnythingOfType("time.Time"),
+			"import interrupted").
+			Return(int64(0), errInternal).
+			Once()
+
+		app := NewDeployments(database, objectStore, 0, false)
+
+		err := app.CleanupExpiredUploads(ctx, 0, time.Second)
+		assert.ErrorIs(t, err, errInternal)
+	})
+}
+
+func TestTimeoutStuckDeviceDeployments(t *testing.T) {
+	t.Parallel()
+
+	const tenantID = "123456789012345678901234"
+	timeouts := map[model.DeviceDeploymentStatus]time.Duration{
+		model.DeviceDeploymentStatusDownloading: time.Hour,
+		model.DeviceDeploymentStatusRebooting:   0,
+	}
+	matchTenant := mock.MatchedBy(func(ctx context.Context) bool {
+		id := identity.FromContext(ctx)
+		return id != nil && id.Tenant == tenantID
+	})
+
+	t.Run("single-shot/ok", func(t *testing.T) {
+		ctx := context.Background()
+
+		deployment, err := model.NewDeploymentFromConstructor(
+			&model.DeploymentConstructor{
+				Name:         "foo",
+				ArtifactName: "bar",
+				Devices:      []string{"device-1", "device-2"},
+			},
+		)
+		assert.NoError(t, err)
+		deployment.MaxDevices = 2
+		stuck := []model.DeviceDeployment{
+			*model.NewDeviceDeployment("device-1", deployment.Id),
+			*model.NewDeviceDeployment("device-2", deployment.Id),
+		}
+		stuck[0].Status = model.DeviceDeploymentStatusDownloading
+
+		database := new(mstore.DataStore)
+		defer database.AssertExpectations(t)
+
+		database.On("GetTenantDbs").
+			Return([]string{ctxstore.DbNameForTenant(tenantID, mongo.DbName)}, nil).
+			Once()
+		database.On("FindStuckDeviceDeployments",
+			matchTenant,
+			model.DeviceDeploymentStatusDownloading,
+			mock.AnythingOfType("time.Time"),
+		).Run(func(args mock.Arguments) {
+			since := args.Get(2).(time.Time)
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), since, time.Minute)
+		}).Return(NewArrayIterator(stuck), nil).Once()
+
+		// first device deployment is timed out
+		database.On("GetDeviceDeployment",
+			matchTenant, deployment.Id, "device-1", false,
+		).Return(&stuck[0], nil).Once()
+		database.On("UpdateDeviceDeploymentStatus",
+			matchTenant, "device-1", deployment.Id,
+			mock.MatchedBy(func(state model.DeviceDeploymentState) bool {
+				return state.Status == model.DeviceDeploymentStatusFailure &&
+					state.SubState == DeviceDeploymentSubStateTimeout &&
+					state.FinishTime != nil
+			}),
+		).Return(model.DeviceDeploymentStatusDownloading, nil).Once()
+		database.On("UpdateStatsInc",
+			matchTenant, deployment.Id,
+			model.DeviceDeploymentStatusDownloading,
+			model.DeviceDeploymentStatusFailure,
+		).Return(nil).Once()
+		database.On("FindDeploymentByID", matchTenant, deployment.Id).
+			Return(deployment, nil).Once()
+		database.On("SetDeploymentStatus",
+			matchTenant, deployment.Id,
+			mock.AnythingOfType("model.DeploymentStatus"),
+			mock.AnythingOfType("time.Time"),
+		).Return(nil).Once()
+		database.On("SaveLastDeviceDeploymentStatus",
+			matchTenant,
+			mock.AnythingOfType("model.DeviceDeployment"),
+		).Return(nil).Once()
+
+		// second device deployment finished in the meantime
+		database.On("GetDeviceDeployment",
+			matchTenant, deployment.Id, "device-2", false,
+		).Return(nil, mongo.ErrStorageNotFound).Once()
+
+		app := NewDeployments(database, nil, 0, false)
+
+		err = app.TimeoutStuckDeviceDeployments(ctx, timeouts, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("periodic/context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		iterator := NewArrayIterator([]model.DeviceDeployment{})
+
+		database := new(mstore.DataStore)
+		defer database.AssertExpectations(t)
+
+		database.On("GetTenantDbs").
+			Return([]string{}, nil).
+			Once()
+		database.On("FindStuckDeviceDeployments",
+			ctx,
+			model.DeviceDeploymentStatusDownloading,
+			mock.AnythingOfType("time.Time"),
+		).Return(iterator, nil).Once()
+
+		app := NewDeployments(database, nil, 0, false)
+
+		go func() {
+			select {
+			case <-iterator.closed:
+			case <-time.After(time.Second * 10):
+			}
+			cancel()
+		}()
+		err := app.TimeoutStuckDeviceDeployments(ctx, timeouts, time.Hour)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("error/database find stuck device deployments", func(t *testing.T) {
+		ctx := context.Background()
+
+		database := new(mstore.DataStore)
+		defer database.AssertExpectations(t)
+
+		errInternal := errors.New("internal error")
+		database.On("GetTenantDbs").
+			Return([]string{}, nil).
+			Once()
+		database.On("FindStuckDeviceDeployments",
+			ctx,
+			model.DeviceDeploymentStatusDownloading,
+			mock.AnythingOfType("time.Time"),
+		).Return(nil, errInternal).Once()
+
+		app := NewDeployments(database, nil, 0, false)
+
+		err := app.TimeoutStuckDeviceDeployments(ctx, timeouts, 0)
+		assert.ErrorIs(t, err, errInternal)
+	})
 }
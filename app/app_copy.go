@@ -0,0 +1,247 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+var (
+	ErrCopyArtifactSameTenant = errors.New(
+		"the artifact cannot be copied to the tenant owning it",
+	)
+)
+
+// CopyArtifact copies the artifact of the tenant in the context, with its
+// metadata and file, to the tenant with the given ID and adds the copy to
+// the release of the destination tenant. Tenants sharing the default
+// storage share the file of the artifact, the file is copied otherwise.
+// The signature of the artifact is verified against the public keys of the
+// destination tenant.
+func (d *Deployments) CopyArtifact(
+	ctx context.Context,
+	id string,
+	tenantID string,
+) (*model.Image, error) {
+	l := log.FromContext(ctx)
+	if idty := identity.FromContext(ctx); (idty == nil && tenantID == "") ||
+		(idty != nil && idty.Tenant == tenantID) {
+		return nil, ErrCopyArtifactSameTenant
+	}
+	image, err := d.db.FindImageByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the artifact")
+	} else if image == nil {
+		return nil, ErrImageMetaNotFound
+	}
+	srcCtx, err := d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dstCtx := identity.WithContext(ctx, &identity.Identity{Tenant: tenantID})
+	isArtifactUnique, err := d.db.IsArtifactUnique(dstCtx,
+		image.ArtifactMeta.Name,
+		image.ArtifactMeta.DeviceTypesCompatible,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to check if artifact is unique")
+	} else if !isArtifactUnique {
+		return nil, ErrModelArtifactNotUnique
+	}
+	if err = d.checkReleaseAlias(dstCtx, image.ArtifactMeta.Name); err != nil {
+		return nil, err
+	}
+	signingKeyID, err := d.verifyCopySignature(srcCtx, dstCtx, image)
+	if err != nil {
+		return nil, err
+	}
+	dstCtx, err = d.contextWithStorageSettings(dstCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, _ := uuid.NewRandom()
+	now := time.Now()
+	imageCopy := *image
+	imageCopy.Id = uid.String()
+	imageCopy.Modified = &now
+	imageCopy.Uploaded = &now
+	imageCopy.ObjectID = ""
+	imageCopy.ObjectPath = ""
+	imageCopy.Integrity = nil
+	imageCopy.SigningKeyID = signingKeyID
+	if err = d.reserveStorage(dstCtx, imageCopy.Size); err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		if errDelete := d.deleteArtifactFile(dstCtx, &imageCopy); errDelete != nil {
+			l.Errorf(
+				"failed to clean up artifact storage after failure: %s",
+				errDelete,
+			)
+		}
+		d.releaseStorage(dstCtx, imageCopy.Size)
+	}
+	if err = d.copyArtifactFile(srcCtx, dstCtx, image, &imageCopy); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	if err = d.db.InsertImage(dstCtx, &imageCopy); err != nil {
+		cleanup()
+		if idxErr, ok := err.(*model.ConflictError); ok {
+			return nil, idxErr
+		}
+		return nil, errors.Wrap(err, "Fail to store the metadata")
+	}
+	d.saveUpdateTypes(dstCtx, &imageCopy)
+
+	if err := d.updateRelease(dstCtx, &imageCopy, nil); err != nil {
+		return nil, err
+	}
+	err = d.UpdateDeploymentsWithArtifactName(dstCtx, imageCopy.ArtifactMeta.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to update deployments")
+	}
+	return &imageCopy, nil
+}
+
+// verifyCopySignature verifies the signature of the artifact against the
+// public keys trusted by the destination tenant, enforcing its signature
+// policy; it returns the ID of the destination key which verified the
+// signature, as the keys of the source tenant mean nothing to the copy.
+func (d *Deployments) verifyCopySignature(
+	srcCtx context.Context,
+	dstCtx context.Context,
+	image *model.Image,
+) (string, error) {
+	verifier, err := d.newSignatureVerifier(dstCtx)
+	if err != nil {
+		return "", err
+	}
+	if !image.ArtifactMeta.Signed {
+		return "", verifier.checkSigned(false)
+	} else if len(verifier.keys) == 0 {
+		if verifier.requireSigned {
+			return "", ErrArtifactSignatureInvalid
+		}
+		return "", nil
+	}
+	r, err := d.objectStorage.GetObject(srcCtx, image.ObjectPathFromContext(srcCtx))
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to get the artifact object")
+	}
+	defer r.Close()
+	// the source artifact was verified on upload: reading the headers is
+	// enough, unless the signature policy requires the artifact in full
+	ar := io.Reader(r)
+	if _, err = getMetaFromArchive(&ar, true, verifier); err != nil {
+		switch cause := errors.Cause(err); cause {
+		case ErrArtifactNotSigned, ErrArtifactSignatureInvalid:
+			return "", cause
+		}
+		return "", errors.Wrap(err, "failed to verify the artifact signature")
+	}
+	return verifier.keyID, nil
+}
+
+// copyArtifactFile stores the file of the source image for its copy: the
+// copy references the artifact object of the source image if both tenants
+// share the storage, or gets its own copy of the file.
+func (d *Deployments) copyArtifactFile(
+	srcCtx context.Context,
+	dstCtx context.Context,
+	src *model.Image,
+	dst *model.Image,
+) error {
+	path := model.ImagePathFromContext(dstCtx, dst.Id)
+	if src.ObjectID != "" && src.ObjectID == artifactObjectID(dstCtx, src.Checksum) {
+		obj, err := d.db.AddArtifactObjectReference(
+			dstCtx, src.ObjectID, artifactObjectPath(src.ObjectID), dst.Size, dst.Id,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to add the artifact object reference")
+		}
+		dst.ObjectID = obj.ID
+		dst.ObjectPath = obj.Path
+		if obj.RefCount > 1 {
+			return nil
+		}
+		// the object was deleted in the meantime: store the file
+		path = obj.Path
+	}
+	r, err := d.objectStorage.GetObject(srcCtx, src.ObjectPathFromContext(srcCtx))
+	if err != nil {
+		return errors.WithMessage(err, "failed to get the artifact object")
+	}
+	defer r.Close()
+	if err = d.objectStorage.PutObject(dstCtx, path, r); err != nil {
+		return errors.WithMessage(err, "failed to copy the artifact object")
+	}
+	if dst.ObjectID == "" {
+		d.shareArtifactObject(dstCtx, dst)
+	}
+	return nil
+}
+
+// CopyArtifactToTenants copies the artifact of the tenant in the context to
+// each of the tenants with the given IDs, reporting every copy to `report`
+// as a JSON line. A failed copy does not stop the copies to the other
+// tenants; the error returned reports that at least one copy failed.
+func (d *Deployments) CopyArtifactToTenants(
+	ctx context.Context,
+	id string,
+	tenantIDs []string,
+	report io.Writer,
+) error {
+	var encoder *json.Encoder
+	if report != nil {
+		encoder = json.NewEncoder(report)
+	}
+	var failed int
+	for _, tenantID := range tenantIDs {
+		artifactCopy := &model.ArtifactCopy{TenantID: tenantID}
+		image, err := d.CopyArtifact(ctx, id, tenantID)
+		if err != nil {
+			log.FromContext(ctx).
+				Errorf("failed to copy artifact %s to tenant %q: %s", id, tenantID, err)
+			artifactCopy.Error = err.Error()
+			failed++
+		} else {
+			artifactCopy.ID = image.Id
+			artifactCopy.Name = image.ArtifactMeta.Name
+		}
+		if encoder != nil {
+			if err = encoder.Encode(artifactCopy); err != nil {
+				return errors.Wrap(err, "failed to write the report")
+			}
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to copy the artifact to %d tenant(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"testing"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/mendersoftware/mender-artifact/artifact"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mocks"
+	"github.com/mendersoftware/deployments/store/mongo"
+)
+
+func tenantContext(tenantID string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		idty := identity.FromContext(ctx)
+		return idty != nil && idty.Tenant == tenantID
+	})
+}
+
+func TestCopyArtifact(t *testing.T) {
+	t.Parallel()
+
+	const artifactID = "d1f0d0a6-8f2a-4a36-b5d0-bd5a1fd0a9c1"
+	newImage := func() *model.Image {
+		return &model.Image{
+			Id: artifactID,
+			ArtifactMeta: &model.ArtifactMeta{
+				Name:                  "base-image",
+				DeviceTypesCompatible: []string{"rpi4"},
+			},
+			Size:       10,
+			Checksum:   "checksum",
+			ObjectID:   "checksum",
+			ObjectPath: "other/" + artifactID,
+		}
+	}
+	isCopy := mock.MatchedBy(func(image *model.Image) bool {
+		return image.Id != artifactID && image.ArtifactMeta.Name == "base-image"
+	})
+
+	testCases := []struct {
+		Name string
+
+		TenantID string
+		Image    *model.Image
+
+		NotUnique          bool
+		AliasConflict      bool
+		RequireSigned      bool
+		DstStorageSettings *model.StorageSettings
+		ObjectPath         string
+		InsertError        error
+
+		Error error
+	}{{
+		Name: "ok, shared object",
+
+		TenantID:   "customer",
+		Image:      newImage(),
+		ObjectPath: "other/" + artifactID,
+	}, {
+		Name: "ok, copied to the tenant storage",
+
+		TenantID: "customer",
+		Image:    newImage(),
+		DstStorageSettings: &model.StorageSettings{
+			Region: "region",
+			Bucket: "bucket",
+			Key:    "access-key",
+			Secret: "secret-key",
+		},
+	}, {
+		Name: "error, same tenant",
+
+		TenantID: "golden",
+		Error:    ErrCopyArtifactSameTenant,
+	}, {
+		Name: "error, artifact not found",
+
+		TenantID: "customer",
+		Error:    ErrImageMetaNotFound,
+	}, {
+		Name: "error, artifact not unique",
+
+		TenantID:  "customer",
+		Image:     newImage(),
+		NotUnique: true,
+		Error:     ErrModelArtifactNotUnique,
+	}, {
+		Name: "error, name used as a release alias",
+
+		TenantID:      "customer",
+		Image:         newImage(),
+		AliasConflict: true,
+		Error:         ErrReleaseAliasConflict,
+	}, {
+		Name: "error, signed artifacts required",
+
+		TenantID:      "customer",
+		Image:         newImage(),
+		RequireSigned: true,
+		Error:         ErrArtifactNotSigned,
+	}, {
+		Name: "error, inserting the copy",
+
+		TenantID:    "customer",
+		Image:       newImage(),
+		ObjectPath:  "other/" + artifactID,
+		InsertError: errors.New("internal error"),
+		Error:       errors.New("Fail to store the metadata: internal error"),
+	}}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := identity.WithContext(context.Background(), &identity.Identity{
+				Tenant: "golden",
+			})
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+
+			src := tenantContext("golden")
+			dst := tenantContext(tc.TenantID)
+			if tc.TenantID != "golden" {
+				db.On("FindImageByID", src, artifactID).Return(tc.Image, nil)
+			}
+			if tc.Image != nil {
+				db.On("GetStorageSettings", src).Return(nil, nil)
+				db.On("IsArtifactUnique", dst, "base-image", []string{"rpi4"}).
+					Return(!tc.NotUnique, nil)
+			}
+			if tc.Image != nil && !tc.NotUnique {
+				if tc.AliasConflict {
+					db.On("GetReleaseNameByAlias", dst, "base-image").
+						Return("other-release", nil)
+				} else {
+					db.On("GetReleaseNameByAlias", dst, "base-image").
+						Return("", store.ErrNotFound)
+				}
+			}
+			if tc.Image != nil && !tc.NotUnique && !tc.AliasConflict {
+				db.On("GetTenantSettings", dst).Return(&model.TenantSettings{
+					RequireSignedArtifacts: tc.RequireSigned,
+				}, nil)
+				db.On("ListPublicKeys", dst).Return(nil, nil)
+			}
+			if tc.Image != nil && !tc.NotUnique && !tc.AliasConflict && !tc.RequireSigned {
+				db.On("GetStorageSettings", dst).Return(tc.DstStorageSettings, nil)
+				db.On("GetLimit", dst, model.LimitStorage).
+					Return(nil, mongo.ErrLimitNotFound)
+				db.On("IncrementStorageUsage", dst, int64(10), uint64(0)).Return(nil)
+				if tc.DstStorageSettings == nil {
+					db.On("AddArtifactObjectReference", dst, "checksum",
+						"objects/checksum", int64(10),
+						mock.AnythingOfType("string"),
+					).Return(&model.ArtifactObject{
+						ID:       "checksum",
+						Path:     tc.ObjectPath,
+						RefCount: 2,
+					}, nil)
+				} else {
+					objStore.On("GetObject", mock.Anything, "other/"+artifactID).
+						Return(io.NopCloser(bytes.NewReader([]byte("artifact"))), nil)
+					objStore.On("PutObject", dst,
+						mock.MatchedBy(func(path string) bool {
+							return path != "other/"+artifactID
+						}),
+						mock.Anything,
+					).Return(nil)
+					objStore.On("StatObject", dst, "objects/customer/checksum").
+						Return(&storage.ObjectInfo{}, nil)
+					db.On("AddArtifactObjectReference", dst, "customer/checksum",
+						"objects/customer/checksum", int64(10),
+						mock.AnythingOfType("string"),
+					).Return(nil, errors.New("ignored"))
+				}
+				db.On("InsertImage", dst, isCopy).Return(tc.InsertError)
+			}
+			if tc.InsertError != nil {
+				db.On("RemoveArtifactObjectReference", dst, "checksum",
+					mock.AnythingOfType("string"),
+				).Return(&model.ArtifactObject{
+					ID:       "checksum",
+					Path:     tc.ObjectPath,
+					RefCount: 1,
+				}, nil)
+				db.On("IncrementStorageUsage", dst, int64(-10), uint64(0)).Return(nil)
+			} else if tc.Error == nil {
+				db.On("UpdateReleaseArtifacts", dst, isCopy, (*model.Image)(nil),
+					"base-image").Return(nil)
+				db.On("ExistUnfinishedByArtifactName", dst, "base-image").
+					Return(false, nil)
+			}
+
+			d := NewDeployments(db, objStore, 0, false)
+			image, err := d.CopyArtifact(ctx, artifactID, tc.TenantID)
+			if tc.Error != nil {
+				assert.EqualError(t, err, tc.Error.Error())
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.NotEqual(t, artifactID, image.Id)
+				assert.Equal(t, tc.ObjectPath, image.ObjectPath)
+				if tc.ObjectPath != "" {
+					assert.Equal(t, "checksum", image.ObjectID)
+				} else {
+					assert.Empty(t, image.ObjectID)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyCopySignature(t *testing.T) {
+	t.Parallel()
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signingPEM, err := x509.MarshalECPrivateKey(signingKey)
+	assert.NoError(t, err)
+	signer, err := artifact.NewPKISigner(
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: signingPEM}),
+	)
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signed := writeTestArtifact(t, signer)
+
+	testCases := map[string]struct {
+		signed        bool
+		keys          []model.PublicKey
+		requireSigned bool
+
+		keyID string
+		err   error
+	}{
+		"ok, unsigned": {},
+		"ok, no trusted keys": {
+			signed: true,
+		},
+		"ok, verified with the destination key": {
+			signed: true,
+			keys: []model.PublicKey{
+				newTestPublicKey(t, "other", otherKey.Public()),
+				newTestPublicKey(t, "destination", signingKey.Public()),
+			},
+			requireSigned: true,
+			keyID:         "destination",
+		},
+		"ok, untrusted key": {
+			signed: true,
+			keys:   []model.PublicKey{newTestPublicKey(t, "other", otherKey.Public())},
+		},
+		"error, unsigned": {
+			requireSigned: true,
+			err:           ErrArtifactNotSigned,
+		},
+		"error, no trusted keys": {
+			signed:        true,
+			requireSigned: true,
+			err:           ErrArtifactSignatureInvalid,
+		},
+		"error, untrusted key": {
+			signed:        true,
+			keys:          []model.PublicKey{newTestPublicKey(t, "other", otherKey.Public())},
+			requireSigned: true,
+			err:           ErrArtifactSignatureInvalid,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srcCtx := identity.WithContext(context.Background(), &identity.Identity{
+				Tenant: "golden",
+			})
+			dstCtx := identity.WithContext(context.Background(), &identity.Identity{
+				Tenant: "customer",
+			})
+			image := &model.Image{
+				Id: "artifact",
+				ArtifactMeta: &model.ArtifactMeta{
+					Signed: tc.signed,
+				},
+				SigningKeyID: "golden-key",
+			}
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+			db.On("GetTenantSettings", dstCtx).Return(&model.TenantSettings{
+				RequireSignedArtifacts: tc.requireSigned,
+			}, nil)
+			db.On("ListPublicKeys", dstCtx).Return(tc.keys, nil)
+			if tc.signed && len(tc.keys) > 0 {
+				objStore.On("GetObject", srcCtx, "golden/artifact").
+					Return(io.NopCloser(bytes.NewReader(signed)), nil)
+			}
+
+			d := NewDeployments(db, objStore, 0, false)
+			keyID, err := d.verifyCopySignature(srcCtx, dstCtx, image)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.keyID, keyID)
+			}
+		})
+	}
+}
+
+func TestCopyArtifactToTenants(t *testing.T) {
+	t.Parallel()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "golden",
+	})
+	db := &mocks.DataStore{}
+	defer db.AssertExpectations(t)
+	db.On("FindImageByID", tenantContext("golden"), "artifact").
+		Return(nil, nil)
+
+	var report bytes.Buffer
+	d := NewDeployments(db, nil, 0, false)
+	err := d.CopyArtifactToTenants(ctx, "artifact", []string{"golden", "customer"}, &report)
+	assert.EqualError(t, err, "failed to copy the artifact to 2 tenant(s)")
+	assert.Equal(t,
+		`{"tenant_id":"golden","error":"`+ErrCopyArtifactSameTenant.Error()+`"}`+"\n"+
+			`{"tenant_id":"customer","error":"`+ErrImageMetaNotFound.Error()+`"}`+"\n",
+		report.String())
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+const (
+	// InventoryGatewayScope and InventoryGatewayAttributeName locate the
+	// inventory attribute holding the ID of the gateway a device is
+	// connected through.
+	InventoryGatewayScope         = "inventory"
+	InventoryGatewayAttributeName = "gateway_id"
+)
+
+var (
+	ErrNotGatewayDevice = errors.New("device is not connected through the gateway")
+)
+
+// GetGatewayDevices returns the subset of the given devices which are
+// accepted and connected through the gateway according to the inventory.
+func (d *Deployments) GetGatewayDevices(
+	ctx context.Context,
+	gatewayID string,
+	deviceIDs []string,
+) ([]string, error) {
+	if len(deviceIDs) == 0 {
+		return []string{}, nil
+	}
+	id := identity.FromContext(ctx)
+	if id == nil {
+		id = &identity.Identity{}
+	}
+	searchParams := model.SearchParams{
+		Page:    1,
+		PerPage: len(deviceIDs),
+		Filters: []model.FilterPredicate{
+			{
+				Scope:     InventoryIdentityScope,
+				Attribute: InventoryStatusAttributeName,
+				Type:      "$eq",
+				Value:     InventoryStatusAccepted,
+			},
+			{
+				Scope:     InventoryGatewayScope,
+				Attribute: InventoryGatewayAttributeName,
+				Type:      "$eq",
+				Value:     gatewayID,
+			},
+		},
+		DeviceIDs: deviceIDs,
+	}
+	devices, _, err := d.inventoryClient.Search(ctx, id.Tenant, searchParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search the gateway devices")
+	}
+	requested := make(map[string]struct{}, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		requested[deviceID] = struct{}{}
+	}
+	gatewayDevices := make([]string, 0, len(devices))
+	for _, device := range devices {
+		if _, ok := requested[device.ID]; ok {
+			gatewayDevices = append(gatewayDevices, device.ID)
+		}
+	}
+	return gatewayDevices, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/stretchr/testify/assert"
+
+	inventory_mocks "github.com/mendersoftware/deployments/client/inventory/mocks"
+	"github.com/mendersoftware/deployments/model"
+)
+
+func TestGetGatewayDevices(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tenantID  = "tenant"
+		gatewayID = "gateway"
+	)
+
+	testCases := map[string]struct {
+		deviceIDs []string
+
+		invDevices []model.InvDevice
+		invErr     error
+
+		devices []string
+		err     error
+	}{
+		"ok": {
+			deviceIDs:  []string{"child-1", "child-2", "other"},
+			invDevices: []model.InvDevice{{ID: "child-1"}, {ID: "child-2"}},
+			devices:    []string{"child-1", "child-2"},
+		},
+		"ok, ignores devices not requested": {
+			deviceIDs:  []string{"child-1"},
+			invDevices: []model.InvDevice{{ID: "child-1"}, {ID: "child-3"}},
+			devices:    []string{"child-1"},
+		},
+		"ok, no devices": {
+			devices: []string{},
+		},
+		"error, inventory": {
+			deviceIDs: []string{"child-1"},
+			invErr:    errors.New("connection refused"),
+			err: errors.New(
+				"failed to search the gateway devices: connection refused",
+			),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := identity.WithContext(context.Background(), &identity.Identity{
+				Subject:  gatewayID,
+				Tenant:   tenantID,
+				IsDevice: true,
+			})
+			inv := &inventory_mocks.Client{}
+			defer inv.AssertExpectations(t)
+			if len(tc.deviceIDs) > 0 {
+				inv.On("Search", ctx, tenantID, model.SearchParams{
+					Page:    1,
+					PerPage: len(tc.deviceIDs),
+					Filters: []model.FilterPredicate{{
+						Scope:     InventoryIdentityScope,
+						Attribute: InventoryStatusAttributeName,
+						Type:      "$eq",
+						Value:     InventoryStatusAccepted,
+					}, {
+						Scope:     InventoryGatewayScope,
+						Attribute: InventoryGatewayAttributeName,
+						Type:      "$eq",
+						Value:     gatewayID,
+					}},
+					DeviceIDs: tc.deviceIDs,
+				}).Return(tc.invDevices, len(tc.invDevices), tc.invErr)
+			}
+
+			ds := &Deployments{inventoryClient: inv}
+			devices, err := ds.GetGatewayDevices(ctx, gatewayID, tc.deviceIDs)
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.devices, devices)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mendersoftware/mender-artifact/artifact"
+	"github.com/mendersoftware/mender-artifact/awriter"
+	"github.com/mendersoftware/mender-artifact/handlers"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+var (
+	ErrArtifactGeneratorType = errors.New(
+		"artifact type not supported by the built-in artifact generator",
+	)
+	ErrArtifactGeneratorArgs = errors.New(
+		"invalid arguments for the built-in artifact generator",
+	)
+)
+
+// Names of the files the update modules read their arguments from.
+const (
+	generatorFileDestDir     = "dest_dir"
+	generatorFileFilename    = "filename"
+	generatorFilePermissions = "permissions"
+	generatorFileArchive     = "update.tar"
+)
+
+// generatorDefaultPermissions are the permissions of the files installed
+// by the single-file update module, unless given in the arguments.
+const generatorDefaultPermissions = 0644
+
+// generatorArgs are the arguments of the built-in artifact generator,
+// given as a JSON document in the args of the generation request.
+type generatorArgs struct {
+	// Filename is the name of the file on the device
+	Filename string `json:"filename"`
+	// DestDir is the directory the file, or the archive, is installed to
+	DestDir string `json:"dest_dir"`
+	// Permissions are the octal permissions of the installed file
+	Permissions string `json:"permissions"`
+}
+
+// filePermissions returns the permissions of the installed file, in octal
+// like `stat -c %a` prints them.
+func (args generatorArgs) filePermissions() (string, error) {
+	if args.Permissions == "" {
+		return strconv.FormatUint(generatorDefaultPermissions, 8), nil
+	}
+	mode, err := strconv.ParseUint(args.Permissions, 8, 32)
+	if err != nil || mode > 07777 {
+		return "", ErrArtifactGeneratorArgs
+	}
+	return strconv.FormatUint(mode, 8), nil
+}
+
+// artifactGenerator lays out the payload of an update module.
+type artifactGenerator struct {
+	// module is the update module installing the artifact
+	module string
+	// files writes the files of the update to dir, the payload being the
+	// file at payload, and returns their paths
+	files func(dir, payload string, args generatorArgs) ([]string, error)
+}
+
+// artifactGenerators are the update modules supported by the built-in
+// generator, by artifact type.
+var artifactGenerators = map[string]artifactGenerator{
+	"single_file": {
+		module: "single-file",
+		files: func(dir, payload string, args generatorArgs) ([]string, error) {
+			if !isGeneratorFilename(args.Filename) ||
+				args.Filename == generatorFileDestDir ||
+				args.Filename == generatorFileFilename ||
+				args.Filename == generatorFilePermissions ||
+				args.DestDir == "" {
+				return nil, ErrArtifactGeneratorArgs
+			}
+			permissions, err := args.filePermissions()
+			if err != nil {
+				return nil, err
+			}
+			file := filepath.Join(dir, args.Filename)
+			if err := os.Rename(payload, file); err != nil {
+				return nil, err
+			}
+			destDir, err := writeGeneratorFile(dir, generatorFileDestDir, args.DestDir)
+			if err != nil {
+				return nil, err
+			}
+			filename, err := writeGeneratorFile(dir, generatorFileFilename, args.Filename)
+			if err != nil {
+				return nil, err
+			}
+			permissionsFile, err := writeGeneratorFile(
+				dir, generatorFilePermissions, permissions,
+			)
+			if err != nil {
+				return nil, err
+			}
+			// the layout of the single-file-artifact-gen script
+			return []string{destDir, filename, permissionsFile, file}, nil
+		},
+	},
+	"directory": {
+		module: "directory",
+		files: func(dir, payload string, args generatorArgs) ([]string, error) {
+			if args.DestDir == "" {
+				return nil, ErrArtifactGeneratorArgs
+			}
+			archive := filepath.Join(dir, generatorFileArchive)
+			if err := os.Rename(payload, archive); err != nil {
+				return nil, err
+			}
+			destDir, err := writeGeneratorFile(dir, generatorFileDestDir, args.DestDir)
+			if err != nil {
+				return nil, err
+			}
+			return []string{destDir, archive}, nil
+		},
+	},
+	"script": {
+		module: "script",
+		files: func(dir, payload string, args generatorArgs) ([]string, error) {
+			if args.Filename == "" {
+				args.Filename = "script"
+			} else if !isGeneratorFilename(args.Filename) {
+				return nil, ErrArtifactGeneratorArgs
+			}
+			file := filepath.Join(dir, args.Filename)
+			if err := os.Rename(payload, file); err != nil {
+				return nil, err
+			}
+			return []string{file}, nil
+		},
+	},
+}
+
+// isGeneratorFilename tells whether name is a plain file name.
+func isGeneratorFilename(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsAny(name, `/\`)
+}
+
+func writeGeneratorFile(dir, name, content string) (string, error) {
+	path := filepath.Join(dir, name)
+	return path, os.WriteFile(path, []byte(content), 0600)
+}
+
+// generateArtifact builds the artifact from the raw file in-process, and
+// stores it as if it was uploaded.
+func (d *Deployments) generateArtifact(
+	ctx context.Context,
+	msg *model.MultipartGenerateImageMsg,
+) (string, error) {
+	generator, ok := artifactGenerators[msg.Type]
+	if !ok {
+		return "", ErrArtifactGeneratorType
+	}
+	var args generatorArgs
+	if msg.Args != "" {
+		if err := json.Unmarshal([]byte(msg.Args), &args); err != nil {
+			return "", ErrArtifactGeneratorArgs
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "deployments-generate-")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create the generator directory")
+	}
+	defer os.RemoveAll(dir)
+
+	// the update files get their own directory, so that their names
+	// cannot clash with the payload or the artifact
+	updateDir := filepath.Join(dir, "update")
+	if err = os.Mkdir(updateDir, 0700); err != nil {
+		return "", errors.Wrap(err, "failed to create the generator directory")
+	}
+	payload := filepath.Join(dir, "payload")
+	if err = writeGeneratorPayload(payload, msg.FileReader); err != nil {
+		return "", err
+	}
+	files, err := generator.files(updateDir, payload, args)
+	if err == ErrArtifactGeneratorArgs {
+		return "", err
+	} else if err != nil {
+		return "", errors.Wrap(err, "failed to prepare the update files")
+	}
+
+	artifactFile, err := os.Create(filepath.Join(dir, "artifact.mender"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create the artifact file")
+	}
+	defer artifactFile.Close()
+	if err = writeGeneratedArtifact(artifactFile, generator.module, msg, files); err != nil {
+		return "", errors.Wrap(err, "failed to generate the artifact")
+	}
+	if _, err = artifactFile.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "failed to read the generated artifact")
+	}
+
+	return d.handleArtifact(ctx, &model.MultipartUploadMsg{
+		MetaConstructor: &model.ImageMeta{Description: msg.Description},
+		ArtifactReader:  artifactFile,
+	}, false, nil)
+}
+
+func writeGeneratorPayload(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the payload file")
+	}
+	defer f.Close()
+	if _, err = io.Copy(f, r); err != nil {
+		return errors.Wrap(err, "failed to read the payload file")
+	}
+	return f.Close()
+}
+
+// writeGeneratedArtifact writes an unsigned artifact installing the files
+// with the update module; like mender-artifact does by default, the
+// artifact provides the version of the software named after the module.
+func writeGeneratedArtifact(
+	w io.Writer,
+	module string,
+	msg *model.MultipartGenerateImageMsg,
+	files []string,
+) error {
+	update := handlers.NewModuleImage(module)
+	dataFiles := make([]*handlers.DataFile, len(files))
+	for i, file := range files {
+		dataFiles[i] = &handlers.DataFile{Name: file}
+	}
+	if err := update.SetUpdateFiles(dataFiles); err != nil {
+		return err
+	}
+	softwareVersion := "rootfs-image." + module + ".version"
+	writer := awriter.NewWriter(w, artifact.NewCompressorGzip())
+	return writer.WriteArtifact(&awriter.WriteArtifactArgs{
+		Format:  "mender",
+		Version: 3,
+		Devices: msg.DeviceTypesCompatible,
+		Name:    msg.Name,
+		Updates: &awriter.Updates{Updates: []handlers.Composer{update}},
+		Depends: &artifact.ArtifactDepends{
+			CompatibleDevices: msg.DeviceTypesCompatible,
+		},
+		Provides: &artifact.ArtifactProvides{
+			ArtifactName: msg.Name,
+		},
+		TypeInfoV3: &artifact.TypeInfoV3{
+			Type: &module,
+			ArtifactProvides: artifact.TypeInfoProvides{
+				softwareVersion: msg.Name,
+			},
+			ArtifactDepends:        artifact.TypeInfoDepends{},
+			ClearsArtifactProvides: []string{"rootfs-image." + module + ".*"},
+		},
+	})
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mendersoftware/mender-artifact/areader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func TestGenerateArtifactError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name string
+
+		Type string
+		Args string
+
+		Error error
+	}{{
+		Name:  "error, type not supported",
+		Type:  "rootfs-image",
+		Error: ErrArtifactGeneratorType,
+	}, {
+		Name:  "error, malformed args",
+		Type:  "single_file",
+		Args:  "dest_dir=/etc",
+		Error: ErrArtifactGeneratorArgs,
+	}, {
+		Name:  "error, single_file without dest_dir",
+		Type:  "single_file",
+		Args:  `{"filename":"file.conf"}`,
+		Error: ErrArtifactGeneratorArgs,
+	}, {
+		Name:  "error, single_file with a path as filename",
+		Type:  "single_file",
+		Args:  `{"filename":"../file.conf","dest_dir":"/etc"}`,
+		Error: ErrArtifactGeneratorArgs,
+	}, {
+		Name:  "error, single_file clashing with the module files",
+		Type:  "single_file",
+		Args:  `{"filename":"dest_dir","dest_dir":"/etc"}`,
+		Error: ErrArtifactGeneratorArgs,
+	}, {
+		Name:  "error, single_file with invalid permissions",
+		Type:  "single_file",
+		Args:  `{"filename":"file.conf","dest_dir":"/etc","permissions":"rw-r--r--"}`,
+		Error: ErrArtifactGeneratorArgs,
+	}, {
+		Name:  "error, single_file with out of range permissions",
+		Type:  "single_file",
+		Args:  `{"filename":"file.conf","dest_dir":"/etc","permissions":"17777"}`,
+		Error: ErrArtifactGeneratorArgs,
+	}, {
+		Name:  "error, directory without dest_dir",
+		Type:  "directory",
+		Error: ErrArtifactGeneratorArgs,
+	}, {
+		Name:  "error, script with a path as filename",
+		Type:  "script",
+		Args:  `{"filename":"/bin/sh"}`,
+		Error: ErrArtifactGeneratorArgs,
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			d := NewDeployments(nil, nil, 0, false).
+				WithBuiltinArtifactGenerator()
+			_, err := d.generateArtifact(context.Background(),
+				&model.MultipartGenerateImageMsg{
+					Name:                  "release-1",
+					DeviceTypesCompatible: []string{"qemux86-64"},
+					Type:                  tc.Type,
+					Args:                  tc.Args,
+					FileReader:            strings.NewReader("payload"),
+				})
+			assert.ErrorIs(t, err, tc.Error)
+		})
+	}
+}
+
+func TestWriteGeneratedArtifact(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name string
+
+		Type string
+		Args generatorArgs
+
+		Module   string
+		Files    []string
+		Contents map[string]string
+	}{{
+		Name: "ok, single_file",
+		Type: "single_file",
+		Args: generatorArgs{Filename: "file.conf", DestDir: "/etc"},
+
+		Module: "single-file",
+		Files:  []string{"dest_dir", "filename", "permissions", "file.conf"},
+		Contents: map[string]string{
+			"dest_dir":    "/etc",
+			"filename":    "file.conf",
+			"permissions": "644",
+			"file.conf":   "payload",
+		},
+	}, {
+		Name: "ok, single_file with permissions",
+		Type: "single_file",
+		Args: generatorArgs{Filename: "run.sh", DestDir: "/usr/bin", Permissions: "0755"},
+
+		Module: "single-file",
+		Files:  []string{"dest_dir", "filename", "permissions", "run.sh"},
+		Contents: map[string]string{
+			"dest_dir":    "/usr/bin",
+			"filename":    "run.sh",
+			"permissions": "755",
+			"run.sh":      "payload",
+		},
+	}, {
+		Name: "ok, directory",
+		Type: "directory",
+		Args: generatorArgs{DestDir: "/opt/app"},
+
+		Module: "directory",
+		Files:  []string{"dest_dir", "update.tar"},
+	}, {
+		Name: "ok, script",
+		Type: "script",
+
+		Module: "script",
+		Files:  []string{"script"},
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			payload := filepath.Join(dir, "payload")
+			require.NoError(t, os.WriteFile(payload, []byte("payload"), 0600))
+			updateDir := filepath.Join(dir, "update")
+			require.NoError(t, os.Mkdir(updateDir, 0700))
+
+			files, err := artifactGenerators[tc.Type].files(updateDir, payload, tc.Args)
+			require.NoError(t, err)
+			for name, content := range tc.Contents {
+				b, err := os.ReadFile(filepath.Join(updateDir, name))
+				if assert.NoError(t, err) {
+					assert.Equal(t, content, string(b))
+				}
+			}
+
+			msg := &model.MultipartGenerateImageMsg{
+				Name:                  "release-1",
+				DeviceTypesCompatible: []string{"qemux86-64"},
+				Type:                  tc.Type,
+			}
+			var b bytes.Buffer
+			err = writeGeneratedArtifact(&b, tc.Module, msg, files)
+			require.NoError(t, err)
+
+			// the artifact passes the checks of the uploads, like the
+			// artifacts generated by the workflows
+			r := io.Reader(bytes.NewReader(b.Bytes()))
+			meta, err := getMetaFromArchive(&r, false, &signatureVerifier{})
+			require.NoError(t, err)
+			assert.NoError(t, meta.Validate())
+			if assert.Len(t, meta.Updates, 1) {
+				var names []string
+				for _, file := range meta.Updates[0].Files {
+					names = append(names, file.Name)
+				}
+				assert.Equal(t, tc.Files, names)
+			}
+
+			reader := areader.NewReader(&b)
+			require.NoError(t, reader.ReadArtifact())
+			assert.Equal(t, "release-1", reader.GetArtifactName())
+			assert.Equal(t, []string{"qemux86-64"}, reader.GetCompatibleDevices())
+			updates := reader.GetUpdates()
+			if assert.Len(t, updates, 1) && assert.NotNil(t, updates[0].Type) {
+				assert.Equal(t, tc.Module, *updates[0].Type)
+			}
+			provides, err := reader.MergeArtifactProvides()
+			require.NoError(t, err)
+			assert.Equal(t, "release-1",
+				provides["rootfs-image."+tc.Module+".version"])
+
+			handlers := reader.GetHandlers()
+			if assert.Contains(t, handlers, 0) {
+				var names []string
+				for _, file := range handlers[0].GetUpdateAllFiles() {
+					names = append(names, file.Name)
+				}
+				assert.ElementsMatch(t, tc.Files, names)
+			}
+		})
+	}
+}
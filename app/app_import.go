@@ -0,0 +1,281 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	"github.com/mendersoftware/deployments/store"
+)
+
+var (
+	ErrImportJobNotFound       = errors.New("import job not found")
+	ErrImportAddressNotAllowed = errors.New(
+		"artifacts cannot be imported from non-public addresses",
+	)
+	ErrImportTooManyRedirects = errors.New("too many redirects")
+)
+
+const (
+	// importTimeout bounds the whole import of an artifact, from the
+	// request to the storage of the artifact.
+	importTimeout = time.Hour
+	// importDialTimeout bounds the connection to the remote server
+	importDialTimeout = 30 * time.Second
+	// importMaxRedirects is the number of redirects followed on import
+	importMaxRedirects = 10
+	// importJobStaleTime is the time after which the import jobs still
+	// pending or processing are considered interrupted
+	importJobStaleTime = importTimeout + inprogressIdleTime
+)
+
+// nonPublicNetworks are the networks, not covered by the net.IP
+// predicates, artifacts are not imported from.
+var nonPublicNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // "this" network
+		"100.64.0.0/10", // carrier-grade NAT
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // benchmarking
+		"240.0.0.0/4",   // reserved
+		"64:ff9b::/96",  // IPv4/IPv6 translation
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// isPublicIP tells whether ip is a public unicast address; loopback,
+// private, link-local (including the cloud metadata services) and reserved
+// addresses are not.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// newImportClient returns the client fetching the imported artifacts. The
+// addresses are checked on every connection, after name resolution and on
+// redirects too, so that artifacts are fetched from public addresses only;
+// proxies are not used, as they would connect on behalf of the client.
+func newImportClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: importDialTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return ErrImportAddressNotAllowed
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: importTimeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			TLSHandshakeTimeout:   importDialTimeout,
+			ResponseHeaderTimeout: importDialTimeout,
+		},
+		CheckRedirect: checkImportRedirect,
+	}
+}
+
+// checkImportRedirect limits the redirects of the imports. The headers of
+// the import request, which may carry credentials, are dropped once the
+// redirects leave the host of the URL; Go only drops the Authorization and
+// Cookie headers.
+func checkImportRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= importMaxRedirects {
+		return ErrImportTooManyRedirects
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return model.ErrImportURLScheme
+	}
+	crossHost := req.URL.Host != via[0].URL.Host
+	for _, r := range via[1:] {
+		crossHost = crossHost || r.URL.Host != via[0].URL.Host
+	}
+	if crossHost {
+		for key := range via[0].Header {
+			req.Header.Del(key)
+		}
+	}
+	return nil
+}
+
+// ImportArtifact starts importing an artifact from a remote URL in the
+// background and returns the job tracking the import.
+func (d *Deployments) ImportArtifact(
+	ctx context.Context,
+	req *model.ImportArtifactRequest,
+) (*model.ImportJob, error) {
+	l := log.FromContext(ctx)
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the size of the imported artifact is not known yet
+	if err = d.checkStorageLimit(ctx, 0); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &model.ImportJob{
+		ID:       uuid.NewString(),
+		URL:      u.Redacted(),
+		Checksum: req.Checksum,
+		Status:   model.ImportJobStatusPending,
+		Created:  now,
+		Updated:  now,
+	}
+	if err = d.db.InsertImportJob(ctx, job); err != nil {
+		return nil, errors.Wrap(err, "failed to store the import job")
+	}
+
+	// Create an async context that doesn't cancel when server connection
+	// closes.
+	ctxAsync := context.Background()
+	ctxAsync = log.WithContext(ctxAsync, l)
+	ctxAsync = identity.WithContext(ctxAsync, identity.FromContext(ctx))
+	settings, _ := storage.SettingsFromContext(ctx)
+	ctxAsync = storage.SettingsWithContext(ctxAsync, settings)
+
+	jobCopy := *job
+	go d.processImportJob(ctxAsync, &jobCopy, req) // nolint:errcheck
+	return job, nil
+}
+
+func (d *Deployments) processImportJob(
+	ctx context.Context,
+	job *model.ImportJob,
+	req *model.ImportArtifactRequest,
+) error {
+	l := log.FromContext(ctx)
+	ctx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+	job.Status = model.ImportJobStatusProcessing
+	job.Updated = time.Now()
+	if err := d.db.UpdateImportJob(ctx, job); err != nil {
+		l.Errorf("failed to update import job %s: %s", job.ID, err)
+		return err
+	}
+
+	artifactID, err := d.importArtifact(ctx, job.ID, req)
+	if err != nil {
+		l.Warnf("failed to import artifact from %s: %s", job.URL, err)
+		job.Status = model.ImportJobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = model.ImportJobStatusCompleted
+		job.ArtifactID = artifactID
+	}
+	job.Updated = time.Now()
+	if errDB := d.db.UpdateImportJob(ctx, job); errDB != nil {
+		l.Errorf("failed to update import job %s: %s", job.ID, errDB)
+	}
+	return err
+}
+
+// importArtifact fetches the artifact and streams it through the same
+// pipeline as the uploaded artifacts; the artifact gets the job ID.
+func (d *Deployments) importArtifact(
+	ctx context.Context,
+	artifactID string,
+	req *model.ImportArtifactRequest,
+) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	for key, value := range req.Headers {
+		request.Header.Set(key, value)
+	}
+	rsp, err := d.importClient.Do(request)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch the artifact")
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch the artifact: unexpected status code %d",
+			rsp.StatusCode)
+	}
+	return d.handleArtifact(ctx, &model.MultipartUploadMsg{
+		MetaConstructor: &model.ImageMeta{
+			Description: req.Description,
+		},
+		ArtifactID:     artifactID,
+		ArtifactReader: rsp.Body,
+		Checksum:       req.Checksum,
+	}, false, nil)
+}
+
+func (d *Deployments) GetImportJob(ctx context.Context, id string) (*model.ImportJob, error) {
+	job, err := d.db.GetImportJob(ctx, id)
+	if err == store.ErrNotFound {
+		return nil, ErrImportJobNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get the import job")
+	}
+	return job, nil
+}
+
+// failStaleImportJobs marks as failed the import jobs of all the tenants
+// which were interrupted, e.g. by a restart of the service.
+func (d *Deployments) failStaleImportJobs(ctx context.Context, now time.Time) error {
+	l := log.FromContext(ctx)
+	return d.forEachTenant(ctx, "",
+		func(ctx context.Context, _, db string) error {
+			n, err := d.db.FailStaleImportJobs(ctx,
+				now.Add(-importJobStaleTime), "import interrupted")
+			if err != nil {
+				return errors.Wrapf(err,
+					"failed to update the stale import jobs of DB %s", db)
+			} else if n > 0 {
+				l.Warnf("marked %d stale import jobs of DB %s as failed", n, db)
+			}
+			return nil
+		})
+}
@@ -113,6 +113,20 @@ func (d *Deployments) assignArtifact(
 		}
 	}
 
+	// The edited depends of the artifact are not in the artifact file,
+	// the device cannot check them: look for another artifact of the
+	// deployment whose depends the device satisfies.
+	if artifact != nil && !dependsSatisfied(artifact, installed) {
+		log.FromContext(ctx).Infof(
+			"device %s does not satisfy the depends of artifact %s",
+			deviceDeployment.DeviceId, artifact.Id,
+		)
+		artifact, err = d.findArtifactSatisfyingDepends(ctx, deployment, installed)
+		if err != nil {
+			return errors.Wrap(err, "assigning artifact to device deployment")
+		}
+	}
+
 	// If not having appropriate image, set noartifact status
 	if artifact == nil {
 		return d.assignNoArtifact(ctx, deviceDeployment)
@@ -132,6 +146,50 @@ func (d *Deployments) assignArtifact(
 	return nil
 }
 
+// dependsSatisfied returns true if the device satisfies the edited depends
+// of the artifact; the depends of artifacts never edited are checked by the
+// device itself. Devices using the v1 API report no provides but the
+// artifact name and the device type, so they do not satisfy edited depends
+// on other provides.
+func dependsSatisfied(artifact *model.Image, installed *model.InstalledDeviceDeployment) bool {
+	return len(artifact.ArtifactMetaHistory) == 0 ||
+		artifact.ArtifactMeta.DependsSatisfiedBy(installed.AllProvides())
+}
+
+// findArtifactSatisfyingDepends returns the smallest artifact of the
+// deployment compatible with the device whose edited depends the device
+// satisfies, if any.
+func (d *Deployments) findArtifactSatisfyingDepends(
+	ctx context.Context,
+	deployment *model.Deployment,
+	installed *model.InstalledDeviceDeployment,
+) (*model.Image, error) {
+	var candidates []*model.Image
+	var err error
+	if len(deployment.Artifacts) == 0 {
+		candidates, err = d.db.ImagesByNameAndDeviceType(
+			ctx,
+			installed.ArtifactName,
+			installed.DeviceType,
+		)
+	} else {
+		candidates, err = d.db.ImagesByIdsAndDeviceType(
+			ctx,
+			deployment.Artifacts,
+			installed.DeviceType,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		if dependsSatisfied(candidate, installed) {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
 func (d *Deployments) assignNoArtifact(
 	ctx context.Context,
 	deviceDeployment *model.DeviceDeployment,
@@ -154,3 +212,38 @@ func (d *Deployments) assignNoArtifact(
 	}
 	return nil
 }
+
+// abortDeviceDeployment aborts the device deployment which cannot go on,
+// e.g. because its release was revoked after the deployment was created.
+func (d *Deployments) abortDeviceDeployment(
+	ctx context.Context,
+	deviceDeployment *model.DeviceDeployment,
+	reason string,
+) error {
+	l := log.FromContext(ctx)
+	l.Infof("%s, aborting deployment %s for device %s", reason,
+		deviceDeployment.DeploymentId, deviceDeployment.DeviceId)
+	if err := d.UpdateDeviceDeploymentStatus(ctx, deviceDeployment.DeploymentId,
+		deviceDeployment.DeviceId,
+		model.DeviceDeploymentState{
+			Status: model.DeviceDeploymentStatusAborted,
+		}); err != nil {
+		return errors.Wrap(err, "Failed to update deployment status")
+	}
+	return nil
+}
+
+// assignedArtifactIntact returns false if the artifact assigned to the
+// device deployment was found missing or corrupt after the assignment.
+func (d *Deployments) assignedArtifactIntact(
+	ctx context.Context,
+	deviceDeployment *model.DeviceDeployment,
+) (bool, error) {
+	image, err := d.db.FindImageByID(ctx, deviceDeployment.Image.Id)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get the assigned artifact")
+	} else if image == nil {
+		return false, nil
+	}
+	return image.Integrity.IsValid(), nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+)
+
+var (
+	ErrPublicKeyNotFound = errors.New("Public key not found")
+	ErrPublicKeyConflict = errors.New("Public key is already trusted")
+
+	ErrArtifactNotSigned = errors.New(
+		"Artifact is not signed; the signature policy requires signed artifacts",
+	)
+	ErrArtifactSignatureInvalid = errors.New(
+		"Artifact signature cannot be verified with any of the trusted public keys",
+	)
+)
+
+// AddPublicKey adds a public key trusted to sign the tenant's artifacts
+func (d *Deployments) AddPublicKey(
+	ctx context.Context,
+	constructor *model.PublicKeyConstructor,
+) (*model.PublicKey, error) {
+	key, err := model.NewPublicKey(constructor)
+	if err != nil {
+		return nil, err
+	}
+	err = d.db.InsertPublicKey(ctx, key)
+	if err == store.ErrConflict {
+		return nil, ErrPublicKeyConflict
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to store the public key")
+	}
+	return key, nil
+}
+
+func (d *Deployments) ListPublicKeys(ctx context.Context) ([]model.PublicKey, error) {
+	keys, err := d.db.ListPublicKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the public keys")
+	}
+	return keys, nil
+}
+
+func (d *Deployments) GetPublicKey(ctx context.Context, id string) (*model.PublicKey, error) {
+	key, err := d.db.GetPublicKey(ctx, id)
+	if err == store.ErrNotFound {
+		return nil, ErrPublicKeyNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get the public key")
+	}
+	return key, nil
+}
+
+// DeletePublicKey removes the key from the trusted keys; artifacts already
+// verified with the key keep referring to it.
+func (d *Deployments) DeletePublicKey(ctx context.Context, id string) error {
+	err := d.db.DeletePublicKey(ctx, id)
+	if err == store.ErrNotFound {
+		return ErrPublicKeyNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "failed to delete the public key")
+	}
+	return nil
+}
+
+// signatureVerifier verifies artifact signatures against the tenant's
+// trusted public keys while the artifact is read.
+type signatureVerifier struct {
+	keys          []model.PublicKey
+	requireSigned bool
+
+	// ID of the key which verified the signature
+	keyID string
+}
+
+func (d *Deployments) newSignatureVerifier(ctx context.Context) (*signatureVerifier, error) {
+	settings, err := d.db.GetTenantSettings(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get tenant settings")
+	}
+	keys, err := d.db.ListPublicKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the public keys")
+	}
+	return &signatureVerifier{
+		keys:          keys,
+		requireSigned: settings != nil && settings.RequireSignedArtifacts,
+	}, nil
+}
+
+// verify is the signature callback of the artifact reader; without the
+// signature policy, signatures no trusted key verifies are accepted but the
+// artifact is not attributed to any key.
+func (v *signatureVerifier) verify(message, sig []byte) error {
+	for i := range v.keys {
+		if v.keys[i].Verify(message, sig) == nil {
+			v.keyID = v.keys[i].ID
+			return nil
+		}
+	}
+	if v.requireSigned {
+		return ErrArtifactSignatureInvalid
+	}
+	return nil
+}
+
+// checkSigned enforces the signature policy once the artifact is read.
+func (v *signatureVerifier) checkSigned(signed bool) error {
+	if v.requireSigned && !signed {
+		return ErrArtifactNotSigned
+	}
+	return nil
+}
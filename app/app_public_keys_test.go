@@ -0,0 +1,343 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mendersoftware/mender-artifact/artifact"
+	"github.com/mendersoftware/mender-artifact/awriter"
+	"github.com/mendersoftware/mender-artifact/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mocks"
+)
+
+func newTestPublicKey(t *testing.T, id string, pub interface{}) model.PublicKey {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	key, err := model.NewPublicKey(&model.PublicKeyConstructor{
+		Name: id,
+		Key:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	})
+	assert.NoError(t, err)
+	key.ID = id
+	return *key
+}
+
+func writeTestArtifact(t *testing.T, signer artifact.Signer) []byte {
+	var buf bytes.Buffer
+	writer := awriter.NewWriter(&buf, artifact.NewCompressorNone())
+	if signer != nil {
+		writer = awriter.NewWriterSigned(&buf, artifact.NewCompressorNone(), signer)
+	}
+	updateType := "test-update"
+	err := writer.WriteArtifact(&awriter.WriteArtifactArgs{
+		Format:  "mender",
+		Version: 3,
+		Devices: []string{"device-type"},
+		Name:    "release-1",
+		Updates: &awriter.Updates{
+			Updates: []handlers.Composer{handlers.NewModuleImage(updateType)},
+		},
+		Depends: &artifact.ArtifactDepends{
+			CompatibleDevices: []string{"device-type"},
+		},
+		Provides: &artifact.ArtifactProvides{
+			ArtifactName: "release-1",
+		},
+		TypeInfoV3: &artifact.TypeInfoV3{
+			Type: &updateType,
+		},
+	})
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestGetMetaFromArchiveSignature(t *testing.T) {
+	t.Parallel()
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ecdsaPEM, err := x509.MarshalECPrivateKey(ecdsaKey)
+	assert.NoError(t, err)
+	ecdsaSigner, err := artifact.NewPKISigner(
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecdsaPEM}),
+	)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	rsaSigner, err := artifact.NewPKISigner(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+	}))
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	keys := []model.PublicKey{
+		newTestPublicKey(t, "other", otherKey.Public()),
+		newTestPublicKey(t, "ecdsa", ecdsaKey.Public()),
+		newTestPublicKey(t, "rsa", rsaKey.Public()),
+	}
+
+	testCases := map[string]struct {
+		signer        artifact.Signer
+		keys          []model.PublicKey
+		requireSigned bool
+
+		signed bool
+		keyID  string
+		err    error
+	}{
+		"ok, unsigned": {
+			keys: keys,
+		},
+		"ok, ecdsa": {
+			signer:        ecdsaSigner,
+			keys:          keys,
+			requireSigned: true,
+			signed:        true,
+			keyID:         "ecdsa",
+		},
+		"ok, rsa": {
+			signer:        rsaSigner,
+			keys:          keys,
+			requireSigned: true,
+			signed:        true,
+			keyID:         "rsa",
+		},
+		"ok, untrusted key": {
+			signer: rsaSigner,
+			keys:   keys[:1],
+			signed: true,
+		},
+		"error, unsigned": {
+			keys:          keys,
+			requireSigned: true,
+			err:           ErrArtifactNotSigned,
+		},
+		"error, untrusted key": {
+			signer:        rsaSigner,
+			keys:          keys[:1],
+			requireSigned: true,
+			err:           ErrArtifactSignatureInvalid,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := io.Reader(bytes.NewReader(writeTestArtifact(t, tc.signer)))
+			verifier := &signatureVerifier{
+				keys:          tc.keys,
+				requireSigned: tc.requireSigned,
+			}
+			meta, err := getMetaFromArchive(&r, false, verifier)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, meta)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.signed, meta.Signed)
+				assert.Equal(t, tc.keyID, verifier.keyID)
+			}
+		})
+	}
+}
+
+func TestAddPublicKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	assert.NoError(t, err)
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	testCases := map[string]struct {
+		key   string
+		dbErr error
+
+		err error
+	}{
+		"ok": {
+			key: keyPEM,
+		},
+		"error, invalid key": {
+			key: "key",
+			err: model.ErrPublicKeyInvalidPEM,
+		},
+		"error, already trusted": {
+			key:   keyPEM,
+			dbErr: store.ErrConflict,
+			err:   ErrPublicKeyConflict,
+		},
+		"error, db": {
+			key:   keyPEM,
+			dbErr: errors.New("db error"),
+			err:   errors.New("failed to store the public key: db error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			if tc.err != model.ErrPublicKeyInvalidPEM {
+				db.On("InsertPublicKey", ctx,
+					mock.MatchedBy(func(key *model.PublicKey) bool {
+						return key.Algorithm == model.PublicKeyAlgorithmECDSA &&
+							key.Key == tc.key
+					}),
+				).Return(tc.dbErr)
+			}
+
+			ds := NewDeployments(db, nil, 0, false)
+			key, err := ds.AddPublicKey(ctx, &model.PublicKeyConstructor{
+				Name: "key",
+				Key:  tc.key,
+			})
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				assert.Nil(t, key)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, key.ID)
+			}
+		})
+	}
+}
+
+func TestDeletePublicKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		dbErr error
+
+		err error
+	}{
+		"ok": {},
+		"error, not found": {
+			dbErr: store.ErrNotFound,
+			err:   ErrPublicKeyNotFound,
+		},
+		"error, db": {
+			dbErr: errors.New("db error"),
+			err:   errors.New("failed to delete the public key: db error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("DeletePublicKey", ctx, "id").Return(tc.dbErr)
+
+			ds := NewDeployments(db, nil, 0, false)
+			err := ds.DeletePublicKey(ctx, "id")
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandleArtifactSkipVerifySigned(t *testing.T) {
+	t.Parallel()
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ecdsaPEM, err := x509.MarshalECPrivateKey(ecdsaKey)
+	assert.NoError(t, err)
+	signer, err := artifact.NewPKISigner(
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecdsaPEM}),
+	)
+	assert.NoError(t, err)
+
+	// the payload is tampered with after signing: the signature of the
+	// manifest is still valid, but the checksum of the payload is not
+	payload := filepath.Join(t.TempDir(), "payload")
+	assert.NoError(t, os.WriteFile(payload, []byte("original payload"), 0600))
+	var buf bytes.Buffer
+	updateType := "single-file"
+	update := handlers.NewModuleImage(updateType)
+	assert.NoError(t, update.SetUpdateFiles([]*handlers.DataFile{{Name: payload}}))
+	err = awriter.NewWriterSigned(&buf, artifact.NewCompressorNone(), signer).
+		WriteArtifact(&awriter.WriteArtifactArgs{
+			Format:  "mender",
+			Version: 3,
+			Devices: []string{"device-type"},
+			Name:    "release-1",
+			Updates: &awriter.Updates{Updates: []handlers.Composer{update}},
+			Depends: &artifact.ArtifactDepends{
+				CompatibleDevices: []string{"device-type"},
+			},
+			Provides:   &artifact.ArtifactProvides{ArtifactName: "release-1"},
+			TypeInfoV3: &artifact.TypeInfoV3{Type: &updateType},
+		})
+	assert.NoError(t, err)
+	tampered := bytes.Replace(buf.Bytes(),
+		[]byte("original payload"), []byte("tampered payload"), 1)
+
+	const artifactID = "0b2a0e09-3b0c-4b55-9a4f-6a3c4bb0e7d1"
+	db := &mocks.DataStore{}
+	defer db.AssertExpectations(t)
+	objStore := &fs_mocks.ObjectStorage{}
+	defer objStore.AssertExpectations(t)
+	db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+	db.On("GetTenantSettings", mock.Anything).
+		Return(&model.TenantSettings{RequireSignedArtifacts: true}, nil)
+	db.On("ListPublicKeys", mock.Anything).
+		Return([]model.PublicKey{newTestPublicKey(t, "ecdsa", ecdsaKey.Public())}, nil)
+	// the directly uploaded artifact is removed once rejected
+	objStore.On("DeleteObject", mock.Anything, artifactID).Return(nil).Once()
+
+	d := NewDeployments(db, objStore, 0, false)
+	_, err = d.handleArtifact(context.Background(), &model.MultipartUploadMsg{
+		ArtifactID:     artifactID,
+		ArtifactReader: bytes.NewReader(tampered),
+	}, true, nil)
+	assert.ErrorIs(t, err, ErrModelParsingArtifactFailed)
+}
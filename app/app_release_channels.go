@@ -0,0 +1,135 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+)
+
+var (
+	ErrReleaseChannelNotFound = errors.New("release channel not found")
+)
+
+func (d *Deployments) ListReleaseChannels(
+	ctx context.Context,
+) ([]model.ReleaseChannel, error) {
+	channels, err := d.db.GetReleaseChannels(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list release channels")
+	}
+	return channels, nil
+}
+
+func (d *Deployments) GetReleaseChannel(
+	ctx context.Context,
+	name string,
+) (*model.ReleaseChannel, error) {
+	channel, err := d.db.GetReleaseChannel(ctx, name)
+	if err == store.ErrNotFound {
+		return nil, ErrReleaseChannelNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get the release channel")
+	}
+	return channel, nil
+}
+
+// channelsByRelease returns the names of the release channels pointing at
+// each release.
+func (d *Deployments) channelsByRelease(ctx context.Context) (map[string][]string, error) {
+	channels, err := d.db.GetReleaseChannels(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list release channels")
+	}
+	byRelease := make(map[string][]string, len(channels))
+	for _, channel := range channels {
+		byRelease[channel.ReleaseName] = append(byRelease[channel.ReleaseName], channel.Name)
+	}
+	return byRelease, nil
+}
+
+// PromoteRelease points the channel, created if needed, at the release with
+// the given name or alias, or at the release of another channel. Only deployable releases
+// can be promoted; promoting the release the channel already points at
+// changes nothing.
+func (d *Deployments) PromoteRelease(
+	ctx context.Context,
+	channel string,
+	promotion model.ReleasePromotion,
+) (*model.ReleaseChannel, error) {
+	if err := promotion.Validate(channel); err != nil {
+		return nil, errors.Wrap(err, "Validating release promotion")
+	}
+
+	releaseName := promotion.ReleaseName
+	if promotion.FromChannel != "" {
+		from, err := d.GetReleaseChannel(ctx, promotion.FromChannel)
+		if err != nil {
+			return nil, err
+		}
+		releaseName = from.ReleaseName
+	}
+
+	releaseName, status, err := d.findReleaseStatus(ctx, releaseName)
+	if err != nil {
+		return nil, err
+	} else if !status.IsDeployable() {
+		return nil, ErrReleaseNotDeployable
+	}
+
+	entry := &model.ReleaseChannelPromotion{
+		ReleaseName: releaseName,
+		FromChannel: promotion.FromChannel,
+		Promoted:    time.Now(),
+	}
+	current, err := d.db.GetReleaseChannel(ctx, channel)
+	if err == nil {
+		if current.ReleaseName == releaseName {
+			return current, nil
+		}
+		entry.PreviousReleaseName = current.ReleaseName
+	} else if err != store.ErrNotFound {
+		return nil, errors.Wrap(err, "failed to get the release channel")
+	}
+	if idty := identity.FromContext(ctx); idty != nil && idty.IsUser {
+		entry.UserID = idty.Subject
+	}
+
+	if err := d.db.PromoteReleaseChannel(ctx, channel, entry); err != nil {
+		return nil, errors.Wrap(err, "failed to promote the release")
+	}
+	return d.GetReleaseChannel(ctx, channel)
+}
+
+// resolveReleaseChannel sets the artifact name of the deployment to the
+// release the channel points at.
+func (d *Deployments) resolveReleaseChannel(
+	ctx context.Context,
+	constructor *model.DeploymentConstructor,
+) error {
+	channel, err := d.GetReleaseChannel(ctx, constructor.Channel)
+	if err != nil {
+		return err
+	}
+	constructor.ArtifactName = channel.ReleaseName
+	return nil
+}
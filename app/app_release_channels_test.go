@@ -0,0 +1,298 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mocks"
+)
+
+func TestPromoteRelease(t *testing.T) {
+	t.Parallel()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Subject: "user-id",
+		IsUser:  true,
+	})
+	stable := &model.ReleaseChannel{
+		Name:        "stable",
+		ReleaseName: "release-1",
+	}
+	beta := &model.ReleaseChannel{
+		Name:        "beta",
+		ReleaseName: "release-2",
+	}
+
+	testCases := map[string]struct {
+		Channel   string
+		Promotion model.ReleasePromotion
+
+		GetDatabase func(t *testing.T) *mocks.DataStore
+
+		Result *model.ReleaseChannel
+		Error  error
+	}{
+		"ok, release name": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{ReleaseName: "release-2"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseStatus", ctx, "release-2").
+					Return(model.ReleaseStatusApproved, nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(stable, nil).Once()
+				ds.On("PromoteReleaseChannel", ctx, "stable",
+					mock.MatchedBy(func(p *model.ReleaseChannelPromotion) bool {
+						return assert.Equal(t, "release-2", p.ReleaseName) &&
+							assert.Equal(t, "release-1", p.PreviousReleaseName) &&
+							assert.Empty(t, p.FromChannel) &&
+							assert.Equal(t, "user-id", p.UserID) &&
+							assert.WithinDuration(t, time.Now(), p.Promoted, time.Minute)
+					})).
+					Return(nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(&model.ReleaseChannel{
+						Name:        "stable",
+						ReleaseName: "release-2",
+					}, nil).Once()
+				return ds
+			},
+			Result: &model.ReleaseChannel{
+				Name:        "stable",
+				ReleaseName: "release-2",
+			},
+		},
+		"ok, release alias": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{ReleaseName: "latest"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseStatus", ctx, "latest").
+					Return(model.ReleaseStatus(""), store.ErrNotFound)
+				ds.On("GetReleaseNameByAlias", ctx, "latest").
+					Return("release-2", nil)
+				ds.On("GetReleaseStatus", ctx, "release-2").
+					Return(model.ReleaseStatusApproved, nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(stable, nil).Once()
+				ds.On("PromoteReleaseChannel", ctx, "stable",
+					mock.MatchedBy(func(p *model.ReleaseChannelPromotion) bool {
+						return assert.Equal(t, "release-2", p.ReleaseName)
+					})).
+					Return(nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(&model.ReleaseChannel{
+						Name:        "stable",
+						ReleaseName: "release-2",
+					}, nil).Once()
+				return ds
+			},
+			Result: &model.ReleaseChannel{
+				Name:        "stable",
+				ReleaseName: "release-2",
+			},
+		},
+		"ok, from channel, new channel": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{FromChannel: "beta"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseChannel", ctx, "beta").
+					Return(beta, nil)
+				ds.On("GetReleaseStatus", ctx, "release-2").
+					Return(model.ReleaseStatusDeprecated, nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(nil, store.ErrNotFound).Once()
+				ds.On("PromoteReleaseChannel", ctx, "stable",
+					mock.MatchedBy(func(p *model.ReleaseChannelPromotion) bool {
+						return assert.Equal(t, "release-2", p.ReleaseName) &&
+							assert.Empty(t, p.PreviousReleaseName) &&
+							assert.Equal(t, "beta", p.FromChannel)
+					})).
+					Return(nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(&model.ReleaseChannel{
+						Name:        "stable",
+						ReleaseName: "release-2",
+					}, nil).Once()
+				return ds
+			},
+			Result: &model.ReleaseChannel{
+				Name:        "stable",
+				ReleaseName: "release-2",
+			},
+		},
+		"ok, already promoted": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{ReleaseName: "release-1"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseStatus", ctx, "release-1").
+					Return(model.ReleaseStatusApproved, nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(stable, nil)
+				return ds
+			},
+			Result: stable,
+		},
+		"error, invalid promotion": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{FromChannel: "stable"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				return new(mocks.DataStore)
+			},
+			Error: model.ErrReleasePromotionSameChannel,
+		},
+		"error, from channel not found": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{FromChannel: "beta"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseChannel", ctx, "beta").
+					Return(nil, store.ErrNotFound)
+				return ds
+			},
+			Error: ErrReleaseChannelNotFound,
+		},
+		"error, release not found": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{ReleaseName: "release-3"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseStatus", ctx, "release-3").
+					Return(model.ReleaseStatus(""), store.ErrNotFound)
+				ds.On("GetReleaseNameByAlias", ctx, "release-3").
+					Return("", store.ErrNotFound)
+				return ds
+			},
+			Error: ErrReleaseNotFound,
+		},
+		"error, release not deployable": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{ReleaseName: "release-3"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseStatus", ctx, "release-3").
+					Return(model.ReleaseStatusDraft, nil)
+				return ds
+			},
+			Error: ErrReleaseNotDeployable,
+		},
+		"error, promote": {
+			Channel:   "stable",
+			Promotion: model.ReleasePromotion{ReleaseName: "release-2"},
+
+			GetDatabase: func(t *testing.T) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("GetReleaseStatus", ctx, "release-2").
+					Return(model.ReleaseStatusApproved, nil)
+				ds.On("GetReleaseChannel", ctx, "stable").
+					Return(stable, nil)
+				ds.On("PromoteReleaseChannel", ctx, "stable",
+					mock.AnythingOfType("*model.ReleaseChannelPromotion")).
+					Return(errors.New("internal error"))
+				return ds
+			},
+			Error: errors.New("failed to promote the release: internal error"),
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ds := tc.GetDatabase(t)
+			defer ds.AssertExpectations(t)
+
+			app := NewDeployments(ds, nil, 0, false)
+			channel, err := app.PromoteRelease(ctx, tc.Channel, tc.Promotion)
+			if tc.Error != nil {
+				if !errors.Is(err, tc.Error) {
+					assert.EqualError(t, err, tc.Error.Error())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.Result, channel)
+			}
+		})
+	}
+}
+
+func TestCreateDeploymentToReleaseChannel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newConstructor := func() *model.DeploymentConstructor {
+		return &model.DeploymentConstructor{
+			Name:    "stable rollout",
+			Channel: "stable",
+			Devices: []string{"device-1", "device-2"},
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseChannel", ctx, "stable").
+			Return(&model.ReleaseChannel{
+				Name:        "stable",
+				ReleaseName: "release-1",
+			}, nil)
+		ds.On("ImagesByName", ctx, "release-1").
+			Return([]*model.Image{{Id: "artifact-1"}}, nil)
+		ds.On("GetReleaseStatus", ctx, "release-1").
+			Return(model.ReleaseStatusApproved, nil)
+		ds.On("InsertDeployment", ctx,
+			mock.MatchedBy(func(deployment *model.Deployment) bool {
+				return assert.Equal(t, "release-1", deployment.ArtifactName) &&
+					assert.Equal(t, "stable", deployment.Channel) &&
+					assert.Equal(t, []string{"artifact-1"}, deployment.Artifacts)
+			})).
+			Return(nil)
+
+		app := NewDeployments(ds, nil, 0, false)
+		_, err := app.CreateDeployment(ctx, newConstructor())
+		assert.NoError(t, err)
+	})
+
+	t.Run("error, channel not found", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseChannel", ctx, "stable").
+			Return(nil, store.ErrNotFound)
+
+		app := NewDeployments(ds, nil, 0, false)
+		_, err := app.CreateDeployment(ctx, newConstructor())
+		assert.ErrorIs(t, err, ErrReleaseChannelNotFound)
+	})
+}
@@ -16,9 +16,11 @@ package app
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/mendersoftware/go-lib-micro/identity"
 	"github.com/mendersoftware/go-lib-micro/log"
 
 	"github.com/mendersoftware/deployments/model"
@@ -27,7 +29,14 @@ import (
 
 // Errors expected from App interface
 var (
-	ErrReleaseNotFound = errors.New("release not found")
+	ErrReleaseNotFound           = errors.New("release not found")
+	ErrReleaseInActiveDeployment = errors.New("release used in active deployment")
+	ErrReleaseInChannel          = errors.New("release promoted to a release channel")
+	ErrReleaseDeleteFailed       = errors.New("failed to delete some artifacts of the release")
+	ErrReleaseStatusTransition   = errors.New("release status transition not permitted")
+	ErrReleaseNotDeployable      = errors.New("release cannot be deployed in its current status")
+	ErrReleaseTagsChanged        = errors.New("release tags changed concurrently, please retry")
+	ErrReleaseAliasConflict      = errors.New("release name or alias already in use")
 )
 
 func (d *Deployments) updateReleaseEditArtifact(
@@ -106,23 +115,378 @@ func (d *Deployments) ReplaceReleaseTags(
 	return err
 }
 
+// PatchReleaseTags adds and removes tags of the release.
+func (d *Deployments) PatchReleaseTags(
+	ctx context.Context,
+	releaseName string,
+	patch model.TagsPatch,
+) error {
+	err := d.db.PatchReleaseTags(ctx, releaseName, patch)
+	if err != nil {
+		switch err {
+		case store.ErrNotFound:
+			err = ErrReleaseNotFound
+
+		case store.ErrConflict:
+			err = ErrReleaseTagsChanged
+
+		case model.ErrTooManyTags, model.ErrTooManyUniqueTags:
+			// pass
+
+		default:
+			// Rewrite internal errors
+			log.FromContext(ctx).
+				Errorf("failed to patch tags in database: %s", err.Error())
+			err = ErrModelInternal
+		}
+	}
+	return err
+}
+
 func (d *Deployments) UpdateRelease(
 	ctx context.Context,
 	releaseName string,
 	release model.ReleasePatch,
 ) error {
+	if release.Status != "" {
+		err := d.db.UpdateReleaseStatus(ctx, releaseName,
+			release.Status.StatusesTransitioningTo(), release.Status)
+		switch err {
+		case nil:
+		case store.ErrNotFound:
+			return ErrReleaseNotFound
+		case store.ErrConflict:
+			return ErrReleaseStatusTransition
+		default:
+			log.FromContext(ctx).
+				Errorf("failed to update release status in the database: %s", err.Error())
+			return ErrModelInternal
+		}
+		if !release.UpdatesNotes() && release.Alias == nil {
+			return nil
+		}
+	}
+	if release.Alias != nil && *release.Alias != "" && *release.Alias != releaseName {
+		// release names take precedence over aliases, so an alias
+		// matching the name of another release could never be used
+		images, err := d.db.ImagesByName(ctx, *release.Alias)
+		if err != nil {
+			log.FromContext(ctx).
+				Errorf("failed to find the artifacts of the release: %s", err.Error())
+			return ErrModelInternal
+		} else if len(images) > 0 {
+			return ErrReleaseAliasConflict
+		}
+	}
 	err := d.db.UpdateRelease(ctx, releaseName, release)
 	if err != nil {
 		switch err {
 		case store.ErrNotFound:
 			err = ErrReleaseNotFound
 
+		case store.ErrConflict:
+			err = ErrReleaseAliasConflict
+
 		default:
 			// Rewrite internal errors
 			log.FromContext(ctx).
 				Errorf("failed to update release in the database: %s", err.Error())
 			err = ErrModelInternal
 		}
+		return err
 	}
-	return err
+	if release.UpdatesNotes() {
+		revision := &model.ReleaseNotesRevision{
+			ReleaseName: releaseName,
+			Notes:       release.Notes,
+			Modified:    time.Now(),
+		}
+		if idty := identity.FromContext(ctx); idty != nil && idty.IsUser {
+			revision.UserID = idty.Subject
+		}
+		err = d.db.InsertReleaseNotesRevision(ctx, revision)
+		if err != nil {
+			log.FromContext(ctx).
+				Errorf("failed to record the release notes history: %s", err.Error())
+			return ErrModelInternal
+		}
+	}
+	return nil
+}
+
+// GetReleaseNotesHistory returns a page of the revisions of the notes of
+// the release, newest first, and the total number of revisions.
+func (d *Deployments) GetReleaseNotesHistory(
+	ctx context.Context,
+	releaseName string,
+	skip, limit int,
+) ([]model.ReleaseNotesRevision, int, error) {
+	releaseName, _, err := d.findReleaseStatus(ctx, releaseName)
+	if err != nil {
+		return nil, 0, err
+	}
+	revisions, count, err := d.db.GetReleaseNotesRevisions(ctx, releaseName, skip, limit)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get the release notes history")
+	}
+	return revisions, count, nil
+}
+
+// RenderReleaseNotes renders the Markdown notes of the release to
+// sanitized HTML.
+func (d *Deployments) RenderReleaseNotes(
+	ctx context.Context,
+	releaseName string,
+) ([]byte, error) {
+	notes, err := d.db.GetReleaseNotes(ctx, releaseName)
+	if err == store.ErrNotFound {
+		releaseName, err = d.db.GetReleaseNameByAlias(ctx, releaseName)
+		if err == nil {
+			notes, err = d.db.GetReleaseNotes(ctx, releaseName)
+		}
+	}
+	if err == store.ErrNotFound {
+		return nil, ErrReleaseNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get the release notes")
+	}
+	return notes.HTML(), nil
+}
+
+// imagesByReleaseName returns the artifacts of the release with the given
+// name or alias, and the name of the release; release names take
+// precedence over aliases.
+func (d *Deployments) imagesByReleaseName(
+	ctx context.Context,
+	name string,
+) (string, []*model.Image, error) {
+	images, err := d.db.ImagesByName(ctx, name)
+	if err != nil || len(images) > 0 {
+		return name, images, err
+	}
+	releaseName, err := d.db.GetReleaseNameByAlias(ctx, name)
+	if err == store.ErrNotFound {
+		return name, images, nil
+	} else if err != nil {
+		return "", nil, errors.Wrap(err, "failed to resolve the release alias")
+	}
+	images, err = d.db.ImagesByName(ctx, releaseName)
+	return releaseName, images, err
+}
+
+// findReleaseStatus returns the name and the status of the release with
+// the given name or alias; release names take precedence over aliases.
+func (d *Deployments) findReleaseStatus(
+	ctx context.Context,
+	name string,
+) (string, model.ReleaseStatus, error) {
+	status, err := d.db.GetReleaseStatus(ctx, name)
+	if err == store.ErrNotFound {
+		name, err = d.db.GetReleaseNameByAlias(ctx, name)
+		if err == nil {
+			status, err = d.db.GetReleaseStatus(ctx, name)
+		}
+	}
+	if err == store.ErrNotFound {
+		return "", "", ErrReleaseNotFound
+	} else if err != nil {
+		return "", "", errors.Wrap(err, "failed to get the release status")
+	}
+	return name, status, nil
+}
+
+// checkReleaseAlias rejects the name of a new artifact when it is the alias
+// of another release: the new release would take precedence over the alias.
+func (d *Deployments) checkReleaseAlias(ctx context.Context, artifactName string) error {
+	releaseName, err := d.db.GetReleaseNameByAlias(ctx, artifactName)
+	if err == store.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to resolve the release alias")
+	} else if releaseName != artifactName {
+		return ErrReleaseAliasConflict
+	}
+	return nil
+}
+
+// releaseStatus returns the status of the release; releases without a
+// status, or not stored yet, are approved.
+func (d *Deployments) releaseStatus(
+	ctx context.Context,
+	releaseName string,
+) (model.ReleaseStatus, error) {
+	status, err := d.db.GetReleaseStatus(ctx, releaseName)
+	if err == store.ErrNotFound || (err == nil && status == "") {
+		return model.ReleaseStatusApproved, nil
+	} else if err != nil {
+		return "", errors.Wrap(err, "failed to get the release status")
+	}
+	return status, nil
+}
+
+// DeleteReleases deletes the releases with the given names or aliases with
+// all their artifacts. Nothing is deleted if any of the artifacts is used in
+// an active deployment: ErrReleaseInActiveDeployment is returned with the
+// artifacts in use; nor if a release channel points at any of the
+// releases: ErrReleaseInChannel is returned with the channels. The
+// artifacts are otherwise all deleted, even if some of them fail to be;
+// ErrReleaseDeleteFailed is returned in such case. The deletion of every
+// artifact is reported in all cases.
+func (d *Deployments) DeleteReleases(
+	ctx context.Context,
+	releaseNames []string,
+) ([]model.ReleaseArtifactDeletion, error) {
+	var (
+		images  []*model.Image
+		results []model.ReleaseArtifactDeletion
+		err     error
+	)
+	names := make([]string, 0, len(releaseNames))
+	seen := make(map[string]bool, len(releaseNames))
+	for _, name := range releaseNames {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		releaseName, found, err := d.imagesByReleaseName(ctx, name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find the artifacts of the release")
+		} else if releaseName != name {
+			// the alias of a release given along with its name
+			if seen[releaseName] {
+				continue
+			}
+			seen[releaseName] = true
+		}
+		names = append(names, releaseName)
+		images = append(images, found...)
+	}
+	if len(images) == 0 {
+		return nil, ErrReleaseNotFound
+	}
+	channels, err := d.channelsByRelease(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse, inChannel := false, false
+	for _, image := range images {
+		result := model.ReleaseArtifactDeletion{
+			ReleaseName: image.ArtifactMeta.Name,
+			ID:          image.Id,
+			DeviceTypes: image.ArtifactMeta.DeviceTypesCompatible,
+			Channels:    channels[image.ArtifactMeta.Name],
+		}
+		result.InActiveDeployment, err = d.ImageUsedInActiveDeployment(ctx, image.Id)
+		if err != nil {
+			return nil, err
+		}
+		inUse = inUse || result.InActiveDeployment
+		inChannel = inChannel || len(result.Channels) > 0
+		results = append(results, result)
+	}
+	if inUse {
+		return results, ErrReleaseInActiveDeployment
+	} else if inChannel {
+		return results, ErrReleaseInChannel
+	}
+
+	l := log.FromContext(ctx)
+	ctx, err = d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	failed := make(map[string]bool)
+	for i, image := range images {
+		err := d.deleteArtifactFile(ctx, image)
+		if err == nil {
+			err = d.db.DeleteImage(ctx, image.Id)
+		}
+		if err != nil {
+			l.Errorf("failed to delete artifact %s of release %s: %s",
+				image.Id, image.ArtifactMeta.Name, err)
+			results[i].Error = err.Error()
+			failed[image.ArtifactMeta.Name] = true
+			continue
+		}
+		results[i].Deleted = true
+		d.releaseStorage(ctx, image.Size)
+	}
+
+	// the releases with artifacts left keep a document listing them
+	deletedReleases := make([]string, 0, len(names))
+	for _, name := range names {
+		if !failed[name] {
+			deletedReleases = append(deletedReleases, name)
+		}
+	}
+	if len(deletedReleases) > 0 {
+		if err := d.db.DeleteReleases(ctx, deletedReleases); err != nil {
+			return results, errors.Wrap(err, "failed to delete the releases")
+		}
+	}
+	for i, image := range images {
+		if failed[image.ArtifactMeta.Name] && results[i].Deleted {
+			if err := d.updateRelease(ctx, nil, image); err != nil {
+				return results, err
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return results, ErrReleaseDeleteFailed
+	}
+	return results, nil
+}
+
+// CompareReleases reports the differences between the artifacts of the
+// releases from and to, per device type, based on the stored artifact
+// metadata.
+func (d *Deployments) CompareReleases(
+	ctx context.Context,
+	from, to string,
+) (*model.ReleaseComparison, error) {
+	from, fromImages, err := d.imagesByReleaseName(ctx, from)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the artifacts of the release")
+	}
+	if len(fromImages) == 0 {
+		return nil, errors.WithMessage(ErrReleaseNotFound, from)
+	}
+	to, toImages, err := d.imagesByReleaseName(ctx, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the artifacts of the release")
+	}
+	if len(toImages) == 0 {
+		return nil, errors.WithMessage(ErrReleaseNotFound, to)
+	}
+	return model.NewReleaseComparison(from, to, fromImages, toImages), nil
+}
+
+// GetReleaseStatistics reports the number of devices running the release,
+// given by name or alias, and the outcome of its deployments, per device
+// type.
+func (d *Deployments) GetReleaseStatistics(
+	ctx context.Context,
+	releaseName string,
+) (*model.ReleaseStatistics, error) {
+	releaseName, images, err := d.imagesByReleaseName(ctx, releaseName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the artifacts of the release")
+	}
+	deployments, err := d.db.CountDeploymentsByArtifactName(ctx, releaseName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count the deployments of the release")
+	}
+	if deployments == 0 && len(images) == 0 {
+		return nil, ErrReleaseNotFound
+	}
+	installed, err := d.db.GetReleaseInstalledDevices(ctx, releaseName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count the devices running the release")
+	}
+	stats, err := d.db.GetReleaseDeviceDeploymentStats(ctx, releaseName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to aggregate the device deployments of the release")
+	}
+	return model.NewReleaseStatistics(releaseName, deployments, installed, stats), nil
 }
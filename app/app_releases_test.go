@@ -17,13 +17,19 @@ package app
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
 
 	"github.com/mendersoftware/deployments/model"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
 	"github.com/mendersoftware/deployments/store"
 	"github.com/mendersoftware/deployments/store/mocks"
+	"github.com/mendersoftware/deployments/utils/pointers"
 )
 
 func TestReplaceReleaseTags(t *testing.T) {
@@ -116,6 +122,58 @@ func TestReplaceReleaseTags(t *testing.T) {
 	}
 }
 
+func TestPatchReleaseTags(t *testing.T) {
+	t.Parallel()
+
+	patch := model.TagsPatch{
+		Add:    model.Tags{"fips=true"},
+		Remove: model.Tags{"beta"},
+	}
+	testCases := map[string]struct {
+		StoreError error
+
+		Error error
+	}{
+		"ok": {},
+		"error/not found": {
+			StoreError: store.ErrNotFound,
+			Error:      ErrReleaseNotFound,
+		},
+		"error/tags changed": {
+			StoreError: store.ErrConflict,
+			Error:      ErrReleaseTagsChanged,
+		},
+		"error/too many tags": {
+			StoreError: model.ErrTooManyTags,
+			Error:      model.ErrTooManyTags,
+		},
+		"error/internal error": {
+			StoreError: errors.New("internal error with sensitive info"),
+			Error:      ErrModelInternal,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			ds := new(mocks.DataStore)
+			defer ds.AssertExpectations(t)
+			ds.On("PatchReleaseTags", ctx, "foobar", patch).
+				Return(tc.StoreError)
+
+			app := NewDeployments(ds, nil, 0, false)
+
+			err := app.PatchReleaseTags(ctx, "foobar", patch)
+			if tc.Error != nil {
+				assert.ErrorIs(t, err, tc.Error)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestListReleaseTags(t *testing.T) {
 	t.Parallel()
 
@@ -252,6 +310,31 @@ func TestUpdateRelease(t *testing.T) {
 		{
 			Name: "ok",
 
+			Context: identity.WithContext(context.Background(), &identity.Identity{
+				Subject: "user",
+				IsUser:  true,
+			}),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Notes: "New Release fixes 2023"},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("UpdateRelease", self.Context, self.ReleaseName, self.Release).
+					Return(nil)
+				ds.On("InsertReleaseNotesRevision", self.Context,
+					mock.MatchedBy(func(revision *model.ReleaseNotesRevision) bool {
+						return assert.Equal(t, self.ReleaseName, revision.ReleaseName) &&
+							assert.Equal(t, self.Release.Notes, revision.Notes) &&
+							assert.Equal(t, "user", revision.UserID) &&
+							assert.WithinDuration(t, time.Now(), revision.Modified, time.Minute)
+					})).
+					Return(nil)
+				return ds
+			},
+		},
+		{
+			Name: "error/notes history",
+
 			Context:     context.Background(),
 			ReleaseName: "foobar",
 			Release:     model.ReleasePatch{Notes: "New Release fixes 2023"},
@@ -260,8 +343,12 @@ func TestUpdateRelease(t *testing.T) {
 				ds := new(mocks.DataStore)
 				ds.On("UpdateRelease", self.Context, self.ReleaseName, self.Release).
 					Return(nil)
+				ds.On("InsertReleaseNotesRevision", self.Context,
+					mock.AnythingOfType("*model.ReleaseNotesRevision")).
+					Return(errors.New("internal error with sensitive info"))
 				return ds
 			},
+			Error: ErrModelInternal,
 		},
 		{
 			Name: "error/not found",
@@ -293,6 +380,169 @@ func TestUpdateRelease(t *testing.T) {
 			},
 			Error: ErrModelInternal,
 		},
+		{
+			Name: "ok/status",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Status: model.ReleaseStatusRevoked},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("UpdateReleaseStatus", self.Context, self.ReleaseName,
+					mock.MatchedBy(func(from []model.ReleaseStatus) bool {
+						return assert.ElementsMatch(t, []model.ReleaseStatus{
+							model.ReleaseStatusDraft,
+							model.ReleaseStatusApproved,
+							model.ReleaseStatusDeprecated,
+							model.ReleaseStatusRevoked,
+						}, from)
+					}),
+					model.ReleaseStatusRevoked).
+					Return(nil)
+				return ds
+			},
+		},
+		{
+			Name: "ok/status and notes",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release: model.ReleasePatch{
+				Notes:  "Approved for production",
+				Status: model.ReleaseStatusApproved,
+			},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("UpdateReleaseStatus", self.Context, self.ReleaseName,
+					mock.AnythingOfType("[]model.ReleaseStatus"),
+					model.ReleaseStatusApproved).
+					Return(nil)
+				ds.On("UpdateRelease", self.Context, self.ReleaseName, self.Release).
+					Return(nil)
+				ds.On("InsertReleaseNotesRevision", self.Context,
+					mock.AnythingOfType("*model.ReleaseNotesRevision")).
+					Return(nil)
+				return ds
+			},
+		},
+		{
+			Name: "error/status transition",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release: model.ReleasePatch{
+				Notes:  "Approved for production",
+				Status: model.ReleaseStatusApproved,
+			},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("UpdateReleaseStatus", self.Context, self.ReleaseName,
+					mock.AnythingOfType("[]model.ReleaseStatus"),
+					model.ReleaseStatusApproved).
+					Return(store.ErrConflict)
+				return ds
+			},
+			Error: ErrReleaseStatusTransition,
+		},
+		{
+			Name: "error/status not found",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Status: model.ReleaseStatusDraft},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("UpdateReleaseStatus", self.Context, self.ReleaseName,
+					mock.AnythingOfType("[]model.ReleaseStatus"),
+					model.ReleaseStatusDraft).
+					Return(store.ErrNotFound)
+				return ds
+			},
+			Error: ErrReleaseNotFound,
+		},
+		{
+			Name: "error/status internal error",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Status: model.ReleaseStatusDraft},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("UpdateReleaseStatus", self.Context, self.ReleaseName,
+					mock.AnythingOfType("[]model.ReleaseStatus"),
+					model.ReleaseStatusDraft).
+					Return(errors.New("internal error with sensitive info"))
+				return ds
+			},
+			Error: ErrModelInternal,
+		},
+		{
+			Name: "ok/alias",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Alias: pointers.StringToPointer("Summer 2023")},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("ImagesByName", self.Context, "Summer 2023").
+					Return([]*model.Image{}, nil)
+				ds.On("UpdateRelease", self.Context, self.ReleaseName, self.Release).
+					Return(nil)
+				return ds
+			},
+		},
+		{
+			Name: "ok/remove alias",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Alias: pointers.StringToPointer("")},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("UpdateRelease", self.Context, self.ReleaseName, self.Release).
+					Return(nil)
+				return ds
+			},
+		},
+		{
+			Name: "error/alias is a release name",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Alias: pointers.StringToPointer("bazqux")},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("ImagesByName", self.Context, "bazqux").
+					Return([]*model.Image{{Id: "1"}}, nil)
+				return ds
+			},
+			Error: ErrReleaseAliasConflict,
+		},
+		{
+			Name: "error/alias in use",
+
+			Context:     context.Background(),
+			ReleaseName: "foobar",
+			Release:     model.ReleasePatch{Alias: pointers.StringToPointer("Summer 2023")},
+
+			GetDatabase: func(t *testing.T, self *testCase) *mocks.DataStore {
+				ds := new(mocks.DataStore)
+				ds.On("ImagesByName", self.Context, "Summer 2023").
+					Return([]*model.Image{}, nil)
+				ds.On("UpdateRelease", self.Context, self.ReleaseName, self.Release).
+					Return(store.ErrConflict)
+				return ds
+			},
+			Error: ErrReleaseAliasConflict,
+		},
 	}
 
 	for i := range testCases {
@@ -313,3 +563,498 @@ func TestUpdateRelease(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteReleases(t *testing.T) {
+	t.Parallel()
+
+	newImage := func(id, name string) *model.Image {
+		return &model.Image{
+			Id:   id,
+			Size: 10,
+			ArtifactMeta: &model.ArtifactMeta{
+				Name:                  name,
+				DeviceTypesCompatible: []string{"rpi4"},
+			},
+		}
+	}
+	images := map[string][]*model.Image{
+		"release-1": {newImage("1a", "release-1"), newImage("1b", "release-1")},
+		"release-2": {newImage("2a", "release-2")},
+	}
+	aliases := map[string]string{"stable": "release-2"}
+	errInternal := errors.New("internal error")
+
+	testCases := map[string]struct {
+		releaseNames []string
+		inUse        map[string]bool
+		channels     []model.ReleaseChannel
+		deleteErr    map[string]error
+
+		deletedReleases []string
+		updatedRelease  []string
+		deleted         []string
+		err             error
+	}{
+		"ok": {
+			releaseNames:    []string{"release-1", "release-2", "release-1"},
+			deletedReleases: []string{"release-1", "release-2"},
+			deleted:         []string{"1a", "1b", "2a"},
+		},
+		"ok, alias": {
+			releaseNames:    []string{"stable", "release-1", "release-2"},
+			deletedReleases: []string{"release-2", "release-1"},
+			deleted:         []string{"2a", "1a", "1b"},
+		},
+		"error, not found": {
+			releaseNames: []string{"unknown"},
+			err:          ErrReleaseNotFound,
+		},
+		"error, in active deployment": {
+			releaseNames: []string{"release-1", "release-2"},
+			inUse:        map[string]bool{"1b": true},
+			err:          ErrReleaseInActiveDeployment,
+		},
+		"error, in release channel": {
+			releaseNames: []string{"release-1", "release-2"},
+			channels: []model.ReleaseChannel{
+				{Name: "stable", ReleaseName: "release-2"},
+				{Name: "beta", ReleaseName: "release-3"},
+			},
+			err: ErrReleaseInChannel,
+		},
+		"error, artifact not deleted": {
+			releaseNames:    []string{"release-1", "release-2"},
+			deleteErr:       map[string]error{"1b": errInternal},
+			deletedReleases: []string{"release-2"},
+			updatedRelease:  []string{"1a"},
+			deleted:         []string{"1a", "2a"},
+			err:             ErrReleaseDeleteFailed,
+		},
+	}
+
+	for name := range testCases {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			ds := new(mocks.DataStore)
+			defer ds.AssertExpectations(t)
+			objStore := new(fs_mocks.ObjectStorage)
+			defer objStore.AssertExpectations(t)
+
+			var all []*model.Image
+			seen := map[string]bool{}
+			for _, releaseName := range tc.releaseNames {
+				if seen[releaseName] {
+					continue
+				}
+				seen[releaseName] = true
+				ds.On("ImagesByName", ctx, releaseName).
+					Return(images[releaseName], nil).Once()
+				if len(images[releaseName]) == 0 {
+					alias, ok := aliases[releaseName]
+					if !ok {
+						ds.On("GetReleaseNameByAlias", ctx, releaseName).
+							Return("", store.ErrNotFound)
+						continue
+					}
+					ds.On("GetReleaseNameByAlias", ctx, releaseName).
+						Return(alias, nil)
+					ds.On("ImagesByName", ctx, alias).
+						Return(images[alias], nil).Once()
+					seen[alias] = true
+					releaseName = alias
+				}
+				all = append(all, images[releaseName]...)
+			}
+			if len(all) > 0 {
+				ds.On("GetReleaseChannels", ctx).Return(tc.channels, nil)
+			}
+			mockImageInActiveDeployment(ds, tc.inUse)
+			if len(tc.inUse) == 0 && len(tc.channels) == 0 && len(all) > 0 {
+				ds.On("GetStorageSettings", ctx).Return(nil, nil)
+				for _, image := range all {
+					objStore.On("DeleteObject", mock.Anything, image.Id).
+						Return(tc.deleteErr[image.Id])
+				}
+				for _, id := range tc.deleted {
+					ds.On("DeleteImage", mock.Anything, id).Return(nil)
+					ds.On("IncrementStorageUsage", mock.Anything, int64(-10), uint64(0)).
+						Return(nil)
+				}
+				ds.On("DeleteReleases", mock.Anything, tc.deletedReleases).Return(nil)
+				for _, id := range tc.updatedRelease {
+					ds.On("UpdateReleaseArtifacts", mock.Anything, (*model.Image)(nil),
+						mock.MatchedBy(func(image *model.Image) bool {
+							return image.Id == id
+						}), "release-1").Return(nil)
+				}
+			}
+
+			app := NewDeployments(ds, objStore, 0, false)
+			results, err := app.DeleteReleases(ctx, tc.releaseNames)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.err == ErrReleaseNotFound {
+				return
+			}
+			if assert.Len(t, results, len(all)) {
+				for i, result := range results {
+					assert.Equal(t, all[i].Id, result.ID)
+					assert.Equal(t, all[i].ArtifactMeta.Name, result.ReleaseName)
+					assert.Equal(t, tc.inUse[result.ID], result.InActiveDeployment)
+					var channels []string
+					for _, channel := range tc.channels {
+						if channel.ReleaseName == result.ReleaseName {
+							channels = append(channels, channel.Name)
+						}
+					}
+					assert.Equal(t, channels, result.Channels)
+					assert.Equal(t, tc.deleteErr[result.ID] != nil, result.Error != "")
+				}
+			}
+			var deleted []string
+			for _, result := range results {
+				if result.Deleted {
+					deleted = append(deleted, result.ID)
+				}
+			}
+			assert.Equal(t, tc.deleted, deleted)
+		})
+	}
+}
+
+func TestCreateDeploymentWithReleaseAlias(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newConstructor := func() *model.DeploymentConstructor {
+		return &model.DeploymentConstructor{
+			Name:         "summer rollout",
+			ArtifactName: "Summer 2023",
+			Devices:      []string{"device-1", "device-2"},
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("ImagesByName", ctx, "Summer 2023").
+			Return([]*model.Image{}, nil)
+		ds.On("GetReleaseNameByAlias", ctx, "Summer 2023").
+			Return("release-1", nil)
+		ds.On("ImagesByName", ctx, "release-1").
+			Return([]*model.Image{{Id: "artifact-1"}}, nil)
+		ds.On("GetReleaseStatus", ctx, "release-1").
+			Return(model.ReleaseStatusApproved, nil)
+		ds.On("InsertDeployment", ctx,
+			mock.MatchedBy(func(deployment *model.Deployment) bool {
+				return assert.Equal(t, "release-1", deployment.ArtifactName) &&
+					assert.Equal(t, []string{"artifact-1"}, deployment.Artifacts)
+			})).
+			Return(nil)
+
+		app := NewDeployments(ds, nil, 0, false)
+		_, err := app.CreateDeployment(ctx, newConstructor())
+		assert.NoError(t, err)
+	})
+
+	t.Run("error, no such release or alias", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("ImagesByName", ctx, "Summer 2023").
+			Return([]*model.Image{}, nil)
+		ds.On("GetReleaseNameByAlias", ctx, "Summer 2023").
+			Return("", store.ErrNotFound)
+
+		app := NewDeployments(ds, nil, 0, false)
+		_, err := app.CreateDeployment(ctx, newConstructor())
+		assert.ErrorIs(t, err, ErrNoArtifact)
+	})
+}
+
+func TestCompareReleases(t *testing.T) {
+	t.Parallel()
+
+	newImage := func(id, name, version string) *model.Image {
+		return &model.Image{
+			Id: id,
+			ArtifactMeta: &model.ArtifactMeta{
+				Name:                  name,
+				DeviceTypesCompatible: []string{"rpi4"},
+				Provides:              map[string]string{"version": version},
+			},
+		}
+	}
+	images := map[string][]*model.Image{
+		"release-1": {newImage("1a", "release-1", "1")},
+		"release-2": {newImage("2a", "release-2", "2")},
+	}
+	aliases := map[string]string{"stable": "release-2"}
+	errInternal := errors.New("internal error")
+
+	testCases := map[string]struct {
+		from, to string
+		dbErr    error
+
+		comparison *model.ReleaseComparison
+		err        error
+	}{
+		"ok": {
+			from: "release-1",
+			to:   "release-2",
+			comparison: model.NewReleaseComparison(
+				"release-1", "release-2", images["release-1"], images["release-2"],
+			),
+		},
+		"ok, alias": {
+			from: "release-1",
+			to:   "stable",
+			comparison: model.NewReleaseComparison(
+				"release-1", "release-2", images["release-1"], images["release-2"],
+			),
+		},
+		"error, from not found": {
+			from: "unknown",
+			to:   "release-2",
+			err:  ErrReleaseNotFound,
+		},
+		"error, to not found": {
+			from: "release-1",
+			to:   "unknown",
+			err:  ErrReleaseNotFound,
+		},
+		"error, internal": {
+			from:  "release-1",
+			to:    "release-2",
+			dbErr: errInternal,
+			err:   errInternal,
+		},
+	}
+
+	for name := range testCases {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			ds := new(mocks.DataStore)
+			ds.On("ImagesByName", ctx, mock.AnythingOfType("string")).
+				Return(func(_ context.Context, name string) []*model.Image {
+					return images[name]
+				}, tc.dbErr)
+			ds.On("GetReleaseNameByAlias", ctx, mock.AnythingOfType("string")).
+				Return(func(_ context.Context, alias string) string {
+					return aliases[alias]
+				}, func(_ context.Context, alias string) error {
+					if _, ok := aliases[alias]; !ok {
+						return store.ErrNotFound
+					}
+					return nil
+				})
+
+			d := NewDeployments(ds, nil, 0, false)
+			comparison, err := d.CompareReleases(ctx, tc.from, tc.to)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, comparison)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.comparison, comparison)
+			}
+		})
+	}
+}
+
+func TestGetReleaseStatistics(t *testing.T) {
+	t.Parallel()
+
+	installed := map[string]int{"rpi4": 2}
+	stats := map[string]model.Stats{
+		"rpi4": {model.DeviceDeploymentStatusSuccessStr: 2},
+	}
+	errInternal := errors.New("internal error")
+
+	testCases := map[string]struct {
+		alias       bool
+		deployments int
+		images      []*model.Image
+		countErr    error
+		statsErr    error
+
+		statistics *model.ReleaseStatistics
+		err        error
+	}{
+		"ok": {
+			deployments: 1,
+			statistics:  model.NewReleaseStatistics("release", 1, installed, stats),
+		},
+		"ok, never deployed": {
+			images:     []*model.Image{{Id: "artifact"}},
+			statistics: model.NewReleaseStatistics("release", 0, installed, stats),
+		},
+		"ok, alias": {
+			alias:       true,
+			deployments: 1,
+			images:      []*model.Image{{Id: "artifact"}},
+			statistics:  model.NewReleaseStatistics("release", 1, installed, stats),
+		},
+		"error, not found": {
+			err: ErrReleaseNotFound,
+		},
+		"error, count deployments": {
+			countErr: errInternal,
+			err:      errInternal,
+		},
+		"error, device deployment stats": {
+			deployments: 1,
+			statsErr:    errInternal,
+			err:         errInternal,
+		},
+	}
+
+	for name := range testCases {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			ds := new(mocks.DataStore)
+			defer ds.AssertExpectations(t)
+
+			name := "release"
+			if tc.alias {
+				name = "stable"
+				ds.On("ImagesByName", ctx, name).Return(nil, nil)
+				ds.On("GetReleaseNameByAlias", ctx, name).Return("release", nil)
+			} else if len(tc.images) == 0 {
+				ds.On("GetReleaseNameByAlias", ctx, name).Return("", store.ErrNotFound)
+			}
+			ds.On("ImagesByName", ctx, "release").Return(tc.images, nil)
+			ds.On("CountDeploymentsByArtifactName", ctx, "release").
+				Return(tc.deployments, tc.countErr)
+			if tc.countErr == nil && (tc.deployments > 0 || len(tc.images) > 0) {
+				ds.On("GetReleaseInstalledDevices", ctx, "release").
+					Return(installed, nil)
+				ds.On("GetReleaseDeviceDeploymentStats", ctx, "release").
+					Return(stats, tc.statsErr)
+			}
+
+			d := NewDeployments(ds, nil, 0, false)
+			statistics, err := d.GetReleaseStatistics(ctx, name)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, statistics)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.statistics, statistics)
+			}
+		})
+	}
+}
+
+func TestGetReleaseNotesHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	revisions := []model.ReleaseNotesRevision{{
+		ReleaseName: "release-1",
+		Notes:       "# Release 1\n\nSecurity fixes",
+		UserID:      "user",
+		Modified:    time.Now(),
+	}}
+
+	t.Run("ok", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseStatus", ctx, "release-1").
+			Return(model.ReleaseStatusApproved, nil)
+		ds.On("GetReleaseNotesRevisions", ctx, "release-1", 20, 10).
+			Return(revisions, 21, nil)
+
+		app := NewDeployments(ds, nil, 0, false)
+		result, count, err := app.GetReleaseNotesHistory(ctx, "release-1", 20, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, revisions, result)
+		assert.Equal(t, 21, count)
+	})
+
+	t.Run("ok, alias", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseStatus", ctx, "stable").
+			Return(model.ReleaseStatus(""), store.ErrNotFound)
+		ds.On("GetReleaseNameByAlias", ctx, "stable").Return("release-1", nil)
+		ds.On("GetReleaseStatus", ctx, "release-1").
+			Return(model.ReleaseStatusApproved, nil)
+		ds.On("GetReleaseNotesRevisions", ctx, "release-1", 0, 10).
+			Return(revisions, 1, nil)
+
+		app := NewDeployments(ds, nil, 0, false)
+		result, count, err := app.GetReleaseNotesHistory(ctx, "stable", 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, revisions, result)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("error, release not found", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseStatus", ctx, "release-1").
+			Return(model.ReleaseStatus(""), store.ErrNotFound)
+		ds.On("GetReleaseNameByAlias", ctx, "release-1").Return("", store.ErrNotFound)
+
+		app := NewDeployments(ds, nil, 0, false)
+		_, _, err := app.GetReleaseNotesHistory(ctx, "release-1", 0, 10)
+		assert.ErrorIs(t, err, ErrReleaseNotFound)
+	})
+}
+
+func TestRenderReleaseNotes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseNotes", ctx, "release-1").
+			Return(model.Notes("Security *fixes*"), nil)
+
+		app := NewDeployments(ds, nil, 0, false)
+		html, err := app.RenderReleaseNotes(ctx, "release-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "<p>Security <em>fixes</em></p>\n", string(html))
+	})
+
+	t.Run("ok, alias", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseNotes", ctx, "stable").
+			Return(model.Notes(""), store.ErrNotFound)
+		ds.On("GetReleaseNameByAlias", ctx, "stable").Return("release-1", nil)
+		ds.On("GetReleaseNotes", ctx, "release-1").
+			Return(model.Notes("Security *fixes*"), nil)
+
+		app := NewDeployments(ds, nil, 0, false)
+		html, err := app.RenderReleaseNotes(ctx, "stable")
+		assert.NoError(t, err)
+		assert.Equal(t, "<p>Security <em>fixes</em></p>\n", string(html))
+	})
+
+	t.Run("error, release not found", func(t *testing.T) {
+		ds := new(mocks.DataStore)
+		defer ds.AssertExpectations(t)
+		ds.On("GetReleaseNotes", ctx, "release-1").
+			Return(model.Notes(""), store.ErrNotFound)
+		ds.On("GetReleaseNameByAlias", ctx, "release-1").Return("", store.ErrNotFound)
+
+		app := NewDeployments(ds, nil, 0, false)
+		_, err := app.RenderReleaseNotes(ctx, "release-1")
+		assert.ErrorIs(t, err, ErrReleaseNotFound)
+	})
+}
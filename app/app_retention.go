@@ -0,0 +1,237 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func (d *Deployments) GetRetentionPolicy(ctx context.Context) (*model.RetentionPolicy, error) {
+	policy, err := d.db.GetRetentionPolicy(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the retention policy")
+	} else if policy == nil {
+		policy = &model.RetentionPolicy{}
+	}
+	return policy, nil
+}
+
+func (d *Deployments) SetRetentionPolicy(
+	ctx context.Context,
+	policy *model.RetentionPolicy,
+) error {
+	if err := d.db.SetRetentionPolicy(ctx, policy); err != nil {
+		return errors.Wrap(err, "failed to set the retention policy")
+	}
+	return nil
+}
+
+// FindExpiredArtifacts returns the artifacts the retention policy of the
+// tenant selects for deletion at the given time. Artifacts of tagged releases
+// (if the policy keeps them), of releases release channels point at, and
+// artifacts used in active deployments are never selected.
+func (d *Deployments) FindExpiredArtifacts(
+	ctx context.Context,
+	now time.Time,
+) ([]model.ExpiredArtifact, error) {
+	policy, err := d.db.GetRetentionPolicy(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the retention policy")
+	} else if policy == nil || policy.IsEmpty() {
+		return nil, nil
+	}
+	images, _, err := d.db.ListImages(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list artifacts")
+	}
+
+	var kept map[string]bool
+	if policy.KeepLastPerDeviceType > 0 {
+		kept = newestImagesPerDeviceType(images, policy.KeepLastPerDeviceType)
+	}
+	var lastDeployed map[string]time.Time
+	if policy.DeleteUnusedAfterDays > 0 {
+		lastDeployed, err = d.db.GetArtifactsLastDeployed(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the last use of artifacts")
+		}
+	}
+	tagged := make(map[string]bool)
+	if policy.KeepTaggedReleases {
+		names, err := d.db.ListTaggedReleaseNames(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list tagged releases")
+		}
+		for _, name := range names {
+			tagged[name] = true
+		}
+	}
+	channels, err := d.channelsByRelease(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unusedSince := now.AddDate(0, 0, -policy.DeleteUnusedAfterDays)
+
+	var expired []model.ExpiredArtifact
+	for _, image := range images {
+		if image.ArtifactMeta == nil || tagged[image.ArtifactMeta.Name] ||
+			len(channels[image.ArtifactMeta.Name]) > 0 {
+			continue
+		}
+		artifact := model.ExpiredArtifact{
+			ID:          image.Id,
+			Name:        image.ArtifactMeta.Name,
+			DeviceTypes: image.ArtifactMeta.DeviceTypesCompatible,
+			Size:        image.Size,
+		}
+		if image.Modified != nil {
+			artifact.LastUsed = *image.Modified
+		}
+		if deployed := lastDeployed[image.Id]; deployed.After(artifact.LastUsed) {
+			artifact.LastUsed = deployed
+		}
+		if policy.KeepLastPerDeviceType > 0 {
+			if kept[image.Id] {
+				continue
+			}
+			artifact.Reasons = append(artifact.Reasons, model.RetentionReasonKeepLast)
+		}
+		if policy.DeleteUnusedAfterDays > 0 {
+			if !artifact.LastUsed.Before(unusedSince) {
+				continue
+			}
+			artifact.Reasons = append(artifact.Reasons, model.RetentionReasonUnused)
+		}
+		inUse, err := d.ImageUsedInActiveDeployment(ctx, image.Id)
+		if err != nil {
+			return nil, err
+		} else if inUse {
+			continue
+		}
+		expired = append(expired, artifact)
+	}
+	return expired, nil
+}
+
+// newestImagesPerDeviceType returns the IDs of the n most recently uploaded
+// images of each device type; editing an image does not make it newer.
+func newestImagesPerDeviceType(images []*model.Image, n int) map[string]bool {
+	byDeviceType := make(map[string][]*model.Image)
+	for _, image := range images {
+		if image.ArtifactMeta == nil {
+			continue
+		}
+		for _, deviceType := range image.ArtifactMeta.DeviceTypesCompatible {
+			byDeviceType[deviceType] = append(byDeviceType[deviceType], image)
+		}
+	}
+	kept := make(map[string]bool)
+	for _, deviceTypeImages := range byDeviceType {
+		sort.SliceStable(deviceTypeImages, func(i, j int) bool {
+			return uploadTime(deviceTypeImages[i]).
+				After(uploadTime(deviceTypeImages[j]))
+		})
+		for i := 0; i < n && i < len(deviceTypeImages); i++ {
+			kept[deviceTypeImages[i].Id] = true
+		}
+	}
+	return kept
+}
+
+// uploadTime returns the upload time of the image, or its last
+// modification time for images uploaded before the upload time was
+// recorded.
+func uploadTime(image *model.Image) time.Time {
+	if image.Uploaded != nil {
+		return *image.Uploaded
+	} else if image.Modified != nil {
+		return *image.Modified
+	}
+	return time.Time{}
+}
+
+// ApplyRetentionPolicy deletes the artifacts the retention policy of the
+// tenant selects for deletion and returns them; in dry-run mode the
+// artifacts are only returned.
+func (d *Deployments) ApplyRetentionPolicy(
+	ctx context.Context,
+	dryRun bool,
+) ([]model.ExpiredArtifact, error) {
+	l := log.FromContext(ctx)
+	expired, err := d.FindExpiredArtifacts(ctx, time.Now())
+	if err != nil || dryRun {
+		return expired, err
+	}
+	for i := range expired {
+		err := d.DeleteImage(ctx, expired[i].ID)
+		switch errors.Cause(err) {
+		case nil:
+			expired[i].Deleted = true
+		case ErrImageMetaNotFound:
+			// deleted in the meantime
+		default:
+			l.Errorf("failed to delete expired artifact %s: %s", expired[i].ID, err)
+			expired[i].Error = err.Error()
+		}
+	}
+	return expired, nil
+}
+
+// ApplyRetentionPolicies applies the retention policies of all the tenants,
+// or of the given tenant only, every `interval`; a zero interval applies the
+// policies once. The affected artifacts are reported to `report` as JSON
+// lines, if not nil.
+func (d *Deployments) ApplyRetentionPolicies(
+	ctx context.Context,
+	tenantID string,
+	interval time.Duration,
+	dryRun bool,
+	report io.Writer,
+) error {
+	var encoder *json.Encoder
+	if report != nil {
+		encoder = json.NewEncoder(report)
+	}
+
+	return runPeriodically(ctx, interval, func() error {
+		return d.forEachTenant(ctx, tenantID,
+			func(ctx context.Context, tenant, db string) error {
+				expired, err := d.ApplyRetentionPolicy(ctx, dryRun)
+				if err != nil {
+					return errors.Wrapf(err,
+						"failed to apply the retention policy of DB %s", db)
+				}
+				for i := range expired {
+					expired[i].TenantID = tenant
+					if encoder == nil {
+						continue
+					}
+					if err := encoder.Encode(expired[i]); err != nil {
+						return errors.Wrap(err, "failed to write the report")
+					}
+				}
+				return nil
+			})
+	})
+}
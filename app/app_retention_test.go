@@ -0,0 +1,298 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store/mocks"
+)
+
+func retentionTestImage(id, name string, modified time.Time, deviceTypes ...string) *model.Image {
+	return &model.Image{
+		Id: id,
+		ArtifactMeta: &model.ArtifactMeta{
+			Name:                  name,
+			DeviceTypesCompatible: deviceTypes,
+		},
+		Size:     10,
+		Modified: &modified,
+	}
+}
+
+func mockImageInActiveDeployment(db *mocks.DataStore, inUse map[string]bool) {
+	db.On("ExistUnfinishedByArtifactId", mock.Anything, mock.AnythingOfType("string")).
+		Return(func(_ context.Context, id string) bool {
+			return inUse[id]
+		}, nil).
+		Maybe()
+	args := []interface{}{mock.Anything, mock.AnythingOfType("string")}
+	for _, status := range model.ActiveDeploymentStatuses() {
+		args = append(args, status)
+	}
+	db.On("ExistAssignedImageWithIDAndStatuses", args...).
+		Return(false, nil).
+		Maybe()
+}
+
+func TestFindExpiredArtifacts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.AddDate(0, 0, -days)
+	}
+	images := []*model.Image{
+		retentionTestImage("a1", "release-1", daysAgo(100), "dt1"),
+		retentionTestImage("a2", "release-2", daysAgo(50), "dt1"),
+		retentionTestImage("a3", "release-3", daysAgo(10), "dt1"),
+		retentionTestImage("b1", "release-1", daysAgo(100), "dt2"),
+	}
+	lastDeployed := map[string]time.Time{
+		"b1": daysAgo(5),
+	}
+
+	testCases := []struct {
+		Name string
+
+		Policy    *model.RetentionPolicy
+		PolicyErr error
+		Tagged    []string
+		Channels  []model.ReleaseChannel
+		InUse     map[string]bool
+
+		Expected []model.ExpiredArtifact
+		Error    error
+	}{{
+		Name: "ok, no policy",
+	}, {
+		Name:   "ok, empty policy",
+		Policy: &model.RetentionPolicy{KeepTaggedReleases: true},
+	}, {
+		Name:   "ok, keep last",
+		Policy: &model.RetentionPolicy{KeepLastPerDeviceType: 2},
+		Expected: []model.ExpiredArtifact{{
+			ID:          "a1",
+			Name:        "release-1",
+			DeviceTypes: []string{"dt1"},
+			Size:        10,
+			LastUsed:    daysAgo(100),
+			Reasons:     []string{model.RetentionReasonKeepLast},
+		}},
+	}, {
+		Name:   "ok, unused",
+		Policy: &model.RetentionPolicy{DeleteUnusedAfterDays: 30},
+		Expected: []model.ExpiredArtifact{{
+			ID:          "a1",
+			Name:        "release-1",
+			DeviceTypes: []string{"dt1"},
+			Size:        10,
+			LastUsed:    daysAgo(100),
+			Reasons:     []string{model.RetentionReasonUnused},
+		}, {
+			ID:          "a2",
+			Name:        "release-2",
+			DeviceTypes: []string{"dt1"},
+			Size:        10,
+			LastUsed:    daysAgo(50),
+			Reasons:     []string{model.RetentionReasonUnused},
+		}},
+	}, {
+		Name: "ok, all rules must match",
+		Policy: &model.RetentionPolicy{
+			KeepLastPerDeviceType: 2,
+			DeleteUnusedAfterDays: 30,
+		},
+		Expected: []model.ExpiredArtifact{{
+			ID:          "a1",
+			Name:        "release-1",
+			DeviceTypes: []string{"dt1"},
+			Size:        10,
+			LastUsed:    daysAgo(100),
+			Reasons: []string{
+				model.RetentionReasonKeepLast,
+				model.RetentionReasonUnused,
+			},
+		}},
+	}, {
+		Name: "ok, keep tagged releases and active deployments",
+		Policy: &model.RetentionPolicy{
+			DeleteUnusedAfterDays: 5,
+			KeepTaggedReleases:    true,
+		},
+		Tagged: []string{"release-1"},
+		InUse:  map[string]bool{"a3": true},
+		Expected: []model.ExpiredArtifact{{
+			ID:          "a2",
+			Name:        "release-2",
+			DeviceTypes: []string{"dt1"},
+			Size:        10,
+			LastUsed:    daysAgo(50),
+			Reasons:     []string{model.RetentionReasonUnused},
+		}},
+	}, {
+		Name:   "ok, keep the releases of channels",
+		Policy: &model.RetentionPolicy{DeleteUnusedAfterDays: 30},
+		Channels: []model.ReleaseChannel{
+			{Name: "stable", ReleaseName: "release-1"},
+		},
+		Expected: []model.ExpiredArtifact{{
+			ID:          "a2",
+			Name:        "release-2",
+			DeviceTypes: []string{"dt1"},
+			Size:        10,
+			LastUsed:    daysAgo(50),
+			Reasons:     []string{model.RetentionReasonUnused},
+		}},
+	}, {
+		Name:      "error, policy",
+		PolicyErr: errors.New("internal error"),
+		Error:     errors.New("failed to get the retention policy: internal error"),
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("GetRetentionPolicy", mock.Anything).
+				Return(tc.Policy, tc.PolicyErr)
+			if tc.Policy != nil && !tc.Policy.IsEmpty() {
+				db.On("ListImages", mock.Anything, (*model.ReleaseOrImageFilter)(nil)).
+					Return(images, len(images), nil)
+				if tc.Policy.DeleteUnusedAfterDays > 0 {
+					db.On("GetArtifactsLastDeployed", mock.Anything).
+						Return(lastDeployed, nil)
+				}
+				if tc.Policy.KeepTaggedReleases {
+					db.On("ListTaggedReleaseNames", mock.Anything).
+						Return(tc.Tagged, nil)
+				}
+				db.On("GetReleaseChannels", mock.Anything).Return(tc.Channels, nil)
+				mockImageInActiveDeployment(db, tc.InUse)
+			}
+
+			d := NewDeployments(db, nil, 0, false)
+			expired, err := d.FindExpiredArtifacts(context.Background(), now)
+			if tc.Error != nil {
+				assert.EqualError(t, err, tc.Error.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.Expected, expired)
+		})
+	}
+}
+
+func TestNewestImagesPerDeviceType(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	uploaded := func(image *model.Image, days int) *model.Image {
+		at := now.AddDate(0, 0, -days)
+		image.Uploaded = &at
+		return image
+	}
+	images := []*model.Image{
+		// edited today, but the oldest upload
+		uploaded(retentionTestImage("edited", "release-1", now, "dt1"), 100),
+		uploaded(retentionTestImage("new", "release-2", now.AddDate(0, 0, -50), "dt1"), 10),
+		// uploaded before the upload time was recorded
+		retentionTestImage("legacy", "release-3", now.AddDate(0, 0, -20), "dt1"),
+	}
+
+	kept := newestImagesPerDeviceType(images, 2)
+	assert.Equal(t, map[string]bool{"new": true, "legacy": true}, kept)
+}
+
+func TestApplyRetentionPolicies(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Now().AddDate(0, 0, -100)
+	image := retentionTestImage("a1", "release-1", modified, "dt1")
+	images := []*model.Image{image}
+
+	testCases := []struct {
+		Name string
+
+		DryRun bool
+	}{{
+		Name:   "dry run",
+		DryRun: true,
+	}, {
+		Name: "delete",
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+
+			db.On("GetTenantDbs").Return([]string{"deployment_service-tenant"}, nil)
+			db.On("GetRetentionPolicy", mock.Anything).
+				Return(&model.RetentionPolicy{DeleteUnusedAfterDays: 30}, nil)
+			db.On("ListImages", mock.Anything, (*model.ReleaseOrImageFilter)(nil)).
+				Return(images, len(images), nil)
+			db.On("GetArtifactsLastDeployed", mock.Anything).
+				Return(map[string]time.Time{}, nil)
+			db.On("GetReleaseChannels", mock.Anything).Return(nil, nil)
+			mockImageInActiveDeployment(db, nil)
+			if !tc.DryRun {
+				db.On("FindImageByID", mock.Anything, image.Id).
+					Return(image, nil)
+				db.On("GetStorageSettings", mock.Anything).
+					Return(nil, nil)
+				objStore.On("DeleteObject", mock.Anything, "tenant/"+image.Id).
+					Return(nil)
+				db.On("DeleteImage", mock.Anything, image.Id).
+					Return(nil)
+				db.On("IncrementStorageUsage", mock.Anything, -image.Size, uint64(0)).
+					Return(nil)
+				db.On("UpdateReleaseArtifacts", mock.Anything,
+					(*model.Image)(nil), image, image.ArtifactMeta.Name).
+					Return(nil)
+			}
+
+			d := NewDeployments(db, objStore, 0, false)
+			report := &bytes.Buffer{}
+			err := d.ApplyRetentionPolicies(context.Background(), "", 0, tc.DryRun, report)
+			assert.NoError(t, err)
+
+			var expired model.ExpiredArtifact
+			if assert.NoError(t, json.NewDecoder(report).Decode(&expired)) {
+				assert.Equal(t, "tenant", expired.TenantID)
+				assert.Equal(t, image.Id, expired.ID)
+				assert.Equal(t, !tc.DryRun, expired.Deleted)
+			}
+		})
+	}
+}
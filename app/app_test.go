@@ -36,6 +36,7 @@ import (
 	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
 	"github.com/mendersoftware/deployments/store"
 	"github.com/mendersoftware/deployments/store/mocks"
+	"github.com/mendersoftware/deployments/store/mongo"
 	h "github.com/mendersoftware/deployments/utils/testing"
 	"github.com/mendersoftware/go-lib-micro/config"
 	"github.com/mendersoftware/go-lib-micro/identity"
@@ -168,6 +169,8 @@ func TestDeploymentModelCreateDeployment(t *testing.T) {
 
 		InputDeploymentStorageInsertError error
 		InputImagesByNameError            error
+		InputReleaseStatus                model.ReleaseStatus
+		InputReleaseStatusError           error
 
 		InvDevices        []model.InvDevice
 		InvDevicesPageTwo []model.InvDevice
@@ -198,6 +201,47 @@ func TestDeploymentModelCreateDeployment(t *testing.T) {
 
 			OutputError: errors.New("Storing deployment data: insert error"),
 		},
+		"release draft": {
+			InputConstructor: &model.DeploymentConstructor{
+				Name:         "NYC Production",
+				ArtifactName: "App 123",
+				Devices:      []string{"b532b01a-9313-404f-8d19-e7fcbe5cc347"},
+			},
+			InputReleaseStatus: model.ReleaseStatusDraft,
+
+			OutputError: ErrReleaseNotDeployable,
+		},
+		"release revoked": {
+			InputConstructor: &model.DeploymentConstructor{
+				Name:         "NYC Production",
+				ArtifactName: "App 123",
+				Devices:      []string{"b532b01a-9313-404f-8d19-e7fcbe5cc347"},
+			},
+			InputReleaseStatus: model.ReleaseStatusRevoked,
+
+			OutputError: ErrReleaseNotDeployable,
+		},
+		"release status error": {
+			InputConstructor: &model.DeploymentConstructor{
+				Name:         "NYC Production",
+				ArtifactName: "App 123",
+				Devices:      []string{"b532b01a-9313-404f-8d19-e7fcbe5cc347"},
+			},
+			InputReleaseStatusError: errors.New("status error"),
+
+			OutputError: errors.New("failed to get the release status: status error"),
+		},
+		"ok deprecated": {
+			InputConstructor: &model.DeploymentConstructor{
+				Name:         "NYC Production",
+				ArtifactName: "App 123",
+				Devices:      []string{"b532b01a-9313-404f-8d19-e7fcbe5cc347"},
+			},
+			InputReleaseStatus:  model.ReleaseStatusDeprecated,
+			CallGetDeviceGroups: true,
+
+			OutputBody: true,
+		},
 		"ok": {
 			InputConstructor: &model.DeploymentConstructor{
 				Name:         "NYC Production",
@@ -313,6 +357,8 @@ func TestDeploymentModelCreateDeployment(t *testing.T) {
 							Depends: map[string]interface{}{},
 						}, artifactSize)},
 					testCase.InputImagesByNameError)
+			db.On("GetReleaseStatus", ctx, "App 123").
+				Return(testCase.InputReleaseStatus, testCase.InputReleaseStatusError)
 
 			fs := &fs_mocks.ObjectStorage{}
 			ds := NewDeployments(&db, fs, 0, false)
@@ -616,6 +662,12 @@ func TestCompleteUpload(t *testing.T) {
 
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
+			ds.On("GetTenantSettings", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil).
+				On("ListPublicKeys", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once().
@@ -671,6 +723,12 @@ func TestCompleteUpload(t *testing.T) {
 
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
+			ds.On("GetTenantSettings", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil).
+				On("ListPublicKeys", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once().
@@ -697,6 +755,12 @@ func TestCompleteUpload(t *testing.T) {
 				contextHasIdentity(t, self.Identity),
 				intentID).
 				Return(r, nil)
+			// the rejected artifact is removed from its final path
+			os.On("DeleteObject",
+				contextHasIdentity(t, self.Identity),
+				intentID).
+				Return(nil).
+				Maybe()
 			self.syncChan = r.ch
 			return os
 		},
@@ -724,6 +788,12 @@ func TestCompleteUpload(t *testing.T) {
 		},
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
+			ds.On("GetTenantSettings", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil).
+				On("ListPublicKeys", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once().
@@ -783,6 +853,12 @@ func TestCompleteUpload(t *testing.T) {
 		},
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
+			ds.On("GetTenantSettings", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil).
+				On("ListPublicKeys", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once().
@@ -810,6 +886,11 @@ func TestCompleteUpload(t *testing.T) {
 				contextHasIdentity(t, self.Identity),
 				objectPath).
 				Return(r, nil)
+			os.On("DeleteObject",
+				contextHasIdentity(t, self.Identity),
+				objectPath).
+				Return(nil).
+				Maybe()
 			self.syncChan = r.ch
 			return os
 		},
@@ -834,6 +915,8 @@ func TestCompleteUpload(t *testing.T) {
 
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once().
@@ -877,6 +960,8 @@ func TestCompleteUpload(t *testing.T) {
 
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once().
@@ -920,6 +1005,8 @@ func TestCompleteUpload(t *testing.T) {
 
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once()
@@ -943,6 +1030,8 @@ func TestCompleteUpload(t *testing.T) {
 
 		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
 			ds := new(mocks.DataStore)
+			ds.On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(nil, mongo.ErrLimitNotFound)
 			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
 				Return(nil, nil).
 				Once()
@@ -979,6 +1068,34 @@ func TestCompleteUpload(t *testing.T) {
 		ErrorAssertionFunc: func(t *testing.T, self *testCase, err error) {
 			assert.ErrorIs(t, err, testErr)
 		},
+	}, {
+		Name: "error/storage limit exceeded",
+
+		Database: func(t *testing.T, self *testCase) *mocks.DataStore {
+			ds := new(mocks.DataStore)
+			ds.On("GetStorageSettings", contextHasIdentity(t, self.Identity)).
+				Return(nil, nil).
+				Once().
+				On("GetLimit", contextHasIdentity(t, self.Identity), model.LimitStorage).
+				Return(&model.Limit{Name: model.LimitStorage, Value: 100}, nil).
+				On("GetStorageUsage", contextHasIdentity(t, self.Identity)).
+				Return(int64(90), nil)
+			return ds
+		},
+		ObjectStorage: func(t *testing.T, self *testCase) *fs_mocks.ObjectStorage {
+			os := new(fs_mocks.ObjectStorage)
+			size := int64(20)
+			os.On("StatObject",
+				contextHasIdentity(t, self.Identity),
+				intentID+fileSuffixTmp).
+				Return(&storage.ObjectInfo{Size: &size}, nil).
+				Once()
+			return os
+		},
+
+		ErrorAssertionFunc: func(t *testing.T, self *testCase, err error) {
+			assert.ErrorIs(t, err, ErrStorageLimitExceeded)
+		},
 	}}
 	for i := range testCases {
 		tc := testCases[i]
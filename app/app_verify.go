@@ -0,0 +1,177 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/pkg/errors"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	"github.com/mendersoftware/deployments/store"
+)
+
+// verifyArtifact reads the stored object of the image in full and compares
+// it with the checksum recorded on upload, or with the recorded size for
+// artifacts uploaded before checksums were recorded; the size of direct
+// uploads used to be the one declared by the client.
+func (d *Deployments) verifyArtifact(
+	ctx context.Context,
+	image *model.Image,
+	now time.Time,
+) (*model.ImageIntegrity, error) {
+	integrity := &model.ImageIntegrity{Checked: now}
+	obj, err := d.objectStorage.GetObject(ctx, image.ObjectPathFromContext(ctx))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		integrity.Status = model.IntegrityStatusMissing
+		return integrity, nil
+	} else if err != nil {
+		return nil, errors.WithMessage(err, "failed to get the artifact object")
+	}
+	defer obj.Close()
+
+	checksum := sha256.New()
+	integrity.Size, err = io.Copy(checksum, obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the artifact object")
+	}
+	integrity.Checksum = hex.EncodeToString(checksum.Sum(nil))
+	var intact bool
+	if image.Checksum != "" {
+		intact = strings.EqualFold(integrity.Checksum, image.Checksum)
+	} else {
+		intact = integrity.Size == image.Size
+	}
+	if !intact {
+		integrity.Status = model.IntegrityStatusCorrupt
+	} else {
+		integrity.Status = model.IntegrityStatusOK
+	}
+	return integrity, nil
+}
+
+// VerifyArtifacts verifies the stored objects of all the artifacts of the
+// tenant and records the result on the artifacts, unless in dry-run mode;
+// artifacts missing or corrupt are not assigned to devices. The
+// verification of the artifacts which are not intact, which could not be
+// verified, or whose missing checksum was recorded is passed to `report`.
+func (d *Deployments) VerifyArtifacts(
+	ctx context.Context,
+	dryRun bool,
+	report func(*model.ArtifactVerification) error,
+) error {
+	l := log.FromContext(ctx)
+	ctx, err := d.contextWithStorageSettings(ctx)
+	if err != nil {
+		return err
+	}
+	it, err := d.db.FindImages(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the artifacts")
+	}
+	defer it.Close(ctx) // nolint:errcheck
+
+	for {
+		next, err := it.Next(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to list the artifacts")
+		} else if !next {
+			break
+		}
+		var image model.Image
+		if err = it.Decode(&image); err != nil {
+			return errors.Wrap(err, "failed to decode the artifact")
+		}
+		verification := &model.ArtifactVerification{ID: image.Id}
+		if image.ArtifactMeta != nil {
+			verification.Name = image.ArtifactMeta.Name
+		}
+
+		integrity, err := d.verifyArtifact(ctx, &image, time.Now())
+		if err != nil {
+			l.Errorf("failed to verify artifact %s: %s", image.Id, err)
+			verification.Error = err.Error()
+			if err = report(verification); err != nil {
+				return err
+			}
+			continue
+		}
+		verification.ImageIntegrity = *integrity
+
+		var checksum string
+		if image.Checksum == "" && integrity.Status == model.IntegrityStatusOK {
+			checksum = integrity.Checksum
+			verification.Backfilled = true
+		}
+		if !integrity.IsValid() {
+			l.Warnf("artifact %s is %s", image.Id, integrity.Status)
+		}
+		if !dryRun {
+			err = d.db.SetImageIntegrity(ctx, image.Id, checksum, integrity)
+			if err != nil && !errors.Is(err, store.ErrNotFound) {
+				return errors.Wrapf(err,
+					"failed to record the integrity of artifact %s", image.Id)
+			}
+		}
+		if !integrity.IsValid() || verification.Backfilled {
+			if err = report(verification); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyTenantsArtifacts verifies the artifacts of all the tenants, or of
+// the given tenant only, every `interval`; a zero interval verifies the
+// artifacts once. The reported verifications are written to `report` as
+// JSON lines, if not nil.
+func (d *Deployments) VerifyTenantsArtifacts(
+	ctx context.Context,
+	tenantID string,
+	interval time.Duration,
+	dryRun bool,
+	report io.Writer,
+) error {
+	var encoder *json.Encoder
+	if report != nil {
+		encoder = json.NewEncoder(report)
+	}
+
+	return runPeriodically(ctx, interval, func() error {
+		return d.forEachTenant(ctx, tenantID,
+			func(ctx context.Context, tenant, db string) error {
+				err := d.VerifyArtifacts(ctx, dryRun,
+					func(verification *model.ArtifactVerification) error {
+						verification.TenantID = tenant
+						if encoder == nil {
+							return nil
+						}
+						return errors.Wrap(encoder.Encode(verification),
+							"failed to write the report")
+					})
+				return errors.Wrapf(err,
+					"failed to verify the artifacts of DB %s", db)
+			})
+	})
+}
@@ -0,0 +1,328 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mendersoftware/go-lib-micro/identity"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/storage"
+	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store"
+	"github.com/mendersoftware/deployments/store/mocks"
+	"github.com/mendersoftware/deployments/store/mongo"
+)
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHandleArtifactSkipVerifyChecksum(t *testing.T) {
+	t.Parallel()
+
+	artifact := writeTestArtifact(t, nil)
+	const artifactID = "0b2a0e09-3b0c-4b55-9a4f-6a3c4bb0e7d1"
+
+	db := &mocks.DataStore{}
+	defer db.AssertExpectations(t)
+	objStore := &fs_mocks.ObjectStorage{}
+	defer objStore.AssertExpectations(t)
+
+	db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+	db.On("GetTenantSettings", mock.Anything).Return(nil, nil)
+	db.On("ListPublicKeys", mock.Anything).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", mock.Anything, "release-1").
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", mock.Anything, model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
+	db.On("IncrementStorageUsage", mock.Anything, int64(len(artifact)), uint64(0)).
+		Return(nil)
+	db.On("IncrementStorageUsage", mock.Anything, -int64(len(artifact)), uint64(0)).
+		Return(nil)
+	objectPath := "objects/" + sha256Hex(artifact)
+	objStore.On("StatObject", mock.Anything, objectPath).
+		Return(&storage.ObjectInfo{}, nil)
+	db.On("AddArtifactObjectReference", mock.Anything, sha256Hex(artifact),
+		objectPath, int64(len(artifact)), artifactID).
+		Return(&model.ArtifactObject{
+			ID:       sha256Hex(artifact),
+			Path:     objectPath,
+			RefCount: 1,
+		}, nil)
+	db.On("RemoveArtifactObjectReference", mock.Anything, sha256Hex(artifact),
+		artifactID).
+		Return(&model.ArtifactObject{ID: sha256Hex(artifact), Path: objectPath}, nil)
+	db.On("DeleteArtifactObject", mock.Anything, sha256Hex(artifact)).Return(nil)
+	errInternal := errors.New("internal error")
+	db.On("InsertImage", mock.Anything, mock.MatchedBy(func(image *model.Image) bool {
+		return image.Checksum == sha256Hex(artifact) &&
+			image.Size == int64(len(artifact))
+	})).Return(errInternal)
+	objStore.On("DeleteObject", mock.Anything, artifactID).Return(nil)
+	objStore.On("DeleteObject", mock.Anything, objectPath).Return(nil)
+
+	d := NewDeployments(db, objStore, 0, false)
+	_, err := d.handleArtifact(context.Background(), &model.MultipartUploadMsg{
+		ArtifactID:     artifactID,
+		ArtifactReader: bytes.NewReader(artifact),
+	}, true, nil)
+	assert.ErrorIs(t, err, errInternal)
+}
+
+func TestHandleArtifactSkipVerifySizeMismatch(t *testing.T) {
+	t.Parallel()
+
+	artifact := writeTestArtifact(t, nil)
+	const artifactID = "0b2a0e09-3b0c-4b55-9a4f-6a3c4bb0e7d1"
+	var r io.Reader = bytes.NewReader(artifact)
+	meta, err := getMetaFromArchive(&r, true, &signatureVerifier{})
+	assert.NoError(t, err)
+
+	db := &mocks.DataStore{}
+	defer db.AssertExpectations(t)
+	objStore := &fs_mocks.ObjectStorage{}
+	defer objStore.AssertExpectations(t)
+
+	db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+	db.On("GetTenantSettings", mock.Anything).Return(nil, nil)
+	db.On("ListPublicKeys", mock.Anything).Return(nil, nil)
+	objStore.On("DeleteObject", mock.Anything, artifactID).Return(nil)
+
+	d := NewDeployments(db, objStore, 0, false)
+	_, err = d.handleArtifact(context.Background(), &model.MultipartUploadMsg{
+		ArtifactID:     artifactID,
+		ArtifactReader: bytes.NewReader(artifact),
+	}, true, &model.DirectUploadMetadata{
+		Size:    1,
+		Updates: meta.Updates,
+	})
+	assert.ErrorIs(t, err, ErrArtifactSizeMismatch)
+}
+
+func TestVerifyArtifacts(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("artifact content")
+	checksum := sha256Hex(content)
+	newImage := func(id string, size int64, checksum string) *model.Image {
+		return &model.Image{
+			Id:           id,
+			ArtifactMeta: &model.ArtifactMeta{Name: "release-" + id},
+			Size:         size,
+			Checksum:     checksum,
+		}
+	}
+	integrityWithStatus := func(status model.IntegrityStatus) interface{} {
+		return mock.MatchedBy(func(integrity *model.ImageIntegrity) bool {
+			return integrity.Status == status
+		})
+	}
+
+	testCases := []struct {
+		Name string
+
+		Image     *model.Image
+		Object    []byte
+		ObjectErr error
+		DryRun    bool
+
+		Checksum string
+		Status   model.IntegrityStatus
+		Reported *model.ArtifactVerification
+	}{{
+		Name:   "ok",
+		Image:  newImage("1", int64(len(content)), checksum),
+		Object: content,
+		Status: model.IntegrityStatusOK,
+	}, {
+		Name:     "ok, checksum backfilled",
+		Image:    newImage("2", int64(len(content)), ""),
+		Object:   content,
+		Checksum: checksum,
+		Status:   model.IntegrityStatusOK,
+		Reported: &model.ArtifactVerification{
+			ID:   "2",
+			Name: "release-2",
+			ImageIntegrity: model.ImageIntegrity{
+				Status:   model.IntegrityStatusOK,
+				Checksum: checksum,
+				Size:     int64(len(content)),
+			},
+			Backfilled: true,
+		},
+	}, {
+		Name:   "truncated",
+		Image:  newImage("3", int64(len(content)), checksum),
+		Object: content[:4],
+		Status: model.IntegrityStatusCorrupt,
+		Reported: &model.ArtifactVerification{
+			ID:   "3",
+			Name: "release-3",
+			ImageIntegrity: model.ImageIntegrity{
+				Status:   model.IntegrityStatusCorrupt,
+				Checksum: sha256Hex(content[:4]),
+				Size:     4,
+			},
+		},
+	}, {
+		Name:   "checksum mismatch, dry-run",
+		Image:  newImage("4", int64(len(content)), strings.Repeat("0", 64)),
+		Object: content,
+		DryRun: true,
+		Reported: &model.ArtifactVerification{
+			ID:   "4",
+			Name: "release-4",
+			ImageIntegrity: model.ImageIntegrity{
+				Status:   model.IntegrityStatusCorrupt,
+				Checksum: checksum,
+				Size:     int64(len(content)),
+			},
+		},
+	}, {
+		Name:   "ok, recorded size differs from the checksummed object",
+		Image:  newImage("7", 1, checksum),
+		Object: content,
+		Status: model.IntegrityStatusOK,
+	}, {
+		Name:   "truncated, no checksum recorded",
+		Image:  newImage("8", int64(len(content)), ""),
+		Object: content[:4],
+		Status: model.IntegrityStatusCorrupt,
+		Reported: &model.ArtifactVerification{
+			ID:   "8",
+			Name: "release-8",
+			ImageIntegrity: model.ImageIntegrity{
+				Status:   model.IntegrityStatusCorrupt,
+				Checksum: sha256Hex(content[:4]),
+				Size:     4,
+			},
+		},
+	}, {
+		Name:      "missing",
+		Image:     newImage("5", int64(len(content)), checksum),
+		ObjectErr: storage.ErrObjectNotFound,
+		Status:    model.IntegrityStatusMissing,
+		Reported: &model.ArtifactVerification{
+			ID:   "5",
+			Name: "release-5",
+			ImageIntegrity: model.ImageIntegrity{
+				Status: model.IntegrityStatusMissing,
+			},
+		},
+	}, {
+		Name:      "storage error",
+		Image:     newImage("6", int64(len(content)), checksum),
+		ObjectErr: errors.New("internal error"),
+		Reported: &model.ArtifactVerification{
+			ID:    "6",
+			Name:  "release-6",
+			Error: "failed to get the artifact object: internal error",
+		},
+	}}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			objStore := &fs_mocks.ObjectStorage{}
+			defer objStore.AssertExpectations(t)
+
+			db.On("GetStorageSettings", mock.Anything).Return(nil, nil)
+			db.On("FindImages", mock.Anything).
+				Return(NewArrayIterator([]model.Image{*tc.Image}), nil)
+			var obj io.ReadCloser
+			if tc.ObjectErr == nil {
+				obj = io.NopCloser(bytes.NewReader(tc.Object))
+			}
+			objStore.On("GetObject", mock.Anything, tc.Image.Id).
+				Return(obj, tc.ObjectErr)
+			if tc.Status != "" && !tc.DryRun {
+				db.On("SetImageIntegrity", mock.Anything,
+					tc.Image.Id, tc.Checksum, integrityWithStatus(tc.Status),
+				).Return(nil)
+			}
+
+			var reported []*model.ArtifactVerification
+			d := NewDeployments(db, objStore, 0, false)
+			err := d.VerifyArtifacts(context.Background(), tc.DryRun,
+				func(verification *model.ArtifactVerification) error {
+					verification.Checked = time.Time{}
+					reported = append(reported, verification)
+					return nil
+				})
+			assert.NoError(t, err)
+			if tc.Reported != nil {
+				assert.Equal(t, []*model.ArtifactVerification{tc.Reported}, reported)
+			} else {
+				assert.Empty(t, reported)
+			}
+		})
+	}
+}
+
+func TestVerifyTenantsArtifacts(t *testing.T) {
+	t.Parallel()
+
+	const tenantID = "123456789012345678901234"
+	db := &mocks.DataStore{}
+	defer db.AssertExpectations(t)
+	objStore := &fs_mocks.ObjectStorage{}
+	defer objStore.AssertExpectations(t)
+
+	hasTenant := mock.MatchedBy(func(ctx context.Context) bool {
+		idty := identity.FromContext(ctx)
+		return idty != nil && idty.Tenant == tenantID
+	})
+	db.On("GetTenantDbs").Return([]string{mongo.DbName + "-" + tenantID}, nil)
+	db.On("GetStorageSettings", hasTenant).Return(nil, nil)
+	db.On("FindImages", hasTenant).Return(NewArrayIterator([]model.Image{{
+		Id:           "1",
+		ArtifactMeta: &model.ArtifactMeta{Name: "release-1"},
+		Size:         1024,
+	}}), nil)
+	objStore.On("GetObject", hasTenant, tenantID+"/1").
+		Return(nil, storage.ErrObjectNotFound)
+	db.On("SetImageIntegrity", hasTenant, "1", "", mock.Anything).
+		Return(store.ErrNotFound)
+
+	var report bytes.Buffer
+	d := NewDeployments(db, objStore, 0, false)
+	err := d.VerifyTenantsArtifacts(context.Background(), "", 0, false, &report)
+	assert.NoError(t, err)
+
+	var verification model.ArtifactVerification
+	if assert.NoError(t, json.Unmarshal(report.Bytes(), &verification)) {
+		assert.Equal(t, tenantID, verification.TenantID)
+		assert.Equal(t, "1", verification.ID)
+		assert.Equal(t, model.IntegrityStatusMissing, verification.Status)
+	}
+}
@@ -26,7 +26,9 @@ import (
 	workflows_mocks "github.com/mendersoftware/deployments/client/workflows/mocks"
 	"github.com/mendersoftware/deployments/model"
 	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store"
 	"github.com/mendersoftware/deployments/store/mocks"
+	"github.com/mendersoftware/deployments/store/mongo"
 	h "github.com/mendersoftware/deployments/utils/testing"
 	"github.com/mendersoftware/go-lib-micro/identity"
 	"github.com/mendersoftware/mender-artifact/areader"
@@ -73,6 +75,11 @@ func TestGenerateImageArtifactIsNotUnique(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 
 	db.On("IsArtifactUnique",
 		h.ContextMatcher(),
@@ -99,10 +106,32 @@ func TestGenerateImageArtifactIsNotUnique(t *testing.T) {
 	db.AssertExpectations(t)
 }
 
+func TestGenerateImageReleaseAliasConflict(t *testing.T) {
+	db := mocks.DataStore{}
+	defer db.AssertExpectations(t)
+	d := NewDeployments(&db, nil, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), "stable").
+		Return("release-1", nil)
+
+	artifactID, err := d.GenerateImage(context.Background(), &model.MultipartGenerateImageMsg{
+		Name:                  "stable",
+		DeviceTypesCompatible: []string{"Beagle Bone"},
+		Type:                  "single_file",
+	})
+	assert.Empty(t, artifactID)
+	assert.ErrorIs(t, err, ErrReleaseAliasConflict)
+}
+
 func TestGenerateImageErrorWhileCheckingIfArtifactIsNotUnique(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 
 	db.On("IsArtifactUnique",
 		h.ContextMatcher(),
@@ -133,6 +162,11 @@ func TestGenerateImageErrorWhileUploading(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 	ctx := context.Background()
 
 	fs.On("PutObject",
@@ -174,6 +208,11 @@ func TestGenerateImageErrorS3GetRequest(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 	ctx := context.Background()
 
 	fs.On("PutObject",
@@ -226,6 +265,11 @@ func TestGenerateImageErrorS3DeleteRequest(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 	ctx := context.Background()
 
 	fs.On("PutObject",
@@ -287,6 +331,11 @@ func TestGenerateImageErrorWhileStartingWorkflow(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 	ctx := context.Background()
 
 	fs.On("GetRequest",
@@ -359,6 +408,11 @@ func TestGenerateImageErrorWhileStartingWorkflowAndFailsWhenCleaningUp(t *testin
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 	ctx := context.Background()
 
 	workflowsClient := &workflows_mocks.Client{}
@@ -433,6 +487,11 @@ func TestGenerateImageSuccessful(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 
 	multipartGenerateImage := &model.MultipartGenerateImageMsg{
 		Name:                  "name",
@@ -500,6 +559,11 @@ func TestGenerateImageSuccessfulWithTenant(t *testing.T) {
 	db := mocks.DataStore{}
 	fs := &fs_mocks.ObjectStorage{}
 	d := NewDeployments(&db, fs, 0, false)
+	db.On("GetTenantSettings", h.ContextMatcher()).Return(nil, nil)
+	db.On("GetReleaseNameByAlias", h.ContextMatcher(), mock.AnythingOfType("string")).
+		Return("", store.ErrNotFound)
+	db.On("GetLimit", h.ContextMatcher(), model.LimitStorage).
+		Return(nil, mongo.ErrLimitNotFound)
 
 	multipartGenerateImage := &model.MultipartGenerateImageMsg{
 		Name:                  "name",
@@ -25,6 +25,7 @@ import (
 
 	"github.com/mendersoftware/deployments/model"
 	fs_mocks "github.com/mendersoftware/deployments/storage/mocks"
+	"github.com/mendersoftware/deployments/store"
 	"github.com/mendersoftware/deployments/store/mocks"
 	"github.com/mendersoftware/deployments/store/mongo"
 )
@@ -96,3 +97,166 @@ func TestGetLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLimitStorageUsage(t *testing.T) {
+	db := mocks.DataStore{}
+	db.On("GetLimit", mock.Anything, model.LimitStorage).
+		Return(&model.Limit{Name: model.LimitStorage, Value: 200}, nil)
+	db.On("GetStorageUsage", mock.Anything).
+		Return(int64(150), nil)
+
+	d := NewDeployments(&db, &fs_mocks.ObjectStorage{}, 0, false)
+
+	lim, err := d.GetLimit(context.Background(), model.LimitStorage)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Limit{
+		Name:  model.LimitStorage,
+		Value: 200,
+		Usage: 150,
+	}, lim)
+
+	db.AssertExpectations(t)
+}
+
+func TestCheckStorageLimit(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		limit    *model.Limit
+		limitErr error
+		usage    int64
+		size     int64
+
+		err error
+	}{
+		{
+			name:     "ok, no limit",
+			limitErr: mongo.ErrLimitNotFound,
+			size:     1000,
+		},
+		{
+			name:  "ok, fits",
+			limit: &model.Limit{Name: model.LimitStorage, Value: 100},
+			usage: 50,
+			size:  50,
+		},
+		{
+			name:  "error, exceeds limit",
+			limit: &model.Limit{Name: model.LimitStorage, Value: 100},
+			usage: 50,
+			size:  51,
+			err:   ErrStorageLimitExceeded,
+		},
+		{
+			name:  "error, limit reached",
+			limit: &model.Limit{Name: model.LimitStorage, Value: 100},
+			usage: 100,
+			err:   ErrStorageLimitExceeded,
+		},
+		{
+			name:     "error, limit",
+			limitErr: errors.New("internal error"),
+			err:      errors.New("failed to obtain limit from storage: internal error"),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			db := mocks.DataStore{}
+			db.On("GetLimit", mock.Anything, model.LimitStorage).
+				Return(tc.limit, tc.limitErr)
+			if tc.limit != nil {
+				db.On("GetStorageUsage", mock.Anything).
+					Return(tc.usage, nil)
+			}
+
+			d := NewDeployments(&db, &fs_mocks.ObjectStorage{}, 0, false)
+
+			err := d.checkStorageLimit(context.Background(), tc.size)
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			db.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateImageStorageLimit(t *testing.T) {
+	t.Parallel()
+
+	db := &mocks.DataStore{}
+	defer db.AssertExpectations(t)
+	db.On("GetLimit", mock.Anything, model.LimitStorage).
+		Return(&model.Limit{Name: model.LimitStorage, Value: 1000}, nil)
+	db.On("GetStorageUsage", mock.Anything).Return(int64(1000), nil)
+
+	// the artifact is not read nor stored
+	d := NewDeployments(db, nil, 0, false)
+	_, err := d.CreateImage(context.Background(), &model.MultipartUploadMsg{})
+	assert.ErrorIs(t, err, ErrStorageLimitExceeded)
+}
+
+func TestReserveStorage(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		limit    *model.Limit
+		limitErr error
+		incErr   error
+
+		err error
+	}{
+		{
+			name:     "ok, no limit",
+			limitErr: mongo.ErrLimitNotFound,
+		},
+		{
+			name:  "ok",
+			limit: &model.Limit{Name: model.LimitStorage, Value: 100},
+		},
+		{
+			name:   "error, exceeds limit",
+			limit:  &model.Limit{Name: model.LimitStorage, Value: 100},
+			incErr: store.ErrLimitExceeded,
+			err:    ErrStorageLimitExceeded,
+		},
+		{
+			name:   "error, update usage",
+			limit:  &model.Limit{Name: model.LimitStorage, Value: 100},
+			incErr: errors.New("internal error"),
+			err:    errors.New("failed to update storage usage: internal error"),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var limit uint64
+			if tc.limit != nil {
+				limit = tc.limit.Value
+			}
+			db := mocks.DataStore{}
+			db.On("GetLimit", mock.Anything, model.LimitStorage).
+				Return(tc.limit, tc.limitErr)
+			db.On("IncrementStorageUsage", mock.Anything, int64(10), limit).
+				Return(tc.incErr)
+
+			d := NewDeployments(&db, &fs_mocks.ObjectStorage{}, 0, false)
+
+			err := d.reserveStorage(context.Background(), 10)
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			db.AssertExpectations(t)
+		})
+	}
+}
@@ -62,6 +62,66 @@ func (_m *App) AbortDeviceDeployments(ctx context.Context, deviceID string) erro
 	return r0
 }
 
+// AddPublicKey provides a mock function with given fields: ctx, constructor
+func (_m *App) AddPublicKey(ctx context.Context, constructor *model.PublicKeyConstructor) (*model.PublicKey, error) {
+	ret := _m.Called(ctx, constructor)
+
+	var r0 *model.PublicKey
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PublicKeyConstructor) *model.PublicKey); ok {
+		r0 = rf(ctx, constructor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.PublicKeyConstructor) error); ok {
+		r1 = rf(ctx, constructor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompareReleases provides a mock function with given fields: ctx, from, to
+func (_m *App) CompareReleases(ctx context.Context, from string, to string) (*model.ReleaseComparison, error) {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 *model.ReleaseComparison
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.ReleaseComparison); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReleaseComparison)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteChunkedUpload provides a mock function with given fields: ctx, id
+func (_m *App) CompleteChunkedUpload(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CompleteUpload provides a mock function with given fields: ctx, intentID, skipVerify, metadata
 func (_m *App) CompleteUpload(ctx context.Context, intentID string, skipVerify bool, metadata *model.DirectUploadMetadata) error {
 	ret := _m.Called(ctx, intentID, skipVerify, metadata)
@@ -76,6 +136,52 @@ func (_m *App) CompleteUpload(ctx context.Context, intentID string, skipVerify b
 	return r0
 }
 
+// CopyArtifact provides a mock function with given fields: ctx, id, tenantID
+func (_m *App) CopyArtifact(ctx context.Context, id string, tenantID string) (*model.Image, error) {
+	ret := _m.Called(ctx, id, tenantID)
+
+	var r0 *model.Image
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Image); ok {
+		r0 = rf(ctx, id, tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateChunkedUpload provides a mock function with given fields: ctx, length, expire
+func (_m *App) CreateChunkedUpload(ctx context.Context, length int64, expire time.Duration) (*model.UploadLink, error) {
+	ret := _m.Called(ctx, length, expire)
+
+	var r0 *model.UploadLink
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Duration) *model.UploadLink); ok {
+		r0 = rf(ctx, length, expire)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UploadLink)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Duration) error); ok {
+		r1 = rf(ctx, length, expire)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateDeployment provides a mock function with given fields: ctx, constructor
 func (_m *App) CreateDeployment(ctx context.Context, constructor *model.DeploymentConstructor) (string, error) {
 	ret := _m.Called(ctx, constructor)
@@ -181,6 +287,43 @@ func (_m *App) DeleteImage(ctx context.Context, imageID string) error {
 	return r0
 }
 
+// DeletePublicKey provides a mock function with given fields: ctx, id
+func (_m *App) DeletePublicKey(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteReleases provides a mock function with given fields: ctx, releaseNames
+func (_m *App) DeleteReleases(ctx context.Context, releaseNames []string) ([]model.ReleaseArtifactDeletion, error) {
+	ret := _m.Called(ctx, releaseNames)
+
+	var r0 []model.ReleaseArtifactDeletion
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []model.ReleaseArtifactDeletion); ok {
+		r0 = rf(ctx, releaseNames)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ReleaseArtifactDeletion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, releaseNames)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DownloadLink provides a mock function with given fields: ctx, imageID, expire
 func (_m *App) DownloadLink(ctx context.Context, imageID string, expire time.Duration) (*model.Link, error) {
 	ret := _m.Called(ctx, imageID, expire)
@@ -204,6 +347,29 @@ func (_m *App) DownloadLink(ctx context.Context, imageID string, expire time.Dur
 	return r0, r1
 }
 
+// EditArtifactMeta provides a mock function with given fields: ctx, id, edit
+func (_m *App) EditArtifactMeta(ctx context.Context, id string, edit *model.ArtifactMetaEdit) (*model.Image, error) {
+	ret := _m.Called(ctx, id, edit)
+
+	var r0 *model.Image
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.ArtifactMetaEdit) *model.Image); ok {
+		r0 = rf(ctx, id, edit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *model.ArtifactMetaEdit) error); ok {
+		r1 = rf(ctx, id, edit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EditImage provides a mock function with given fields: ctx, id, constructorData
 func (_m *App) EditImage(ctx context.Context, id string, constructorData *model.ImageMeta) (bool, error) {
 	ret := _m.Called(ctx, id, constructorData)
@@ -225,6 +391,29 @@ func (_m *App) EditImage(ctx context.Context, id string, constructorData *model.
 	return r0, r1
 }
 
+// FindExpiredArtifacts provides a mock function with given fields: ctx, now
+func (_m *App) FindExpiredArtifacts(ctx context.Context, now time.Time) ([]model.ExpiredArtifact, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 []model.ExpiredArtifact
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []model.ExpiredArtifact); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ExpiredArtifact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateConfigurationImage provides a mock function with given fields: ctx, deviceType, deploymentID
 func (_m *App) GenerateConfigurationImage(ctx context.Context, deviceType string, deploymentID string) (io.Reader, error) {
 	ret := _m.Called(ctx, deviceType, deploymentID)
@@ -269,6 +458,84 @@ func (_m *App) GenerateImage(ctx context.Context, multipartUploadMsg *model.Mult
 	return r0, r1
 }
 
+// GetArtifactContents provides a mock function with given fields: ctx, id
+func (_m *App) GetArtifactContents(ctx context.Context, id string) (*model.ArtifactContents, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.ArtifactContents
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ArtifactContents); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ArtifactContents)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetArtifactFile provides a mock function with given fields: ctx, id, name
+func (_m *App) GetArtifactFile(ctx context.Context, id string, name string) (io.ReadCloser, *model.UpdateFile, error) {
+	ret := _m.Called(ctx, id, name)
+
+	var r0 io.ReadCloser
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) io.ReadCloser); ok {
+		r0 = rf(ctx, id, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	var r1 *model.UpdateFile
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *model.UpdateFile); ok {
+		r1 = rf(ctx, id, name)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.UpdateFile)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, id, name)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetChunkedUpload provides a mock function with given fields: ctx, id
+func (_m *App) GetChunkedUpload(ctx context.Context, id string) (*model.UploadLink, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.UploadLink
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.UploadLink); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UploadLink)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDeployment provides a mock function with given fields: ctx, deploymentID
 func (_m *App) GetDeployment(ctx context.Context, deploymentID string) (*model.Deployment, error) {
 	ret := _m.Called(ctx, deploymentID)
@@ -368,6 +635,52 @@ func (_m *App) GetDeploymentsStats(ctx context.Context, deploymentIDs ...string)
 	return r0, r1
 }
 
+// GetDeviceDeploymentDownloadLink provides a mock function with given fields: ctx, deploymentID, deviceID
+func (_m *App) GetDeviceDeploymentDownloadLink(ctx context.Context, deploymentID string, deviceID string) (*model.Link, error) {
+	ret := _m.Called(ctx, deploymentID, deviceID)
+
+	var r0 *model.Link
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Link); ok {
+		r0 = rf(ctx, deploymentID, deviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Link)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, deploymentID, deviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeviceDeploymentHistory provides a mock function with given fields: ctx, deploymentID, deviceID
+func (_m *App) GetDeviceDeploymentHistory(ctx context.Context, deploymentID string, deviceID string) (*model.DeviceDeploymentHistory, error) {
+	ret := _m.Called(ctx, deploymentID, deviceID)
+
+	var r0 *model.DeviceDeploymentHistory
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.DeviceDeploymentHistory); ok {
+		r0 = rf(ctx, deploymentID, deviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DeviceDeploymentHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, deploymentID, deviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDeviceDeploymentLastStatus provides a mock function with given fields: ctx, devicesIds
 func (_m *App) GetDeviceDeploymentLastStatus(ctx context.Context, devicesIds []string) (model.DeviceDeploymentLastStatuses, error) {
 	ret := _m.Called(ctx, devicesIds)
@@ -495,6 +808,29 @@ func (_m *App) GetDevicesListForDeployment(ctx context.Context, query store.List
 	return r0, r1, r2
 }
 
+// GetGatewayDevices provides a mock function with given fields: ctx, gatewayID, deviceIDs
+func (_m *App) GetGatewayDevices(ctx context.Context, gatewayID string, deviceIDs []string) ([]string, error) {
+	ret := _m.Called(ctx, gatewayID, deviceIDs)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []string); ok {
+		r0 = rf(ctx, gatewayID, deviceIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, gatewayID, deviceIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetImage provides a mock function with given fields: ctx, id
 func (_m *App) GetImage(ctx context.Context, id string) (*model.Image, error) {
 	ret := _m.Called(ctx, id)
@@ -518,6 +854,29 @@ func (_m *App) GetImage(ctx context.Context, id string) (*model.Image, error) {
 	return r0, r1
 }
 
+// GetImportJob provides a mock function with given fields: ctx, id
+func (_m *App) GetImportJob(ctx context.Context, id string) (*model.ImportJob, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.ImportJob
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ImportJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ImportJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLimit provides a mock function with given fields: ctx, name
 func (_m *App) GetLimit(ctx context.Context, name string) (*model.Limit, error) {
 	ret := _m.Called(ctx, name)
@@ -541,6 +900,105 @@ func (_m *App) GetLimit(ctx context.Context, name string) (*model.Limit, error)
 	return r0, r1
 }
 
+// GetPublicKey provides a mock function with given fields: ctx, id
+func (_m *App) GetPublicKey(ctx context.Context, id string) (*model.PublicKey, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.PublicKey
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.PublicKey); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReleaseChannel provides a mock function with given fields: ctx, name
+func (_m *App) GetReleaseChannel(ctx context.Context, name string) (*model.ReleaseChannel, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *model.ReleaseChannel
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ReleaseChannel); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReleaseChannel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReleaseNotesHistory provides a mock function with given fields: ctx, releaseName, skip, limit
+func (_m *App) GetReleaseNotesHistory(ctx context.Context, releaseName string, skip int, limit int) ([]model.ReleaseNotesRevision, int, error) {
+	ret := _m.Called(ctx, releaseName, skip, limit)
+
+	var r0 []model.ReleaseNotesRevision
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []model.ReleaseNotesRevision); ok {
+		r0 = rf(ctx, releaseName, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ReleaseNotesRevision)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = rf(ctx, releaseName, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(ctx, releaseName, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReleaseStatistics provides a mock function with given fields: ctx, releaseName
+func (_m *App) GetReleaseStatistics(ctx context.Context, releaseName string) (*model.ReleaseStatistics, error) {
+	ret := _m.Called(ctx, releaseName)
+
+	var r0 *model.ReleaseStatistics
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ReleaseStatistics); ok {
+		r0 = rf(ctx, releaseName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReleaseStatistics)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, releaseName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetReleasesUpdateTypes provides a mock function with given fields: ctx
 func (_m *App) GetReleasesUpdateTypes(ctx context.Context) ([]string, error) {
 	ret := _m.Called(ctx)
@@ -564,6 +1022,29 @@ func (_m *App) GetReleasesUpdateTypes(ctx context.Context) ([]string, error) {
 	return r0, r1
 }
 
+// GetRetentionPolicy provides a mock function with given fields: ctx
+func (_m *App) GetRetentionPolicy(ctx context.Context) (*model.RetentionPolicy, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.RetentionPolicy
+	if rf, ok := ret.Get(0).(func(context.Context) *model.RetentionPolicy); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.RetentionPolicy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStorageSettings provides a mock function with given fields: ctx
 func (_m *App) GetStorageSettings(ctx context.Context) (*model.StorageSettings, error) {
 	ret := _m.Called(ctx)
@@ -587,6 +1068,29 @@ func (_m *App) GetStorageSettings(ctx context.Context) (*model.StorageSettings,
 	return r0, r1
 }
 
+// GetTenantSettings provides a mock function with given fields: ctx
+func (_m *App) GetTenantSettings(ctx context.Context) (*model.TenantSettings, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.TenantSettings
+	if rf, ok := ret.Get(0).(func(context.Context) *model.TenantSettings); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TenantSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasDeploymentForDevice provides a mock function with given fields: ctx, deploymentID, deviceID
 func (_m *App) HasDeploymentForDevice(ctx context.Context, deploymentID string, deviceID string) (bool, error) {
 	ret := _m.Called(ctx, deploymentID, deviceID)
@@ -622,6 +1126,29 @@ func (_m *App) HealthCheck(ctx context.Context) error {
 	return r0
 }
 
+// ImportArtifact provides a mock function with given fields: ctx, req
+func (_m *App) ImportArtifact(ctx context.Context, req *model.ImportArtifactRequest) (*model.ImportJob, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 *model.ImportJob
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ImportArtifactRequest) *model.ImportJob); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ImportJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.ImportArtifactRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsDeploymentFinished provides a mock function with given fields: ctx, deploymentID
 func (_m *App) IsDeploymentFinished(ctx context.Context, deploymentID string) (bool, error) {
 	ret := _m.Called(ctx, deploymentID)
@@ -673,6 +1200,52 @@ func (_m *App) ListImages(ctx context.Context, filters *model.ReleaseOrImageFilt
 	return r0, r1, r2
 }
 
+// ListPublicKeys provides a mock function with given fields: ctx
+func (_m *App) ListPublicKeys(ctx context.Context) ([]model.PublicKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []model.PublicKey
+	if rf, ok := ret.Get(0).(func(context.Context) []model.PublicKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.PublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListReleaseChannels provides a mock function with given fields: ctx
+func (_m *App) ListReleaseChannels(ctx context.Context) ([]model.ReleaseChannel, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []model.ReleaseChannel
+	if rf, ok := ret.Get(0).(func(context.Context) []model.ReleaseChannel); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ReleaseChannel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListReleaseTags provides a mock function with given fields: ctx
 func (_m *App) ListReleaseTags(ctx context.Context) (model.Tags, error) {
 	ret := _m.Called(ctx)
@@ -726,6 +1299,43 @@ func (_m *App) LookupDeployment(ctx context.Context, query model.Query) ([]*mode
 	return r0, r1, r2
 }
 
+// PatchReleaseTags provides a mock function with given fields: ctx, releaseName, patch
+func (_m *App) PatchReleaseTags(ctx context.Context, releaseName string, patch model.TagsPatch) error {
+	ret := _m.Called(ctx, releaseName, patch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TagsPatch) error); ok {
+		r0 = rf(ctx, releaseName, patch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PromoteRelease provides a mock function with given fields: ctx, channel, promotion
+func (_m *App) PromoteRelease(ctx context.Context, channel string, promotion model.ReleasePromotion) (*model.ReleaseChannel, error) {
+	ret := _m.Called(ctx, channel, promotion)
+
+	var r0 *model.ReleaseChannel
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.ReleasePromotion) *model.ReleaseChannel); ok {
+		r0 = rf(ctx, channel, promotion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReleaseChannel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.ReleasePromotion) error); ok {
+		r1 = rf(ctx, channel, promotion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProvisionTenant provides a mock function with given fields: ctx, tenant_id
 func (_m *App) ProvisionTenant(ctx context.Context, tenant_id string) error {
 	ret := _m.Called(ctx, tenant_id)
@@ -740,6 +1350,29 @@ func (_m *App) ProvisionTenant(ctx context.Context, tenant_id string) error {
 	return r0
 }
 
+// RenderReleaseNotes provides a mock function with given fields: ctx, releaseName
+func (_m *App) RenderReleaseNotes(ctx context.Context, releaseName string) ([]byte, error) {
+	ret := _m.Called(ctx, releaseName)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, releaseName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, releaseName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReplaceReleaseTags provides a mock function with given fields: ctx, releaseName, tags
 func (_m *App) ReplaceReleaseTags(ctx context.Context, releaseName string, tags model.Tags) error {
 	ret := _m.Called(ctx, releaseName, tags)
@@ -768,6 +1401,20 @@ func (_m *App) SaveDeviceDeploymentLog(ctx context.Context, deviceID string, dep
 	return r0
 }
 
+// SetRetentionPolicy provides a mock function with given fields: ctx, policy
+func (_m *App) SetRetentionPolicy(ctx context.Context, policy *model.RetentionPolicy) error {
+	ret := _m.Called(ctx, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.RetentionPolicy) error); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetStorageSettings provides a mock function with given fields: ctx, storageSettings
 func (_m *App) SetStorageSettings(ctx context.Context, storageSettings *model.StorageSettings) error {
 	ret := _m.Called(ctx, storageSettings)
@@ -782,6 +1429,20 @@ func (_m *App) SetStorageSettings(ctx context.Context, storageSettings *model.St
 	return r0
 }
 
+// SetTenantSettings provides a mock function with given fields: ctx, settings
+func (_m *App) SetTenantSettings(ctx context.Context, settings *model.TenantSettings) error {
+	ret := _m.Called(ctx, settings)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TenantSettings) error); ok {
+		r0 = rf(ctx, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateDeploymentsWithArtifactName provides a mock function with given fields: ctx, artifactName
 func (_m *App) UpdateDeploymentsWithArtifactName(ctx context.Context, artifactName string) error {
 	ret := _m.Called(ctx, artifactName)
@@ -824,6 +1485,27 @@ func (_m *App) UpdateRelease(ctx context.Context, releaseName string, release mo
 	return r0
 }
 
+// UploadChunk provides a mock function with given fields: ctx, id, offset, chunk, size
+func (_m *App) UploadChunk(ctx context.Context, id string, offset int64, chunk io.Reader, size int64) (int64, error) {
+	ret := _m.Called(ctx, id, offset, chunk, size)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader, int64) int64); ok {
+		r0 = rf(ctx, id, offset, chunk, size)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, io.Reader, int64) error); ok {
+		r1 = rf(ctx, id, offset, chunk, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UploadLink provides a mock function with given fields: ctx, expire, skipVerify
 func (_m *App) UploadLink(ctx context.Context, expire time.Duration, skipVerify bool) (*model.UploadLink, error) {
 	ret := _m.Called(ctx, expire, skipVerify)
@@ -153,3 +153,51 @@ func TestSetStorageSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTenantSettings(t *testing.T) {
+	testCases := map[string]struct {
+		dbSettings *model.TenantSettings
+		dbErr      error
+
+		settings *model.TenantSettings
+		err      error
+	}{
+		"ok": {
+			dbSettings: &model.TenantSettings{
+				StatusTransitions: model.StatusTransitionsModeEnforce,
+			},
+			settings: &model.TenantSettings{
+				StatusTransitions: model.StatusTransitionsModeEnforce,
+			},
+		},
+		"ok, no settings": {
+			settings: &model.TenantSettings{},
+		},
+		"error": {
+			dbErr: errors.New("generic error"),
+			err:   errors.New("Searching for settings failed: generic error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db := mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("GetTenantSettings",
+				mock.MatchedBy(func(ctx context.Context) bool { return true }),
+			).Return(tc.dbSettings, tc.dbErr)
+
+			ds := &Deployments{
+				db: &db,
+			}
+
+			settings, err := ds.GetTenantSettings(context.Background())
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.settings, settings)
+			}
+		})
+	}
+}
@@ -102,6 +102,189 @@ func TestUpdateDeviceDeploymentStatus(t *testing.T) {
 	assert.Equal(t, err, ErrStorageNotFound)
 }
 
+func TestUpdateDeviceDeploymentStatusTransitions(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("db error")
+
+	testCases := map[string]struct {
+		current model.DeviceDeploymentStatus
+		next    model.DeviceDeploymentStatus
+
+		defaultMode    model.StatusTransitionsMode
+		tenantSettings *model.TenantSettings
+		settingsErr    error
+
+		// whether the update reaches the data store
+		updated bool
+		err     error
+	}{
+		"ok, legal transition": {
+			current:     model.DeviceDeploymentStatusDownloading,
+			next:        model.DeviceDeploymentStatusInstalling,
+			defaultMode: model.StatusTransitionsModeEnforce,
+			updated:     true,
+		},
+		"ok, re-report": {
+			current:     model.DeviceDeploymentStatusSuccess,
+			next:        model.DeviceDeploymentStatusSuccess,
+			defaultMode: model.StatusTransitionsModeEnforce,
+		},
+		"ok, illegal transition with warn": {
+			current:     model.DeviceDeploymentStatusRebooting,
+			next:        model.DeviceDeploymentStatusDownloading,
+			defaultMode: model.StatusTransitionsModeWarn,
+			updated:     true,
+		},
+		"ok, tenant overrides enforce with warn": {
+			current:     model.DeviceDeploymentStatusSuccess,
+			next:        model.DeviceDeploymentStatusInstalling,
+			defaultMode: model.StatusTransitionsModeEnforce,
+			tenantSettings: &model.TenantSettings{
+				StatusTransitions: model.StatusTransitionsModeWarn,
+			},
+			updated: true,
+		},
+		"error, illegal transition with enforce": {
+			current:     model.DeviceDeploymentStatusSuccess,
+			next:        model.DeviceDeploymentStatusInstalling,
+			defaultMode: model.StatusTransitionsModeEnforce,
+			err:         ErrInvalidStatusTransition,
+		},
+		"error, tenant overrides warn with enforce": {
+			current:     model.DeviceDeploymentStatusRebooting,
+			next:        model.DeviceDeploymentStatusDownloading,
+			defaultMode: model.StatusTransitionsModeWarn,
+			tenantSettings: &model.TenantSettings{
+				StatusTransitions: model.StatusTransitionsModeEnforce,
+			},
+			err: ErrInvalidStatusTransition,
+		},
+		"error, tenant settings": {
+			current:     model.DeviceDeploymentStatusRebooting,
+			next:        model.DeviceDeploymentStatusDownloading,
+			defaultMode: model.StatusTransitionsModeWarn,
+			settingsErr: errors.New("settings error"),
+			err:         errors.New("failed to get tenant settings: settings error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dd := model.NewDeviceDeployment("device", "deployment")
+			dd.Status = tc.current
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("GetDeviceDeployment", ctx, dd.DeploymentId, dd.DeviceId, false).
+				Return(dd, nil)
+			if !tc.current.CanTransitionTo(tc.next) {
+				db.On("GetTenantSettings", ctx).Return(tc.tenantSettings, tc.settingsErr)
+			}
+			if tc.updated {
+				// stop the update short after the status is written
+				db.On("UpdateDeviceDeploymentStatus", ctx, dd.DeviceId, dd.DeploymentId,
+					mock.MatchedBy(func(state model.DeviceDeploymentState) bool {
+						return state.Status == tc.next
+					})).Return(tc.current, errDB)
+			}
+
+			ds := NewDeployments(db, nil, 0, false).
+				WithStatusTransitionsMode(tc.defaultMode)
+			err := ds.UpdateDeviceDeploymentStatus(ctx, dd.DeploymentId, dd.DeviceId,
+				model.DeviceDeploymentState{Status: tc.next})
+			switch {
+			case tc.updated:
+				assert.ErrorIs(t, err, errDB)
+			case tc.err != nil:
+				assert.EqualError(t, err, tc.err.Error())
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpdateDeviceDeploymentStatusSubState(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		current  model.DeviceDeploymentStatus
+		subState string
+		state    model.DeviceDeploymentState
+
+		recorded bool
+	}{
+		"ok, new substate": {
+			current:  model.DeviceDeploymentStatusDownloading,
+			subState: "50%",
+			state: model.DeviceDeploymentState{
+				Status:   model.DeviceDeploymentStatusDownloading,
+				SubState: "75%",
+			},
+			recorded: true,
+		},
+		"ok, finished with a new substate": {
+			current: model.DeviceDeploymentStatusSuccess,
+			state: model.DeviceDeploymentState{
+				Status:   model.DeviceDeploymentStatusSuccess,
+				SubState: "verified",
+			},
+			recorded: true,
+		},
+		"ok, same substate": {
+			current:  model.DeviceDeploymentStatusDownloading,
+			subState: "50%",
+			state: model.DeviceDeploymentState{
+				Status:   model.DeviceDeploymentStatusDownloading,
+				SubState: "50%",
+			},
+		},
+		"ok, no substate": {
+			current:  model.DeviceDeploymentStatusDownloading,
+			subState: "50%",
+			state: model.DeviceDeploymentState{
+				Status: model.DeviceDeploymentStatusDownloading,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dd := model.NewDeviceDeployment("device", "deployment")
+			dd.Status = tc.current
+			dd.SubState = tc.subState
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			db.On("GetDeviceDeployment", ctx, dd.DeploymentId, dd.DeviceId, false).
+				Return(dd, nil)
+			if tc.recorded {
+				// the status is left as is: neither the statistics nor
+				// the finish time change
+				db.On("UpdateDeviceDeploymentStatus", ctx, dd.DeviceId, dd.DeploymentId,
+					mock.MatchedBy(func(state model.DeviceDeploymentState) bool {
+						return state.Status == tc.current &&
+							state.SubState == tc.state.SubState &&
+							state.FinishTime == nil
+					})).Return(tc.current, nil)
+			}
+
+			ds := NewDeployments(db, nil, 0, false)
+			err := ds.UpdateDeviceDeploymentStatus(ctx, dd.DeploymentId, dd.DeviceId, tc.state)
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestGetDeploymentForDeviceWithCurrent(t *testing.T) {
 	ctx := context.TODO()
 
@@ -152,6 +335,8 @@ func TestGetDeploymentForDeviceWithCurrent(t *testing.T) {
 		fakeDeployment, nil).Once()
 
 	db.On("DeviceCountByDeployment", ctx, fakeDeployment.Id).Return(2, nil)
+	db.On("GetReleaseStatus", ctx, depArtifact).
+		Return(model.ReleaseStatusApproved, nil)
 	db.On("GetDeviceDeployment", ctx,
 		fakeDeployment.Id, fakeDeviceDeployment.DeviceId, false).Return(
 		fakeDeviceDeployment, nil)
@@ -206,6 +391,77 @@ func TestGetDeploymentForDeviceWithCurrent(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetDeploymentForDeviceWithCurrentRevoked(t *testing.T) {
+	ctx := context.TODO()
+
+	devId := "somedevice"
+	devType := "baz"
+	depArtifact := "bar"
+
+	request := &model.DeploymentNextRequest{
+		DeviceProvides: &model.InstalledDeviceDeployment{
+			ArtifactName: "foo",
+			DeviceType:   devType,
+		},
+	}
+
+	fakeDeployment, err := model.NewDeploymentFromConstructor(
+		&model.DeploymentConstructor{
+			Name:         "foo",
+			ArtifactName: depArtifact,
+			Devices:      []string{devId},
+		},
+	)
+	assert.NoError(t, err)
+	fakeDeployment.MaxDevices = 1
+
+	fakeDeviceDeployment := model.NewDeviceDeployment(
+		devId, fakeDeployment.Id)
+	fakeDeviceDeployment.Status = model.DeviceDeploymentStatusPending
+
+	db := mocks.DataStore{}
+	defer db.AssertExpectations(t)
+
+	db.On("FindOldestActiveDeviceDeployment", ctx, devId).Return(
+		fakeDeviceDeployment, nil)
+	db.On("FindDeploymentByID", ctx, fakeDeployment.Id).Return(
+		fakeDeployment, nil)
+	db.On("SaveDeviceDeploymentRequest", ctx,
+		fakeDeviceDeployment.Id,
+		request).Return(nil)
+
+	db.On("GetReleaseStatus", ctx, depArtifact).
+		Return(model.ReleaseStatusRevoked, nil)
+
+	// the device deployment is aborted without assigning an artifact
+	db.On("GetDeviceDeployment", ctx,
+		fakeDeployment.Id, devId, false).Return(
+		fakeDeviceDeployment, nil)
+	db.On("UpdateDeviceDeploymentStatus", ctx,
+		devId,
+		fakeDeployment.Id,
+		mock.MatchedBy(func(ddStatus model.DeviceDeploymentState) bool {
+			return ddStatus.Status == model.DeviceDeploymentStatusAborted
+		})).Return(model.DeviceDeploymentStatusPending, nil)
+	db.On("UpdateStatsInc", ctx,
+		fakeDeployment.Id,
+		model.DeviceDeploymentStatusPending,
+		model.DeviceDeploymentStatusAborted).Return(nil)
+	db.On("SetDeploymentStatus", ctx,
+		fakeDeployment.Id,
+		mock.AnythingOfType("model.DeploymentStatus"),
+		mock.AnythingOfType("time.Time")).Return(nil)
+	db.On("SaveLastDeviceDeploymentStatus", ctx,
+		mock.AnythingOfType("model.DeviceDeployment"),
+	).Return(nil).Maybe()
+
+	ds := NewDeployments(&db, &fs_mocks.ObjectStorage{}, 0, false)
+
+	instructions, err := ds.GetDeploymentForDeviceWithCurrent(ctx, devId, request)
+	assert.NoError(t, err)
+	assert.Nil(t, instructions)
+}
+
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
@@ -591,3 +847,241 @@ func TestAbortDeviceDeployments(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDeviceDeploymentHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+
+	deploymentID := "30b3e62c-9ec2-4312-a7fa-cff24cc7397a"
+	deviceID := "somedevice"
+
+	fakeDeviceDeployment := model.NewDeviceDeployment(deviceID, deploymentID)
+	fakeDeviceDeployment.Status = model.DeviceDeploymentStatusDownloading
+	fakeDeviceDeployment.StatusHistory = append(fakeDeviceDeployment.StatusHistory,
+		model.DeviceDeploymentStatusTransition{
+			Status:    model.DeviceDeploymentStatusDownloading,
+			Timestamp: fakeDeviceDeployment.Created.Add(time.Second),
+		})
+
+	testCases := map[string]struct {
+		deviceDeployment *model.DeviceDeployment
+		dbErr            error
+
+		history bool
+		err     error
+	}{
+		"ok": {
+			deviceDeployment: fakeDeviceDeployment,
+			history:          true,
+		},
+		"ok, not found": {
+			dbErr: mongo.ErrStorageNotFound,
+		},
+		"error": {
+			dbErr: errors.New("generic error"),
+			err:   errors.New("generic error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := mocks.DataStore{}
+			defer db.AssertExpectations(t)
+
+			db.On("GetDeviceDeployment", ctx,
+				deploymentID, deviceID, false).Return(
+				tc.deviceDeployment, tc.dbErr)
+
+			ds := NewDeployments(&db, nil, 0, false)
+
+			history, err := ds.GetDeviceDeploymentHistory(ctx, deploymentID, deviceID)
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			if !tc.history {
+				assert.Nil(t, history)
+				return
+			}
+			if assert.NotNil(t, history) {
+				assert.Equal(t, deploymentID, history.DeploymentID)
+				assert.Equal(t, deviceID, history.DeviceID)
+				assert.Equal(t, model.DeviceDeploymentStatusDownloading, history.Status)
+				assert.Len(t, history.Phases, 2)
+				assert.Equal(t, float64(1),
+					history.Durations[model.DeviceDeploymentStatusPendingStr])
+			}
+		})
+	}
+}
+
+func TestGetDeviceDeploymentDownloadLink(t *testing.T) {
+	t.Parallel()
+
+	const (
+		deploymentID = "deployment"
+		deviceID     = "device"
+	)
+	image := &model.Image{
+		Id: "image",
+		ArtifactMeta: &model.ArtifactMeta{
+			Name: "artifact",
+		},
+	}
+	link := &model.Link{
+		Uri:    "https://example.com/artifact",
+		Expire: time.Now().Add(DefaultUpdateDownloadLinkExpire),
+	}
+
+	testCases := map[string]struct {
+		deviceDeployment *model.DeviceDeployment
+		dbErr            error
+		releaseStatus    model.ReleaseStatus
+		integrity        *model.ImageIntegrity
+		link             *model.Link
+		storageErr       error
+
+		err error
+	}{
+		"ok": {
+			deviceDeployment: &model.DeviceDeployment{
+				Id:           deploymentID,
+				DeviceId:     deviceID,
+				DeploymentId: deploymentID,
+				Status:       model.DeviceDeploymentStatusDownloading,
+				Image:        image,
+			},
+			link: link,
+		},
+		"error: not found": {
+			dbErr: mongo.ErrStorageNotFound,
+			err:   ErrStorageNotFound,
+		},
+		"error: generic db error": {
+			dbErr: errors.New("generic error"),
+			err:   errors.New("failed to get the device deployment: generic error"),
+		},
+		"error: finished": {
+			deviceDeployment: &model.DeviceDeployment{
+				Id:           deploymentID,
+				DeviceId:     deviceID,
+				DeploymentId: deploymentID,
+				Status:       model.DeviceDeploymentStatusSuccess,
+				Image:        image,
+			},
+			err: ErrDeviceDeploymentFinished,
+		},
+		"error: no artifact": {
+			deviceDeployment: &model.DeviceDeployment{
+				Id:           deploymentID,
+				DeviceId:     deviceID,
+				DeploymentId: deploymentID,
+				Status:       model.DeviceDeploymentStatusPending,
+			},
+			err: ErrNoArtifact,
+		},
+		"error: storage": {
+			deviceDeployment: &model.DeviceDeployment{
+				Id:           deploymentID,
+				DeviceId:     deviceID,
+				DeploymentId: deploymentID,
+				Status:       model.DeviceDeploymentStatusDownloading,
+				Image:        image,
+			},
+			storageErr: errors.New("storage error"),
+			err:        errors.New("Generating download link for the device: storage error"),
+		},
+		"error: release revoked": {
+			deviceDeployment: &model.DeviceDeployment{
+				Id:           deploymentID,
+				DeviceId:     deviceID,
+				DeploymentId: deploymentID,
+				Status:       model.DeviceDeploymentStatusDownloading,
+				Image:        image,
+			},
+			releaseStatus: model.ReleaseStatusRevoked,
+			err:           ErrDeploymentAborted,
+		},
+		"error: artifact corrupt": {
+			deviceDeployment: &model.DeviceDeployment{
+				Id:           deploymentID,
+				DeviceId:     deviceID,
+				DeploymentId: deploymentID,
+				Status:       model.DeviceDeploymentStatusDownloading,
+				Image:        image,
+			},
+			integrity: &model.ImageIntegrity{Status: model.IntegrityStatusCorrupt},
+			err:       ErrDeploymentAborted,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db := &mocks.DataStore{}
+			defer db.AssertExpectations(t)
+			fs := &fs_mocks.ObjectStorage{}
+			defer fs.AssertExpectations(t)
+
+			db.On("GetDeviceDeployment", ctx,
+				deploymentID, deviceID, false).Return(
+				tc.deviceDeployment, tc.dbErr)
+			if tc.deviceDeployment != nil && tc.deviceDeployment.Image != nil &&
+				tc.deviceDeployment.Status.Active() {
+				db.On("GetReleaseStatus", ctx, image.ArtifactMeta.Name).
+					Return(tc.releaseStatus, nil)
+				if tc.releaseStatus != model.ReleaseStatusRevoked {
+					current := *image
+					current.Integrity = tc.integrity
+					db.On("FindImageByID", ctx, image.Id).Return(&current, nil)
+				}
+			}
+			if tc.err == ErrDeploymentAborted {
+				db.On("UpdateDeviceDeploymentStatus", ctx, deviceID, deploymentID,
+					mock.MatchedBy(func(state model.DeviceDeploymentState) bool {
+						return state.Status == model.DeviceDeploymentStatusAborted
+					})).Return(model.DeviceDeploymentStatusDownloading, nil)
+				db.On("UpdateStatsInc", ctx, deploymentID,
+					model.DeviceDeploymentStatusDownloading,
+					model.DeviceDeploymentStatusAborted).Return(nil)
+				db.On("FindDeploymentByID", ctx, deploymentID).
+					Return(&model.Deployment{Id: deploymentID, MaxDevices: 1}, nil)
+				db.On("SetDeploymentStatus", ctx, deploymentID,
+					mock.AnythingOfType("model.DeploymentStatus"),
+					mock.AnythingOfType("time.Time")).Return(nil)
+				db.On("SaveLastDeviceDeploymentStatus", ctx,
+					mock.AnythingOfType("model.DeviceDeployment"),
+				).Return(nil).Maybe()
+			}
+			if tc.link != nil || tc.storageErr != nil {
+				db.On("GetStorageSettings", ctx).Return(nil, nil)
+				fs.On("GetRequest",
+					mock.AnythingOfType("*context.valueCtx"),
+					model.ImagePathFromContext(ctx, image.Id),
+					image.Name+model.ArtifactFileSuffix,
+					DefaultUpdateDownloadLinkExpire,
+				).Return(tc.link, tc.storageErr)
+			}
+
+			ds := NewDeployments(db, fs, 0, false)
+
+			res, err := ds.GetDeviceDeploymentDownloadLink(ctx, deploymentID, deviceID)
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				assert.Nil(t, res)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.link, res)
+			}
+		})
+	}
+}
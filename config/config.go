@@ -59,6 +59,10 @@ const (
 	SettingsStorageUploadExpireSeconds          = SettingStorage + ".upload_expire_seconds"
 	SettingsStorageUploadExpireSecondsDefault   = 3600
 
+	SettingsStorageChunkedUploadExpireSeconds = SettingStorage +
+		".chunked_upload_expire_seconds"
+	SettingsStorageChunkedUploadExpireSecondsDefault = 86400
+
 	SettingsAws                       = "aws"
 	SettingAwsS3Region                = SettingsAws + ".region"
 	SettingAwsS3RegionDefault         = "us-east-1"
@@ -142,6 +146,64 @@ const (
 	// pre-signed url.
 	SettingPresignScheme        = "presign.url_scheme"
 	SettingPresignSchemeDefault = "https"
+
+	// SettingDeviceDeploymentTimeout holds the number of seconds a device
+	// deployment may stay in an active status before the
+	// deployment-timeout-daemon marks it as failed. A value of 0 disables
+	// the timeout for the status.
+	SettingDeviceDeploymentTimeout = "device_deployment_timeout"
+
+	SettingDeviceDeploymentTimeoutPending = SettingDeviceDeploymentTimeout +
+		".pending_seconds"
+	SettingDeviceDeploymentTimeoutPendingDefault = 0
+
+	SettingDeviceDeploymentTimeoutDownloading = SettingDeviceDeploymentTimeout +
+		".downloading_seconds"
+	SettingDeviceDeploymentTimeoutDownloadingDefault = 24 * 60 * 60
+
+	SettingDeviceDeploymentTimeoutInstalling = SettingDeviceDeploymentTimeout +
+		".installing_seconds"
+	SettingDeviceDeploymentTimeoutInstallingDefault = 24 * 60 * 60
+
+	SettingDeviceDeploymentTimeoutRebooting = SettingDeviceDeploymentTimeout +
+		".rebooting_seconds"
+	SettingDeviceDeploymentTimeoutRebootingDefault = 24 * 60 * 60
+
+	SettingDeviceDeploymentTimeoutPauseBeforeInstall = SettingDeviceDeploymentTimeout +
+		".pause_before_installing_seconds"
+	SettingDeviceDeploymentTimeoutPauseBeforeInstallDefault = 0
+
+	SettingDeviceDeploymentTimeoutPauseBeforeCommit = SettingDeviceDeploymentTimeout +
+		".pause_before_committing_seconds"
+	SettingDeviceDeploymentTimeoutPauseBeforeCommitDefault = 0
+
+	SettingDeviceDeploymentTimeoutPauseBeforeReboot = SettingDeviceDeploymentTimeout +
+		".pause_before_rebooting_seconds"
+	SettingDeviceDeploymentTimeoutPauseBeforeRebootDefault = 0
+
+	// SettingDeviceDeploymentStatusTransitions sets the default handling of
+	// device deployment status reports that do not follow the update
+	// process: "warn" logs and accepts them, "enforce" rejects them.
+	// Tenants may override it through the internal tenant settings API.
+	SettingDeviceDeploymentStatusTransitions        = "device_deployment_status_transitions"
+	SettingDeviceDeploymentStatusTransitionsDefault = StatusTransitionsWarn
+
+	// SettingArtifactGenerator selects how artifacts are generated from
+	// raw files: "workflows" starts a job in the workflows service,
+	// "builtin" generates single_file, directory and script artifacts
+	// in-process, for setups without a workflows service.
+	SettingArtifactGenerator        = "artifact_generator"
+	SettingArtifactGeneratorDefault = ArtifactGeneratorWorkflows
+)
+
+const (
+	ArtifactGeneratorWorkflows = "workflows"
+	ArtifactGeneratorBuiltin   = "builtin"
+)
+
+const (
+	StatusTransitionsWarn    = "warn"
+	StatusTransitionsEnforce = "enforce"
 )
 
 const (
@@ -210,6 +272,28 @@ func ValidateStorage(c config.Reader) error {
 	return nil
 }
 
+func ValidateStatusTransitions(c config.Reader) error {
+	mode := c.GetString(SettingDeviceDeploymentStatusTransitions)
+	if mode != StatusTransitionsWarn && mode != StatusTransitionsEnforce {
+		return fmt.Errorf(
+			`setting "%s" (%s) must be one of "warn" or "enforce"`,
+			SettingDeviceDeploymentStatusTransitions, mode,
+		)
+	}
+	return nil
+}
+
+func ValidateArtifactGenerator(c config.Reader) error {
+	generator := c.GetString(SettingArtifactGenerator)
+	if generator != ArtifactGeneratorWorkflows && generator != ArtifactGeneratorBuiltin {
+		return fmt.Errorf(
+			`setting "%s" (%s) must be one of "workflows" or "builtin"`,
+			SettingArtifactGenerator, generator,
+		)
+	}
+	return nil
+}
+
 // Generate error with missing required option message.
 func MissingOptionError(option string) error {
 	return fmt.Errorf("Required option: '%s'", option)
@@ -261,7 +345,13 @@ func Setup(configPath string) error {
 }
 
 var (
-	Validators = []config.Validator{ValidateAwsAuth, ValidateHttps, ValidateStorage}
+	Validators = []config.Validator{
+		ValidateAwsAuth,
+		ValidateHttps,
+		ValidateStorage,
+		ValidateStatusTransitions,
+		ValidateArtifactGenerator,
+	}
 	// Aliases for deprecated configuration names to preserve backward compatibility.
 	Aliases = []struct {
 		Key   string
@@ -289,6 +379,8 @@ var (
 		{Key: SettingsStorageDownloadExpireSeconds,
 			Value: SettingsStorageDownloadExpireSecondsDefault},
 		{Key: SettingsStorageUploadExpireSeconds, Value: SettingsStorageUploadExpireSecondsDefault},
+		{Key: SettingsStorageChunkedUploadExpireSeconds,
+			Value: SettingsStorageChunkedUploadExpireSecondsDefault},
 		{Key: SettingMongo, Value: SettingMongoDefault},
 		{Key: SettingDbSSL, Value: SettingDbSSLDefault},
 		{Key: SettingDbSSLSkipVerify, Value: SettingDbSSLSkipVerifyDefault},
@@ -302,5 +394,22 @@ var (
 		{Key: SettingPresignExpireSeconds, Value: SettingPresignExpireSecondsDefault},
 		{Key: SettingPresignHost, Value: SettingPresignHostDefault},
 		{Key: SettingPresignScheme, Value: SettingPresignSchemeDefault},
+		{Key: SettingDeviceDeploymentTimeoutPending,
+			Value: SettingDeviceDeploymentTimeoutPendingDefault},
+		{Key: SettingDeviceDeploymentTimeoutDownloading,
+			Value: SettingDeviceDeploymentTimeoutDownloadingDefault},
+		{Key: SettingDeviceDeploymentTimeoutInstalling,
+			Value: SettingDeviceDeploymentTimeoutInstallingDefault},
+		{Key: SettingDeviceDeploymentTimeoutRebooting,
+			Value: SettingDeviceDeploymentTimeoutRebootingDefault},
+		{Key: SettingDeviceDeploymentTimeoutPauseBeforeInstall,
+			Value: SettingDeviceDeploymentTimeoutPauseBeforeInstallDefault},
+		{Key: SettingDeviceDeploymentTimeoutPauseBeforeCommit,
+			Value: SettingDeviceDeploymentTimeoutPauseBeforeCommitDefault},
+		{Key: SettingDeviceDeploymentTimeoutPauseBeforeReboot,
+			Value: SettingDeviceDeploymentTimeoutPauseBeforeRebootDefault},
+		{Key: SettingDeviceDeploymentStatusTransitions,
+			Value: SettingDeviceDeploymentStatusTransitionsDefault},
+		{Key: SettingArtifactGenerator, Value: SettingArtifactGeneratorDefault},
 	}
 )
@@ -28,8 +28,10 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/mendersoftware/deployments/app"
+	"github.com/mendersoftware/deployments/client/reporting"
 	"github.com/mendersoftware/deployments/client/workflows"
 	dconfig "github.com/mendersoftware/deployments/config"
+	"github.com/mendersoftware/deployments/model"
 	"github.com/mendersoftware/deployments/store"
 	"github.com/mendersoftware/deployments/store/mongo"
 )
@@ -108,8 +110,9 @@ func doMain(args []string) {
 			Action: cmdPropagateReporting,
 		},
 		{
-			Name:  "storage-daemon",
-			Usage: "Start storage daemon cleaning up expired objects from storage",
+			Name: "storage-daemon",
+			Usage: "Start storage daemon cleaning up expired objects from storage " +
+				"and failing interrupted artifact imports",
 			Flags: []cli.Flag{
 				cli.DurationFlag{
 					Name: "interval",
@@ -128,6 +131,90 @@ func doMain(args []string) {
 			},
 			Action: cmdStorageDaemon,
 		},
+		{
+			Name: "deployment-timeout-daemon",
+			Usage: "Start daemon failing device deployments stuck in an " +
+				"active status for longer than the configured timeout",
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name: "interval",
+					Usage: "Time interval to run the timeout routine; " +
+						"a value of 0 runs the daemon for one " +
+						"iteration and terminates (cron mode).",
+					Value: 0,
+				},
+			},
+			Action: cmdDeploymentTimeoutDaemon,
+		},
+		{
+			Name: "retention-daemon",
+			Usage: "Start daemon deleting the artifacts expired by " +
+				"the tenants' retention policies",
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name: "interval",
+					Usage: "Time interval to apply the retention policies; " +
+						"a value of 0 runs the daemon for one " +
+						"iteration and terminates (cron mode).",
+					Value: 0,
+				},
+				cli.StringFlag{
+					Name:  "tenant_id",
+					Usage: "Tenant ID (optional) - apply the policy of a single tenant.",
+				},
+				cli.BoolFlag{
+					Name: "dry-run",
+					Usage: "Do not delete any artifact," +
+						" just report the expired artifacts.",
+				},
+			},
+			Action: cmdRetentionDaemon,
+		},
+		{
+			Name: "verify-artifacts",
+			Usage: "Verify the stored artifacts against their checksums; " +
+				"missing and corrupt artifacts are no longer assigned to devices",
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name: "interval",
+					Usage: "Time interval to verify the artifacts; " +
+						"a value of 0 verifies the artifacts once " +
+						"and terminates (cron mode).",
+					Value: 0,
+				},
+				cli.StringFlag{
+					Name:  "tenant_id",
+					Usage: "Tenant ID (optional) - verify the artifacts of a single tenant.",
+				},
+				cli.BoolFlag{
+					Name: "dry-run",
+					Usage: "Do not mark any artifact," +
+						" just report the missing and corrupt artifacts.",
+				},
+			},
+			Action: cmdVerifyArtifacts,
+		},
+		{
+			Name: "copy-artifact",
+			Usage: "Copy an artifact, with its metadata and file, " +
+				"to other tenants and add it to their releases",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "artifact_id",
+					Usage: "ID of the artifact to copy.",
+				},
+				cli.StringFlag{
+					Name:  "tenant_id",
+					Usage: "Tenant ID (optional) - the tenant owning the artifact.",
+				},
+				cli.StringSliceFlag{
+					Name: "to_tenant_id",
+					Usage: "ID of a tenant to copy the artifact to, " +
+						"\"default\" for the default tenant; repeat for more tenants.",
+				},
+			},
+			Action: cmdCopyArtifact,
+		},
 	}
 
 	app.Action = cmdServer
@@ -231,6 +318,125 @@ func cmdStorageDaemon(args *cli.Context) error {
 	)
 }
 
+func cmdDeploymentTimeoutDaemon(args *cli.Context) error {
+	ctx := context.Background()
+	mgo, err := mongo.NewMongoClient(ctx, config.Config)
+	if err != nil {
+		return err
+	}
+	database := mongo.NewDataStoreMongoWithClient(mgo)
+	// configured as in the server, which also reindexes the failed
+	// device deployments in reporting
+	app := app.NewDeployments(database, nil, 0, false).
+		WithStatusTransitionsMode(model.StatusTransitionsMode(
+			config.Config.GetString(dconfig.SettingDeviceDeploymentStatusTransitions),
+		))
+	if addr := config.Config.GetString(dconfig.SettingReportingAddr); addr != "" {
+		app = app.WithReporting(reporting.NewClient(addr))
+	}
+	return app.TimeoutStuckDeviceDeployments(
+		ctx,
+		deviceDeploymentTimeouts(config.Config),
+		args.Duration("interval"),
+	)
+}
+
+func cmdRetentionDaemon(args *cli.Context) error {
+	ctx := context.Background()
+	objectStorage, err := SetupObjectStorage(ctx)
+	if err != nil {
+		return err
+	}
+	mgo, err := mongo.NewMongoClient(ctx, config.Config)
+	if err != nil {
+		return err
+	}
+	database := mongo.NewDataStoreMongoWithClient(mgo)
+	app := app.NewDeployments(database, objectStorage, 0, false)
+	return app.ApplyRetentionPolicies(
+		ctx,
+		args.String("tenant_id"),
+		args.Duration("interval"),
+		args.Bool("dry-run"),
+		os.Stdout,
+	)
+}
+
+func cmdVerifyArtifacts(args *cli.Context) error {
+	ctx := context.Background()
+	objectStorage, err := SetupObjectStorage(ctx)
+	if err != nil {
+		return err
+	}
+	mgo, err := mongo.NewMongoClient(ctx, config.Config)
+	if err != nil {
+		return err
+	}
+	database := mongo.NewDataStoreMongoWithClient(mgo)
+	app := app.NewDeployments(database, objectStorage, 0, false)
+	return app.VerifyTenantsArtifacts(
+		ctx,
+		args.String("tenant_id"),
+		args.Duration("interval"),
+		args.Bool("dry-run"),
+		os.Stdout,
+	)
+}
+
+func cmdCopyArtifact(args *cli.Context) error {
+	artifactID := args.String("artifact_id")
+	if artifactID == "" {
+		return cli.NewExitError("missing artifact_id", 1)
+	}
+	var tenantIDs []string
+	for _, tenantID := range args.StringSlice("to_tenant_id") {
+		if tenantID == "default" {
+			tenantID = ""
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	if len(tenantIDs) == 0 {
+		return cli.NewExitError("missing to_tenant_id", 1)
+	}
+	ctx := context.Background()
+	if tenantID := args.String("tenant_id"); tenantID != "" {
+		ctx = identity.WithContext(ctx, &identity.Identity{Tenant: tenantID})
+	}
+	objectStorage, err := SetupObjectStorage(ctx)
+	if err != nil {
+		return err
+	}
+	mgo, err := mongo.NewMongoClient(ctx, config.Config)
+	if err != nil {
+		return err
+	}
+	database := mongo.NewDataStoreMongoWithClient(mgo)
+	app := app.NewDeployments(database, objectStorage, 0, false)
+	return app.CopyArtifactToTenants(ctx, artifactID, tenantIDs, os.Stdout)
+}
+
+func deviceDeploymentTimeouts(
+	c config.Reader,
+) map[model.DeviceDeploymentStatus]time.Duration {
+	settings := map[model.DeviceDeploymentStatus]string{
+		model.DeviceDeploymentStatusPending:     dconfig.SettingDeviceDeploymentTimeoutPending,
+		model.DeviceDeploymentStatusDownloading: dconfig.SettingDeviceDeploymentTimeoutDownloading,
+		model.DeviceDeploymentStatusInstalling:  dconfig.SettingDeviceDeploymentTimeoutInstalling,
+		model.DeviceDeploymentStatusRebooting:   dconfig.SettingDeviceDeploymentTimeoutRebooting,
+		model.DeviceDeploymentStatusPauseBeforeInstall: dconfig.
+			SettingDeviceDeploymentTimeoutPauseBeforeInstall,
+		model.DeviceDeploymentStatusPauseBeforeCommit: dconfig.
+			SettingDeviceDeploymentTimeoutPauseBeforeCommit,
+		model.DeviceDeploymentStatusPauseBeforeReboot: dconfig.
+			SettingDeviceDeploymentTimeoutPauseBeforeReboot,
+	}
+	timeouts := make(map[model.DeviceDeploymentStatus]time.Duration, len(settings))
+	for status, key := range settings {
+		timeouts[status] = time.Duration(c.GetInt(key)) * time.Second
+	}
+	return timeouts
+}
+
 func cmdPropagateReporting(args *cli.Context) error {
 	if config.Config.GetString(dconfig.SettingReportingAddr) == "" {
 		return cli.NewExitError(errors.New("reporting address not configured"), 1)
@@ -54,6 +54,10 @@ var defaultProdStack = []rest.Middleware{
 	&rest.RecoverMiddleware{},
 }
 
+var reChunkedUploadPath = regexp.MustCompile(
+	"^" + regexp.QuoteMeta(api_http.ApiUrlManagementArtifactsUploads) + "/[^/]+$",
+)
+
 func SetupMiddleware(c config.Reader, api *rest.Api) {
 
 	api.Use(commonLoggingAccessStack...)
@@ -72,6 +76,8 @@ func SetupMiddleware(c config.Reader, api *rest.Api) {
 
 	// Verifies the request Content-Type header if the content is non-null.
 	// For the POST /api/0.0.1/images request expected Content-Type is 'multipart/form-data'.
+	// For the PATCH chunked upload requests expected Content-Type is
+	// 'application/offset+octet-stream'.
 	// For the rest of the requests expected Content-Type is 'application/json'.
 	api.Use(&rest.IfMiddleware{
 		Condition: func(r *rest.Request) bool {
@@ -100,6 +106,24 @@ func SetupMiddleware(c config.Reader, api *rest.Api) {
 				handler(w, r)
 			}
 		}),
-		IfFalse: &rest.ContentTypeCheckerMiddleware{},
+		IfFalse: &rest.IfMiddleware{
+			Condition: func(r *rest.Request) bool {
+				return r.Method == http.MethodPatch && reChunkedUploadPath.MatchString(r.URL.Path)
+			},
+			IfTrue: rest.MiddlewareSimple(func(handler rest.HandlerFunc) rest.HandlerFunc {
+				return func(w rest.ResponseWriter, r *rest.Request) {
+					mediatype, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+					if mediatype != api_http.ChunkedUploadContentType {
+						rest.Error(w,
+							"Bad Content-Type, expected '"+
+								api_http.ChunkedUploadContentType+"'",
+							http.StatusUnsupportedMediaType)
+						return
+					}
+					handler(w, r)
+				}
+			}),
+			IfFalse: &rest.ContentTypeCheckerMiddleware{},
+		},
 	})
 }
@@ -0,0 +1,53 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+// ArtifactContents lists the payloads of an artifact with their files.
+type ArtifactContents struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Info     *ArtifactInfo `json:"info,omitempty"`
+	Payloads []Update      `json:"payloads"`
+}
+
+// FindUpdateFile returns the first payload file with the given name, or nil
+// if none of the payloads contains it.
+func FindUpdateFile(updates []Update, name string) *UpdateFile {
+	for i := range updates {
+		for j := range updates[i].Files {
+			if updates[i].Files[j].Name == name {
+				return &updates[i].Files[j]
+			}
+		}
+	}
+	return nil
+}
+
+// UpdatesComplete tells whether the payloads list the size and checksum of
+// all their files; artifacts uploaded without verification only record the
+// headers of the payloads.
+func UpdatesComplete(updates []Update) bool {
+	if len(updates) == 0 {
+		return false
+	}
+	for _, u := range updates {
+		for _, f := range u.Files {
+			if f.Size == 0 || f.Checksum == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
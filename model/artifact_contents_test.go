@@ -0,0 +1,69 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUpdateFile(t *testing.T) {
+	t.Parallel()
+
+	updates := []Update{
+		{Files: []UpdateFile{{Name: "rootfs.ext4", Size: 1}}},
+		{Files: []UpdateFile{{Name: "app.tar", Size: 2}, {Name: "rootfs.ext4", Size: 3}}},
+	}
+	assert.Equal(t, &updates[0].Files[0], FindUpdateFile(updates, "rootfs.ext4"))
+	assert.Equal(t, &updates[1].Files[0], FindUpdateFile(updates, "app.tar"))
+	assert.Nil(t, FindUpdateFile(updates, "other.bin"))
+	assert.Nil(t, FindUpdateFile(nil, "app.tar"))
+}
+
+func TestUpdatesComplete(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		updates  []Update
+		complete bool
+	}{
+		"complete": {
+			updates: []Update{
+				{Files: []UpdateFile{{Name: "app.tar", Checksum: "abc", Size: 2}}},
+				{},
+			},
+			complete: true,
+		},
+		"no payloads": {},
+		"missing size": {
+			updates: []Update{
+				{Files: []UpdateFile{{Name: "app.tar", Checksum: "abc"}}},
+			},
+		},
+		"missing checksum": {
+			updates: []Update{
+				{Files: []UpdateFile{{Name: "app.tar", Size: 2}}},
+			},
+		},
+	}
+	for name := range testCases {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.complete, UpdatesComplete(tc.updates))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// CopyArtifactRequest requests the copy of an artifact to another tenant
+type CopyArtifactRequest struct {
+	// TenantID is the ID of the destination tenant; "default" for the
+	// default tenant of single-tenant setups
+	TenantID string `json:"tenant_id"`
+}
+
+// Validate checks structure according to valid tags
+func (r CopyArtifactRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.TenantID, validation.Required),
+	)
+}
+
+// ArtifactCopy reports the copy of an artifact to a tenant.
+type ArtifactCopy struct {
+	TenantID string `json:"tenant_id"`
+	// ID is the ID of the copy
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+
+	// Error is set when the artifact could not be copied
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,220 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ArtifactProvidesArtifactName = "artifact_name"
+	ArtifactDependsDeviceType    = "device_type"
+)
+
+var (
+	ErrArtifactMetaEditEmpty = errors.New(
+		"at least one of artifact_provides, artifact_depends " +
+			"and clears_artifact_provides is required",
+	)
+	ErrArtifactMetaEditArtifactName = errors.New(
+		"the artifact_name provide cannot be edited",
+	)
+	ErrArtifactMetaEditDeviceType = errors.New(
+		"the device_type depend is set by the compatible device types",
+	)
+	ErrArtifactMetaEditDependsValue = errors.New(
+		"must be a string, a non-empty list of strings, or null",
+	)
+)
+
+// ArtifactMetaEdit adds, overrides or removes the provides, depends and
+// clears provides of an uploaded artifact; the artifact file is left as is.
+type ArtifactMetaEdit struct {
+	// Provides to add or override; a null value removes the provide
+	Provides map[string]*string `json:"artifact_provides,omitempty"`
+	// Depends to add or override; a null value removes the depend
+	Depends map[string]interface{} `json:"artifact_depends,omitempty"`
+	// ClearsProvides replaces the clears provides of the artifact, if set
+	ClearsProvides []string `json:"clears_artifact_provides,omitempty"`
+}
+
+// Validate checks the edit and normalizes the lists of depends values.
+func (e *ArtifactMetaEdit) Validate() error {
+	if e.Provides == nil && e.Depends == nil && e.ClearsProvides == nil {
+		return ErrArtifactMetaEditEmpty
+	}
+	for key, value := range e.Provides {
+		if key == ArtifactProvidesArtifactName {
+			return ErrArtifactMetaEditArtifactName
+		} else if err := validation.Validate(key,
+			validation.Required, lengthIn1To4096); err != nil {
+			return errors.Wrap(err, "artifact_provides")
+		} else if value != nil {
+			if err := lengthLessThan4096.Validate(*value); err != nil {
+				return errors.Wrapf(err, "artifact_provides: %s", key)
+			}
+		}
+	}
+	for key, value := range e.Depends {
+		if key == ArtifactDependsDeviceType {
+			return ErrArtifactMetaEditDeviceType
+		} else if err := validation.Validate(key,
+			validation.Required, lengthIn1To4096); err != nil {
+			return errors.Wrap(err, "artifact_depends")
+		}
+		switch v := value.(type) {
+		case nil, string:
+		case []interface{}:
+			values := make([]string, len(v))
+			for i := range v {
+				s, ok := v[i].(string)
+				if !ok {
+					return errors.Wrapf(ErrArtifactMetaEditDependsValue,
+						"artifact_depends: %s", key)
+				}
+				values[i] = s
+			}
+			if len(values) == 0 {
+				return errors.Wrapf(ErrArtifactMetaEditDependsValue,
+					"artifact_depends: %s", key)
+			}
+			e.Depends[key] = values
+		case []string:
+			if len(v) == 0 {
+				return errors.Wrapf(ErrArtifactMetaEditDependsValue,
+					"artifact_depends: %s", key)
+			}
+		default:
+			return errors.Wrapf(ErrArtifactMetaEditDependsValue,
+				"artifact_depends: %s", key)
+		}
+	}
+	err := validation.Validate(e.ClearsProvides,
+		validation.Each(validation.Required, lengthIn1To4096))
+	return errors.Wrap(err, "clears_artifact_provides")
+}
+
+// Apply applies the edit to the artifact metadata.
+func (e *ArtifactMetaEdit) Apply(am *ArtifactMeta) {
+	if len(e.Provides) > 0 && am.Provides == nil {
+		am.Provides = make(map[string]string, len(e.Provides))
+	}
+	for key, value := range e.Provides {
+		if value == nil {
+			delete(am.Provides, key)
+		} else {
+			am.Provides[key] = *value
+		}
+	}
+	if len(e.Depends) > 0 && am.Depends == nil {
+		am.Depends = make(map[string]interface{}, len(e.Depends))
+	}
+	for key, value := range e.Depends {
+		if value == nil {
+			delete(am.Depends, key)
+		} else {
+			am.Depends[key] = value
+		}
+	}
+	if e.ClearsProvides != nil {
+		am.ClearsProvides = e.ClearsProvides
+	}
+}
+
+// ArtifactDependencies are the provides, depends and clears provides of an
+// artifact.
+type ArtifactDependencies struct {
+	//nolint:lll
+	Provides map[string]string `json:"artifact_provides,omitempty" bson:"provides,omitempty"`
+	//nolint:lll
+	Depends map[string]interface{} `json:"artifact_depends,omitempty" bson:"depends,omitempty"`
+	//nolint:lll
+	ClearsProvides []string `json:"clears_artifact_provides,omitempty" bson:"clears_provides,omitempty"`
+}
+
+// ArtifactMetaChange records an edit of the provides, depends and clears
+// provides of an artifact.
+type ArtifactMetaChange struct {
+	// UserID is the ID of the user who edited the artifact
+	UserID  string    `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Changed time.Time `json:"changed" bson:"changed"`
+
+	Before ArtifactDependencies `json:"before" bson:"before"`
+	After  ArtifactDependencies `json:"after" bson:"after"`
+}
+
+// Dependencies returns a copy of the provides, depends and clears provides
+// of the artifact.
+func (am *ArtifactMeta) Dependencies() ArtifactDependencies {
+	deps := ArtifactDependencies{}
+	if am.Provides != nil {
+		deps.Provides = make(map[string]string, len(am.Provides))
+		for key, value := range am.Provides {
+			deps.Provides[key] = value
+		}
+	}
+	if am.Depends != nil {
+		deps.Depends = make(map[string]interface{}, len(am.Depends))
+		for key, value := range am.Depends {
+			deps.Depends[key] = value
+		}
+	}
+	if am.ClearsProvides != nil {
+		deps.ClearsProvides = append([]string{}, am.ClearsProvides...)
+	}
+	return deps
+}
+
+// DependsSatisfiedBy returns true if the device provides satisfy all the
+// depends of the artifact: the device provides the value of the depend, or
+// one of the values of a list.
+func (am *ArtifactMeta) DependsSatisfiedBy(provides map[string]string) bool {
+	for key, value := range am.Depends {
+		provided, ok := provides[key]
+		if !ok {
+			return false
+		}
+		var values []interface{}
+		switch v := value.(type) {
+		case string:
+			values = []interface{}{v}
+		case []string:
+			for _, s := range v {
+				values = append(values, s)
+			}
+		case []interface{}:
+			values = v
+		case primitive.A:
+			values = v
+		default:
+			return false
+		}
+		satisfied := false
+		for _, v := range values {
+			if s, ok := v.(string); ok && s == provided {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
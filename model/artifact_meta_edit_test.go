@@ -0,0 +1,184 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestArtifactMetaEditValidate(t *testing.T) {
+	t.Parallel()
+
+	version := "2.0"
+	testCases := map[string]struct {
+		edit ArtifactMetaEdit
+		err  string
+
+		depends map[string]interface{}
+	}{
+		"ok": {
+			edit: ArtifactMetaEdit{
+				Provides: map[string]*string{
+					"rootfs-image.version": &version,
+					"rootfs-image.old":     nil,
+				},
+				Depends: map[string]interface{}{
+					"artifact_name": []interface{}{"release-1", "release-2"},
+					"checksum":      "abc",
+					"old":           nil,
+				},
+				ClearsProvides: []string{"rootfs-image.*"},
+			},
+			depends: map[string]interface{}{
+				"artifact_name": []string{"release-1", "release-2"},
+				"checksum":      "abc",
+				"old":           nil,
+			},
+		},
+		"ok, clears provides only": {
+			edit: ArtifactMetaEdit{ClearsProvides: []string{}},
+		},
+		"error, empty": {
+			err: ErrArtifactMetaEditEmpty.Error(),
+		},
+		"error, artifact name": {
+			edit: ArtifactMetaEdit{
+				Provides: map[string]*string{"artifact_name": &version},
+			},
+			err: ErrArtifactMetaEditArtifactName.Error(),
+		},
+		"error, device type": {
+			edit: ArtifactMetaEdit{
+				Depends: map[string]interface{}{"device_type": "rpi4"},
+			},
+			err: ErrArtifactMetaEditDeviceType.Error(),
+		},
+		"error, empty provide key": {
+			edit: ArtifactMetaEdit{
+				Provides: map[string]*string{"": &version},
+			},
+			err: "artifact_provides: cannot be blank",
+		},
+		"error, depends value": {
+			edit: ArtifactMetaEdit{
+				Depends: map[string]interface{}{"checksum": 1.0},
+			},
+			err: "artifact_depends: checksum: " + ErrArtifactMetaEditDependsValue.Error(),
+		},
+		"error, empty depends list": {
+			edit: ArtifactMetaEdit{
+				Depends: map[string]interface{}{"checksum": []interface{}{}},
+			},
+			err: "artifact_depends: checksum: " + ErrArtifactMetaEditDependsValue.Error(),
+		},
+		"error, depends list value": {
+			edit: ArtifactMetaEdit{
+				Depends: map[string]interface{}{"checksum": []interface{}{"abc", 1.0}},
+			},
+			err: "artifact_depends: checksum: " + ErrArtifactMetaEditDependsValue.Error(),
+		},
+		"error, clears provides": {
+			edit: ArtifactMetaEdit{ClearsProvides: []string{""}},
+			err:  "clears_artifact_provides: 0: cannot be blank.",
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.edit.Validate()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.depends, tc.edit.Depends)
+			}
+		})
+	}
+}
+
+func TestArtifactMetaEditApply(t *testing.T) {
+	t.Parallel()
+
+	version := "2.0"
+	meta := &ArtifactMeta{
+		Name: "release-1",
+		Provides: map[string]string{
+			"artifact_name":        "release-1",
+			"rootfs-image.version": "1.0",
+			"rootfs-image.old":     "old",
+		},
+		Depends: map[string]interface{}{
+			"device_type": []string{"rpi4"},
+			"old":         "old",
+		},
+		ClearsProvides: []string{"rootfs-image.*"},
+	}
+	before := meta.Dependencies()
+	edit := &ArtifactMetaEdit{
+		Provides: map[string]*string{
+			"rootfs-image.version": &version,
+			"rootfs-image.old":     nil,
+		},
+		Depends: map[string]interface{}{
+			"checksum": "abc",
+			"old":      nil,
+		},
+	}
+	edit.Apply(meta)
+	assert.Equal(t, map[string]string{
+		"artifact_name":        "release-1",
+		"rootfs-image.version": "2.0",
+	}, meta.Provides)
+	assert.Equal(t, map[string]interface{}{
+		"device_type": []string{"rpi4"},
+		"checksum":    "abc",
+	}, meta.Depends)
+	assert.Equal(t, []string{"rootfs-image.*"}, meta.ClearsProvides)
+
+	// the copy is not modified
+	assert.Equal(t, "1.0", before.Provides["rootfs-image.version"])
+	assert.Equal(t, "old", before.Depends["old"])
+}
+
+func TestArtifactMetaDependsSatisfiedBy(t *testing.T) {
+	t.Parallel()
+
+	meta := &ArtifactMeta{
+		Depends: map[string]interface{}{
+			"device_type":   primitive.A{"rpi3", "rpi4"},
+			"artifact_name": []string{"release-1", "release-2"},
+			"checksum":      "abc",
+		},
+	}
+	device := &InstalledDeviceDeployment{
+		ArtifactName: "release-2",
+		DeviceType:   "rpi4",
+		Provides:     map[string]string{"checksum": "abc"},
+	}
+	assert.True(t, meta.DependsSatisfiedBy(device.AllProvides()))
+
+	device.Provides["checksum"] = "def"
+	assert.False(t, meta.DependsSatisfiedBy(device.AllProvides()))
+
+	device.Provides = nil
+	assert.False(t, meta.DependsSatisfiedBy(device.AllProvides()))
+
+	device.Provides = map[string]string{"checksum": "abc"}
+	device.ArtifactName = "release-3"
+	assert.False(t, meta.DependsSatisfiedBy(device.AllProvides()))
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+// ArtifactObject is an artifact file stored once and shared by all the
+// artifacts with the same content. The object is the file of the first
+// artifact uploaded with this content; it is deleted with the last artifact
+// referencing it.
+type ArtifactObject struct {
+	// ID is the SHA-256 checksum of the content, prefixed with the tenant
+	// ID for tenants with their own storage
+	ID string `bson:"_id"`
+	// Path of the object in the storage
+	Path string `bson:"path"`
+	Size int64  `bson:"size"`
+
+	References []ArtifactObjectReference `bson:"references"`
+	RefCount   int                       `bson:"ref_count"`
+
+	Created time.Time `bson:"created"`
+}
+
+// ArtifactObjectReference is an artifact stored in an ArtifactObject
+type ArtifactObjectReference struct {
+	TenantID string `bson:"tenant_id,omitempty"`
+	ImageID  string `bson:"image_id"`
+}
@@ -36,6 +36,9 @@ var (
 	ErrInvalidDeploymentDefinitionConflict = errors.New(
 		"Invalid deployments definition: list of devices provided togheter with all_devices flag",
 	)
+	ErrInvalidDeploymentDefinitionChannel = errors.New(
+		"Invalid deployments definition: artifact_name provided together with channel",
+	)
 	ErrInvalidDeploymentToGroupDefinitionConflict = errors.New(
 		"The deployment for group constructor should have neither list of devices" +
 			" nor all_devices flag set",
@@ -76,6 +79,10 @@ type DeploymentConstructor struct {
 	// Artifact name to be installed required, associated with image
 	ArtifactName string `json:"artifact_name,omitempty"`
 
+	// Release channel resolved to the artifact name when the deployment
+	// is created; exclusive with the artifact name
+	Channel string `json:"channel,omitempty" bson:"channel,omitempty"`
+
 	// List of device id's targeted for deployments, required
 	Devices []string `json:"devices,omitempty" bson:"-"`
 
@@ -95,7 +102,15 @@ type DeploymentConstructor struct {
 func (c DeploymentConstructor) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.Name, validation.Required, lengthIn1To4096),
-		validation.Field(&c.ArtifactName, validation.Required, lengthIn1To4096),
+		validation.Field(&c.ArtifactName,
+			validation.When(c.Channel == "", validation.Required),
+			lengthLessThan4096,
+		),
+		validation.Field(&c.Channel, validation.When(c.Channel != "",
+			validation.By(func(interface{}) error {
+				return ValidateReleaseChannelName(c.Channel)
+			}),
+		)),
 		validation.Field(&c.Devices, validation.Each(validation.Required)),
 	)
 }
@@ -105,6 +120,10 @@ func (c DeploymentConstructor) ValidateNew() error {
 		return err
 	}
 
+	if c.ArtifactName != "" && c.Channel != "" {
+		return ErrInvalidDeploymentDefinitionChannel
+	}
+
 	if len(c.Group) == 0 {
 		if len(c.Devices) == 0 && !c.AllDevices {
 			return ErrInvalidDeploymentDefinitionNoDevices
@@ -31,6 +31,7 @@ func TestDeploymentConstructorValidate(t *testing.T) {
 	testCases := []struct {
 		InputName         string
 		InputArtifactName string
+		InputChannel      string
 		InputDevices      []string
 		InputAllDevices   bool
 		InputGroup        string
@@ -119,6 +120,25 @@ func TestDeploymentConstructorValidate(t *testing.T) {
 			InputAllDevices:   true,
 			IsValid:           false,
 		},
+		{
+			InputName:    "f826484e-1157-4109-af21-304e6d711560",
+			InputChannel: "stable",
+			InputDevices: []string{"lala"},
+			IsValid:      true,
+		},
+		{
+			InputName:    "f826484e-1157-4109-af21-304e6d711560",
+			InputChannel: "not a channel",
+			InputDevices: []string{"lala"},
+			IsValid:      false,
+		},
+		{
+			InputName:         "f826484e-1157-4109-af21-304e6d711560",
+			InputArtifactName: "f826484e-1157-4109-af21-304e6d711560",
+			InputChannel:      "stable",
+			InputDevices:      []string{"lala"},
+			IsValid:           false,
+		},
 	}
 
 	for _, test := range testCases {
@@ -126,6 +146,7 @@ func TestDeploymentConstructorValidate(t *testing.T) {
 		dep := &DeploymentConstructor{}
 		dep.Name = test.InputName
 		dep.ArtifactName = test.InputArtifactName
+		dep.Channel = test.InputChannel
 		dep.Devices = test.InputDevices
 		dep.Group = test.InputGroup
 		dep.AllDevices = test.InputAllDevices
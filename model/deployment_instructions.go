@@ -19,6 +19,11 @@ type ArtifactDeploymentInstructions struct {
 	ArtifactName          string   `json:"artifact_name"`
 	Source                Link     `json:"source"`
 	DeviceTypesCompatible []string `json:"device_types_compatible"`
+
+	// Size and hex-encoded SHA-256 checksum of the artifact file, which
+	// let the device verify a download resumed with Range requests.
+	Size     int64  `json:"size,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
 }
 
 type DeploymentInstructions struct {
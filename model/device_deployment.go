@@ -248,6 +248,9 @@ type DeviceDeployment struct {
 
 	// Device reported substate
 	SubState string `json:"substate,omitempty" bson:"substate,omitempty"`
+
+	// Status transitions in the order they were reported
+	StatusHistory []DeviceDeploymentStatusTransition `json:"-" bson:"status_history,omitempty"`
 }
 
 func NewDeviceDeployment(deviceId, deploymentId string) *DeviceDeployment {
@@ -268,6 +271,10 @@ func NewDeviceDeployment(deviceId, deploymentId string) *DeviceDeployment {
 		Id:             id,
 		Created:        &now,
 		IsLogAvailable: false,
+		StatusHistory: []DeviceDeploymentStatusTransition{{
+			Status:    DeviceDeploymentStatusPending,
+			Timestamp: now,
+		}},
 	}
 }
 
@@ -368,6 +375,18 @@ type InstalledDeviceDeployment struct {
 	Provides     map[string]string `json:"artifact_provides,omitempty"`
 }
 
+// AllProvides returns the provides of the device including its artifact
+// name and device type.
+func (i *InstalledDeviceDeployment) AllProvides() map[string]string {
+	provides := make(map[string]string, len(i.Provides)+2)
+	for key, value := range i.Provides {
+		provides[key] = value
+	}
+	provides[ArtifactProvidesArtifactName] = i.ArtifactName
+	provides[ArtifactDependsDeviceType] = i.DeviceType
+	return provides
+}
+
 // DeploymentNextRequest holds a deployments/next request
 type DeploymentNextRequest struct {
 	DeviceProvides   *InstalledDeviceDeployment `json:"device_provides"`
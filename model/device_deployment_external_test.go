@@ -35,6 +35,10 @@ func TestNewDeviceDeployment(t *testing.T) {
 	assert.NotEmpty(t, dd.Id)
 	assert.WithinDuration(t, time.Now(), *dd.Created, time.Minute)
 	assert.Equal(t, false, dd.IsLogAvailable)
+	assert.Equal(t, []DeviceDeploymentStatusTransition{{
+		Status:    DeviceDeploymentStatusPending,
+		Timestamp: *dd.Created,
+	}}, dd.StatusHistory)
 }
 
 func TestDeviceDeploymentValidate(t *testing.T) {
@@ -0,0 +1,119 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+// DeviceDeploymentStatusTransition records a single change of the status
+// of a device deployment.
+type DeviceDeploymentStatusTransition struct {
+	// Status the device moved to
+	Status DeviceDeploymentStatus `json:"status" bson:"status"`
+
+	// Substate reported by the device together with the status
+	SubState string `json:"substate,omitempty" bson:"substate,omitempty"`
+
+	// Time of the transition
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+}
+
+// DeviceDeploymentPhase describes the time spent by the device in a single
+// status of the deployment.
+type DeviceDeploymentPhase struct {
+	DeviceDeploymentStatusTransition
+
+	// Time the device left the status; unset for the current status
+	Finished *time.Time `json:"finished,omitempty"`
+
+	// Duration of the phase in seconds; for the current phase of an
+	// unfinished deployment it is the time elapsed so far.
+	Duration float64 `json:"duration"`
+}
+
+// DeviceDeploymentHistory holds the path a device took through a deployment.
+type DeviceDeploymentHistory struct {
+	DeploymentID string                 `json:"deployment_id"`
+	DeviceID     string                 `json:"device_id"`
+	Status       DeviceDeploymentStatus `json:"status"`
+
+	// Phases lists the statuses in the order the device reported them
+	Phases []DeviceDeploymentPhase `json:"phases"`
+
+	// Durations sums up the seconds spent in each status
+	Durations map[string]float64 `json:"durations"`
+
+	// Duration is the overall duration of the deployment on the device in
+	// seconds
+	Duration float64 `json:"duration"`
+}
+
+// NewDeviceDeploymentHistory computes the phases of the device deployment
+// from its status transitions; the phase the device is currently in, if the
+// deployment is not finished yet, is measured up to `now`.
+func NewDeviceDeploymentHistory(
+	dd *DeviceDeployment,
+	now time.Time,
+) *DeviceDeploymentHistory {
+	transitions := dd.statusTransitions()
+	history := &DeviceDeploymentHistory{
+		DeploymentID: dd.DeploymentId,
+		DeviceID:     dd.DeviceId,
+		Status:       dd.Status,
+		Phases:       make([]DeviceDeploymentPhase, len(transitions)),
+		Durations:    make(map[string]float64, len(transitions)),
+	}
+	for i, transition := range transitions {
+		phase := DeviceDeploymentPhase{
+			DeviceDeploymentStatusTransition: transition,
+		}
+		if i+1 < len(transitions) {
+			finished := transitions[i+1].Timestamp
+			phase.Finished = &finished
+			phase.Duration = finished.Sub(transition.Timestamp).Seconds()
+		} else if !IsDeviceDeploymentStatusFinished(transition.Status) {
+			phase.Duration = now.Sub(transition.Timestamp).Seconds()
+		}
+		history.Phases[i] = phase
+		history.Durations[transition.Status.String()] += phase.Duration
+		history.Duration += phase.Duration
+	}
+	return history
+}
+
+// statusTransitions returns the recorded status transitions. Device
+// deployments created before the transitions were recorded only keep the
+// creation and finish time, so the history is approximated from those.
+func (d *DeviceDeployment) statusTransitions() []DeviceDeploymentStatusTransition {
+	if len(d.StatusHistory) > 0 {
+		return d.StatusHistory
+	}
+	var transitions []DeviceDeploymentStatusTransition
+	if d.Created != nil {
+		transitions = append(transitions, DeviceDeploymentStatusTransition{
+			Status:    DeviceDeploymentStatusPending,
+			Timestamp: *d.Created,
+		})
+	}
+	if d.Status != DeviceDeploymentStatusPending && d.Finished != nil {
+		transitions = append(transitions, DeviceDeploymentStatusTransition{
+			Status:    d.Status,
+			SubState:  d.SubState,
+			Timestamp: *d.Finished,
+		})
+	}
+	return transitions
+}
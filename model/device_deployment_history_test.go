@@ -0,0 +1,194 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDeviceDeploymentHistory(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time {
+		return start.Add(d)
+	}
+	ptr := func(t time.Time) *time.Time {
+		return &t
+	}
+
+	testCases := map[string]struct {
+		deviceDeployment *DeviceDeployment
+		now              time.Time
+
+		phases    []DeviceDeploymentPhase
+		durations map[string]float64
+		duration  float64
+	}{
+		"ok, finished": {
+			deviceDeployment: &DeviceDeployment{
+				Status: DeviceDeploymentStatusFailure,
+				StatusHistory: []DeviceDeploymentStatusTransition{{
+					Status:    DeviceDeploymentStatusPending,
+					Timestamp: at(0),
+				}, {
+					Status:    DeviceDeploymentStatusDownloading,
+					Timestamp: at(10 * time.Second),
+				}, {
+					Status:    DeviceDeploymentStatusInstalling,
+					Timestamp: at(70 * time.Second),
+				}, {
+					Status:    DeviceDeploymentStatusRebooting,
+					Timestamp: at(100 * time.Second),
+				}, {
+					Status:    DeviceDeploymentStatusInstalling,
+					SubState:  "rollback",
+					Timestamp: at(130 * time.Second),
+				}, {
+					Status:    DeviceDeploymentStatusFailure,
+					Timestamp: at(140 * time.Second),
+				}},
+			},
+			now: at(time.Hour),
+
+			phases: []DeviceDeploymentPhase{{
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusPending,
+					Timestamp: at(0),
+				},
+				Finished: ptr(at(10 * time.Second)),
+				Duration: 10,
+			}, {
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusDownloading,
+					Timestamp: at(10 * time.Second),
+				},
+				Finished: ptr(at(70 * time.Second)),
+				Duration: 60,
+			}, {
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusInstalling,
+					Timestamp: at(70 * time.Second),
+				},
+				Finished: ptr(at(100 * time.Second)),
+				Duration: 30,
+			}, {
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusRebooting,
+					Timestamp: at(100 * time.Second),
+				},
+				Finished: ptr(at(130 * time.Second)),
+				Duration: 30,
+			}, {
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusInstalling,
+					SubState:  "rollback",
+					Timestamp: at(130 * time.Second),
+				},
+				Finished: ptr(at(140 * time.Second)),
+				Duration: 10,
+			}, {
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusFailure,
+					Timestamp: at(140 * time.Second),
+				},
+			}},
+			durations: map[string]float64{
+				DeviceDeploymentStatusPendingStr:     10,
+				DeviceDeploymentStatusDownloadingStr: 60,
+				DeviceDeploymentStatusInstallingStr:  40,
+				DeviceDeploymentStatusRebootingStr:   30,
+				DeviceDeploymentStatusFailureStr:     0,
+			},
+			duration: 140,
+		},
+		"ok, in progress": {
+			deviceDeployment: &DeviceDeployment{
+				Status: DeviceDeploymentStatusDownloading,
+				StatusHistory: []DeviceDeploymentStatusTransition{{
+					Status:    DeviceDeploymentStatusPending,
+					Timestamp: at(0),
+				}, {
+					Status:    DeviceDeploymentStatusDownloading,
+					Timestamp: at(10 * time.Second),
+				}},
+			},
+			now: at(time.Minute),
+
+			phases: []DeviceDeploymentPhase{{
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusPending,
+					Timestamp: at(0),
+				},
+				Finished: ptr(at(10 * time.Second)),
+				Duration: 10,
+			}, {
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusDownloading,
+					Timestamp: at(10 * time.Second),
+				},
+				Duration: 50,
+			}},
+			durations: map[string]float64{
+				DeviceDeploymentStatusPendingStr:     10,
+				DeviceDeploymentStatusDownloadingStr: 50,
+			},
+			duration: 60,
+		},
+		"ok, no history recorded": {
+			deviceDeployment: &DeviceDeployment{
+				Status:   DeviceDeploymentStatusSuccess,
+				Created:  ptr(at(0)),
+				Finished: ptr(at(time.Minute)),
+			},
+			now: at(time.Hour),
+
+			phases: []DeviceDeploymentPhase{{
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusPending,
+					Timestamp: at(0),
+				},
+				Finished: ptr(at(time.Minute)),
+				Duration: 60,
+			}, {
+				DeviceDeploymentStatusTransition: DeviceDeploymentStatusTransition{
+					Status:    DeviceDeploymentStatusSuccess,
+					Timestamp: at(time.Minute),
+				},
+			}},
+			durations: map[string]float64{
+				DeviceDeploymentStatusPendingStr: 60,
+				DeviceDeploymentStatusSuccessStr: 0,
+			},
+			duration: 60,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			history := NewDeviceDeploymentHistory(tc.deviceDeployment, tc.now)
+			assert.Equal(t, tc.deviceDeployment.Status, history.Status)
+			assert.Equal(t, tc.phases, history.Phases)
+			assert.Equal(t, tc.durations, history.Durations)
+			assert.Equal(t, tc.duration, history.Duration)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+// deviceDeploymentProgress orders the active statuses along the path a
+// device takes through the update process.
+var deviceDeploymentProgress = map[DeviceDeploymentStatus]int{
+	DeviceDeploymentStatusPending:            0,
+	DeviceDeploymentStatusDownloading:        1,
+	DeviceDeploymentStatusPauseBeforeInstall: 2,
+	DeviceDeploymentStatusInstalling:         3,
+	DeviceDeploymentStatusPauseBeforeReboot:  4,
+	DeviceDeploymentStatusRebooting:          5,
+	DeviceDeploymentStatusPauseBeforeCommit:  6,
+}
+
+// deviceDeploymentRollback holds the statuses a device may go back to once
+// it started installing: a device rolling back the update reinstalls the
+// previous software and reboots into it, and a device retrying an
+// interrupted installation reports installing again.
+var deviceDeploymentRollback = map[DeviceDeploymentStatus]bool{
+	DeviceDeploymentStatusInstalling: true,
+	DeviceDeploymentStatusRebooting:  true,
+}
+
+// CanTransitionTo reports whether a device deployment in the given status
+// may move to the next status. Active statuses move forward along the
+// update process, states may be skipped, and any active status may finish;
+// from installing on, a device may go back to installing or rebooting to
+// roll back or retry the update. Finished statuses are final; reporting the
+// current status again is always allowed.
+func (stat DeviceDeploymentStatus) CanTransitionTo(next DeviceDeploymentStatus) bool {
+	if stat == next {
+		return true
+	}
+	from, active := deviceDeploymentProgress[stat]
+	if !active {
+		return false
+	}
+	if IsDeviceDeploymentStatusFinished(next) {
+		return true
+	}
+	if deviceDeploymentRollback[next] &&
+		from >= deviceDeploymentProgress[DeviceDeploymentStatusInstalling] {
+		return true
+	}
+	to, ok := deviceDeploymentProgress[next]
+	return ok && to > from
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceDeploymentStatusCanTransitionTo(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		from DeviceDeploymentStatus
+		to   DeviceDeploymentStatus
+
+		allowed bool
+	}{
+		{from: DeviceDeploymentStatusPending, to: DeviceDeploymentStatusDownloading, allowed: true},
+		{from: DeviceDeploymentStatusPending, to: DeviceDeploymentStatusInstalling, allowed: true},
+		{from: DeviceDeploymentStatusPending, to: DeviceDeploymentStatusAlreadyInst, allowed: true},
+		{from: DeviceDeploymentStatusPending, to: DeviceDeploymentStatusNoArtifact, allowed: true},
+		{
+			from:    DeviceDeploymentStatusDownloading,
+			to:      DeviceDeploymentStatusPauseBeforeInstall,
+			allowed: true,
+		},
+		{
+			from:    DeviceDeploymentStatusPauseBeforeInstall,
+			to:      DeviceDeploymentStatusInstalling,
+			allowed: true,
+		},
+		{
+			from:    DeviceDeploymentStatusInstalling,
+			to:      DeviceDeploymentStatusPauseBeforeCommit,
+			allowed: true,
+		},
+		{
+			from:    DeviceDeploymentStatusInstalling,
+			to:      DeviceDeploymentStatusRebooting,
+			allowed: true,
+		},
+		{from: DeviceDeploymentStatusRebooting, to: DeviceDeploymentStatusSuccess, allowed: true},
+		{from: DeviceDeploymentStatusRebooting, to: DeviceDeploymentStatusFailure, allowed: true},
+		{from: DeviceDeploymentStatusRebooting, to: DeviceDeploymentStatusRebooting, allowed: true},
+		{from: DeviceDeploymentStatusSuccess, to: DeviceDeploymentStatusSuccess, allowed: true},
+		{
+			from:    DeviceDeploymentStatusDownloading,
+			to:      DeviceDeploymentStatusDownloading,
+			allowed: true,
+		},
+		// rollback and retry of the installation
+		{
+			from:    DeviceDeploymentStatusRebooting,
+			to:      DeviceDeploymentStatusInstalling,
+			allowed: true,
+		},
+		{
+			from:    DeviceDeploymentStatusPauseBeforeReboot,
+			to:      DeviceDeploymentStatusInstalling,
+			allowed: true,
+		},
+		{
+			from:    DeviceDeploymentStatusPauseBeforeCommit,
+			to:      DeviceDeploymentStatusRebooting,
+			allowed: true,
+		},
+		{
+			from:    DeviceDeploymentStatusPauseBeforeCommit,
+			to:      DeviceDeploymentStatusInstalling,
+			allowed: true,
+		},
+
+		{from: DeviceDeploymentStatusRebooting, to: DeviceDeploymentStatusDownloading},
+		{from: DeviceDeploymentStatusInstalling, to: DeviceDeploymentStatusPending},
+		{from: DeviceDeploymentStatusRebooting, to: DeviceDeploymentStatusPauseBeforeInstall},
+		{
+			from: DeviceDeploymentStatusPauseBeforeCommit,
+			to:   DeviceDeploymentStatusPauseBeforeReboot,
+		},
+		{from: DeviceDeploymentStatusSuccess, to: DeviceDeploymentStatusInstalling},
+		{from: DeviceDeploymentStatusSuccess, to: DeviceDeploymentStatusFailure},
+		{from: DeviceDeploymentStatusFailure, to: DeviceDeploymentStatusSuccess},
+		{from: DeviceDeploymentStatusAborted, to: DeviceDeploymentStatusDownloading},
+		{from: DeviceDeploymentStatusDecommissioned, to: DeviceDeploymentStatusFailure},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.from.String()+"->"+tc.to.String(), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.allowed, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestTenantSettingsValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, TenantSettings{}.Validate())
+	assert.NoError(t, TenantSettings{StatusTransitions: StatusTransitionsModeWarn}.Validate())
+	assert.NoError(t, TenantSettings{StatusTransitions: StatusTransitionsModeEnforce}.Validate())
+	assert.Error(t, TenantSettings{StatusTransitions: "ignore"}.Validate())
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pkg/errors"
+)
+
+// MaxGatewayDevices limits the number of devices a gateway may handle in
+// a single request.
+const MaxGatewayDevices = 100
+
+var (
+	errGatewayDuplicateDevice = errors.New("duplicate device in request")
+
+	ruleGatewayDevices = validation.Length(1, MaxGatewayDevices)
+)
+
+// GatewayDeviceProvides is the artifact currently installed on a device
+// behind a gateway.
+type GatewayDeviceProvides struct {
+	DeviceID string `json:"device_id"`
+	InstalledDeviceDeployment
+}
+
+func (p GatewayDeviceProvides) Validate() error {
+	err := validation.ValidateStruct(&p,
+		validation.Field(&p.DeviceID, validation.Required),
+	)
+	if err != nil {
+		return err
+	}
+	return p.InstalledDeviceDeployment.Validate()
+}
+
+// GatewayDeploymentsNextRequest lists the devices a gateway checks for
+// deployments on behalf of.
+type GatewayDeploymentsNextRequest struct {
+	Devices []GatewayDeviceProvides `json:"devices"`
+}
+
+func (r GatewayDeploymentsNextRequest) Validate() error {
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.Devices, validation.Required, ruleGatewayDevices),
+	)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(r.Devices))
+	for _, dev := range r.Devices {
+		if _, ok := seen[dev.DeviceID]; ok {
+			return errors.WithMessage(errGatewayDuplicateDevice, dev.DeviceID)
+		}
+		seen[dev.DeviceID] = struct{}{}
+	}
+	return nil
+}
+
+// DeviceIDs returns the IDs of the devices in the request.
+func (r GatewayDeploymentsNextRequest) DeviceIDs() []string {
+	ids := make([]string, len(r.Devices))
+	for i, dev := range r.Devices {
+		ids[i] = dev.DeviceID
+	}
+	return ids
+}
+
+// GatewayDeploymentNext is the outcome of the deployment check for a single
+// device behind a gateway. Status follows the response codes of the
+// device API: 200 with the deployment, 204 if there is nothing to install,
+// otherwise an error.
+type GatewayDeploymentNext struct {
+	DeviceID   string                  `json:"device_id"`
+	Status     int                     `json:"status"`
+	Deployment *DeploymentInstructions `json:"deployment,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+}
+
+// GatewayStatusReport is a status report for a deployment on a single
+// device behind a gateway.
+type GatewayStatusReport struct {
+	DeviceID     string                 `json:"device_id"`
+	DeploymentID string                 `json:"deployment_id"`
+	Status       DeviceDeploymentStatus `json:"status"`
+	SubState     string                 `json:"substate"`
+}
+
+func (s GatewayStatusReport) Validate() error {
+	err := validation.ValidateStruct(&s,
+		validation.Field(&s.DeviceID, validation.Required),
+		validation.Field(&s.DeploymentID, validation.Required, is.UUID),
+		validation.Field(&s.Status, validation.Required),
+	)
+	if err != nil {
+		return err
+	}
+	return StatusReport{
+		Status:   s.Status,
+		SubState: s.SubState,
+	}.Validate()
+}
+
+// GatewayStatusReportsRequest holds the status reports a gateway sends on
+// behalf of its devices.
+type GatewayStatusReportsRequest struct {
+	Statuses []GatewayStatusReport `json:"statuses"`
+}
+
+func (r GatewayStatusReportsRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Statuses, validation.Required, ruleGatewayDevices),
+	)
+}
+
+// DeviceIDs returns the distinct IDs of the devices in the request.
+func (r GatewayStatusReportsRequest) DeviceIDs() []string {
+	ids := make([]string, 0, len(r.Statuses))
+	seen := make(map[string]struct{}, len(r.Statuses))
+	for _, report := range r.Statuses {
+		if _, ok := seen[report.DeviceID]; !ok {
+			seen[report.DeviceID] = struct{}{}
+			ids = append(ids, report.DeviceID)
+		}
+	}
+	return ids
+}
+
+// GatewayStatusReportResult is the outcome of a single status report sent
+// by a gateway. Status follows the response codes of the device API.
+type GatewayStatusReportResult struct {
+	DeviceID     string `json:"device_id"`
+	DeploymentID string `json:"deployment_id"`
+	Status       int    `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGatewayDeploymentsNextRequest(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body string
+
+		deviceIDs []string
+		err       string
+	}{
+		"ok": {
+			body: `{"devices": [{
+				"device_id": "child-1",
+				"artifact_name": "release-1",
+				"device_type": "sensor",
+				"artifact_provides": {"rootfs-image.version": "1"}
+			}, {
+				"device_id": "child-2",
+				"artifact_name": "release-1",
+				"device_type": "sensor"
+			}]}`,
+			deviceIDs: []string{"child-1", "child-2"},
+		},
+		"error, no devices": {
+			body: `{"devices": []}`,
+			err:  "devices: cannot be blank.",
+		},
+		"error, missing device ID": {
+			body: `{"devices": [{"artifact_name": "release-1", "device_type": "sensor"}]}`,
+			err:  "devices: (0: (device_id: cannot be blank.).).",
+		},
+		"error, missing device type": {
+			body: `{"devices": [{"device_id": "child-1", "artifact_name": "release-1"}]}`,
+			err:  "devices: (0: (device_type: cannot be blank.).).",
+		},
+		"error, duplicate device": {
+			body: `{"devices": [{
+				"device_id": "child-1",
+				"artifact_name": "release-1",
+				"device_type": "sensor"
+			}, {
+				"device_id": "child-1",
+				"artifact_name": "release-1",
+				"device_type": "sensor"
+			}]}`,
+			err: "child-1: duplicate device in request",
+		},
+		"error, too many devices": {
+			body: `{"devices": [` + strings.Repeat(
+				`{"device_id": "child", "artifact_name": "release-1", "device_type": "sensor"},`,
+				MaxGatewayDevices,
+			) + `{"device_id": "child", "artifact_name": "release-1", "device_type": "sensor"}]}`,
+			err: "devices: the length must be between 1 and 100.",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var req GatewayDeploymentsNextRequest
+			err := json.Unmarshal([]byte(tc.body), &req)
+			assert.NoError(t, err)
+
+			err = req.Validate()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.deviceIDs, req.DeviceIDs())
+			}
+		})
+	}
+}
+
+func TestGatewayStatusReportsRequest(t *testing.T) {
+	t.Parallel()
+
+	const deploymentID = "2b4f9c2e-8a0e-4a31-9b7a-0d4c8f1e6a52"
+
+	testCases := map[string]struct {
+		body string
+
+		deviceIDs []string
+		err       string
+	}{
+		"ok": {
+			body: `{"statuses": [{
+				"device_id": "child-1",
+				"deployment_id": "` + deploymentID + `",
+				"status": "installing"
+			}, {
+				"device_id": "child-2",
+				"deployment_id": "` + deploymentID + `",
+				"status": "failure",
+				"substate": "checksum mismatch"
+			}, {
+				"device_id": "child-1",
+				"deployment_id": "` + deploymentID + `",
+				"status": "success"
+			}]}`,
+			deviceIDs: []string{"child-1", "child-2"},
+		},
+		"error, no statuses": {
+			body: `{"statuses": []}`,
+			err:  "statuses: cannot be blank.",
+		},
+		"error, invalid deployment ID": {
+			body: `{"statuses": [{
+				"device_id": "child-1",
+				"deployment_id": "foo",
+				"status": "installing"
+			}]}`,
+			err: "statuses: (0: (deployment_id: must be a valid UUID.).).",
+		},
+		"error, missing status": {
+			body: `{"statuses": [{
+				"device_id": "child-1",
+				"deployment_id": "` + deploymentID + `"
+			}]}`,
+			err: "statuses: (0: (status: cannot be blank.).).",
+		},
+		"error, status not reported by devices": {
+			body: `{"statuses": [{
+				"device_id": "child-1",
+				"deployment_id": "` + deploymentID + `",
+				"status": "aborted"
+			}]}`,
+			err: "statuses: (0: (status: must be a valid value.).).",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var req GatewayStatusReportsRequest
+			err := json.Unmarshal([]byte(tc.body), &req)
+			assert.NoError(t, err)
+
+			err = req.Validate()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.deviceIDs, req.DeviceIDs())
+			}
+		})
+	}
+}
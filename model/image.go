@@ -188,8 +188,33 @@ type Image struct {
 	// Artifact total size
 	Size int64 `json:"size" bson:"size" valid:"-"`
 
+	// Hex-encoded SHA-256 checksum of the whole artifact file; empty for
+	// artifacts uploaded before checksums were recorded
+	Checksum string `json:"checksum,omitempty" bson:"checksum,omitempty" valid:"-"`
+
+	// ObjectID is the ID of the ArtifactObject storing the artifact file;
+	// empty for artifacts stored before the deduplication of artifacts
+	ObjectID string `json:"-" bson:"object_id,omitempty" valid:"-"`
+
+	// ObjectPath is the path of the artifact file in the storage, if it
+	// is not the path of the artifact, see ObjectPathFromContext
+	ObjectPath string `json:"-" bson:"object_path,omitempty" valid:"-"`
+
+	// Result of the last verification of the stored artifact file
+	Integrity *ImageIntegrity `json:"integrity,omitempty" bson:"integrity,omitempty" valid:"-"` //nolint:lll
+
+	// ID of the trusted public key which verified the artifact signature
+	SigningKeyID string `json:"signing_key_id,omitempty" bson:"signing_key_id,omitempty" valid:"-"` //nolint:lll
+
+	// History of the edits of the provides, depends and clears provides
+	//nolint:lll
+	ArtifactMetaHistory []ArtifactMetaChange `json:"artifact_meta_history,omitempty" bson:"meta_artifact_history,omitempty" valid:"-"`
+
 	// Last modification time, including image upload time
 	Modified *time.Time `json:"modified" valid:"-"`
+
+	// Upload time; unset for artifacts uploaded before it was recorded
+	Uploaded *time.Time `json:"-" bson:"uploaded,omitempty" valid:"-"`
 }
 
 func (img Image) MarshalBSON() (b []byte, err error) {
@@ -222,11 +247,21 @@ func NewImage(
 		ImageMeta:    metaConstructor,
 		ArtifactMeta: metaArtifactConstructor,
 		Modified:     &now,
+		Uploaded:     &now,
 		Id:           id,
 		Size:         artifactSize,
 	}
 }
 
+// ObjectPathFromContext returns the path of the artifact file in the
+// storage; artifacts with the same content share the file.
+func (s *Image) ObjectPathFromContext(ctx context.Context) string {
+	if s.ObjectPath != "" {
+		return s.ObjectPath
+	}
+	return ImagePathFromContext(ctx, s.Id)
+}
+
 // SetModified set last modification time for the image.
 func (s *Image) SetModified(time time.Time) {
 	s.Modified = &time
@@ -247,6 +282,11 @@ type MultipartUploadMsg struct {
 	ArtifactID string
 	// reader pointing to the beginning of the artifact data
 	ArtifactReader io.Reader
+	// Checksum is the expected hex-encoded SHA-256 checksum of the
+	// artifact; the upload fails when it does not match
+	Checksum string
+	// Size is the size of the artifact given with the request, if any
+	Size int64
 }
 
 // MultipartGenerateImageMsg is a structure with fields extracted from the multipart/form-data
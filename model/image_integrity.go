@@ -0,0 +1,65 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+type IntegrityStatus string
+
+const (
+	// IntegrityStatusOK marks artifacts whose stored object matches the
+	// size and checksum recorded on upload.
+	IntegrityStatusOK IntegrityStatus = "ok"
+	// IntegrityStatusMissing marks artifacts without a stored object.
+	IntegrityStatusMissing IntegrityStatus = "missing"
+	// IntegrityStatusCorrupt marks artifacts whose stored object does not
+	// match the size or checksum recorded on upload.
+	IntegrityStatusCorrupt IntegrityStatus = "corrupt"
+)
+
+// ImageIntegrity is the result of the last verification of the stored
+// object of an artifact.
+type ImageIntegrity struct {
+	Status IntegrityStatus `json:"status" bson:"status"`
+
+	// Checksum and Size of the stored object; empty when it is missing
+	Checksum string `json:"checksum,omitempty" bson:"checksum,omitempty"`
+	Size     int64  `json:"size,omitempty" bson:"size,omitempty"`
+
+	// Checked is the time of the verification
+	Checked time.Time `json:"checked" bson:"checked"`
+}
+
+// IsValid returns false if the artifact failed its last verification
+func (i *ImageIntegrity) IsValid() bool {
+	return i == nil || i.Status == IntegrityStatusOK
+}
+
+// ArtifactVerification reports the verification of an artifact.
+type ArtifactVerification struct {
+	TenantID string `json:"tenant_id,omitempty"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+
+	ImageIntegrity
+
+	// Backfilled is set when the artifact had no checksum and the
+	// checksum of the stored object was recorded.
+	Backfilled bool `json:"backfilled,omitempty"`
+	// Error is set when the artifact could not be verified
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageIntegrityIsValid(t *testing.T) {
+	t.Parallel()
+
+	var integrity *ImageIntegrity
+	assert.True(t, integrity.IsValid())
+	assert.True(t, (&ImageIntegrity{Status: IntegrityStatusOK}).IsValid())
+	assert.False(t, (&ImageIntegrity{Status: IntegrityStatusMissing}).IsValid())
+	assert.False(t, (&ImageIntegrity{Status: IntegrityStatusCorrupt}).IsValid())
+}
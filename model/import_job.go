@@ -0,0 +1,84 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+var ErrImportURLScheme = errors.New("must be an http or https URL")
+
+// ImportJobStatus is the status of an artifact import job
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending    ImportJobStatus = "pending"
+	ImportJobStatusProcessing ImportJobStatus = "processing"
+	ImportJobStatusCompleted  ImportJobStatus = "completed"
+	ImportJobStatusFailed     ImportJobStatus = "failed"
+)
+
+// ImportArtifactRequest requests the import of an artifact from a remote URL
+type ImportArtifactRequest struct {
+	// URL to fetch the artifact from
+	URL string `json:"url"`
+	// Headers added to the request fetching the artifact, e.g. for
+	// authorization; they are not stored.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Checksum is the expected hex-encoded SHA-256 checksum of the artifact
+	Checksum string `json:"checksum,omitempty"`
+	// Description of the imported artifact
+	Description string `json:"description,omitempty"`
+}
+
+// Validate checks structure according to valid tags
+func (r ImportArtifactRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.URL, validation.Required, is.URL,
+			validation.By(validateImportURLScheme)),
+		validation.Field(&r.Checksum, is.Hexadecimal, validation.Length(64, 64)),
+	)
+}
+
+func validateImportURLScheme(value interface{}) error {
+	s, _ := value.(string)
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrImportURLScheme
+	}
+	return nil
+}
+
+// ImportJob tracks the background import of an artifact from a remote URL
+type ImportJob struct {
+	ID       string          `json:"id" bson:"_id"`
+	URL      string          `json:"url" bson:"url"`
+	Checksum string          `json:"checksum,omitempty" bson:"checksum,omitempty"`
+	Status   ImportJobStatus `json:"status" bson:"status"`
+	// ArtifactID is the ID of the imported artifact, once completed
+	ArtifactID string `json:"artifact_id,omitempty" bson:"artifact_id,omitempty"`
+	// Error describes why the import failed
+	Error   string    `json:"error,omitempty" bson:"error,omitempty"`
+	Created time.Time `json:"created" bson:"created"`
+	Updated time.Time `json:"updated" bson:"updated"`
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportArtifactRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		req ImportArtifactRequest
+		err string
+	}{
+		"ok": {
+			req: ImportArtifactRequest{
+				URL:      "https://artifacts.example.com/release-1.mender",
+				Headers:  map[string]string{"Authorization": "Bearer token"},
+				Checksum: strings.Repeat("a", 64),
+			},
+		},
+		"ok, http": {
+			req: ImportArtifactRequest{
+				URL: "http://artifacts.internal:8080/release-1.mender",
+			},
+		},
+		"error, missing url": {
+			err: "url: cannot be blank.",
+		},
+		"error, scheme": {
+			req: ImportArtifactRequest{
+				URL: "ftp://artifacts.example.com/release-1.mender",
+			},
+			err: "url: must be an http or https URL.",
+		},
+		"error, checksum": {
+			req: ImportArtifactRequest{
+				URL:      "https://artifacts.example.com/release-1.mender",
+				Checksum: "abc",
+			},
+			err: "checksum: the length must be exactly 64.",
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.req.Validate()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -25,6 +25,9 @@ var (
 type Limit struct {
 	Name  string `bson:"_id"`
 	Value uint64 `bson:"value" json:"value"`
+	// Usage is the current usage of the limited resource; it is tracked
+	// apart from the limit.
+	Usage uint64 `bson:"-" json:"usage"`
 }
 
 func (l Limit) IsLess(what uint64) bool {
@@ -36,6 +36,29 @@ type UploadLink struct {
 	IssuedAt  time.Time  `json:"-" bson:"issued_ts"`
 	UpdatedTS time.Time  `json:"-" bson:"updated_ts"`
 	Status    LinkStatus `json:"-" bson:"status"`
+
+	// Chunked is the state of the upload for chunked uploads
+	Chunked *ChunkedUpload `json:"-" bson:"chunked,omitempty"`
+}
+
+// ChunkedUpload is the state of an artifact uploaded in chunks through
+// the service; Offset is the size of the chunks received so far.
+type ChunkedUpload struct {
+	UploadID string `bson:"upload_id"`
+	Length   int64  `bson:"length"`
+	Offset   int64  `bson:"offset"`
+	Chunks   int    `bson:"chunks"`
+
+	// Lock is held while the chunk at Offset is written, so that
+	// concurrent chunks at the same offset cannot overwrite each other
+	Lock *ChunkLock `bson:"lock,omitempty"`
+}
+
+// ChunkLock is the lock of a chunked upload held by the request writing
+// the next chunk; it is released once the chunk is stored, or expires.
+type ChunkLock struct {
+	ID     string    `bson:"id"`
+	Expire time.Time `bson:"expire"`
 }
 
 type LinkStatus uint32
@@ -0,0 +1,154 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/mendersoftware/mender-artifact/artifact"
+	"github.com/pkg/errors"
+)
+
+// PublicKeyAlgorithm is the algorithm of a key verifying artifact signatures
+type PublicKeyAlgorithm string
+
+const (
+	PublicKeyAlgorithmRSA   PublicKeyAlgorithm = "rsa"
+	PublicKeyAlgorithmECDSA PublicKeyAlgorithm = "ecdsa"
+)
+
+var (
+	ErrPublicKeyInvalidPEM = errors.New("key is not a PEM encoded public key")
+	ErrPublicKeyType       = errors.New(
+		"unsupported key type: supported types are RSA and ECDSA (P-256)",
+	)
+	ErrSignatureVerification = errors.New("signature verification failed")
+)
+
+// PublicKeyConstructor is the payload for adding a trusted public key
+type PublicKeyConstructor struct {
+	// Human readable name of the key
+	Name string `json:"name"`
+	// PEM encoded PKIX public key
+	Key string `json:"key"`
+}
+
+// Validate checks structure according to valid tags
+func (c PublicKeyConstructor) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, lengthIn1To4096),
+		validation.Field(&c.Key, validation.Required),
+	)
+}
+
+// PublicKey is a key trusted to sign the artifacts of a tenant
+type PublicKey struct {
+	ID   string `json:"id" bson:"_id"`
+	Name string `json:"name" bson:"name"`
+	// PEM encoded PKIX public key
+	Key       string             `json:"key" bson:"key"`
+	Algorithm PublicKeyAlgorithm `json:"algorithm" bson:"algorithm"`
+	// Hex-encoded SHA-256 digest of the DER encoded key
+	Fingerprint string    `json:"fingerprint" bson:"fingerprint"`
+	Created     time.Time `json:"created" bson:"created"`
+}
+
+// NewPublicKey parses the key from the constructor and returns the trusted
+// public key.
+func NewPublicKey(constructor *PublicKeyConstructor) (*PublicKey, error) {
+	der, pub, err := parsePublicKey(constructor.Key)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := publicKeyAlgorithm(pub)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(der)
+
+	return &PublicKey{
+		ID:          uuid.New().String(),
+		Name:        constructor.Name,
+		Key:         constructor.Key,
+		Algorithm:   algorithm,
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+		Created:     time.Now().UTC(),
+	}, nil
+}
+
+func parsePublicKey(key string) ([]byte, interface{}, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, nil, ErrPublicKeyInvalidPEM
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, nil, ErrPublicKeyInvalidPEM
+	}
+	return block.Bytes, pub, nil
+}
+
+func publicKeyAlgorithm(pub interface{}) (PublicKeyAlgorithm, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return PublicKeyAlgorithmRSA, nil
+	case *ecdsa.PublicKey:
+		// mender-artifact only signs with ECDSA P-256
+		if pub.Curve != elliptic.P256() {
+			return "", ErrPublicKeyType
+		}
+		return PublicKeyAlgorithmECDSA, nil
+	default:
+		return "", ErrPublicKeyType
+	}
+}
+
+// Verify verifies the base64 encoded artifact signature of the message
+// (the artifact manifest) with the key.
+func (k *PublicKey) Verify(message, sig []byte) error {
+	_, pub, err := parsePublicKey(k.Key)
+	if err != nil {
+		return err
+	}
+	dec := make([]byte, base64.StdEncoding.DecodedLen(len(sig)))
+	n, err := base64.StdEncoding.Decode(dec, sig)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode signature")
+	}
+	dec = dec[:n]
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		err = new(artifact.RSA).Verify(message, dec, pub)
+	case *ecdsa.PublicKey:
+		err = new(artifact.ECDSA256).Verify(message, dec, pub)
+	default:
+		return ErrPublicKeyType
+	}
+	if err != nil {
+		return errors.Wrap(ErrSignatureVerification, err.Error())
+	}
+	return nil
+}
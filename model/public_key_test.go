@@ -0,0 +1,137 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/mendersoftware/mender-artifact/artifact"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePublicKey(t *testing.T, pub crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestPublicKey(t *testing.T) {
+	t.Parallel()
+
+	message := []byte("manifest")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ecdsaP384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	ed25519Pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	digest := sha256.Sum256(message)
+	rsaSig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+	ecdsaSig, err := new(artifact.ECDSA256).Sign(message, ecdsaKey)
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		key string
+		sig []byte
+
+		algorithm PublicKeyAlgorithm
+		err       error
+		verifyErr bool
+	}{
+		"ok, rsa": {
+			key:       encodePublicKey(t, rsaKey.Public()),
+			sig:       rsaSig,
+			algorithm: PublicKeyAlgorithmRSA,
+		},
+		"ok, ecdsa": {
+			key:       encodePublicKey(t, ecdsaKey.Public()),
+			sig:       ecdsaSig,
+			algorithm: PublicKeyAlgorithmECDSA,
+		},
+		"ok, signature of another key": {
+			key:       encodePublicKey(t, ecdsaKey.Public()),
+			sig:       rsaSig,
+			algorithm: PublicKeyAlgorithmECDSA,
+			verifyErr: true,
+		},
+		"error, ed25519": {
+			// mender-artifact does not sign with Ed25519
+			key: encodePublicKey(t, ed25519Pub),
+			err: ErrPublicKeyType,
+		},
+		"error, unsupported curve": {
+			key: encodePublicKey(t, ecdsaP384Key.Public()),
+			err: ErrPublicKeyType,
+		},
+		"error, not a PEM": {
+			key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIG",
+			err: ErrPublicKeyInvalidPEM,
+		},
+		"error, not a public key": {
+			key: string(pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+			})),
+			err: ErrPublicKeyInvalidPEM,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := NewPublicKey(&PublicKeyConstructor{
+				Name: "key",
+				Key:  tc.key,
+			})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.algorithm, key.Algorithm)
+			assert.Len(t, key.Fingerprint, 64)
+
+			sig := []byte(base64.StdEncoding.EncodeToString(tc.sig))
+			err = key.Verify(message, sig)
+			if tc.verifyErr {
+				assert.ErrorIs(t, err, ErrSignatureVerification)
+			} else {
+				assert.NoError(t, err)
+				assert.ErrorIs(t,
+					key.Verify([]byte("tampered"), sig),
+					ErrSignatureVerification)
+			}
+		})
+	}
+}
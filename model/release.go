@@ -18,15 +18,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 )
 
 const (
 	TagsMaxPerRelease = 20  // Maximum number of tags per release.
-	TagsMaxUnique     = 100 // Maximum number of unique tags.
+	TagsMaxUnique     = 100 // Maximum number of unique tag keys.
+
+	// TagSeparator separates the key from the value of key/value tags.
+	TagSeparator = "="
 )
 
 var (
@@ -39,6 +46,7 @@ var (
 			strconv.Itoa(TagsMaxUnique) +
 			") has been exceeded",
 	)
+	ErrTagKeyDuplicate = errors.New("tag keys must be unique within a release")
 )
 
 type Tags []Tag
@@ -47,14 +55,28 @@ func (tags Tags) Validate() (err error) {
 	if len(tags) > TagsMaxPerRelease {
 		return ErrTooManyTags
 	}
+	keys := make(map[string]struct{}, len(tags))
 	for _, tag := range tags {
 		if err = tag.Validate(); err != nil {
 			return err
 		}
+		if _, exists := keys[tag.Key()]; exists {
+			return ErrTagKeyDuplicate
+		}
+		keys[tag.Key()] = struct{}{}
 	}
 	return nil
 }
 
+// Keys returns the keys of the tags.
+func (tags Tags) Keys() []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tag.Key()
+	}
+	return keys
+}
+
 func (tags Tags) MarshalJSON() ([]byte, error) {
 	if len(tags) == 0 {
 		return []byte{'[', ']'}, nil
@@ -84,6 +106,8 @@ func (tags *Tags) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// Tag is either a flat tag, made of a key only, or a key/value tag such
+// as `product=gateway`. Keys are case insensitive, values are not.
 type Tag string
 
 const (
@@ -95,8 +119,31 @@ var (
 	ErrTagTooLong = errors.New("tag must be less than " +
 		strconv.Itoa(TagMaxLength) +
 		" characters")
+	ErrTagValueEmpty = errors.New("tag value cannot be empty")
 )
 
+// NewTag returns the tag with the given key and value; tags without
+// a value are flat tags.
+func NewTag(key, value string) Tag {
+	key = strings.ToLower(key)
+	if value == "" {
+		return Tag(key)
+	}
+	return Tag(key + TagSeparator + value)
+}
+
+// Key returns the key of the tag, the whole tag for flat tags.
+func (tag Tag) Key() string {
+	key, _, _ := strings.Cut(string(tag), TagSeparator)
+	return key
+}
+
+// Value returns the value of the tag, empty for flat tags.
+func (tag Tag) Value() string {
+	_, value, _ := strings.Cut(string(tag), TagSeparator)
+	return value
+}
+
 type InvalidCharacterError struct {
 	Source string
 	Char   rune
@@ -106,22 +153,40 @@ func (err *InvalidCharacterError) Error() string {
 	return fmt.Sprintf(`invalid character '%c' in string "%s"`, err.Char, err.Source)
 }
 
+func isTagKeyChar(c rune) bool { // [A-Za-z0-9-_.]
+	return (c >= 'A' && c <= 'Z') ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= '0' && c <= '9') ||
+		c == '-' || c == '_' || c == '.'
+}
+
+func isTagValueChar(c rune) bool { // [A-Za-z0-9-_.:/+@]
+	return isTagKeyChar(c) ||
+		c == ':' || c == '/' || c == '+' || c == '@'
+}
+
 func (tag Tag) Validate() error {
 	if len(tag) < 1 {
 		return ErrTagEmpty
 	} else if len(tag) > TagMaxLength {
 		return ErrTagTooLong
 	}
-	for _, c := range tag { // [A-Za-z0-9-_.]
-		if c >= 'A' && c <= 'Z' {
-			continue
-		} else if c >= 'a' && c <= 'z' {
-			continue
-		} else if c >= '0' && c <= '9' {
-			continue
-		} else if c == '-' || c == '_' || c == '.' {
-			continue
-		} else {
+	key, value, hasValue := strings.Cut(string(tag), TagSeparator)
+	if key == "" {
+		return ErrTagEmpty
+	} else if hasValue && value == "" {
+		return ErrTagValueEmpty
+	}
+	for _, c := range key {
+		if !isTagKeyChar(c) {
+			return &InvalidCharacterError{
+				Source: string(tag),
+				Char:   c,
+			}
+		}
+	}
+	for _, c := range value {
+		if !isTagValueChar(c) {
 			return &InvalidCharacterError{
 				Source: string(tag),
 				Char:   c,
@@ -131,21 +196,127 @@ func (tag Tag) Validate() error {
 	return nil
 }
 
+// tagDocument is the representation of tags in the database, and the
+// object form of tags in JSON.
+type tagDocument struct {
+	Key   string `json:"key" bson:"key"`
+	Value string `json:"value,omitempty" bson:"value,omitempty"`
+}
+
 func (tag *Tag) UnmarshalJSON(b []byte) error {
-	// Convert tag to lower case
+	// Convert tag key to lower case
 	var s string
 	err := json.Unmarshal(b, &s)
-	if err != nil {
+	if err == nil {
+		key, value, hasValue := strings.Cut(s, TagSeparator)
+		*tag = Tag(strings.ToLower(key))
+		if hasValue {
+			*tag += Tag(TagSeparator + value)
+		}
+		return nil
+	}
+	var doc tagDocument
+	if json.Unmarshal(b, &doc) != nil {
 		return err
 	}
-	*tag = Tag(strings.ToLower(s))
+	*tag = NewTag(doc.Key, doc.Value)
 	return nil
 }
 
+// MarshalBSONValue stores the tag as a document with the key and the
+// value, for filtering tags by key.
+func (tag Tag) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(tagDocument{
+		Key:   tag.Key(),
+		Value: tag.Value(),
+	})
+}
+
+// UnmarshalBSONValue reads tags stored as documents, and flat tags stored
+// as strings.
+func (tag *Tag) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.String:
+		*tag = Tag(raw.StringValue())
+	case bsontype.EmbeddedDocument:
+		var doc tagDocument
+		if err := raw.Unmarshal(&doc); err != nil {
+			return err
+		}
+		*tag = NewTag(doc.Key, doc.Value)
+	default:
+		return fmt.Errorf("cannot decode %s into a tag", t)
+	}
+	return nil
+}
+
+// TagsPatch adds and removes tags of a release; added tags replace the
+// tags with the same key.
+type TagsPatch struct {
+	Add Tags `json:"add,omitempty"`
+	// Remove removes the tags with the same keys; the tags are only
+	// removed if their value matches, when a value is given.
+	Remove Tags `json:"remove,omitempty"`
+}
+
+var (
+	ErrTagsPatchEmpty    = errors.New("at least one tag to add or remove is required")
+	ErrTagsPatchConflict = errors.New("tag keys cannot be both added and removed")
+)
+
+func (p TagsPatch) Validate() error {
+	if len(p.Add) == 0 && len(p.Remove) == 0 {
+		return ErrTagsPatchEmpty
+	}
+	if err := p.Add.Validate(); err != nil {
+		return err
+	}
+	added := make(map[string]struct{}, len(p.Add))
+	for _, key := range p.Add.Keys() {
+		added[key] = struct{}{}
+	}
+	for _, tag := range p.Remove {
+		if err := tag.Validate(); err != nil {
+			return err
+		} else if _, exists := added[tag.Key()]; exists {
+			return ErrTagsPatchConflict
+		}
+	}
+	return nil
+}
+
+// Apply returns the tags patched.
+func (p TagsPatch) Apply(tags Tags) Tags {
+	patched := make(Tags, 0, len(tags)+len(p.Add))
+	replaced := make(map[string]bool, len(p.Add))
+	for _, key := range p.Add.Keys() {
+		replaced[key] = true
+	}
+	for _, tag := range tags {
+		if replaced[tag.Key()] {
+			continue
+		}
+		removed := false
+		for _, remove := range p.Remove {
+			if remove.Key() == tag.Key() &&
+				(remove.Value() == "" || remove.Value() == tag.Value()) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			patched = append(patched, tag)
+		}
+	}
+	return append(patched, p.Add...)
+}
+
+// Notes are the release notes, in Markdown.
 type Notes string
 
 var (
-	NotesLengthMaximumCharacters = 1024
+	NotesLengthMaximumCharacters = 64 * 1024
 
 	ErrReleaseNotesTooLong  = errors.New("release notes too long")
 	ErrCharactersNotAllowed = errors.New("release notes contain characters which are not allowed")
@@ -164,12 +335,18 @@ func IsNotGraphic(r rune) bool {
 	return !unicode.IsGraphic(r)
 }
 
+// isNotNotesChar tells whether the rune cannot appear in the notes; the
+// notes are Markdown, so line breaks and tabs are allowed.
+func isNotNotesChar(r rune) bool {
+	return r != '\n' && r != '\r' && r != '\t' && IsNotGraphic(r)
+}
+
 func (n Notes) Validate() error {
 	length := len(n)
 	if length > NotesLengthMaximumCharacters {
 		return ErrReleaseNotesTooLong
 	}
-	if i := strings.IndexFunc(string(n), IsNotGraphic); i > 0 {
+	if i := strings.IndexFunc(string(n), isNotNotesChar); i > 0 {
 		return &InvalidCharError{
 			Char:   n[i],
 			Offset: i,
@@ -179,22 +356,84 @@ func (n Notes) Validate() error {
 	return nil
 }
 
+// ReleaseStatus is the lifecycle status of a release.
+type ReleaseStatus string
+
+const (
+	// ReleaseStatusDraft releases cannot be deployed yet
+	ReleaseStatusDraft ReleaseStatus = "draft"
+	// ReleaseStatusApproved releases can be deployed
+	ReleaseStatusApproved ReleaseStatus = "approved"
+	// ReleaseStatusDeprecated releases can still be deployed, but should
+	// not be anymore
+	ReleaseStatusDeprecated ReleaseStatus = "deprecated"
+	// ReleaseStatusRevoked releases cannot be deployed, and are not served
+	// to the devices anymore, even by ongoing deployments
+	ReleaseStatusRevoked ReleaseStatus = "revoked"
+)
+
+var ErrReleaseStatusInvalid = errors.New("invalid release status")
+
+// releaseStatusTransitions lists the statuses each status can change to.
+var releaseStatusTransitions = map[ReleaseStatus][]ReleaseStatus{
+	ReleaseStatusDraft: {ReleaseStatusApproved, ReleaseStatusRevoked},
+	ReleaseStatusApproved: {
+		ReleaseStatusDraft, ReleaseStatusDeprecated, ReleaseStatusRevoked,
+	},
+	ReleaseStatusDeprecated: {ReleaseStatusApproved, ReleaseStatusRevoked},
+	ReleaseStatusRevoked:    {},
+}
+
+func (s ReleaseStatus) Validate() error {
+	if _, ok := releaseStatusTransitions[s]; !ok {
+		return ErrReleaseStatusInvalid
+	}
+	return nil
+}
+
+// StatusesTransitioningTo returns the statuses which can change to the
+// given status, including the status itself.
+func (s ReleaseStatus) StatusesTransitioningTo() []ReleaseStatus {
+	statuses := []ReleaseStatus{s}
+	for from, to := range releaseStatusTransitions {
+		for _, status := range to {
+			if status == s {
+				statuses = append(statuses, from)
+			}
+		}
+	}
+	return statuses
+}
+
+// IsDeployable tells whether deployments of the release can be created;
+// releases without a status predate the statuses and are deployable.
+func (s ReleaseStatus) IsDeployable() bool {
+	return s != ReleaseStatusDraft && s != ReleaseStatusRevoked
+}
+
 type Release struct {
-	Name           string     `json:"name" bson:"_id"`
-	Modified       *time.Time `json:"modified,omitempty" bson:"modified,omitempty"`
-	Artifacts      []Image    `json:"artifacts" bson:"artifacts"`
-	ArtifactsCount int        `json:"artifacts_count" bson:"artifacts_count"`
-	Tags           Tags       `json:"tags" bson:"tags,omitempty"`
-	Notes          Notes      `json:"notes" bson:"notes,omitempty"`
+	Name           string        `json:"name" bson:"_id"`
+	Modified       *time.Time    `json:"modified,omitempty" bson:"modified,omitempty"`
+	Artifacts      []Image       `json:"artifacts" bson:"artifacts"`
+	ArtifactsCount int           `json:"artifacts_count" bson:"artifacts_count"`
+	Tags           Tags          `json:"tags" bson:"tags,omitempty"`
+	Notes          Notes         `json:"notes" bson:"notes,omitempty"`
+	Status         ReleaseStatus `json:"status,omitempty" bson:"status,omitempty"`
+	// Alias is an alternative name of the release, such as a marketing
+	// name, usable wherever the release name is; the release name is the
+	// artifact name signed into the artifacts, and cannot change.
+	Alias string `json:"alias,omitempty" bson:"alias,omitempty"`
 }
 
 type ReleaseV1 struct {
-	Name           string     `json:"Name"`
-	Modified       *time.Time `json:"Modified,omitempty"`
-	Artifacts      []Image    `json:"Artifacts"`
-	ArtifactsCount int        `json:"ArtifactsCount"`
-	Tags           Tags       `json:"tags"`
-	Notes          Notes      `json:"notes"`
+	Name           string        `json:"Name"`
+	Modified       *time.Time    `json:"Modified,omitempty"`
+	Artifacts      []Image       `json:"Artifacts"`
+	ArtifactsCount int           `json:"ArtifactsCount"`
+	Tags           Tags          `json:"tags"`
+	Notes          Notes         `json:"notes"`
+	Status         ReleaseStatus `json:"status,omitempty"`
+	Alias          string        `json:"alias,omitempty"`
 }
 
 func ConvertReleasesToV1(releases []Release) []ReleaseV1 {
@@ -207,21 +446,149 @@ func ConvertReleasesToV1(releases []Release) []ReleaseV1 {
 
 type ReleasePatch struct {
 	Notes Notes `json:"notes" bson:"notes,omitempty"`
+	// Status changes the status of the release; the notes are left as
+	// they are when only the status is given
+	Status ReleaseStatus `json:"status,omitempty" bson:"-"`
+	// Alias sets the alias of the release, or removes it when empty; the
+	// notes are left as they are when only the alias is given
+	Alias *string `json:"alias,omitempty" bson:"-"`
 }
 
 func (r ReleasePatch) Validate() error {
+	if r.Status != "" {
+		if err := r.Status.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Alias != nil && *r.Alias != "" {
+		if err := ValidateReleaseAlias(*r.Alias); err != nil {
+			return err
+		}
+	}
 	return r.Notes.Validate()
 }
 
+// UpdatesNotes tells whether the patch updates the notes of the release.
+func (r ReleasePatch) UpdatesNotes() bool {
+	return r.Notes != "" || (r.Status == "" && r.Alias == nil)
+}
+
+const ReleaseAliasMaxLength = 4096
+
+var ErrReleaseAliasInvalid = errors.New(
+	"release alias must be at most " +
+		strconv.Itoa(ReleaseAliasMaxLength) +
+		" printable characters, without leading or trailing spaces",
+)
+
+// ValidateReleaseAlias checks that the alias is a valid release name.
+func ValidateReleaseAlias(alias string) error {
+	if alias == "" ||
+		len(alias) > ReleaseAliasMaxLength ||
+		strings.TrimSpace(alias) != alias ||
+		strings.IndexFunc(alias, IsNotGraphic) >= 0 {
+		return ErrReleaseAliasInvalid
+	}
+	return nil
+}
+
+const (
+	// SortFieldRelevance sorts the results of a full-text search from the
+	// most to the least relevant.
+	SortFieldRelevance = "relevance"
+
+	// ProvidesFilterWildcard matches any sequence of characters in the
+	// value of a provides filter.
+	ProvidesFilterWildcard = "*"
+
+	SearchTextMaxLength = 256
+	ProvidesFiltersMax  = 10
+)
+
+var (
+	ErrProvidesFilterInvalid = errors.New(
+		"provides filter must be a key or a key=value pair",
+	)
+	ErrTooManyProvidesFilters = errors.New(
+		"the number of provides filters exceeds the maximum of " +
+			strconv.Itoa(ProvidesFiltersMax),
+	)
+	ErrSearchTextTooLong = errors.New(
+		"search text must be less than " +
+			strconv.Itoa(SearchTextMaxLength) +
+			" characters",
+	)
+	ErrSortRelevanceWithoutSearch = errors.New(
+		"sorting by relevance requires a search text",
+	)
+)
+
 type ReleaseOrImageFilter struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
 	DeviceType  string   `json:"device_type"`
 	Tags        []string `json:"tags"`
 	UpdateType  string   `json:"update_type"`
-	Page        int      `json:"page"`
-	PerPage     int      `json:"per_page"`
-	Sort        string   `json:"sort"`
+	Status      string   `json:"status"`
+	// Provides filters the artifacts by their provides, see
+	// ParseProvidesFilter
+	Provides []string `json:"provides"`
+	// SearchText searches the release notes and the artifact descriptions
+	SearchText string `json:"search"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	Sort       string `json:"sort"`
+}
+
+func (f ReleaseOrImageFilter) Validate() error {
+	if len(f.Provides) > ProvidesFiltersMax {
+		return ErrTooManyProvidesFilters
+	}
+	for _, provides := range f.Provides {
+		if _, err := ParseProvidesFilter(provides); err != nil {
+			return err
+		}
+	}
+	if len(f.SearchText) > SearchTextMaxLength {
+		return ErrSearchTextTooLong
+	}
+	if f.SearchText == "" && strings.HasPrefix(f.Sort, SortFieldRelevance+":") {
+		return ErrSortRelevanceWithoutSearch
+	}
+	return nil
+}
+
+// ProvidesFilter matches the artifacts providing the key, with a value
+// matching the value of the filter if it is not empty.
+type ProvidesFilter struct {
+	Key   string
+	Value string
+}
+
+// ParseProvidesFilter parses a provides filter given either as a key, such
+// as `rootfs-image.version`, or as a key=value pair, such as
+// `rootfs-image.version=3.*`, where the value may contain wildcards.
+func ParseProvidesFilter(filter string) (ProvidesFilter, error) {
+	key, value, hasValue := strings.Cut(filter, "=")
+	if key == "" || (hasValue && value == "") {
+		return ProvidesFilter{}, ErrProvidesFilterInvalid
+	}
+	return ProvidesFilter{Key: key, Value: value}, nil
+}
+
+// HasWildcard returns true if the value of the filter contains wildcards.
+func (f ProvidesFilter) HasWildcard() bool {
+	return strings.Contains(f.Value, ProvidesFilterWildcard)
+}
+
+// ValuePattern returns the regular expression matching the whole value of
+// the filter, where wildcards match any sequence of characters.
+func (f ProvidesFilter) ValuePattern() string {
+	parts := strings.Split(f.Value, ProvidesFilterWildcard)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return "^" + strings.Join(parts, ".*") + "$"
 }
 
 type DirectUploadMetadata struct {
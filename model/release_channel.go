@@ -0,0 +1,101 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ReleaseChannelNameMaxLength is the maximum length of channel names
+	ReleaseChannelNameMaxLength = 64
+	// ReleaseChannelHistoryMax is the number of promotions kept in the
+	// history of a channel
+	ReleaseChannelHistoryMax = 100
+)
+
+var (
+	ErrReleasePromotionSource = errors.New(
+		"exactly one of release_name and from_channel is required",
+	)
+	ErrReleasePromotionSameChannel = errors.New(
+		"cannot promote a channel to itself",
+	)
+
+	releaseChannelNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+)
+
+// ValidateReleaseChannelName checks that the channel name is not empty,
+// and only contains letters, digits, underscores, periods and hyphens.
+func ValidateReleaseChannelName(name string) error {
+	return validation.Validate(name,
+		validation.Required,
+		validation.Length(1, ReleaseChannelNameMaxLength),
+		validation.Match(releaseChannelNameRegexp),
+	)
+}
+
+// ReleaseChannel is a named pointer to a release, such as `dev`, `beta`
+// or `stable`, which deployments can target instead of a release name.
+type ReleaseChannel struct {
+	Name        string    `json:"name" bson:"_id"`
+	ReleaseName string    `json:"release_name" bson:"release_name"`
+	Modified    time.Time `json:"modified" bson:"modified"`
+
+	// History lists the last promotions to the channel, oldest first
+	History []ReleaseChannelPromotion `json:"history,omitempty" bson:"history,omitempty"`
+}
+
+// ReleaseChannelPromotion is the audit entry of a release promoted to
+// a channel.
+type ReleaseChannelPromotion struct {
+	ReleaseName string `json:"release_name" bson:"release_name"`
+	// PreviousReleaseName is the release the channel pointed at before
+	PreviousReleaseName string `json:"previous_release_name,omitempty" bson:"previous,omitempty"`
+	// FromChannel is set when the release was promoted from another channel
+	FromChannel string `json:"from_channel,omitempty" bson:"from_channel,omitempty"`
+	// UserID is the ID of the user who promoted the release
+	UserID   string    `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Promoted time.Time `json:"promoted" bson:"promoted"`
+}
+
+// ReleasePromotion moves a release to a channel, either by name or from
+// another channel.
+type ReleasePromotion struct {
+	ReleaseName string `json:"release_name,omitempty"`
+	FromChannel string `json:"from_channel,omitempty"`
+}
+
+// Validate checks the promotion to the given channel.
+func (p ReleasePromotion) Validate(channel string) error {
+	if err := ValidateReleaseChannelName(channel); err != nil {
+		return errors.Wrap(err, "channel")
+	}
+	if (p.ReleaseName == "") == (p.FromChannel == "") {
+		return ErrReleasePromotionSource
+	}
+	if p.FromChannel != "" {
+		if err := ValidateReleaseChannelName(p.FromChannel); err != nil {
+			return errors.Wrap(err, "from_channel")
+		} else if p.FromChannel == channel {
+			return ErrReleasePromotionSameChannel
+		}
+	}
+	return lengthLessThan4096.Validate(p.ReleaseName)
+}
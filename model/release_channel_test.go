@@ -0,0 +1,85 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleasePromotionValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		Channel   string
+		Promotion ReleasePromotion
+
+		Error error
+	}{
+		"ok, release name": {
+			Channel:   "stable",
+			Promotion: ReleasePromotion{ReleaseName: "release-1.0"},
+		},
+		"ok, from channel": {
+			Channel:   "stable",
+			Promotion: ReleasePromotion{FromChannel: "beta"},
+		},
+		"error, no source": {
+			Channel: "stable",
+			Error:   ErrReleasePromotionSource,
+		},
+		"error, both sources": {
+			Channel: "stable",
+			Promotion: ReleasePromotion{
+				ReleaseName: "release-1.0",
+				FromChannel: "beta",
+			},
+			Error: ErrReleasePromotionSource,
+		},
+		"error, same channel": {
+			Channel:   "stable",
+			Promotion: ReleasePromotion{FromChannel: "stable"},
+			Error:     ErrReleasePromotionSameChannel,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			err := tc.Promotion.Validate(tc.Channel)
+			if tc.Error != nil {
+				assert.ErrorIs(t, err, tc.Error)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateReleaseChannelName(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateReleaseChannelName("stable"))
+	assert.NoError(t, ValidateReleaseChannelName("beta-2.x_eu"))
+	assert.Error(t, ValidateReleaseChannelName(""))
+	assert.Error(t, ValidateReleaseChannelName("beta/eu"))
+	assert.Error(t, ValidateReleaseChannelName("beta eu"))
+	assert.Error(t, ValidateReleaseChannelName(
+		strings.Repeat("a", ReleaseChannelNameMaxLength+1)))
+
+	err := ReleasePromotion{FromChannel: "beta/eu"}.Validate("stable")
+	assert.EqualError(t, err, "from_channel: must be in a valid format")
+}
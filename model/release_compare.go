@@ -0,0 +1,269 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ReleaseComparison reports the differences between the artifacts of two
+// releases, per compatible device type.
+type ReleaseComparison struct {
+	From        string                 `json:"from"`
+	To          string                 `json:"to"`
+	DeviceTypes []DeviceTypeComparison `json:"device_types"`
+}
+
+// DeviceTypeComparison reports the differences between the artifacts of two
+// releases compatible with the same device type. FromArtifactID or
+// ToArtifactID is empty when only one of the releases supports the device
+// type, in which case every attribute of the other artifact is reported as
+// added or removed.
+type DeviceTypeComparison struct {
+	DeviceType     string `json:"device_type"`
+	FromArtifactID string `json:"from_artifact_id,omitempty"`
+	ToArtifactID   string `json:"to_artifact_id,omitempty"`
+
+	Provides       MapDiff     `json:"artifact_provides"`
+	Depends        MapDiff     `json:"artifact_depends"`
+	ClearsProvides StringsDiff `json:"clears_artifact_provides"`
+	UpdateTypes    StringsDiff `json:"update_types"`
+	Files          []FileDiff  `json:"files"`
+}
+
+// ValueChange holds the value of an attribute in both releases.
+type ValueChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// MapDiff reports the keys added, removed and changed between two maps.
+type MapDiff struct {
+	Added   map[string]interface{} `json:"added,omitempty"`
+	Removed map[string]interface{} `json:"removed,omitempty"`
+	Changed map[string]ValueChange `json:"changed,omitempty"`
+}
+
+// StringsDiff reports the items added and removed between two sets.
+type StringsDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+const (
+	FileDiffAdded   = "added"
+	FileDiffRemoved = "removed"
+	FileDiffChanged = "changed"
+)
+
+// FileInfo holds the checksum and the size of a payload file.
+type FileInfo struct {
+	Checksum string `json:"checksum"`
+	Size     int64  `json:"size"`
+}
+
+// FileDiff reports a payload file which differs between two releases.
+type FileDiff struct {
+	Name   string    `json:"name"`
+	Status string    `json:"status"`
+	From   *FileInfo `json:"from,omitempty"`
+	To     *FileInfo `json:"to,omitempty"`
+}
+
+// NewReleaseComparison compares the artifacts of the releases from and to.
+// When a release holds several artifacts compatible with a device type,
+// the first one is compared.
+func NewReleaseComparison(from, to string, fromImages, toImages []*Image) *ReleaseComparison {
+	fromByType := imagesByDeviceType(fromImages)
+	toByType := imagesByDeviceType(toImages)
+
+	deviceTypes := make([]string, 0, len(fromByType)+len(toByType))
+	for deviceType := range fromByType {
+		deviceTypes = append(deviceTypes, deviceType)
+	}
+	for deviceType := range toByType {
+		if _, ok := fromByType[deviceType]; !ok {
+			deviceTypes = append(deviceTypes, deviceType)
+		}
+	}
+	sort.Strings(deviceTypes)
+
+	comparison := &ReleaseComparison{
+		From:        from,
+		To:          to,
+		DeviceTypes: make([]DeviceTypeComparison, len(deviceTypes)),
+	}
+	for i, deviceType := range deviceTypes {
+		comparison.DeviceTypes[i] = compareImages(
+			deviceType, fromByType[deviceType], toByType[deviceType],
+		)
+	}
+	return comparison
+}
+
+func imagesByDeviceType(images []*Image) map[string]*Image {
+	byType := make(map[string]*Image)
+	for _, image := range images {
+		if image == nil || image.ArtifactMeta == nil {
+			continue
+		}
+		for _, deviceType := range image.DeviceTypesCompatible {
+			if _, ok := byType[deviceType]; !ok {
+				byType[deviceType] = image
+			}
+		}
+	}
+	return byType
+}
+
+func compareImages(deviceType string, from, to *Image) DeviceTypeComparison {
+	var fromMeta, toMeta ArtifactMeta
+	comparison := DeviceTypeComparison{DeviceType: deviceType}
+	if from != nil {
+		comparison.FromArtifactID = from.Id
+		fromMeta = *from.ArtifactMeta
+	}
+	if to != nil {
+		comparison.ToArtifactID = to.Id
+		toMeta = *to.ArtifactMeta
+	}
+
+	fromProvides := make(map[string]interface{}, len(fromMeta.Provides))
+	for key, value := range fromMeta.Provides {
+		fromProvides[key] = value
+	}
+	toProvides := make(map[string]interface{}, len(toMeta.Provides))
+	for key, value := range toMeta.Provides {
+		toProvides[key] = value
+	}
+	comparison.Provides = diffMaps(fromProvides, toProvides)
+	comparison.Depends = diffMaps(fromMeta.Depends, toMeta.Depends)
+	comparison.ClearsProvides = diffStrings(fromMeta.ClearsProvides, toMeta.ClearsProvides)
+	comparison.UpdateTypes = diffStrings(updateTypes(fromMeta), updateTypes(toMeta))
+	comparison.Files = diffFiles(updateFiles(fromMeta), updateFiles(toMeta))
+	return comparison
+}
+
+func diffMaps(from, to map[string]interface{}) MapDiff {
+	var diff MapDiff
+	for key, value := range from {
+		toValue, ok := to[key]
+		if !ok {
+			if diff.Removed == nil {
+				diff.Removed = make(map[string]interface{})
+			}
+			diff.Removed[key] = value
+		} else if !reflect.DeepEqual(value, toValue) {
+			if diff.Changed == nil {
+				diff.Changed = make(map[string]ValueChange)
+			}
+			diff.Changed[key] = ValueChange{From: value, To: toValue}
+		}
+	}
+	for key, value := range to {
+		if _, ok := from[key]; !ok {
+			if diff.Added == nil {
+				diff.Added = make(map[string]interface{})
+			}
+			diff.Added[key] = value
+		}
+	}
+	return diff
+}
+
+func diffStrings(from, to []string) StringsDiff {
+	var diff StringsDiff
+	fromSet := make(map[string]bool, len(from))
+	for _, item := range from {
+		fromSet[item] = true
+	}
+	toSet := make(map[string]bool, len(to))
+	for _, item := range to {
+		toSet[item] = true
+		if !fromSet[item] {
+			diff.Added = append(diff.Added, item)
+			fromSet[item] = true
+		}
+	}
+	for _, item := range from {
+		if !toSet[item] {
+			diff.Removed = append(diff.Removed, item)
+			toSet[item] = true
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+func updateTypes(meta ArtifactMeta) []string {
+	types := make([]string, 0, len(meta.Updates))
+	for _, update := range meta.Updates {
+		if update.TypeInfo.Type != nil {
+			types = append(types, *update.TypeInfo.Type)
+		}
+	}
+	return types
+}
+
+func updateFiles(meta ArtifactMeta) map[string]FileInfo {
+	files := make(map[string]FileInfo)
+	for _, update := range meta.Updates {
+		for _, file := range update.Files {
+			files[file.Name] = FileInfo{
+				Checksum: file.Checksum,
+				Size:     file.Size,
+			}
+		}
+	}
+	return files
+}
+
+func diffFiles(from, to map[string]FileInfo) []FileDiff {
+	diffs := []FileDiff{}
+	for name, fromFile := range from {
+		fromFile := fromFile
+		toFile, ok := to[name]
+		if !ok {
+			diffs = append(diffs, FileDiff{
+				Name:   name,
+				Status: FileDiffRemoved,
+				From:   &fromFile,
+			})
+		} else if fromFile != toFile {
+			diffs = append(diffs, FileDiff{
+				Name:   name,
+				Status: FileDiffChanged,
+				From:   &fromFile,
+				To:     &toFile,
+			})
+		}
+	}
+	for name, toFile := range to {
+		toFile := toFile
+		if _, ok := from[name]; !ok {
+			diffs = append(diffs, FileDiff{
+				Name:   name,
+				Status: FileDiffAdded,
+				To:     &toFile,
+			})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReleaseComparison(t *testing.T) {
+	t.Parallel()
+
+	rootfs := "rootfs-image"
+	app := "single-file"
+	fromImages := []*Image{{
+		Id: "from-1",
+		ArtifactMeta: &ArtifactMeta{
+			Name:                  "release-1",
+			DeviceTypesCompatible: []string{"rpi4", "bbb"},
+			Provides: map[string]string{
+				"rootfs-image.version":  "1",
+				"rootfs-image.checksum": "aaa",
+			},
+			Depends: map[string]interface{}{
+				"device_type": []interface{}{"rpi4", "bbb"},
+			},
+			ClearsProvides: []string{"rootfs-image.*"},
+			Updates: []Update{{
+				TypeInfo: ArtifactUpdateTypeInfo{Type: &rootfs},
+				Files: []UpdateFile{
+					{Name: "rootfs.ext4", Checksum: "aaa", Size: 100},
+					{Name: "boot.img", Checksum: "bbb", Size: 10},
+				},
+			}},
+		},
+	}}
+	toImages := []*Image{{
+		Id: "to-1",
+		ArtifactMeta: &ArtifactMeta{
+			Name:                  "release-2",
+			DeviceTypesCompatible: []string{"rpi4", "qemu"},
+			Provides: map[string]string{
+				"rootfs-image.version": "2",
+				"data.version":         "1",
+			},
+			Depends: map[string]interface{}{
+				"device_type": []interface{}{"rpi4", "qemu"},
+			},
+			ClearsProvides: []string{"rootfs-image.*"},
+			Updates: []Update{{
+				TypeInfo: ArtifactUpdateTypeInfo{Type: &rootfs},
+				Files: []UpdateFile{
+					{Name: "rootfs.ext4", Checksum: "ccc", Size: 120},
+					{Name: "boot.img", Checksum: "bbb", Size: 10},
+				},
+			}, {
+				TypeInfo: ArtifactUpdateTypeInfo{Type: &app},
+				Files: []UpdateFile{
+					{Name: "app.conf", Checksum: "ddd", Size: 1},
+				},
+			}},
+		},
+	}}
+
+	comparison := NewReleaseComparison("release-1", "release-2", fromImages, toImages)
+	assert.Equal(t, "release-1", comparison.From)
+	assert.Equal(t, "release-2", comparison.To)
+	if !assert.Len(t, comparison.DeviceTypes, 3) {
+		return
+	}
+
+	bbb := comparison.DeviceTypes[0]
+	assert.Equal(t, "bbb", bbb.DeviceType)
+	assert.Equal(t, "from-1", bbb.FromArtifactID)
+	assert.Empty(t, bbb.ToArtifactID)
+	assert.Len(t, bbb.Provides.Removed, 2)
+	assert.Empty(t, bbb.Provides.Added)
+	assert.Equal(t, []string{"rootfs-image.*"}, bbb.ClearsProvides.Removed)
+	assert.Equal(t, []string{rootfs}, bbb.UpdateTypes.Removed)
+	assert.Equal(t, []FileDiff{{
+		Name:   "boot.img",
+		Status: FileDiffRemoved,
+		From:   &FileInfo{Checksum: "bbb", Size: 10},
+	}, {
+		Name:   "rootfs.ext4",
+		Status: FileDiffRemoved,
+		From:   &FileInfo{Checksum: "aaa", Size: 100},
+	}}, bbb.Files)
+
+	qemu := comparison.DeviceTypes[1]
+	assert.Equal(t, "qemu", qemu.DeviceType)
+	assert.Empty(t, qemu.FromArtifactID)
+	assert.Equal(t, "to-1", qemu.ToArtifactID)
+	assert.Len(t, qemu.Provides.Added, 2)
+	assert.Equal(t, []string{rootfs, app}, qemu.UpdateTypes.Added)
+	assert.Len(t, qemu.Files, 3)
+
+	rpi4 := comparison.DeviceTypes[2]
+	assert.Equal(t, "rpi4", rpi4.DeviceType)
+	assert.Equal(t, "from-1", rpi4.FromArtifactID)
+	assert.Equal(t, "to-1", rpi4.ToArtifactID)
+	assert.Equal(t, MapDiff{
+		Added:   map[string]interface{}{"data.version": "1"},
+		Removed: map[string]interface{}{"rootfs-image.checksum": "aaa"},
+		Changed: map[string]ValueChange{
+			"rootfs-image.version": {From: "1", To: "2"},
+		},
+	}, rpi4.Provides)
+	assert.Equal(t, MapDiff{
+		Changed: map[string]ValueChange{
+			"device_type": {
+				From: []interface{}{"rpi4", "bbb"},
+				To:   []interface{}{"rpi4", "qemu"},
+			},
+		},
+	}, rpi4.Depends)
+	assert.Equal(t, StringsDiff{}, rpi4.ClearsProvides)
+	assert.Equal(t, StringsDiff{Added: []string{app}}, rpi4.UpdateTypes)
+	assert.Equal(t, []FileDiff{{
+		Name:   "app.conf",
+		Status: FileDiffAdded,
+		To:     &FileInfo{Checksum: "ddd", Size: 1},
+	}, {
+		Name:   "rootfs.ext4",
+		Status: FileDiffChanged,
+		From:   &FileInfo{Checksum: "aaa", Size: 100},
+		To:     &FileInfo{Checksum: "ccc", Size: 120},
+	}}, rpi4.Files)
+}
+
+func TestNewReleaseComparisonIdentical(t *testing.T) {
+	t.Parallel()
+
+	images := []*Image{{
+		Id: "artifact",
+		ArtifactMeta: &ArtifactMeta{
+			Name:                  "release",
+			DeviceTypesCompatible: []string{"rpi4"},
+			Provides:              map[string]string{"version": "1"},
+		},
+	}}
+	comparison := NewReleaseComparison("release", "release", images, images)
+	assert.Equal(t, &ReleaseComparison{
+		From: "release",
+		To:   "release",
+		DeviceTypes: []DeviceTypeComparison{{
+			DeviceType:     "rpi4",
+			FromArtifactID: "artifact",
+			ToArtifactID:   "artifact",
+			Files:          []FileDiff{},
+		}},
+	}, comparison)
+}
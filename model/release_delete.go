@@ -0,0 +1,33 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+// ReleaseArtifactDeletion reports the deletion of an artifact of a release.
+type ReleaseArtifactDeletion struct {
+	ReleaseName string   `json:"release_name"`
+	ID          string   `json:"id"`
+	DeviceTypes []string `json:"device_types_compatible"`
+
+	// InActiveDeployment is set when the artifact is used in an active
+	// deployment, preventing the deletion of the release
+	InActiveDeployment bool `json:"in_active_deployment,omitempty"`
+	// Channels lists the release channels pointing at the release,
+	// preventing its deletion
+	Channels []string `json:"channels,omitempty"`
+
+	Deleted bool `json:"deleted"`
+	// Error is set when the artifact could not be deleted
+	Error string `json:"error,omitempty"`
+}
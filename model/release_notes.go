@@ -0,0 +1,58 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday/v2"
+)
+
+// ReleaseNotesRevision is a change of the notes of a release: the notes
+// as set by the change, who set them, and when.
+type ReleaseNotesRevision struct {
+	ReleaseName string    `json:"-" bson:"release_name"`
+	Notes       Notes     `json:"notes" bson:"notes"`
+	UserID      string    `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Modified    time.Time `json:"modified" bson:"modified"`
+}
+
+// notesHTMLFlags render the notes to HTML safe to embed in a page: raw
+// HTML and images are dropped, and only links to trusted protocols are
+// rendered, opening in a new tab without referrer.
+const notesHTMLFlags = blackfriday.SkipHTML |
+	blackfriday.SkipImages |
+	blackfriday.Safelink |
+	blackfriday.NofollowLinks |
+	blackfriday.NoreferrerLinks |
+	blackfriday.HrefTargetBlank
+
+// notesHTMLPolicy sanitizes the rendered notes, so that the output stays
+// safe whatever the Markdown renderer lets through.
+var notesHTMLPolicy = bluemonday.UGCPolicy().
+	RequireNoReferrerOnLinks(true).
+	AddTargetBlankToFullyQualifiedLinks(true)
+
+// HTML renders the Markdown notes to sanitized HTML.
+func (n Notes) HTML() []byte {
+	renderer := blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+		Flags: notesHTMLFlags,
+	})
+	return notesHTMLPolicy.SanitizeBytes(blackfriday.Run([]byte(n),
+		blackfriday.WithExtensions(blackfriday.CommonExtensions),
+		blackfriday.WithRenderer(renderer),
+	))
+}
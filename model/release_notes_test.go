@@ -0,0 +1,63 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseNotesMarkdown(t *testing.T) {
+	notes := Notes("# Release 1.0\n\n* security fixes\n\t* CVE-2023-1234\r\n")
+	assert.NoError(t, notes.Validate())
+	assert.Error(t, Notes("bell \a").Validate())
+	assert.NoError(t, Notes(strings.Repeat("a", NotesLengthMaximumCharacters)).Validate())
+}
+
+func TestReleaseNotesHTML(t *testing.T) {
+	testCases := map[string]struct {
+		notes Notes
+		html  string
+	}{
+		"markdown": {
+			notes: "# Release 1.0\n\n* security fixes\n",
+			html:  "<h1>Release 1.0</h1>\n\n<ul>\n<li>security fixes</li>\n</ul>\n",
+		},
+		"link": {
+			notes: "[changelog](https://example.com/changelog)",
+			html: `<p><a href="https://example.com/changelog" ` +
+				`rel="nofollow noreferrer noopener" target="_blank">changelog</a></p>` + "\n",
+		},
+		"script link": {
+			notes: "[changelog](javascript:void)",
+			html:  "<p><tt>changelog</tt></p>\n",
+		},
+		"raw html": {
+			notes: "fixes<script>alert(1)</script>\n\n<iframe src=\"https://example.com\"></iframe>\n",
+			html:  "<p>fixesalert(1)</p>\n",
+		},
+		"image": {
+			notes: "![logo](https://example.com/logo.png)",
+			html:  "<p></p>\n",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.html, string(tc.notes.HTML()))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import "sort"
+
+// ReleaseStatistics reports the adoption of a release and the outcome of
+// its deployments, overall and per device type.
+type ReleaseStatistics struct {
+	ReleaseName string `json:"release_name"`
+	// Deployments is the number of deployments of the release.
+	Deployments int `json:"deployments"`
+
+	ReleaseOutcomes
+	DeviceTypes []ReleaseDeviceTypeStatistics `json:"device_types"`
+}
+
+// ReleaseDeviceTypeStatistics reports the adoption and the outcome of the
+// deployments of a release for a single device type.
+type ReleaseDeviceTypeStatistics struct {
+	DeviceType string `json:"device_type"`
+
+	ReleaseOutcomes
+}
+
+// ReleaseOutcomes counts the devices running a release and the outcome of
+// the device deployments of the release. Successes include devices which
+// already had the release installed; the rates are computed over the
+// device deployments which either succeeded or failed.
+type ReleaseOutcomes struct {
+	// DevicesInstalled is the number of devices whose latest successful
+	// deployment installed the release.
+	DevicesInstalled  int     `json:"devices_installed"`
+	DeviceDeployments int     `json:"device_deployments"`
+	Succeeded         int     `json:"succeeded"`
+	Failed            int     `json:"failed"`
+	SuccessRate       float64 `json:"success_rate"`
+	FailureRate       float64 `json:"failure_rate"`
+}
+
+func (o *ReleaseOutcomes) add(installed int, stats Stats) {
+	o.DevicesInstalled += installed
+	for _, count := range stats {
+		o.DeviceDeployments += count
+	}
+	o.Succeeded += stats[DeviceDeploymentStatusSuccessStr] +
+		stats[DeviceDeploymentStatusAlreadyInstStr]
+	o.Failed += stats[DeviceDeploymentStatusFailureStr]
+	if finished := o.Succeeded + o.Failed; finished > 0 {
+		o.SuccessRate = float64(o.Succeeded) / float64(finished)
+		o.FailureRate = float64(o.Failed) / float64(finished)
+	}
+}
+
+// NewReleaseStatistics aggregates the number of devices running the release
+// and the device deployment statistics of the release, both indexed by
+// device type.
+func NewReleaseStatistics(
+	releaseName string,
+	deployments int,
+	installed map[string]int,
+	stats map[string]Stats,
+) *ReleaseStatistics {
+	deviceTypes := make([]string, 0, len(stats))
+	for deviceType := range stats {
+		deviceTypes = append(deviceTypes, deviceType)
+	}
+	for deviceType := range installed {
+		if _, ok := stats[deviceType]; !ok {
+			deviceTypes = append(deviceTypes, deviceType)
+		}
+	}
+	sort.Strings(deviceTypes)
+
+	releaseStats := &ReleaseStatistics{
+		ReleaseName: releaseName,
+		Deployments: deployments,
+		DeviceTypes: make([]ReleaseDeviceTypeStatistics, len(deviceTypes)),
+	}
+	for i, deviceType := range deviceTypes {
+		releaseStats.DeviceTypes[i].DeviceType = deviceType
+		releaseStats.DeviceTypes[i].add(installed[deviceType], stats[deviceType])
+		releaseStats.add(installed[deviceType], stats[deviceType])
+	}
+	return releaseStats
+}
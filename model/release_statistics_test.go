@@ -0,0 +1,78 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReleaseStatistics(t *testing.T) {
+	t.Parallel()
+
+	stats := map[string]Stats{
+		"rpi4": {
+			DeviceDeploymentStatusSuccessStr:     6,
+			DeviceDeploymentStatusAlreadyInstStr: 2,
+			DeviceDeploymentStatusFailureStr:     2,
+			DeviceDeploymentStatusInstallingStr:  1,
+		},
+		"bbb": {
+			DeviceDeploymentStatusFailureStr: 2,
+		},
+	}
+	installed := map[string]int{
+		"rpi4": 7,
+		"qemu": 1,
+	}
+
+	releaseStats := NewReleaseStatistics("release", 3, installed, stats)
+	assert.Equal(t, &ReleaseStatistics{
+		ReleaseName: "release",
+		Deployments: 3,
+		ReleaseOutcomes: ReleaseOutcomes{
+			DevicesInstalled:  8,
+			DeviceDeployments: 13,
+			Succeeded:         8,
+			Failed:            4,
+			SuccessRate:       8.0 / 12.0,
+			FailureRate:       4.0 / 12.0,
+		},
+		DeviceTypes: []ReleaseDeviceTypeStatistics{{
+			DeviceType: "bbb",
+			ReleaseOutcomes: ReleaseOutcomes{
+				DeviceDeployments: 2,
+				Failed:            2,
+				FailureRate:       1,
+			},
+		}, {
+			DeviceType: "qemu",
+			ReleaseOutcomes: ReleaseOutcomes{
+				DevicesInstalled: 1,
+			},
+		}, {
+			DeviceType: "rpi4",
+			ReleaseOutcomes: ReleaseOutcomes{
+				DevicesInstalled:  7,
+				DeviceDeployments: 11,
+				Succeeded:         8,
+				Failed:            2,
+				SuccessRate:       0.8,
+				FailureRate:       0.2,
+			},
+		}},
+	}, releaseStats)
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func TestReleaseTags(t *testing.T) {
@@ -63,6 +64,103 @@ func TestReleaseTags(t *testing.T) {
 		err = json.Unmarshal([]byte(`{}`), &tags)
 		assert.Error(t, err)
 	})
+
+	t.Run("key/value", func(t *testing.T) {
+		tag := Tag("product=gateway")
+		assert.Equal(t, "product", tag.Key())
+		assert.Equal(t, "gateway", tag.Value())
+		assert.NoError(t, tag.Validate())
+		assert.Equal(t, "fips", Tag("fips").Key())
+		assert.Empty(t, Tag("fips").Value())
+		assert.Equal(t, Tag("fips=true"), NewTag("FIPS", "true"))
+		assert.Equal(t, Tag("fips"), NewTag("fips", ""))
+
+		assert.NoError(t, Tag("version=1.2.3+build:4/rc@eu").Validate())
+		assert.ErrorIs(t, Tag("fips=").Validate(), ErrTagValueEmpty)
+		assert.ErrorIs(t, Tag("=true").Validate(), ErrTagEmpty)
+		var charErr *InvalidCharacterError
+		assert.ErrorAs(t, Tag("product=gate way").Validate(), &charErr)
+		assert.ErrorAs(t, Tag("product=a=b").Validate(), &charErr)
+
+		err := Tags{"product=gateway", "product=sensor"}.Validate()
+		assert.ErrorIs(t, err, ErrTagKeyDuplicate)
+		err = Tags{"product", "product=sensor"}.Validate()
+		assert.ErrorIs(t, err, ErrTagKeyDuplicate)
+		assert.Equal(t, []string{"product", "fips"},
+			Tags{"product=gateway", "fips"}.Keys())
+	})
+
+	t.Run("key/value JSON", func(t *testing.T) {
+		var tags Tags
+		err := json.Unmarshal(
+			[]byte(`["Product=Gateway", {"key": "FIPS", "value": "true"}, {"key": "beta"}]`),
+			&tags,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, Tags{"product=Gateway", "fips=true", "beta"}, tags)
+
+		b, _ := json.Marshal(tags)
+		assert.JSONEq(t, `["product=Gateway", "fips=true", "beta"]`, string(b))
+
+		err = json.Unmarshal([]byte(`[1]`), &tags)
+		assert.Error(t, err)
+	})
+
+	t.Run("BSON", func(t *testing.T) {
+		type doc struct {
+			Tags Tags `bson:"tags"`
+		}
+		b, err := bson.Marshal(doc{Tags: Tags{"product=gateway", "fips"}})
+		assert.NoError(t, err)
+		var raw bson.M
+		assert.NoError(t, bson.Unmarshal(b, &raw))
+		assert.Equal(t, bson.A{
+			bson.M{"key": "product", "value": "gateway"},
+			bson.M{"key": "fips"},
+		}, raw["tags"])
+
+		var decoded doc
+		assert.NoError(t, bson.Unmarshal(b, &decoded))
+		assert.Equal(t, Tags{"product=gateway", "fips"}, decoded.Tags)
+
+		// flat tags stored before the key/value tags
+		b, _ = bson.Marshal(bson.M{"tags": bson.A{"product-gateway"}})
+		assert.NoError(t, bson.Unmarshal(b, &decoded))
+		assert.Equal(t, Tags{"product-gateway"}, decoded.Tags)
+
+		b, _ = bson.Marshal(bson.M{"tags": bson.A{1}})
+		assert.Error(t, bson.Unmarshal(b, &decoded))
+	})
+}
+
+func TestTagsPatch(t *testing.T) {
+	t.Parallel()
+
+	assert.ErrorIs(t, TagsPatch{}.Validate(), ErrTagsPatchEmpty)
+	assert.ErrorIs(t, TagsPatch{
+		Add:    Tags{"fips=true"},
+		Remove: Tags{"fips"},
+	}.Validate(), ErrTagsPatchConflict)
+	assert.ErrorIs(t, TagsPatch{
+		Add: Tags{"fips=true", "fips=false"},
+	}.Validate(), ErrTagKeyDuplicate)
+	assert.ErrorIs(t, TagsPatch{
+		Remove: Tags{"fips="},
+	}.Validate(), ErrTagValueEmpty)
+	assert.NoError(t, TagsPatch{
+		Add:    Tags{"fips=true"},
+		Remove: Tags{"product=gateway", "beta"},
+	}.Validate())
+
+	tags := Tags{"product=gateway", "fips=false", "beta", "region=eu"}
+	patched := TagsPatch{
+		Add:    Tags{"fips=true", "stable"},
+		Remove: Tags{"beta", "region=us", "unknown"},
+	}.Apply(tags)
+	assert.Equal(t, Tags{"product=gateway", "region=eu", "fips=true", "stable"}, patched)
+
+	patched = TagsPatch{Remove: Tags{"region=eu"}}.Apply(tags)
+	assert.Equal(t, Tags{"product=gateway", "fips=false", "beta"}, patched)
 }
 
 func TestReleaseNotesValidation(t *testing.T) {
@@ -115,3 +213,104 @@ func TestConvertReleasesToV1(t *testing.T) {
 	releasesV1 := ConvertReleasesToV1(releases)
 	assert.Equal(t, expected, releasesV1)
 }
+
+func TestReleaseStatus(t *testing.T) {
+	assert.NoError(t, ReleaseStatusDraft.Validate())
+	assert.NoError(t, ReleaseStatusApproved.Validate())
+	assert.NoError(t, ReleaseStatusDeprecated.Validate())
+	assert.NoError(t, ReleaseStatusRevoked.Validate())
+	assert.ErrorIs(t, ReleaseStatus("archived").Validate(), ErrReleaseStatusInvalid)
+	assert.ErrorIs(t, ReleasePatch{Status: "archived"}.Validate(), ErrReleaseStatusInvalid)
+
+	assert.ElementsMatch(t, []ReleaseStatus{
+		ReleaseStatusDraft,
+		ReleaseStatusApproved,
+	}, ReleaseStatusDraft.StatusesTransitioningTo())
+	assert.ElementsMatch(t, []ReleaseStatus{
+		ReleaseStatusApproved,
+		ReleaseStatusDraft,
+		ReleaseStatusDeprecated,
+	}, ReleaseStatusApproved.StatusesTransitioningTo())
+	assert.ElementsMatch(t, []ReleaseStatus{
+		ReleaseStatusDeprecated,
+		ReleaseStatusApproved,
+	}, ReleaseStatusDeprecated.StatusesTransitioningTo())
+	assert.ElementsMatch(t, []ReleaseStatus{
+		ReleaseStatusRevoked,
+		ReleaseStatusDraft,
+		ReleaseStatusApproved,
+		ReleaseStatusDeprecated,
+	}, ReleaseStatusRevoked.StatusesTransitioningTo())
+
+	assert.False(t, ReleaseStatusDraft.IsDeployable())
+	assert.True(t, ReleaseStatusApproved.IsDeployable())
+	assert.True(t, ReleaseStatusDeprecated.IsDeployable())
+	assert.False(t, ReleaseStatusRevoked.IsDeployable())
+	assert.True(t, ReleaseStatus("").IsDeployable())
+}
+
+func TestReleaseAlias(t *testing.T) {
+	alias := func(s string) *string { return &s }
+
+	assert.NoError(t, ValidateReleaseAlias("Summer 2023"))
+	assert.ErrorIs(t, ValidateReleaseAlias(""), ErrReleaseAliasInvalid)
+	assert.ErrorIs(t, ValidateReleaseAlias(" Summer 2023"), ErrReleaseAliasInvalid)
+	assert.ErrorIs(t, ValidateReleaseAlias("Summer\n2023"), ErrReleaseAliasInvalid)
+	assert.ErrorIs(t,
+		ValidateReleaseAlias(strings.Repeat("a", ReleaseAliasMaxLength+1)),
+		ErrReleaseAliasInvalid)
+
+	assert.NoError(t, ReleasePatch{Alias: alias("")}.Validate())
+	assert.ErrorIs(t, ReleasePatch{Alias: alias("\t")}.Validate(), ErrReleaseAliasInvalid)
+
+	assert.True(t, ReleasePatch{}.UpdatesNotes())
+	assert.True(t, ReleasePatch{Notes: "notes", Alias: alias("Summer 2023")}.UpdatesNotes())
+	assert.False(t, ReleasePatch{Alias: alias("Summer 2023")}.UpdatesNotes())
+	assert.False(t, ReleasePatch{Status: ReleaseStatusApproved}.UpdatesNotes())
+}
+
+func TestProvidesFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := ParseProvidesFilter("rootfs-image.version")
+	assert.NoError(t, err)
+	assert.Equal(t, ProvidesFilter{Key: "rootfs-image.version"}, filter)
+
+	filter, err = ParseProvidesFilter("rootfs-image.version=3.1")
+	assert.NoError(t, err)
+	assert.Equal(t, ProvidesFilter{Key: "rootfs-image.version", Value: "3.1"}, filter)
+	assert.False(t, filter.HasWildcard())
+	assert.Equal(t, `^3\.1$`, filter.ValuePattern())
+
+	filter, err = ParseProvidesFilter("rootfs-image.version=3.*-rc*")
+	assert.NoError(t, err)
+	assert.True(t, filter.HasWildcard())
+	assert.Equal(t, `^3\..*-rc.*$`, filter.ValuePattern())
+
+	_, err = ParseProvidesFilter("=3.1")
+	assert.ErrorIs(t, err, ErrProvidesFilterInvalid)
+	_, err = ParseProvidesFilter("rootfs-image.version=")
+	assert.ErrorIs(t, err, ErrProvidesFilterInvalid)
+}
+
+func TestReleaseOrImageFilterValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ReleaseOrImageFilter{
+		Provides:   []string{"rootfs-image.version=3.*", "data-partition.app"},
+		SearchText: "security fixes",
+		Sort:       SortFieldRelevance + ":" + SortDirectionDescending,
+	}.Validate())
+	assert.ErrorIs(t, ReleaseOrImageFilter{
+		Provides: []string{"=3.*"},
+	}.Validate(), ErrProvidesFilterInvalid)
+	assert.ErrorIs(t, ReleaseOrImageFilter{
+		Provides: make([]string, ProvidesFiltersMax+1),
+	}.Validate(), ErrTooManyProvidesFilters)
+	assert.ErrorIs(t, ReleaseOrImageFilter{
+		SearchText: strings.Repeat("a", SearchTextMaxLength+1),
+	}.Validate(), ErrSearchTextTooLong)
+	assert.ErrorIs(t, ReleaseOrImageFilter{
+		Sort: SortFieldRelevance + ":" + SortDirectionDescending,
+	}.Validate(), ErrSortRelevanceWithoutSearch)
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	// RetentionReasonKeepLast marks artifacts older than the last
+	// KeepLastPerDeviceType artifacts of each of their device types.
+	RetentionReasonKeepLast = "keep_last_per_device_type"
+	// RetentionReasonUnused marks artifacts not uploaded nor deployed for
+	// longer than DeleteUnusedAfterDays.
+	RetentionReasonUnused = "delete_unused_after_days"
+)
+
+// RetentionPolicy holds the rules selecting the artifacts of a tenant which
+// are deleted automatically. An artifact is deleted when it matches all the
+// configured rules; a policy without rules deletes nothing.
+type RetentionPolicy struct {
+	// KeepLastPerDeviceType keeps the given number of most recent
+	// artifacts for each device type.
+	KeepLastPerDeviceType int `json:"keep_last_per_device_type,omitempty" bson:"keep_last_per_device_type,omitempty"` //nolint:lll
+
+	// DeleteUnusedAfterDays deletes the artifacts neither uploaded nor
+	// deployed in the given number of days.
+	DeleteUnusedAfterDays int `json:"delete_unused_after_days,omitempty" bson:"delete_unused_after_days,omitempty"` //nolint:lll
+
+	// KeepTaggedReleases never deletes artifacts of releases with tags.
+	KeepTaggedReleases bool `json:"keep_tagged_releases,omitempty" bson:"keep_tagged_releases,omitempty"` //nolint:lll
+}
+
+// Validate checks structure according to valid tags
+func (p RetentionPolicy) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.KeepLastPerDeviceType, validation.Min(0)),
+		validation.Field(&p.DeleteUnusedAfterDays, validation.Min(0)),
+	)
+}
+
+// IsEmpty returns true when the policy has no rule selecting artifacts
+func (p RetentionPolicy) IsEmpty() bool {
+	return p.KeepLastPerDeviceType <= 0 && p.DeleteUnusedAfterDays <= 0
+}
+
+// ExpiredArtifact is an artifact selected for deletion by a retention policy
+type ExpiredArtifact struct {
+	TenantID    string    `json:"tenant_id,omitempty"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	DeviceTypes []string  `json:"device_types_compatible"`
+	Size        int64     `json:"size"`
+	LastUsed    time.Time `json:"last_used"`
+	Reasons     []string  `json:"reasons"`
+	Deleted     bool      `json:"deleted"`
+	Error       string    `json:"error,omitempty"`
+}
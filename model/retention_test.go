@@ -0,0 +1,67 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy RetentionPolicy
+
+		empty bool
+		err   string
+	}{
+		"ok": {
+			policy: RetentionPolicy{
+				KeepLastPerDeviceType: 3,
+				DeleteUnusedAfterDays: 90,
+				KeepTaggedReleases:    true,
+			},
+		},
+		"ok, empty": {
+			policy: RetentionPolicy{KeepTaggedReleases: true},
+			empty:  true,
+		},
+		"error, negative count": {
+			policy: RetentionPolicy{KeepLastPerDeviceType: -1},
+			empty:  true,
+			err:    "keep_last_per_device_type: must be no less than 0.",
+		},
+		"error, negative days": {
+			policy: RetentionPolicy{DeleteUnusedAfterDays: -1},
+			empty:  true,
+			err:    "delete_unused_after_days: must be no less than 0.",
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.policy.Validate()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.empty, tc.policy.IsEmpty())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// StatusTransitionsMode selects how illegal device deployment status
+// transitions are handled.
+type StatusTransitionsMode string
+
+const (
+	// StatusTransitionsModeWarn logs illegal transitions and applies them
+	StatusTransitionsModeWarn StatusTransitionsMode = "warn"
+	// StatusTransitionsModeEnforce rejects illegal transitions
+	StatusTransitionsModeEnforce StatusTransitionsMode = "enforce"
+)
+
+// Validate checks the mode is one of the known values
+func (mode StatusTransitionsMode) Validate() error {
+	return validation.Validate(string(mode), validation.In(
+		string(StatusTransitionsModeWarn),
+		string(StatusTransitionsModeEnforce),
+	))
+}
+
+// TenantSettings holds the per-tenant deployment settings.
+type TenantSettings struct {
+	// StatusTransitions overrides the default handling of illegal device
+	// deployment status transitions; unset falls back to the default.
+	StatusTransitions StatusTransitionsMode `json:"status_transitions,omitempty" bson:"status_transitions,omitempty"` //nolint:lll
+
+	// RequireSignedArtifacts rejects artifact uploads which are not signed
+	// with one of the tenant's trusted public keys.
+	RequireSignedArtifacts bool `json:"require_signed_artifacts,omitempty" bson:"require_signed_artifacts,omitempty"` //nolint:lll
+}
+
+// Validate checks structure according to valid tags
+func (s TenantSettings) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.StatusTransitions),
+	)
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/mendersoftware/deployments/app"
 	"github.com/mendersoftware/deployments/client/reporting"
 	dconfig "github.com/mendersoftware/deployments/config"
+	"github.com/mendersoftware/deployments/model"
 	"github.com/mendersoftware/deployments/storage"
 	"github.com/mendersoftware/deployments/storage/azblob"
 	"github.com/mendersoftware/deployments/storage/manager"
@@ -175,11 +176,17 @@ func RunServer(ctx context.Context) error {
 		return errors.WithMessage(err, "main: failed to setup storage client")
 	}
 
-	app := app.NewDeployments(ds, objStore, 0, false)
+	app := app.NewDeployments(ds, objStore, 0, false).
+		WithStatusTransitionsMode(model.StatusTransitionsMode(
+			c.GetString(dconfig.SettingDeviceDeploymentStatusTransitions),
+		))
 	if addr := c.GetString(dconfig.SettingReportingAddr); addr != "" {
 		c := reporting.NewClient(addr)
 		app = app.WithReporting(c)
 	}
+	if c.GetString(dconfig.SettingArtifactGenerator) == dconfig.ArtifactGeneratorBuiltin {
+		app = app.WithBuiltinArtifactGenerator()
+	}
 
 	// Setup API Router configuration
 	base64Repl := strings.NewReplacer("-", "+", "_", "/", "=", "")
@@ -17,6 +17,7 @@ package azblob
 import (
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -28,6 +29,7 @@ import (
 	"github.com/mendersoftware/deployments/utils"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
@@ -35,6 +37,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/google/uuid"
 )
 
 const (
@@ -512,3 +515,111 @@ func (c *client) PutRequest(
 	}
 	return link, nil
 }
+
+// blockID returns the ID of the block storing the given chunk; the IDs of
+// the blocks of a blob must have the same length.
+func blockID(uploadID string, chunk int) string {
+	return base64.StdEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%s-%05d", uploadID, chunk)),
+	)
+}
+
+// InitChunkedUpload returns a new upload ID; chunks are staged as blocks
+// of the blob and committed by CompleteChunkedUpload.
+func (c *client) InitChunkedUpload(
+	ctx context.Context,
+	path string,
+) (string, error) {
+	if _, err := c.clientFromContext(ctx); err != nil {
+		return "", OpError{
+			Op:     OpInitChunkedUpload,
+			Reason: err,
+		}
+	}
+	return uuid.NewString(), nil
+}
+
+func (c *client) PutChunk(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	chunk int,
+	src io.Reader,
+	size int64,
+) error {
+	azClient, err := c.clientFromContext(ctx)
+	if err != nil {
+		return OpError{
+			Op:     OpPutChunk,
+			Reason: err,
+		}
+	}
+	// staging a block requires a seekable body
+	body, err := storage.SpoolChunk(src, size)
+	if err != nil {
+		return OpError{
+			Op:      OpPutChunk,
+			Message: "failed to read chunk",
+			Reason:  err,
+		}
+	}
+	defer body.Close()
+	bc := azClient.NewBlockBlobClient(path)
+	_, err = bc.StageBlock(
+		ctx,
+		blockID(uploadID, chunk),
+		streaming.NopCloser(body),
+		&blockblob.StageBlockOptions{},
+	)
+	if err != nil {
+		return OpError{
+			Op:      OpPutChunk,
+			Message: "failed to stage block",
+			Reason:  err,
+		}
+	}
+	return nil
+}
+
+func (c *client) CompleteChunkedUpload(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	chunks int,
+) error {
+	azClient, err := c.clientFromContext(ctx)
+	if err != nil {
+		return OpError{
+			Op:     OpCompleteChunkedUpload,
+			Reason: err,
+		}
+	}
+	blockIDs := make([]string, chunks)
+	for i := range blockIDs {
+		blockIDs[i] = blockID(uploadID, i)
+	}
+	bc := azClient.NewBlockBlobClient(path)
+	_, err = bc.CommitBlockList(ctx, blockIDs, &blockblob.CommitBlockListOptions{
+		HTTPHeaders: &blob.HTTPHeaders{
+			BlobContentType: c.contentType,
+		},
+	})
+	if err != nil {
+		return OpError{
+			Op:      OpCompleteChunkedUpload,
+			Message: "failed to commit block list",
+			Reason:  err,
+		}
+	}
+	return nil
+}
+
+// AbortChunkedUpload is a noop: Azure discards the uncommitted blocks of a
+// blob after a week.
+func (c *client) AbortChunkedUpload(
+	ctx context.Context,
+	path string,
+	uploadID string,
+) error {
+	return nil
+}
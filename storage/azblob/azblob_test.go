@@ -219,6 +219,19 @@ func TestObjectStorage(t *testing.T) {
 					_ = rsp.Body.Close()
 					assert.Equal(t, blobContent, string(b))
 				}
+
+				// The link supports resuming the download
+				req, err = http.NewRequest(link.Method, link.Uri, nil)
+				if assert.NoError(t, err) {
+					req.Header.Set("Range", "bytes=3-5")
+					rsp, err := client.Do(req)
+					assert.NoError(t, err)
+					b, err := io.ReadAll(rsp.Body)
+					assert.NoError(t, err)
+					_ = rsp.Body.Close()
+					assert.Equal(t, http.StatusPartialContent, rsp.StatusCode)
+					assert.Equal(t, blobContent[3:6], string(b))
+				}
 			}
 
 			link, err = c.DeleteRequest(ctx, subPrefix+"foo", time.Minute)
@@ -462,3 +475,60 @@ func TestGetObject(t *testing.T) {
 		})
 	}
 }
+
+func TestChunkedUpload(t *testing.T) {
+	t.Parallel()
+
+	var blocks []string
+	azClient, srv := newTestStorageAndServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/container/foo/bar", r.URL.Path)
+			assert.Equal(t, http.MethodPut, r.Method)
+			q := r.URL.Query()
+			switch q.Get("comp") {
+			case "block":
+				b, _ := io.ReadAll(r.Body)
+				blocks = append(blocks, q.Get("blockid")+":"+string(b))
+			case "blocklist":
+				b, _ := io.ReadAll(r.Body)
+				for _, block := range blocks {
+					blockID, _, _ := strings.Cut(block, ":")
+					assert.Contains(t, string(b), blockID)
+				}
+				assert.Equal(t, "application/vnd-test",
+					r.Header.Get("X-Ms-Blob-Content-Type"))
+			default:
+				assert.Failf(t, "unexpected request", "%s %s", r.Method, r.URL)
+			}
+			w.WriteHeader(http.StatusCreated)
+		},
+	))
+	defer srv.Close()
+	ctx := context.Background()
+
+	uploadID, err := azClient.InitChunkedUpload(ctx, "foo/bar")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = uuid.Parse(uploadID)
+	assert.NoError(t, err)
+
+	err = azClient.PutChunk(ctx, "foo/bar", uploadID, 0, strings.NewReader("hello "), 6)
+	assert.NoError(t, err)
+	err = azClient.PutChunk(ctx, "foo/bar", uploadID, 1, strings.NewReader("world"), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		blockID(uploadID, 0) + ":hello ",
+		blockID(uploadID, 1) + ":world",
+	}, blocks)
+	assert.Len(t, blockID(uploadID, 10), len(blockID(uploadID, 1)))
+
+	err = azClient.PutChunk(ctx, "foo/bar", uploadID, 2, strings.NewReader("short"), 10)
+	assert.Error(t, err)
+
+	err = azClient.CompleteChunkedUpload(ctx, "foo/bar", uploadID, 2)
+	assert.NoError(t, err)
+
+	err = azClient.AbortChunkedUpload(ctx, "foo/bar", uploadID)
+	assert.NoError(t, err)
+}
@@ -49,6 +49,10 @@ const (
 	OpGetRequest    = "GetRequest"
 	OpDeleteRequest = "DeleteRequest"
 	OpPutRequest    = "PutRequest"
+
+	OpInitChunkedUpload     = "InitChunkedUpload"
+	OpPutChunk              = "PutChunk"
+	OpCompleteChunkedUpload = "CompleteChunkedUpload"
 )
 
 var (
@@ -150,3 +150,47 @@ func (c *client) PutRequest(
 	}
 	return objStore.PutRequest(ctx, path, duration)
 }
+
+func (c *client) InitChunkedUpload(ctx context.Context, path string) (string, error) {
+	objStore, err := c.clientFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return objStore.InitChunkedUpload(ctx, path)
+}
+
+func (c *client) PutChunk(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	chunk int,
+	src io.Reader,
+	size int64,
+) error {
+	objStore, err := c.clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return objStore.PutChunk(ctx, path, uploadID, chunk, src, size)
+}
+
+func (c *client) CompleteChunkedUpload(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	chunks int,
+) error {
+	objStore, err := c.clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return objStore.CompleteChunkedUpload(ctx, path, uploadID, chunks)
+}
+
+func (c *client) AbortChunkedUpload(ctx context.Context, path, uploadID string) error {
+	objStore, err := c.clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return objStore.AbortChunkedUpload(ctx, path, uploadID)
+}
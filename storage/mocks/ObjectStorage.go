@@ -34,6 +34,34 @@ type ObjectStorage struct {
 	mock.Mock
 }
 
+// AbortChunkedUpload provides a mock function with given fields: ctx, path, uploadID
+func (_m *ObjectStorage) AbortChunkedUpload(ctx context.Context, path string, uploadID string) error {
+	ret := _m.Called(ctx, path, uploadID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, path, uploadID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteChunkedUpload provides a mock function with given fields: ctx, path, uploadID, chunks
+func (_m *ObjectStorage) CompleteChunkedUpload(ctx context.Context, path string, uploadID string, chunks int) error {
+	ret := _m.Called(ctx, path, uploadID, chunks)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = rf(ctx, path, uploadID, chunks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteObject provides a mock function with given fields: ctx, path
 func (_m *ObjectStorage) DeleteObject(ctx context.Context, path string) error {
 	ret := _m.Called(ctx, path)
@@ -131,6 +159,41 @@ func (_m *ObjectStorage) HealthCheck(ctx context.Context) error {
 	return r0
 }
 
+// InitChunkedUpload provides a mock function with given fields: ctx, path
+func (_m *ObjectStorage) InitChunkedUpload(ctx context.Context, path string) (string, error) {
+	ret := _m.Called(ctx, path)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, path)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutChunk provides a mock function with given fields: ctx, path, uploadID, chunk, src, size
+func (_m *ObjectStorage) PutChunk(ctx context.Context, path string, uploadID string, chunk int, src io.Reader, size int64) error {
+	ret := _m.Called(ctx, path, uploadID, chunk, src, size)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, io.Reader, int64) error); ok {
+		r0 = rf(ctx, path, uploadID, chunk, src, size)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PutObject provides a mock function with given fields: ctx, path, src
 func (_m *ObjectStorage) PutObject(ctx context.Context, path string, src io.Reader) error {
 	ret := _m.Called(ctx, path, src)
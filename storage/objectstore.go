@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
 	"time"
 
 	"github.com/mendersoftware/deployments/model"
@@ -27,6 +28,52 @@ var (
 	ErrObjectNotFound = errors.New("object not found")
 )
 
+// Limits of chunked uploads common to the storage providers
+const (
+	// MinChunkSize is the minimum size of the chunks but the last one
+	MinChunkSize = 5 * 1024 * 1024
+	// MaxChunkSize is the maximum size of a chunk
+	MaxChunkSize = 100 * 1024 * 1024
+	// MaxChunks is the maximum number of chunks of an upload
+	MaxChunks = 10000
+)
+
+// SpoolChunk copies the chunk of the given size to a temporary file, so
+// that the providers get a seekable body without keeping chunks of up to
+// MaxChunkSize in memory; the file is removed once closed. It fails with
+// io.ErrUnexpectedEOF if src is shorter than size.
+func SpoolChunk(src io.Reader, size int64) (io.ReadSeekCloser, error) {
+	f, err := os.CreateTemp("", "deployments-chunk-")
+	if err != nil {
+		return nil, err
+	}
+	chunk := &spooledChunk{File: f}
+	n, err := io.Copy(f, io.LimitReader(src, size))
+	if err == nil && n < size {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = chunk.Close()
+		return nil, err
+	}
+	return chunk, nil
+}
+
+type spooledChunk struct {
+	*os.File
+}
+
+func (chunk *spooledChunk) Close() error {
+	err := chunk.File.Close()
+	if errRemove := os.Remove(chunk.Name()); err == nil {
+		err = errRemove
+	}
+	return err
+}
+
 // ObjectStorage allows to store and manage large files
 //
 //go:generate ../utils/mockgen.sh
@@ -38,12 +85,22 @@ type ObjectStorage interface {
 	StatObject(ctx context.Context, path string) (*ObjectInfo, error)
 
 	// The following interface generates signed URLs.
+	// The URLs from GetRequest accept HTTP Range requests.
 	GetRequest(ctx context.Context, path string, filename string,
 		duration time.Duration) (*model.Link, error)
 	DeleteRequest(ctx context.Context, path string,
 		duration time.Duration) (*model.Link, error)
 	PutRequest(ctx context.Context, path string,
 		duration time.Duration) (*model.Link, error)
+
+	// Chunked uploads assemble an object from chunks uploaded in order by
+	// separate requests; the ID returned by InitChunkedUpload identifies
+	// the upload in the other calls.
+	InitChunkedUpload(ctx context.Context, path string) (string, error)
+	PutChunk(ctx context.Context, path, uploadID string,
+		chunk int, src io.Reader, size int64) error
+	CompleteChunkedUpload(ctx context.Context, path, uploadID string, chunks int) error
+	AbortChunkedUpload(ctx context.Context, path, uploadID string) error
 }
 
 type ObjectInfo struct {
@@ -0,0 +1,46 @@
+// Copyright 2022 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package storage
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpoolChunk(t *testing.T) {
+	t.Parallel()
+
+	chunk, err := SpoolChunk(strings.NewReader("chunk and more"), 5)
+	if !assert.NoError(t, err) {
+		return
+	}
+	b, err := io.ReadAll(chunk)
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk", string(b))
+	_, err = chunk.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+
+	name := chunk.(*spooledChunk).Name()
+	assert.NoError(t, chunk.Close())
+	_, err = os.Stat(name)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = SpoolChunk(strings.NewReader("short"), 10)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
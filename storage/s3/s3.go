@@ -664,3 +664,130 @@ func capDurationToLimits(duration time.Duration) time.Duration {
 	}
 	return duration
 }
+
+// InitChunkedUpload starts a multipart upload; each chunk is uploaded as a
+// part of the upload.
+func (s *SimpleStorageService) InitChunkedUpload(
+	ctx context.Context,
+	path string,
+) (string, error) {
+	opts, err := s.optionsFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	rsp, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      opts.BucketName,
+		Key:         aws.String(path),
+		ContentType: s.contentType,
+	}, opts.options)
+	if err != nil {
+		return "", errors.WithMessage(err, "s3: failed to create multipart upload")
+	}
+	return aws.ToString(rsp.UploadId), nil
+}
+
+func (s *SimpleStorageService) PutChunk(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	chunk int,
+	src io.Reader,
+	size int64,
+) error {
+	opts, err := s.optionsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// The request body must be seekable to be signed.
+	body, err := storage.SpoolChunk(src, size)
+	if err != nil {
+		return errors.WithMessage(err, "s3: failed to read part")
+	}
+	defer body.Close()
+	_, err = s.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        opts.BucketName,
+		Key:           aws.String(path),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    int32(chunk + 1),
+		Body:          body,
+		ContentLength: size,
+	}, opts.options)
+	if err != nil {
+		return errors.WithMessage(err, "s3: failed to upload part")
+	}
+	return nil
+}
+
+// CompleteChunkedUpload assembles the first `chunks` parts of the multipart
+// upload into the object.
+func (s *SimpleStorageService) CompleteChunkedUpload(
+	ctx context.Context,
+	path string,
+	uploadID string,
+	chunks int,
+) error {
+	opts, err := s.optionsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	completedParts := make([]types.CompletedPart, 0, chunks)
+	paginator := s3.NewListPartsPaginator(s.client, &s3.ListPartsInput{
+		Bucket:   opts.BucketName,
+		Key:      aws.String(path),
+		UploadId: aws.String(uploadID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx, opts.options)
+		if err != nil {
+			return errors.WithMessage(err, "s3: failed to list parts")
+		}
+		for _, part := range page.Parts {
+			if int(part.PartNumber) > chunks {
+				continue
+			}
+			completedParts = append(completedParts, types.CompletedPart{
+				ETag:       part.ETag,
+				PartNumber: part.PartNumber,
+			})
+		}
+	}
+	if len(completedParts) != chunks {
+		return fmt.Errorf("s3: expected %d parts, found %d", chunks, len(completedParts))
+	}
+	_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   opts.BucketName,
+		Key:      aws.String(path),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completedParts,
+		},
+	}, opts.options)
+	if err != nil {
+		return errors.WithMessage(err, "s3: failed to complete multipart upload")
+	}
+	return nil
+}
+
+func (s *SimpleStorageService) AbortChunkedUpload(
+	ctx context.Context,
+	path string,
+	uploadID string,
+) error {
+	opts, err := s.optionsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   opts.BucketName,
+		Key:      aws.String(path),
+		UploadId: aws.String(uploadID),
+	}, opts.options)
+	var rspErr *awsHttp.ResponseError
+	if errors.As(err, &rspErr) &&
+		rspErr.Response.StatusCode == http.StatusNotFound {
+		return storage.ErrObjectNotFound
+	} else if err != nil {
+		return errors.WithMessage(err, "s3: failed to abort multipart upload")
+	}
+	return nil
+}
@@ -381,3 +381,139 @@ func TestGetObject(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRequest(t *testing.T) {
+	t.Parallel()
+
+	const content = "artifact content"
+	s3c, srv := newTestServerAndClient(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/foo/bar", r.URL.Path)
+			if r.Method == http.MethodHead {
+				w.Header().Set("Content-Length", "1024")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			// the download through the presigned link
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.NotEmpty(t, r.URL.Query().Get("X-Amz-Signature"))
+			http.ServeContent(w, r, "bar.mender", time.Time{}, strings.NewReader(content))
+		},
+	))
+	defer srv.Close()
+
+	link, err := s3c.GetRequest(context.Background(), "foo/bar", "bar.mender", time.Hour)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.MethodGet, link.Method)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), link.Expire, time.Minute)
+
+		linkURL, err := url.Parse(link.Uri)
+		if assert.NoError(t, err) {
+			q := linkURL.Query()
+			// Only the host is signed, so devices can add Range headers to
+			// resume interrupted downloads.
+			assert.Equal(t, "host", q.Get("X-Amz-SignedHeaders"))
+			assert.Equal(t,
+				`attachment; filename="bar.mender"`,
+				q.Get("response-content-disposition"),
+			)
+
+			// resume the download with a Range request
+			linkURL.Scheme = "http"
+			linkURL.Host = srv.Listener.Addr().String()
+			req, _ := http.NewRequest(link.Method, linkURL.String(), nil)
+			req.Header.Set("Range", "bytes=9-")
+			rsp, err := http.DefaultClient.Do(req)
+			if assert.NoError(t, err) {
+				defer rsp.Body.Close()
+				b, _ := io.ReadAll(rsp.Body)
+				assert.Equal(t, http.StatusPartialContent, rsp.StatusCode)
+				assert.Equal(t, content[9:], string(b))
+			}
+		}
+	}
+}
+
+func TestChunkedUpload(t *testing.T) {
+	t.Parallel()
+
+	var parts []string
+	s3c, srv := newTestServerAndClient(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/foo/bar", r.URL.Path)
+			q := r.URL.Query()
+			switch {
+			case r.Method == http.MethodPost && q.Has("uploads"):
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+					`<InitiateMultipartUploadResult><Bucket>bucket</Bucket>` +
+					`<Key>foo/bar</Key><UploadId>upload</UploadId>` +
+					`</InitiateMultipartUploadResult>`))
+
+			case r.Method == http.MethodPut:
+				assert.Equal(t, "upload", q.Get("uploadId"))
+				b, _ := io.ReadAll(r.Body)
+				parts = append(parts, string(b))
+				w.Header().Set("ETag", fmt.Sprintf(`"%s"`, q.Get("partNumber")))
+				w.WriteHeader(http.StatusOK)
+
+			case r.Method == http.MethodGet:
+				assert.Equal(t, "upload", q.Get("uploadId"))
+				var b strings.Builder
+				b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><ListPartsResult>`)
+				for i := range parts {
+					fmt.Fprintf(&b, `<Part><PartNumber>%d</PartNumber>`+
+						`<ETag>"%d"</ETag></Part>`, i+1, i+1)
+				}
+				b.WriteString(`<IsTruncated>false</IsTruncated></ListPartsResult>`)
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(b.String()))
+
+			case r.Method == http.MethodPost:
+				assert.Equal(t, "upload", q.Get("uploadId"))
+				b, _ := io.ReadAll(r.Body)
+				assert.Contains(t, string(b), `<PartNumber>2</PartNumber>`)
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+					`<CompleteMultipartUploadResult><Key>foo/bar</Key>` +
+					`</CompleteMultipartUploadResult>`))
+
+			case r.Method == http.MethodDelete:
+				if q.Get("uploadId") == "upload" {
+					w.WriteHeader(http.StatusNoContent)
+				} else {
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}
+		},
+	))
+	defer srv.Close()
+	ctx := context.Background()
+
+	uploadID, err := s3c.InitChunkedUpload(ctx, "foo/bar")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "upload", uploadID)
+
+	err = s3c.PutChunk(ctx, "foo/bar", uploadID, 0, strings.NewReader("hello "), 6)
+	assert.NoError(t, err)
+	err = s3c.PutChunk(ctx, "foo/bar", uploadID, 1, strings.NewReader("world"), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hello ", "world"}, parts)
+
+	err = s3c.PutChunk(ctx, "foo/bar", uploadID, 2, strings.NewReader("short"), 10)
+	assert.Error(t, err)
+
+	err = s3c.CompleteChunkedUpload(ctx, "foo/bar", uploadID, 3)
+	assert.EqualError(t, err, "s3: expected 3 parts, found 2")
+
+	err = s3c.CompleteChunkedUpload(ctx, "foo/bar", uploadID, 2)
+	assert.NoError(t, err)
+
+	err = s3c.AbortChunkedUpload(ctx, "foo/bar", uploadID)
+	assert.NoError(t, err)
+
+	err = s3c.AbortChunkedUpload(ctx, "foo/bar", "unknown")
+	assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+}
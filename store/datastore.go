@@ -38,26 +38,62 @@ type DataStore interface {
 		artifactToEdit *model.Image,
 		releaseName string,
 	) error
+	UpdateReleaseArtifact(
+		ctx context.Context,
+		artifact *model.Image,
+		releaseName string,
+	) error
 
 	//limits
 	GetLimit(ctx context.Context, name string) (*model.Limit, error)
+	GetStorageUsage(ctx context.Context) (int64, error)
+	IncrementStorageUsage(ctx context.Context, size int64, limit uint64) error
 
 	//storage settings
 	GetStorageSettings(ctx context.Context) (*model.StorageSettings, error)
 	SetStorageSettings(ctx context.Context, storageSettings *model.StorageSettings) error
 
+	//tenant settings
+	GetTenantSettings(ctx context.Context) (*model.TenantSettings, error)
+	SetTenantSettings(ctx context.Context, settings *model.TenantSettings) error
+
+	//artifact retention policy
+	GetRetentionPolicy(ctx context.Context) (*model.RetentionPolicy, error)
+	SetRetentionPolicy(ctx context.Context, policy *model.RetentionPolicy) error
+
+	//public keys
+	InsertPublicKey(ctx context.Context, key *model.PublicKey) error
+	ListPublicKeys(ctx context.Context) ([]model.PublicKey, error)
+	GetPublicKey(ctx context.Context, id string) (*model.PublicKey, error)
+	DeletePublicKey(ctx context.Context, id string) error
+
+	//artifact import jobs
+	InsertImportJob(ctx context.Context, job *model.ImportJob) error
+	GetImportJob(ctx context.Context, id string) (*model.ImportJob, error)
+	UpdateImportJob(ctx context.Context, job *model.ImportJob) error
+	FailStaleImportJobs(ctx context.Context, updatedBefore time.Time, reason string) (int64, error)
+
 	//tenants
 	ProvisionTenant(ctx context.Context, tenantId string) error
 
 	//images
 	Exists(ctx context.Context, id string) (bool, error)
 	Update(ctx context.Context, image *model.Image) (bool, error)
+	UpdateImageArtifactMeta(
+		ctx context.Context,
+		id string,
+		meta *model.ArtifactMeta,
+		change *model.ArtifactMetaChange,
+	) error
 	InsertImage(ctx context.Context, image *model.Image) error
 	FindImageByID(ctx context.Context, id string) (*model.Image, error)
 	IsArtifactUnique(ctx context.Context, artifactName string,
 		deviceTypesCompatible []string) (bool, error)
 	DeleteImage(ctx context.Context, id string) error
 	ListImages(ctx context.Context, filt *model.ReleaseOrImageFilter) ([]*model.Image, int, error)
+	FindImages(ctx context.Context) (Iterator[model.Image], error)
+	SetImageIntegrity(ctx context.Context, id string, checksum string,
+		integrity *model.ImageIntegrity) error
 
 	//artifact getter
 	ImagesByName(ctx context.Context,
@@ -66,11 +102,26 @@ type DataStore interface {
 		ids []string, deviceType string) (*model.Image, error)
 	ImageByNameAndDeviceType(ctx context.Context,
 		name, deviceType string) (*model.Image, error)
+	ImagesByIdsAndDeviceType(ctx context.Context,
+		ids []string, deviceType string) ([]*model.Image, error)
+	ImagesByNameAndDeviceType(ctx context.Context,
+		name, deviceType string) ([]*model.Image, error)
+
+	// artifact objects shared by the images with the same content
+	AddArtifactObjectReference(ctx context.Context, id, path string, size int64,
+		imageID string) (*model.ArtifactObject, error)
+	RemoveArtifactObjectReference(ctx context.Context,
+		id, imageID string) (*model.ArtifactObject, error)
+	DeleteArtifactObject(ctx context.Context, id string) error
 
 	// upload intents
 	InsertUploadIntent(ctx context.Context, link *model.UploadLink) error
 	UpdateUploadIntentStatus(ctx context.Context, id string, from, to model.LinkStatus) error
 	FindUploadLinks(ctx context.Context, expired time.Time) (Iterator[model.UploadLink], error)
+	FindUploadLinkByID(ctx context.Context, id string) (*model.UploadLink, error)
+	LockUploadIntentChunk(ctx context.Context, id string, offset int64, lock model.ChunkLock) error
+	UnlockUploadIntentChunk(ctx context.Context, id, lockID string) error
+	UpdateUploadIntentChunk(ctx context.Context, id, lockID string, offset, size int64) error
 
 	//device deployment log
 	SaveDeviceDeploymentLog(ctx context.Context, log model.DeploymentLog) error
@@ -98,6 +149,11 @@ type DataStore interface {
 		deploymentID string,
 		state model.DeviceDeploymentState,
 	) (model.DeviceDeploymentStatus, error)
+	FindStuckDeviceDeployments(
+		ctx context.Context,
+		status model.DeviceDeploymentStatus,
+		since time.Time,
+	) (Iterator[model.DeviceDeployment], error)
 	UpdateDeviceDeploymentLogAvailability(ctx context.Context,
 		deviceID string, deploymentID string, log bool) error
 	AssignArtifact(
@@ -172,6 +228,13 @@ type DataStore interface {
 		artifactName string,
 		artifactIDs []string,
 	) error
+	GetArtifactsLastDeployed(ctx context.Context) (map[string]time.Time, error)
+	CountDeploymentsByArtifactName(ctx context.Context, artifactName string) (int, error)
+	GetReleaseDeviceDeploymentStats(
+		ctx context.Context,
+		releaseName string,
+	) (map[string]model.Stats, error)
+	GetReleaseInstalledDevices(ctx context.Context, releaseName string) (map[string]int, error)
 
 	GetTenantDbs() ([]string, error)
 	SaveLastDeviceDeploymentStatus(
@@ -189,17 +252,51 @@ type DataStore interface {
 		releaseName string,
 		tags model.Tags,
 	) error
+	PatchReleaseTags(
+		ctx context.Context,
+		releaseName string,
+		patch model.TagsPatch,
+	) error
 	UpdateRelease(
 		ctx context.Context,
 		releaseName string,
 		release model.ReleasePatch,
 	) error
+	DeleteReleases(ctx context.Context, releaseNames []string) error
+	GetReleaseChannels(ctx context.Context) ([]model.ReleaseChannel, error)
+	GetReleaseChannel(ctx context.Context, name string) (*model.ReleaseChannel, error)
+	PromoteReleaseChannel(
+		ctx context.Context,
+		name string,
+		promotion *model.ReleaseChannelPromotion,
+	) error
+	GetReleaseStatus(ctx context.Context, releaseName string) (model.ReleaseStatus, error)
+	GetReleaseNameByAlias(ctx context.Context, alias string) (string, error)
+	GetReleaseNotes(ctx context.Context, releaseName string) (model.Notes, error)
+	InsertReleaseNotesRevision(ctx context.Context, revision *model.ReleaseNotesRevision) error
+	GetReleaseNotesRevisions(
+		ctx context.Context,
+		releaseName string,
+		skip, limit int,
+	) ([]model.ReleaseNotesRevision, int, error)
+	UpdateReleaseStatus(
+		ctx context.Context,
+		releaseName string,
+		from []model.ReleaseStatus,
+		status model.ReleaseStatus,
+	) error
 	ListReleaseTags(ctx context.Context) (model.Tags, error)
+	ListTaggedReleaseNames(ctx context.Context) ([]string, error)
 	SaveUpdateTypes(ctx context.Context, updateTypes []string) error
 	GetUpdateTypes(ctx context.Context) ([]string, error)
 }
 
-var ErrNotFound = errors.New("document not found")
+var (
+	ErrNotFound = errors.New("document not found")
+	ErrConflict = errors.New("document already exists")
+	// ErrLimitExceeded is returned when an update would exceed a limit
+	ErrLimitExceeded = errors.New("limit exceeded")
+)
 
 type Iterator[T interface{}] interface {
 	Next(ctx context.Context) (bool, error)
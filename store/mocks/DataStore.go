@@ -46,6 +46,29 @@ func (_m *DataStore) AbortDeviceDeployments(ctx context.Context, deploymentID st
 	return r0
 }
 
+// AddArtifactObjectReference provides a mock function with given fields: ctx, id, path, size, imageID
+func (_m *DataStore) AddArtifactObjectReference(ctx context.Context, id string, path string, size int64, imageID string) (*model.ArtifactObject, error) {
+	ret := _m.Called(ctx, id, path, size, imageID)
+
+	var r0 *model.ArtifactObject
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, string) *model.ArtifactObject); ok {
+		r0 = rf(ctx, id, path, size, imageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ArtifactObject)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, string) error); ok {
+		r1 = rf(ctx, id, path, size, imageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AggregateDeviceDeploymentByStatus provides a mock function with given fields: ctx, id
 func (_m *DataStore) AggregateDeviceDeploymentByStatus(ctx context.Context, id string) (model.Stats, error) {
 	ret := _m.Called(ctx, id)
@@ -83,6 +106,27 @@ func (_m *DataStore) AssignArtifact(ctx context.Context, deviceID string, deploy
 	return r0
 }
 
+// CountDeploymentsByArtifactName provides a mock function with given fields: ctx, artifactName
+func (_m *DataStore) CountDeploymentsByArtifactName(ctx context.Context, artifactName string) (int, error) {
+	ret := _m.Called(ctx, artifactName)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, artifactName)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, artifactName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DecommissionDeviceDeployments provides a mock function with given fields: ctx, deviceId
 func (_m *DataStore) DecommissionDeviceDeployments(ctx context.Context, deviceId string) error {
 	ret := _m.Called(ctx, deviceId)
@@ -97,6 +141,20 @@ func (_m *DataStore) DecommissionDeviceDeployments(ctx context.Context, deviceId
 	return r0
 }
 
+// DeleteArtifactObject provides a mock function with given fields: ctx, id
+func (_m *DataStore) DeleteArtifactObject(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteDeployment provides a mock function with given fields: ctx, id
 func (_m *DataStore) DeleteDeployment(ctx context.Context, id string) error {
 	ret := _m.Called(ctx, id)
@@ -139,6 +197,34 @@ func (_m *DataStore) DeleteImage(ctx context.Context, id string) error {
 	return r0
 }
 
+// DeletePublicKey provides a mock function with given fields: ctx, id
+func (_m *DataStore) DeletePublicKey(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteReleases provides a mock function with given fields: ctx, releaseNames
+func (_m *DataStore) DeleteReleases(ctx context.Context, releaseNames []string) error {
+	ret := _m.Called(ctx, releaseNames)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, releaseNames)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeviceCountByDeployment provides a mock function with given fields: ctx, id
 func (_m *DataStore) DeviceCountByDeployment(ctx context.Context, id string) (int, error) {
 	ret := _m.Called(ctx, id)
@@ -272,6 +358,27 @@ func (_m *DataStore) Exists(ctx context.Context, id string) (bool, error) {
 	return r0, r1
 }
 
+// FailStaleImportJobs provides a mock function with given fields: ctx, updatedBefore, reason
+func (_m *DataStore) FailStaleImportJobs(ctx context.Context, updatedBefore time.Time, reason string) (int64, error) {
+	ret := _m.Called(ctx, updatedBefore, reason)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) int64); ok {
+		r0 = rf(ctx, updatedBefore, reason)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string) error); ok {
+		r1 = rf(ctx, updatedBefore, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Find provides a mock function with given fields: ctx, query
 func (_m *DataStore) Find(ctx context.Context, query model.Query) ([]*model.Deployment, int64, error) {
 	ret := _m.Called(ctx, query)
@@ -378,6 +485,29 @@ func (_m *DataStore) FindImageByID(ctx context.Context, id string) (*model.Image
 	return r0, r1
 }
 
+// FindImages provides a mock function with given fields: ctx
+func (_m *DataStore) FindImages(ctx context.Context) (store.Iterator[model.Image], error) {
+	ret := _m.Called(ctx)
+
+	var r0 store.Iterator[model.Image]
+	if rf, ok := ret.Get(0).(func(context.Context) store.Iterator[model.Image]); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.Iterator[model.Image])
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindLatestInactiveDeviceDeployment provides a mock function with given fields: ctx, deviceID
 func (_m *DataStore) FindLatestInactiveDeviceDeployment(ctx context.Context, deviceID string) (*model.DeviceDeployment, error) {
 	ret := _m.Called(ctx, deviceID)
@@ -447,6 +577,29 @@ func (_m *DataStore) FindOldestActiveDeviceDeployment(ctx context.Context, devic
 	return r0, r1
 }
 
+// FindStuckDeviceDeployments provides a mock function with given fields: ctx, status, since
+func (_m *DataStore) FindStuckDeviceDeployments(ctx context.Context, status model.DeviceDeploymentStatus, since time.Time) (store.Iterator[model.DeviceDeployment], error) {
+	ret := _m.Called(ctx, status, since)
+
+	var r0 store.Iterator[model.DeviceDeployment]
+	if rf, ok := ret.Get(0).(func(context.Context, model.DeviceDeploymentStatus, time.Time) store.Iterator[model.DeviceDeployment]); ok {
+		r0 = rf(ctx, status, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.Iterator[model.DeviceDeployment])
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, model.DeviceDeploymentStatus, time.Time) error); ok {
+		r1 = rf(ctx, status, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindUnfinishedByID provides a mock function with given fields: ctx, id
 func (_m *DataStore) FindUnfinishedByID(ctx context.Context, id string) (*model.Deployment, error) {
 	ret := _m.Called(ctx, id)
@@ -470,6 +623,29 @@ func (_m *DataStore) FindUnfinishedByID(ctx context.Context, id string) (*model.
 	return r0, r1
 }
 
+// FindUploadLinkByID provides a mock function with given fields: ctx, id
+func (_m *DataStore) FindUploadLinkByID(ctx context.Context, id string) (*model.UploadLink, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.UploadLink
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.UploadLink); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UploadLink)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindUploadLinks provides a mock function with given fields: ctx, expired
 func (_m *DataStore) FindUploadLinks(ctx context.Context, expired time.Time) (store.Iterator[model.UploadLink], error) {
 	ret := _m.Called(ctx, expired)
@@ -493,6 +669,29 @@ func (_m *DataStore) FindUploadLinks(ctx context.Context, expired time.Time) (st
 	return r0, r1
 }
 
+// GetArtifactsLastDeployed provides a mock function with given fields: ctx
+func (_m *DataStore) GetArtifactsLastDeployed(ctx context.Context) (map[string]time.Time, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]time.Time
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]time.Time); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]time.Time)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDeviceDeployment provides a mock function with given fields: ctx, deploymentID, deviceID, includeDeleted
 func (_m *DataStore) GetDeviceDeployment(ctx context.Context, deploymentID string, deviceID string, includeDeleted bool) (*model.DeviceDeployment, error) {
 	ret := _m.Called(ctx, deploymentID, deviceID, includeDeleted)
@@ -645,6 +844,29 @@ func (_m *DataStore) GetDevicesListForDeployment(ctx context.Context, query stor
 	return r0, r1, r2
 }
 
+// GetImportJob provides a mock function with given fields: ctx, id
+func (_m *DataStore) GetImportJob(ctx context.Context, id string) (*model.ImportJob, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.ImportJob
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ImportJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ImportJob)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastDeviceDeploymentStatus provides a mock function with given fields: ctx, devicesIds
 func (_m *DataStore) GetLastDeviceDeploymentStatus(ctx context.Context, devicesIds []string) ([]model.DeviceDeploymentLastStatus, error) {
 	ret := _m.Called(ctx, devicesIds)
@@ -691,46 +913,62 @@ func (_m *DataStore) GetLimit(ctx context.Context, name string) (*model.Limit, e
 	return r0, r1
 }
 
-// GetReleases provides a mock function with given fields: ctx, filt
-func (_m *DataStore) GetReleases(ctx context.Context, filt *model.ReleaseOrImageFilter) ([]model.Release, int, error) {
-	ret := _m.Called(ctx, filt)
+// GetPublicKey provides a mock function with given fields: ctx, id
+func (_m *DataStore) GetPublicKey(ctx context.Context, id string) (*model.PublicKey, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 []model.Release
-	if rf, ok := ret.Get(0).(func(context.Context, *model.ReleaseOrImageFilter) []model.Release); ok {
-		r0 = rf(ctx, filt)
+	var r0 *model.PublicKey
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.PublicKey); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Release)
+			r0 = ret.Get(0).(*model.PublicKey)
 		}
 	}
 
-	var r1 int
-	if rf, ok := ret.Get(1).(func(context.Context, *model.ReleaseOrImageFilter) int); ok {
-		r1 = rf(ctx, filt)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Error(1)
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, *model.ReleaseOrImageFilter) error); ok {
-		r2 = rf(ctx, filt)
+	return r0, r1
+}
+
+// GetReleaseChannel provides a mock function with given fields: ctx, name
+func (_m *DataStore) GetReleaseChannel(ctx context.Context, name string) (*model.ReleaseChannel, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *model.ReleaseChannel
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ReleaseChannel); ok {
+		r0 = rf(ctx, name)
 	} else {
-		r2 = ret.Error(2)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReleaseChannel)
+		}
 	}
 
-	return r0, r1, r2
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetStorageSettings provides a mock function with given fields: ctx
-func (_m *DataStore) GetStorageSettings(ctx context.Context) (*model.StorageSettings, error) {
+// GetReleaseChannels provides a mock function with given fields: ctx
+func (_m *DataStore) GetReleaseChannels(ctx context.Context) ([]model.ReleaseChannel, error) {
 	ret := _m.Called(ctx)
 
-	var r0 *model.StorageSettings
-	if rf, ok := ret.Get(0).(func(context.Context) *model.StorageSettings); ok {
+	var r0 []model.ReleaseChannel
+	if rf, ok := ret.Get(0).(func(context.Context) []model.ReleaseChannel); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.StorageSettings)
+			r0 = ret.Get(0).([]model.ReleaseChannel)
 		}
 	}
 
@@ -744,22 +982,22 @@ func (_m *DataStore) GetStorageSettings(ctx context.Context) (*model.StorageSett
 	return r0, r1
 }
 
-// GetTenantDbs provides a mock function with given fields:
-func (_m *DataStore) GetTenantDbs() ([]string, error) {
-	ret := _m.Called()
+// GetReleaseDeviceDeploymentStats provides a mock function with given fields: ctx, releaseName
+func (_m *DataStore) GetReleaseDeviceDeploymentStats(ctx context.Context, releaseName string) (map[string]model.Stats, error) {
+	ret := _m.Called(ctx, releaseName)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func() []string); ok {
-		r0 = rf()
+	var r0 map[string]model.Stats
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]model.Stats); ok {
+		r0 = rf(ctx, releaseName)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(map[string]model.Stats)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, releaseName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -767,22 +1005,22 @@ func (_m *DataStore) GetTenantDbs() ([]string, error) {
 	return r0, r1
 }
 
-// GetUpdateTypes provides a mock function with given fields: ctx
-func (_m *DataStore) GetUpdateTypes(ctx context.Context) ([]string, error) {
-	ret := _m.Called(ctx)
+// GetReleaseInstalledDevices provides a mock function with given fields: ctx, releaseName
+func (_m *DataStore) GetReleaseInstalledDevices(ctx context.Context, releaseName string) (map[string]int, error) {
+	ret := _m.Called(ctx, releaseName)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
-		r0 = rf(ctx)
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]int); ok {
+		r0 = rf(ctx, releaseName)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(map[string]int)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, releaseName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -790,20 +1028,20 @@ func (_m *DataStore) GetUpdateTypes(ctx context.Context) ([]string, error) {
 	return r0, r1
 }
 
-// HasDeploymentForDevice provides a mock function with given fields: ctx, deploymentID, deviceID
-func (_m *DataStore) HasDeploymentForDevice(ctx context.Context, deploymentID string, deviceID string) (bool, error) {
-	ret := _m.Called(ctx, deploymentID, deviceID)
+// GetReleaseNameByAlias provides a mock function with given fields: ctx, alias
+func (_m *DataStore) GetReleaseNameByAlias(ctx context.Context, alias string) (string, error) {
+	ret := _m.Called(ctx, alias)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
-		r0 = rf(ctx, deploymentID, deviceID)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, alias)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, deploymentID, deviceID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, alias)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -811,22 +1049,20 @@ func (_m *DataStore) HasDeploymentForDevice(ctx context.Context, deploymentID st
 	return r0, r1
 }
 
-// ImageByIdsAndDeviceType provides a mock function with given fields: ctx, ids, deviceType
-func (_m *DataStore) ImageByIdsAndDeviceType(ctx context.Context, ids []string, deviceType string) (*model.Image, error) {
-	ret := _m.Called(ctx, ids, deviceType)
+// GetReleaseNotes provides a mock function with given fields: ctx, releaseName
+func (_m *DataStore) GetReleaseNotes(ctx context.Context, releaseName string) (model.Notes, error) {
+	ret := _m.Called(ctx, releaseName)
 
-	var r0 *model.Image
-	if rf, ok := ret.Get(0).(func(context.Context, []string, string) *model.Image); ok {
-		r0 = rf(ctx, ids, deviceType)
+	var r0 model.Notes
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Notes); ok {
+		r0 = rf(ctx, releaseName)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Image)
-		}
+		r0 = ret.Get(0).(model.Notes)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, []string, string) error); ok {
-		r1 = rf(ctx, ids, deviceType)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, releaseName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -834,13 +1070,274 @@ func (_m *DataStore) ImageByIdsAndDeviceType(ctx context.Context, ids []string,
 	return r0, r1
 }
 
-// ImageByNameAndDeviceType provides a mock function with given fields: ctx, name, deviceType
-func (_m *DataStore) ImageByNameAndDeviceType(ctx context.Context, name string, deviceType string) (*model.Image, error) {
-	ret := _m.Called(ctx, name, deviceType)
+// GetReleaseNotesRevisions provides a mock function with given fields: ctx, releaseName, skip, limit
+func (_m *DataStore) GetReleaseNotesRevisions(ctx context.Context, releaseName string, skip int, limit int) ([]model.ReleaseNotesRevision, int, error) {
+	ret := _m.Called(ctx, releaseName, skip, limit)
 
-	var r0 *model.Image
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Image); ok {
-		r0 = rf(ctx, name, deviceType)
+	var r0 []model.ReleaseNotesRevision
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []model.ReleaseNotesRevision); ok {
+		r0 = rf(ctx, releaseName, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ReleaseNotesRevision)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = rf(ctx, releaseName, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(ctx, releaseName, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReleaseStatus provides a mock function with given fields: ctx, releaseName
+func (_m *DataStore) GetReleaseStatus(ctx context.Context, releaseName string) (model.ReleaseStatus, error) {
+	ret := _m.Called(ctx, releaseName)
+
+	var r0 model.ReleaseStatus
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.ReleaseStatus); ok {
+		r0 = rf(ctx, releaseName)
+	} else {
+		r0 = ret.Get(0).(model.ReleaseStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, releaseName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReleases provides a mock function with given fields: ctx, filt
+func (_m *DataStore) GetReleases(ctx context.Context, filt *model.ReleaseOrImageFilter) ([]model.Release, int, error) {
+	ret := _m.Called(ctx, filt)
+
+	var r0 []model.Release
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ReleaseOrImageFilter) []model.Release); ok {
+		r0 = rf(ctx, filt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Release)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, *model.ReleaseOrImageFilter) int); ok {
+		r1 = rf(ctx, filt)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *model.ReleaseOrImageFilter) error); ok {
+		r2 = rf(ctx, filt)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRetentionPolicy provides a mock function with given fields: ctx
+func (_m *DataStore) GetRetentionPolicy(ctx context.Context) (*model.RetentionPolicy, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.RetentionPolicy
+	if rf, ok := ret.Get(0).(func(context.Context) *model.RetentionPolicy); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.RetentionPolicy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStorageSettings provides a mock function with given fields: ctx
+func (_m *DataStore) GetStorageSettings(ctx context.Context) (*model.StorageSettings, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.StorageSettings
+	if rf, ok := ret.Get(0).(func(context.Context) *model.StorageSettings); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StorageSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStorageUsage provides a mock function with given fields: ctx
+func (_m *DataStore) GetStorageUsage(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTenantDbs provides a mock function with given fields:
+func (_m *DataStore) GetTenantDbs() ([]string, error) {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTenantSettings provides a mock function with given fields: ctx
+func (_m *DataStore) GetTenantSettings(ctx context.Context) (*model.TenantSettings, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.TenantSettings
+	if rf, ok := ret.Get(0).(func(context.Context) *model.TenantSettings); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TenantSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUpdateTypes provides a mock function with given fields: ctx
+func (_m *DataStore) GetUpdateTypes(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasDeploymentForDevice provides a mock function with given fields: ctx, deploymentID, deviceID
+func (_m *DataStore) HasDeploymentForDevice(ctx context.Context, deploymentID string, deviceID string) (bool, error) {
+	ret := _m.Called(ctx, deploymentID, deviceID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, deploymentID, deviceID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, deploymentID, deviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImageByIdsAndDeviceType provides a mock function with given fields: ctx, ids, deviceType
+func (_m *DataStore) ImageByIdsAndDeviceType(ctx context.Context, ids []string, deviceType string) (*model.Image, error) {
+	ret := _m.Called(ctx, ids, deviceType)
+
+	var r0 *model.Image
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) *model.Image); ok {
+		r0 = rf(ctx, ids, deviceType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) error); ok {
+		r1 = rf(ctx, ids, deviceType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImageByNameAndDeviceType provides a mock function with given fields: ctx, name, deviceType
+func (_m *DataStore) ImageByNameAndDeviceType(ctx context.Context, name string, deviceType string) (*model.Image, error) {
+	ret := _m.Called(ctx, name, deviceType)
+
+	var r0 *model.Image
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Image); ok {
+		r0 = rf(ctx, name, deviceType)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Image)
@@ -857,6 +1354,29 @@ func (_m *DataStore) ImageByNameAndDeviceType(ctx context.Context, name string,
 	return r0, r1
 }
 
+// ImagesByIdsAndDeviceType provides a mock function with given fields: ctx, ids, deviceType
+func (_m *DataStore) ImagesByIdsAndDeviceType(ctx context.Context, ids []string, deviceType string) ([]*model.Image, error) {
+	ret := _m.Called(ctx, ids, deviceType)
+
+	var r0 []*model.Image
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) []*model.Image); ok {
+		r0 = rf(ctx, ids, deviceType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) error); ok {
+		r1 = rf(ctx, ids, deviceType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImagesByName provides a mock function with given fields: ctx, artifactName
 func (_m *DataStore) ImagesByName(ctx context.Context, artifactName string) ([]*model.Image, error) {
 	ret := _m.Called(ctx, artifactName)
@@ -880,6 +1400,29 @@ func (_m *DataStore) ImagesByName(ctx context.Context, artifactName string) ([]*
 	return r0, r1
 }
 
+// ImagesByNameAndDeviceType provides a mock function with given fields: ctx, name, deviceType
+func (_m *DataStore) ImagesByNameAndDeviceType(ctx context.Context, name string, deviceType string) ([]*model.Image, error) {
+	ret := _m.Called(ctx, name, deviceType)
+
+	var r0 []*model.Image
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*model.Image); ok {
+		r0 = rf(ctx, name, deviceType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, deviceType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IncrementDeploymentDeviceCount provides a mock function with given fields: ctx, deploymentID, increment
 func (_m *DataStore) IncrementDeploymentDeviceCount(ctx context.Context, deploymentID string, increment int) error {
 	ret := _m.Called(ctx, deploymentID, increment)
@@ -908,6 +1451,20 @@ func (_m *DataStore) IncrementDeploymentTotalSize(ctx context.Context, deploymen
 	return r0
 }
 
+// IncrementStorageUsage provides a mock function with given fields: ctx, size, limit
+func (_m *DataStore) IncrementStorageUsage(ctx context.Context, size int64, limit uint64) error {
+	ret := _m.Called(ctx, size, limit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uint64) error); ok {
+		r0 = rf(ctx, size, limit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertDeployment provides a mock function with given fields: ctx, deployment
 func (_m *DataStore) InsertDeployment(ctx context.Context, deployment *model.Deployment) error {
 	ret := _m.Called(ctx, deployment)
@@ -950,6 +1507,20 @@ func (_m *DataStore) InsertImage(ctx context.Context, image *model.Image) error
 	return r0
 }
 
+// InsertImportJob provides a mock function with given fields: ctx, job
+func (_m *DataStore) InsertImportJob(ctx context.Context, job *model.ImportJob) error {
+	ret := _m.Called(ctx, job)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ImportJob) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertMany provides a mock function with given fields: ctx, deployment
 func (_m *DataStore) InsertMany(ctx context.Context, deployment ...*model.DeviceDeployment) error {
 	_va := make([]interface{}, len(deployment))
@@ -971,6 +1542,34 @@ func (_m *DataStore) InsertMany(ctx context.Context, deployment ...*model.Device
 	return r0
 }
 
+// InsertPublicKey provides a mock function with given fields: ctx, key
+func (_m *DataStore) InsertPublicKey(ctx context.Context, key *model.PublicKey) error {
+	ret := _m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PublicKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertReleaseNotesRevision provides a mock function with given fields: ctx, revision
+func (_m *DataStore) InsertReleaseNotesRevision(ctx context.Context, revision *model.ReleaseNotesRevision) error {
+	ret := _m.Called(ctx, revision)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ReleaseNotesRevision) error); ok {
+		r0 = rf(ctx, revision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertUploadIntent provides a mock function with given fields: ctx, link
 func (_m *DataStore) InsertUploadIntent(ctx context.Context, link *model.UploadLink) error {
 	ret := _m.Called(ctx, link)
@@ -1036,6 +1635,29 @@ func (_m *DataStore) ListImages(ctx context.Context, filt *model.ReleaseOrImageF
 	return r0, r1, r2
 }
 
+// ListPublicKeys provides a mock function with given fields: ctx
+func (_m *DataStore) ListPublicKeys(ctx context.Context) ([]model.PublicKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []model.PublicKey
+	if rf, ok := ret.Get(0).(func(context.Context) []model.PublicKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.PublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListReleaseTags provides a mock function with given fields: ctx
 func (_m *DataStore) ListReleaseTags(ctx context.Context) (model.Tags, error) {
 	ret := _m.Called(ctx)
@@ -1059,6 +1681,57 @@ func (_m *DataStore) ListReleaseTags(ctx context.Context) (model.Tags, error) {
 	return r0, r1
 }
 
+// ListTaggedReleaseNames provides a mock function with given fields: ctx
+func (_m *DataStore) ListTaggedReleaseNames(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockUploadIntentChunk provides a mock function with given fields: ctx, id, offset, lock
+func (_m *DataStore) LockUploadIntentChunk(ctx context.Context, id string, offset int64, lock model.ChunkLock) error {
+	ret := _m.Called(ctx, id, offset, lock)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, model.ChunkLock) error); ok {
+		r0 = rf(ctx, id, offset, lock)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PatchReleaseTags provides a mock function with given fields: ctx, releaseName, patch
+func (_m *DataStore) PatchReleaseTags(ctx context.Context, releaseName string, patch model.TagsPatch) error {
+	ret := _m.Called(ctx, releaseName, patch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.TagsPatch) error); ok {
+		r0 = rf(ctx, releaseName, patch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Ping provides a mock function with given fields: ctx
 func (_m *DataStore) Ping(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -1073,6 +1746,20 @@ func (_m *DataStore) Ping(ctx context.Context) error {
 	return r0
 }
 
+// PromoteReleaseChannel provides a mock function with given fields: ctx, name, promotion
+func (_m *DataStore) PromoteReleaseChannel(ctx context.Context, name string, promotion *model.ReleaseChannelPromotion) error {
+	ret := _m.Called(ctx, name, promotion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.ReleaseChannelPromotion) error); ok {
+		r0 = rf(ctx, name, promotion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ProvisionTenant provides a mock function with given fields: ctx, tenantId
 func (_m *DataStore) ProvisionTenant(ctx context.Context, tenantId string) error {
 	ret := _m.Called(ctx, tenantId)
@@ -1087,6 +1774,29 @@ func (_m *DataStore) ProvisionTenant(ctx context.Context, tenantId string) error
 	return r0
 }
 
+// RemoveArtifactObjectReference provides a mock function with given fields: ctx, id, imageID
+func (_m *DataStore) RemoveArtifactObjectReference(ctx context.Context, id string, imageID string) (*model.ArtifactObject, error) {
+	ret := _m.Called(ctx, id, imageID)
+
+	var r0 *model.ArtifactObject
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.ArtifactObject); ok {
+		r0 = rf(ctx, id, imageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ArtifactObject)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, imageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReplaceReleaseTags provides a mock function with given fields: ctx, releaseName, tags
 func (_m *DataStore) ReplaceReleaseTags(ctx context.Context, releaseName string, tags model.Tags) error {
 	ret := _m.Called(ctx, releaseName, tags)
@@ -1185,6 +1895,34 @@ func (_m *DataStore) SetDeploymentStatus(ctx context.Context, id string, status
 	return r0
 }
 
+// SetImageIntegrity provides a mock function with given fields: ctx, id, checksum, integrity
+func (_m *DataStore) SetImageIntegrity(ctx context.Context, id string, checksum string, integrity *model.ImageIntegrity) error {
+	ret := _m.Called(ctx, id, checksum, integrity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *model.ImageIntegrity) error); ok {
+		r0 = rf(ctx, id, checksum, integrity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRetentionPolicy provides a mock function with given fields: ctx, policy
+func (_m *DataStore) SetRetentionPolicy(ctx context.Context, policy *model.RetentionPolicy) error {
+	ret := _m.Called(ctx, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.RetentionPolicy) error); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetStorageSettings provides a mock function with given fields: ctx, storageSettings
 func (_m *DataStore) SetStorageSettings(ctx context.Context, storageSettings *model.StorageSettings) error {
 	ret := _m.Called(ctx, storageSettings)
@@ -1199,6 +1937,34 @@ func (_m *DataStore) SetStorageSettings(ctx context.Context, storageSettings *mo
 	return r0
 }
 
+// SetTenantSettings provides a mock function with given fields: ctx, settings
+func (_m *DataStore) SetTenantSettings(ctx context.Context, settings *model.TenantSettings) error {
+	ret := _m.Called(ctx, settings)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TenantSettings) error); ok {
+		r0 = rf(ctx, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnlockUploadIntentChunk provides a mock function with given fields: ctx, id, lockID
+func (_m *DataStore) UnlockUploadIntentChunk(ctx context.Context, id string, lockID string) error {
+	ret := _m.Called(ctx, id, lockID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, lockID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, image
 func (_m *DataStore) Update(ctx context.Context, image *model.Image) (bool, error) {
 	ret := _m.Called(ctx, image)
@@ -1269,6 +2035,34 @@ func (_m *DataStore) UpdateDeviceDeploymentStatus(ctx context.Context, deviceID
 	return r0, r1
 }
 
+// UpdateImageArtifactMeta provides a mock function with given fields: ctx, id, meta, change
+func (_m *DataStore) UpdateImageArtifactMeta(ctx context.Context, id string, meta *model.ArtifactMeta, change *model.ArtifactMetaChange) error {
+	ret := _m.Called(ctx, id, meta, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.ArtifactMeta, *model.ArtifactMetaChange) error); ok {
+		r0 = rf(ctx, id, meta, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateImportJob provides a mock function with given fields: ctx, job
+func (_m *DataStore) UpdateImportJob(ctx context.Context, job *model.ImportJob) error {
+	ret := _m.Called(ctx, job)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ImportJob) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateRelease provides a mock function with given fields: ctx, releaseName, release
 func (_m *DataStore) UpdateRelease(ctx context.Context, releaseName string, release model.ReleasePatch) error {
 	ret := _m.Called(ctx, releaseName, release)
@@ -1283,6 +2077,20 @@ func (_m *DataStore) UpdateRelease(ctx context.Context, releaseName string, rele
 	return r0
 }
 
+// UpdateReleaseArtifact provides a mock function with given fields: ctx, artifact, releaseName
+func (_m *DataStore) UpdateReleaseArtifact(ctx context.Context, artifact *model.Image, releaseName string) error {
+	ret := _m.Called(ctx, artifact, releaseName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Image, string) error); ok {
+		r0 = rf(ctx, artifact, releaseName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateReleaseArtifactDescription provides a mock function with given fields: ctx, artifactToEdit, releaseName
 func (_m *DataStore) UpdateReleaseArtifactDescription(ctx context.Context, artifactToEdit *model.Image, releaseName string) error {
 	ret := _m.Called(ctx, artifactToEdit, releaseName)
@@ -1311,6 +2119,20 @@ func (_m *DataStore) UpdateReleaseArtifacts(ctx context.Context, artifactToAdd *
 	return r0
 }
 
+// UpdateReleaseStatus provides a mock function with given fields: ctx, releaseName, from, status
+func (_m *DataStore) UpdateReleaseStatus(ctx context.Context, releaseName string, from []model.ReleaseStatus, status model.ReleaseStatus) error {
+	ret := _m.Called(ctx, releaseName, from, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []model.ReleaseStatus, model.ReleaseStatus) error); ok {
+		r0 = rf(ctx, releaseName, from, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateStats provides a mock function with given fields: ctx, id, stats
 func (_m *DataStore) UpdateStats(ctx context.Context, id string, stats model.Stats) error {
 	ret := _m.Called(ctx, id, stats)
@@ -1339,6 +2161,20 @@ func (_m *DataStore) UpdateStatsInc(ctx context.Context, id string, stateFrom mo
 	return r0
 }
 
+// UpdateUploadIntentChunk provides a mock function with given fields: ctx, id, lockID, offset, size
+func (_m *DataStore) UpdateUploadIntentChunk(ctx context.Context, id string, lockID string, offset int64, size int64) error {
+	ret := _m.Called(ctx, id, lockID, offset, size)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, int64) error); ok {
+		r0 = rf(ctx, id, lockID, offset, size)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUploadIntentStatus provides a mock function with given fields: ctx, id, from, to
 func (_m *DataStore) UpdateUploadIntentStatus(ctx context.Context, id string, from model.LinkStatus, to model.LinkStatus) error {
 	ret := _m.Called(ctx, id, from, to)
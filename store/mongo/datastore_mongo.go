@@ -51,6 +51,12 @@ const (
 	CollectionUploadIntents        = "uploads"
 	CollectionReleases             = "releases"
 	CollectionUpdateTypes          = "update_types"
+	CollectionPublicKeys           = "public_keys"
+	CollectionUsage                = "usage"
+	CollectionImportJobs           = "import_jobs"
+	CollectionArtifactObjects      = "artifact_objects"
+	CollectionReleaseChannels      = "release_channels"
+	CollectionReleaseNotes         = "release_notes"
 )
 
 const DefaultDocumentLimit = 20
@@ -64,7 +70,8 @@ const (
 )
 
 const (
-	mongoOpSet = "$set"
+	mongoOpSet   = "$set"
+	mongoOpUnset = "$unset"
 )
 
 var currentDbVersion map[string]*migrate.Version
@@ -110,6 +117,29 @@ var (
 	// Indexes 1.2.19
 	IndexNameReleaseArtifactsCount = "release_artifacts_count"
 
+	// Indexes 1.2.20
+	IndexDeviceDeploymentActiveStatusName = "active_status_created"
+
+	// Indexes 1.2.21
+	IndexPublicKeyFingerprintName = "fingerprint"
+
+	// Indexes 1.2.24
+	IndexNameReleaseTagsV2 = "release_tags_v2"
+
+	// Indexes 1.2.25
+	IndexNameReleaseText     = "release_text"
+	IndexNameReleaseProvides = "release_provides"
+	IndexNameImageText       = "image_text"
+
+	// Indexes 1.2.26
+	IndexNameReleaseAlias = "release_alias"
+
+	// Indexes 1.2.27
+	IndexNameReleaseNotesHistory = "release_notes_history"
+
+	// Indexes 1.2.28
+	IndexDeviceDeploymentReleaseStatusName = "release_status"
+
 	_false         = false
 	_true          = true
 	StorageIndexes = mongo.IndexModel{
@@ -372,6 +402,12 @@ var (
 	}
 )
 
+// storageKeyTextScore holds the relevance of the documents matching a
+// full-text search.
+const storageKeyTextScore = "score"
+
+var textScore = bson.M{"$meta": "textScore"}
+
 // Errors
 var (
 	ErrImagesStorageInvalidID           = errors.New("Invalid id")
@@ -412,12 +448,36 @@ const (
 	StorageKeyUpdateType       = "meta_artifact.updates.typeinfo.type"
 	StorageKeyImageDescription = "meta.description"
 	StorageKeyImageModified    = "modified"
+	StorageKeyImageChecksum    = "checksum"
+	StorageKeyImageIntegrity   = "integrity"
+
+	StorageKeyImageIntegrityStatus = "integrity.status"
+	StorageKeyImageArtifactMeta    = "meta_artifact"
+	StorageKeyImageArtifactHistory = "meta_artifact_history"
+
+	StorageKeyArtifactObjectPath       = "path"
+	StorageKeyArtifactObjectSize       = "size"
+	StorageKeyArtifactObjectReferences = "references"
+	StorageKeyArtifactObjectRefCount   = "ref_count"
+	StorageKeyArtifactObjectCreated    = "created"
+
+	StorageKeyReleaseChannelName     = "_id"
+	StorageKeyReleaseChannelRelease  = "release_name"
+	StorageKeyReleaseChannelModified = "modified"
+	StorageKeyReleaseChannelHistory  = "history"
+
+	StorageKeyReleaseNotesRelease  = "release_name"
+	StorageKeyReleaseNotesModified = "modified"
 
 	// releases
 	StorageKeyReleaseName                      = "_id"
 	StorageKeyReleaseModified                  = "modified"
 	StorageKeyReleaseTags                      = "tags"
+	StorageKeyReleaseTagsKey                   = StorageKeyReleaseTags + ".key"
+	StorageKeyReleaseTagsValue                 = StorageKeyReleaseTags + ".value"
 	StorageKeyReleaseNotes                     = "notes"
+	StorageKeyReleaseStatus                    = "status"
+	StorageKeyReleaseAlias                     = "alias"
 	StorageKeyReleaseArtifacts                 = "artifacts"
 	StorageKeyReleaseArtifactsCount            = "artifacts_count"
 	StorageKeyReleaseArtifactsIndexDescription = StorageKeyReleaseArtifacts + ".$." +
@@ -442,6 +502,10 @@ const (
 	StorageKeyDeviceDeploymentAssignedImage   = "image"
 	StorageKeyDeviceDeploymentAssignedImageId = StorageKeyDeviceDeploymentAssignedImage +
 		"." + StorageKeyId
+	StorageKeyDeviceDeploymentAssignedImageName = StorageKeyDeviceDeploymentAssignedImage +
+		"." + StorageKeyImageName
+	StorageKeyDeviceDeploymentDeviceType = StorageKeyDeviceDeploymentRequest +
+		".deviceprovides.devicetype"
 
 	StorageKeyDeviceDeploymentActive         = "active"
 	StorageKeyDeviceDeploymentCreated        = "created"
@@ -454,6 +518,9 @@ const (
 	StorageKeyDeviceDeploymentArtifact       = "image"
 	StorageKeyDeviceDeploymentRequest        = "request"
 	StorageKeyDeviceDeploymentDeleted        = "deleted"
+	StorageKeyDeviceDeploymentStatusHistory  = "status_history"
+
+	StorageKeyDeviceDeploymentStatusHistoryTimestamp = "timestamp"
 
 	StorageKeyDeploymentName         = "deploymentconstructor.name"
 	StorageKeyDeploymentArtifactName = "deploymentconstructor.artifactname"
@@ -470,6 +537,13 @@ const (
 	StorageKeyDeploymentTotalSize    = "statistics.total_size"
 
 	StorageKeyStorageSettingsDefaultID      = "settings"
+	StorageKeyTenantSettingsID              = "tenant"
+	StorageKeyRetentionPolicyID             = "retention"
+	StorageKeyUploadChunkedOffset           = "chunked.offset"
+	StorageKeyUploadChunkedChunks           = "chunked.chunks"
+	StorageKeyUploadChunkedLock             = "chunked.lock"
+	StorageKeyUploadChunkedLockID           = "chunked.lock.id"
+	StorageKeyUploadChunkedLockExpire       = "chunked.lock.expire"
 	StorageKeyStorageSettingsBucket         = "bucket"
 	StorageKeyStorageSettingsRegion         = "region"
 	StorageKeyStorageSettingsKey            = "key"
@@ -482,6 +556,11 @@ const (
 
 	StorageKeyStorageReleaseUpdateTypes = "update_types"
 
+	StorageKeyPublicKeyFingerprint = "fingerprint"
+	StorageKeyPublicKeyCreated     = "created"
+
+	StorageKeyUsage = "usage"
+
 	ArtifactDependsDeviceType = "device_type"
 )
 
@@ -752,13 +831,21 @@ func (db *DataStoreMongo) getReleases_1_2_15(
 	}
 
 	opts := &mopts.FindOptions{}
-	opts.SetSort(bson.D{{Key: sortField, Value: sortOrder}})
 	opts.SetSkip(int64((page - 1) * perPage))
 	opts.SetLimit(int64(perPage))
 	projection := bson.M{
 		StorageKeyReleaseImageDependsIdx:  0,
 		StorageKeyReleaseImageProvidesIdx: 0,
 	}
+	if sortField == model.SortFieldRelevance {
+		projection[storageKeyTextScore] = textScore
+		opts.SetSort(bson.D{
+			{Key: storageKeyTextScore, Value: textScore},
+			{Key: StorageKeyReleaseName, Value: 1},
+		})
+	} else {
+		opts.SetSort(bson.D{{Key: sortField, Value: sortOrder}})
+	}
 	opts.SetProjection(projection)
 
 	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
@@ -767,13 +854,17 @@ func (db *DataStoreMongo) getReleases_1_2_15(
 	filter := bson.M{}
 	if filt != nil {
 		if filt.Name != "" {
-			filter[StorageKeyReleaseName] = bson.M{"$regex": primitive.Regex{
+			name := bson.M{"$regex": primitive.Regex{
 				Pattern: regexp.QuoteMeta(filt.Name) + ".*",
 				Options: "i",
 			}}
+			filter["$or"] = bson.A{
+				bson.M{StorageKeyReleaseName: name},
+				bson.M{StorageKeyReleaseAlias: name},
+			}
 		}
 		if len(filt.Tags) > 0 {
-			filter[StorageKeyReleaseTags] = bson.M{"$all": filt.Tags}
+			filter[StorageKeyReleaseTags] = releaseTagsFilter(filt.Tags)
 		}
 		if filt.Description != "" {
 			filter[StorageKeyReleaseArtifactsDescription] = bson.M{"$regex": primitive.Regex{
@@ -787,6 +878,15 @@ func (db *DataStoreMongo) getReleases_1_2_15(
 		if filt.UpdateType != "" {
 			filter[StorageKeyReleaseArtifactsUpdateTypes] = filt.UpdateType
 		}
+		if filt.Status != "" {
+			filter[StorageKeyReleaseStatus] = filt.Status
+		}
+		if len(filt.Provides) > 0 {
+			filter[StorageKeyReleaseImageProvidesIdx] = providesFilter(filt.Provides)
+		}
+		if filt.SearchText != "" {
+			filter["$text"] = bson.M{"$search": filt.SearchText}
+		}
 	}
 	releases := []model.Release{}
 	cursor, err := collReleases.Find(ctx, filter, opts)
@@ -825,6 +925,54 @@ func (db *DataStoreMongo) GetLimit(ctx context.Context, name string) (*model.Lim
 	return limit, nil
 }
 
+// GetStorageUsage returns the total size of the artifacts
+func (db *DataStoreMongo) GetStorageUsage(ctx context.Context) (int64, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collUsage := database.Collection(CollectionUsage)
+
+	var usage struct {
+		Usage int64 `bson:"usage"`
+	}
+	err := collUsage.FindOne(ctx, bson.M{"_id": model.LimitStorage}).
+		Decode(&usage)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+	return usage.Usage, nil
+}
+
+// IncrementStorageUsage adds size (which is negative for removed artifacts)
+// to the storage usage. With a non-zero limit, the usage is only updated if
+// it stays within the limit; otherwise store.ErrLimitExceeded is returned.
+func (db *DataStoreMongo) IncrementStorageUsage(
+	ctx context.Context,
+	size int64,
+	limit uint64,
+) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collUsage := database.Collection(CollectionUsage)
+
+	filter := bson.D{{Key: "_id", Value: model.LimitStorage}}
+	if limit > 0 && size > 0 {
+		if uint64(size) > limit {
+			return store.ErrLimitExceeded
+		}
+		filter = append(filter, bson.E{
+			Key:   StorageKeyUsage,
+			Value: bson.M{"$lte": int64(limit) - size},
+		})
+	}
+	update := bson.M{
+		"$inc": bson.M{StorageKeyUsage: size},
+	}
+	_, err := collUsage.UpdateOne(ctx, filter, update, mopts.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// the usage document exists, but the usage is above the limit
+		return store.ErrLimitExceeded
+	}
+	return err
+}
+
 func (db *DataStoreMongo) ProvisionTenant(ctx context.Context, tenantId string) error {
 
 	dbname := mstore.DbNameForTenant(tenantId, DbName)
@@ -883,6 +1031,61 @@ func (db *DataStoreMongo) Update(ctx context.Context,
 	return true, nil
 }
 
+// UpdateImageArtifactMeta replaces the artifact metadata of the image,
+// reindexing its provides and depends, and records the change in the
+// history of the image. It fails with a *model.ConflictError if an artifact
+// with the same name has the same depends, and with store.ErrNotFound if
+// the image does not exist.
+func (db *DataStoreMongo) UpdateImageArtifactMeta(
+	ctx context.Context,
+	id string,
+	meta *model.ArtifactMeta,
+	change *model.ArtifactMetaChange,
+) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collImg := database.Collection(CollectionImages)
+
+	// add special representation of artifact provides
+	meta.ProvidesIdx = model.ProvidesIdx(meta.Provides)
+
+	res, err := collImg.UpdateOne(ctx,
+		bson.D{{Key: StorageKeyId, Value: id}},
+		bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: StorageKeyImageArtifactMeta, Value: meta},
+				{Key: StorageKeyImageModified, Value: change.Changed},
+			}},
+			{Key: "$push", Value: bson.D{
+				{Key: StorageKeyImageArtifactHistory, Value: change},
+			}},
+		},
+	)
+	if err != nil {
+		var wExc mongo.WriteException
+		if errors.As(err, &wExc) {
+			for _, wErr := range wExc.WriteErrors {
+				if mongo.IsDuplicateKeyError(wErr) {
+					return newDependsConflictError(wErr)
+				}
+			}
+		}
+		return err
+	} else if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// integrityValidFilter skips the images which failed their last
+// verification, see VerifyArtifacts.
+var integrityValidFilter = bson.E{
+	Key: StorageKeyImageIntegrityStatus,
+	Value: bson.D{{Key: "$nin", Value: bson.A{
+		model.IntegrityStatusMissing,
+		model.IntegrityStatusCorrupt,
+	}}},
+}
+
 // ImageByNameAndDeviceType finds image with specified application name and target device type
 func (db *DataStoreMongo) ImageByNameAndDeviceType(ctx context.Context,
 	name, deviceType string) (*model.Image, error) {
@@ -899,6 +1102,7 @@ func (db *DataStoreMongo) ImageByNameAndDeviceType(ctx context.Context,
 	query := bson.M{
 		StorageKeyImageName:        name,
 		StorageKeyImageDeviceTypes: deviceType,
+		integrityValidFilter.Key:   integrityValidFilter.Value,
 	}
 
 	// If multiple entries matches, pick the smallest one.
@@ -937,6 +1141,7 @@ func (db *DataStoreMongo) ImageByIdsAndDeviceType(ctx context.Context,
 	query := bson.D{
 		{Key: StorageKeyId, Value: bson.M{"$in": ids}},
 		{Key: StorageKeyImageDeviceTypes, Value: deviceType},
+		integrityValidFilter,
 	}
 
 	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
@@ -959,6 +1164,67 @@ func (db *DataStoreMongo) ImageByIdsAndDeviceType(ctx context.Context,
 	return &image, nil
 }
 
+// ImagesByIdsAndDeviceType finds the images with id from ids and target
+// device type, smallest first
+func (db *DataStoreMongo) ImagesByIdsAndDeviceType(ctx context.Context,
+	ids []string, deviceType string) ([]*model.Image, error) {
+
+	if len(deviceType) == 0 {
+		return nil, ErrImagesStorageInvalidDeviceType
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrImagesStorageInvalidID
+	}
+
+	return db.findImagesBySize(ctx, bson.D{
+		{Key: StorageKeyId, Value: bson.M{"$in": ids}},
+		{Key: StorageKeyImageDeviceTypes, Value: deviceType},
+		integrityValidFilter,
+	})
+}
+
+// ImagesByNameAndDeviceType finds the images with specified application
+// name and target device type, smallest first
+func (db *DataStoreMongo) ImagesByNameAndDeviceType(ctx context.Context,
+	name, deviceType string) ([]*model.Image, error) {
+
+	if len(name) == 0 {
+		return nil, ErrImagesStorageInvalidArtifactName
+	}
+
+	if len(deviceType) == 0 {
+		return nil, ErrImagesStorageInvalidDeviceType
+	}
+
+	return db.findImagesBySize(ctx, bson.D{
+		{Key: StorageKeyImageName, Value: name},
+		{Key: StorageKeyImageDeviceTypes, Value: deviceType},
+		integrityValidFilter,
+	})
+}
+
+func (db *DataStoreMongo) findImagesBySize(
+	ctx context.Context,
+	query bson.D,
+) ([]*model.Image, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collImg := database.Collection(CollectionImages)
+
+	findOpts := mopts.Find()
+	findOpts.SetSort(bson.D{{Key: StorageKeyImageSize, Value: 1}})
+
+	cursor, err := collImg.Find(ctx, query, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	images := []*model.Image{}
+	if err = cursor.All(ctx, &images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
 // ImagesByName finds images with specified artifact name
 func (db *DataStoreMongo) ImagesByName(
 	ctx context.Context, name string) ([]*model.Image, error) {
@@ -1090,6 +1356,137 @@ func (db *DataStoreMongo) UpdateUploadIntentStatus(
 	return nil
 }
 
+func (db *DataStoreMongo) FindUploadLinkByID(
+	ctx context.Context,
+	id string,
+) (*model.UploadLink, error) {
+	collUploads := db.client.
+		Database(DatabaseName).
+		Collection(CollectionUploadIntents)
+	q := bson.D{{Key: "_id", Value: id}}
+	if idty := identity.FromContext(ctx); idty != nil {
+		q = append(q, bson.E{
+			Key:   StorageKeyTenantId,
+			Value: idty.Tenant,
+		})
+	}
+	link := new(model.UploadLink)
+	err := collUploads.FindOne(ctx, q).Decode(link)
+	if err == mongo.ErrNoDocuments {
+		return nil, store.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// UpdateUploadIntentChunk records a chunk of the given size received at the
+// given offset of a pending chunked upload; it fails with store.ErrNotFound
+// if the offset of the upload is not the given offset.
+// LockUploadIntentChunk takes the lock of the pending chunked upload for
+// writing the chunk at `offset`; it fails with store.ErrNotFound if the
+// upload is not at that offset or another chunk holds a lock which has not
+// expired.
+func (db *DataStoreMongo) LockUploadIntentChunk(
+	ctx context.Context,
+	id string,
+	offset int64,
+	lock model.ChunkLock,
+) error {
+	collUploads := db.client.
+		Database(DatabaseName).
+		Collection(CollectionUploadIntents)
+	q := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "status", Value: model.LinkStatusPending},
+		{Key: StorageKeyUploadChunkedOffset, Value: offset},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: StorageKeyUploadChunkedLock, Value: nil}},
+			bson.D{{Key: StorageKeyUploadChunkedLockExpire, Value: bson.D{
+				{Key: "$lt", Value: time.Now()},
+			}}},
+		}},
+	}
+	if idty := identity.FromContext(ctx); idty != nil {
+		q = append(q, bson.E{
+			Key:   StorageKeyTenantId,
+			Value: idty.Tenant,
+		})
+	}
+	res, err := collUploads.UpdateOne(ctx, q, bson.D{
+		{Key: "$set", Value: bson.D{{Key: StorageKeyUploadChunkedLock, Value: lock}}},
+	})
+	if err != nil {
+		return err
+	} else if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// UnlockUploadIntentChunk releases the lock of the chunked upload, if
+// still held with the given ID.
+func (db *DataStoreMongo) UnlockUploadIntentChunk(
+	ctx context.Context,
+	id, lockID string,
+) error {
+	collUploads := db.client.
+		Database(DatabaseName).
+		Collection(CollectionUploadIntents)
+	q := bson.D{
+		{Key: "_id", Value: id},
+		{Key: StorageKeyUploadChunkedLockID, Value: lockID},
+	}
+	if idty := identity.FromContext(ctx); idty != nil {
+		q = append(q, bson.E{
+			Key:   StorageKeyTenantId,
+			Value: idty.Tenant,
+		})
+	}
+	_, err := collUploads.UpdateOne(ctx, q, bson.D{
+		{Key: "$unset", Value: bson.D{{Key: StorageKeyUploadChunkedLock, Value: ""}}},
+	})
+	return err
+}
+
+// UpdateUploadIntentChunk records the chunk written at `offset` under the
+// lock with the given ID, and releases the lock.
+func (db *DataStoreMongo) UpdateUploadIntentChunk(
+	ctx context.Context,
+	id, lockID string,
+	offset, size int64,
+) error {
+	collUploads := db.client.
+		Database(DatabaseName).
+		Collection(CollectionUploadIntents)
+	q := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "status", Value: model.LinkStatusPending},
+		{Key: StorageKeyUploadChunkedOffset, Value: offset},
+		{Key: StorageKeyUploadChunkedLockID, Value: lockID},
+	}
+	if idty := identity.FromContext(ctx); idty != nil {
+		q = append(q, bson.E{
+			Key:   StorageKeyTenantId,
+			Value: idty.Tenant,
+		})
+	}
+	res, err := collUploads.UpdateOne(ctx, q, bson.D{
+		{Key: "$set", Value: bson.D{{Key: "updated_ts", Value: time.Now()}}},
+		{Key: "$inc", Value: bson.D{
+			{Key: StorageKeyUploadChunkedOffset, Value: size},
+			{Key: StorageKeyUploadChunkedChunks, Value: 1},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: StorageKeyUploadChunkedLock, Value: ""}}},
+	})
+	if err != nil {
+		return err
+	} else if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
 func (db *DataStoreMongo) FindUploadLinks(
 	ctx context.Context,
 	expiredAt time.Time,
@@ -1144,6 +1541,48 @@ func (db *DataStoreMongo) FindImageByID(ctx context.Context,
 	return &image, nil
 }
 
+// FindImages returns an iterator over all the images
+func (db *DataStoreMongo) FindImages(
+	ctx context.Context,
+) (store.Iterator[model.Image], error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collImg := database.Collection(CollectionImages)
+	opts := mopts.Find().
+		SetSort(bson.D{{Key: StorageKeyId, Value: 1}}).
+		SetProjection(bson.D{
+			{Key: StorageKeyImageDependsIdx, Value: 0},
+			{Key: StorageKeyImageProvidesIdx, Value: 0},
+		})
+	cur, err := collImg.Find(ctx, bson.D{}, opts)
+	return IteratorFromCursor[model.Image](cur), err
+}
+
+// SetImageIntegrity records the result of the verification of the image;
+// a non-empty checksum also sets the checksum of the image.
+func (db *DataStoreMongo) SetImageIntegrity(
+	ctx context.Context,
+	id string,
+	checksum string,
+	integrity *model.ImageIntegrity,
+) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collImg := database.Collection(CollectionImages)
+	update := bson.D{{Key: StorageKeyImageIntegrity, Value: integrity}}
+	if checksum != "" {
+		update = append(update, bson.E{Key: StorageKeyImageChecksum, Value: checksum})
+	}
+	res, err := collImg.UpdateOne(ctx,
+		bson.D{{Key: StorageKeyId, Value: id}},
+		bson.D{{Key: "$set", Value: update}},
+	)
+	if err != nil {
+		return err
+	} else if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
 // IsArtifactUnique checks if there is no artifact with the same artifactName
 // supporting one of the device types from deviceTypesCompatible list.
 // Returns true, nil if artifact is unique;
@@ -1221,6 +1660,9 @@ func (db *DataStoreMongo) DeleteImage(ctx context.Context, id string) error {
 	return nil
 }
 
+// getReleaseSortFieldAndOrder returns the sort field and order of the
+// filter; the results of a full-text search are sorted by relevance unless
+// sorted otherwise.
 func getReleaseSortFieldAndOrder(filt *model.ReleaseOrImageFilter) (string, int) {
 	if filt != nil && filt.Sort != "" {
 		sortParts := strings.SplitN(filt.Sort, ":", 2)
@@ -1228,7 +1670,8 @@ func getReleaseSortFieldAndOrder(filt *model.ReleaseOrImageFilter) (string, int)
 			(sortParts[0] == "name" ||
 				sortParts[0] == "modified" ||
 				sortParts[0] == "artifacts_count" ||
-				sortParts[0] == "tags") {
+				sortParts[0] == "tags" ||
+				(sortParts[0] == model.SortFieldRelevance && filt.SearchText != "")) {
 			sortField := sortParts[0]
 			sortOrder := 1
 			if sortParts[1] == model.SortDirectionDescending {
@@ -1237,9 +1680,32 @@ func getReleaseSortFieldAndOrder(filt *model.ReleaseOrImageFilter) (string, int)
 			return sortField, sortOrder
 		}
 	}
+	if filt != nil && filt.SearchText != "" {
+		return model.SortFieldRelevance, -1
+	}
 	return "", 0
 }
 
+// providesFilter matches the documents whose provides index satisfies
+// every provides filter, see model.ParseProvidesFilter.
+func providesFilter(filters []string) bson.M {
+	conditions := make(bson.A, 0, len(filters))
+	for _, filter := range filters {
+		provides, err := model.ParseProvidesFilter(filter)
+		if err != nil {
+			continue
+		}
+		condition := bson.M{"key": provides.Key}
+		if provides.HasWildcard() {
+			condition["value"] = primitive.Regex{Pattern: provides.ValuePattern()}
+		} else if provides.Value != "" {
+			condition["value"] = provides.Value
+		}
+		conditions = append(conditions, bson.M{"$elemMatch": condition})
+	}
+	return bson.M{"$all": conditions}
+}
+
 // ListImages lists all images
 func (db *DataStoreMongo) ListImages(
 	ctx context.Context,
@@ -1275,7 +1741,12 @@ func (db *DataStoreMongo) ListImages(
 				},
 			}
 		}
-
+		if len(filt.Provides) > 0 {
+			filters[StorageKeyImageProvidesIdx] = providesFilter(filt.Provides)
+		}
+		if filt.SearchText != "" {
+			filters["$text"] = bson.M{"$search": filt.SearchText}
+		}
 	}
 
 	projection := bson.M{
@@ -1296,10 +1767,19 @@ func (db *DataStoreMongo) ListImages(
 	if sortOrder == 0 {
 		sortOrder = 1
 	}
-	findOptions.SetSort(bson.D{
-		{Key: sortField, Value: sortOrder},
-		{Key: "_id", Value: sortOrder},
-	})
+	if sortField == model.SortFieldRelevance {
+		projection[storageKeyTextScore] = textScore
+		findOptions.SetSort(bson.D{
+			{Key: storageKeyTextScore, Value: textScore},
+			{Key: StorageKeyImageName, Value: 1},
+			{Key: "_id", Value: 1},
+		})
+	} else {
+		findOptions.SetSort(bson.D{
+			{Key: sortField, Value: sortOrder},
+			{Key: "_id", Value: sortOrder},
+		})
+	}
 
 	cursor, err := collImg.Find(ctx, filters, findOptions)
 	if err != nil {
@@ -1610,8 +2090,21 @@ func (db *DataStoreMongo) UpdateDeviceDeploymentStatus(
 		set[StorageKeyDeviceDeploymentSubState] = ddState.SubState
 	}
 
+	// and record the transition
+	transition := model.DeviceDeploymentStatusTransition{
+		Status:    ddState.Status,
+		SubState:  ddState.SubState,
+		Timestamp: time.Now(),
+	}
+	if ddState.FinishTime != nil {
+		transition.Timestamp = *ddState.FinishTime
+	}
+
 	update := bson.D{
 		{Key: "$set", Value: set},
+		{Key: "$push", Value: bson.M{
+			StorageKeyDeviceDeploymentStatusHistory: transition,
+		}},
 	}
 
 	var old model.DeviceDeployment
@@ -1628,6 +2121,52 @@ func (db *DataStoreMongo) UpdateDeviceDeploymentStatus(
 	return old.Status, nil
 }
 
+// FindStuckDeviceDeployments returns the active device deployments which have
+// been in the given status since before `since`.
+func (db *DataStoreMongo) FindStuckDeviceDeployments(
+	ctx context.Context,
+	status model.DeviceDeploymentStatus,
+	since time.Time,
+) (store.Iterator[model.DeviceDeployment], error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collDevs := database.Collection(CollectionDevices)
+
+	// device deployments created before the status transitions were
+	// recorded fall back to the creation time
+	filter := bson.D{
+		{Key: StorageKeyDeviceDeploymentActive, Value: true},
+		{Key: StorageKeyDeviceDeploymentStatus, Value: status},
+		{Key: StorageKeyDeviceDeploymentCreated, Value: bson.D{
+			{Key: "$lt", Value: since},
+		}},
+		{Key: StorageKeyDeviceDeploymentStatusHistory, Value: bson.D{
+			{Key: "$not", Value: bson.D{
+				{Key: "$elemMatch", Value: bson.D{
+					{Key: StorageKeyDeviceDeploymentStatusHistoryTimestamp, Value: bson.D{
+						{Key: "$gte", Value: since},
+					}},
+				}},
+			}},
+		}},
+		{Key: StorageKeyDeviceDeploymentDeleted, Value: bson.D{
+			{Key: "$exists", Value: false},
+		}},
+	}
+	opts := mopts.Find().
+		SetProjection(bson.D{
+			{Key: StorageKeyDeviceDeploymentDeviceId, Value: 1},
+			{Key: StorageKeyDeviceDeploymentDeploymentID, Value: 1},
+			{Key: StorageKeyDeviceDeploymentStatus, Value: 1},
+			{Key: StorageKeyDeviceDeploymentCreated, Value: 1},
+		})
+
+	cur, err := collDevs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	return IteratorFromCursor[model.DeviceDeployment](cur), nil
+}
+
 func (db *DataStoreMongo) UpdateDeviceDeploymentLogAvailability(ctx context.Context,
 	deviceID string, deploymentID string, log bool) error {
 
@@ -2063,6 +2602,12 @@ func (db *DataStoreMongo) AbortDeviceDeployments(ctx context.Context,
 			StorageKeyDeviceDeploymentStatus: model.DeviceDeploymentStatusAborted,
 			StorageKeyDeviceDeploymentActive: false,
 		},
+		"$push": bson.M{
+			StorageKeyDeviceDeploymentStatusHistory: model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusAborted,
+				Timestamp: time.Now(),
+			},
+		},
 	}
 
 	if _, err := collDevs.UpdateMany(ctx, selector, update); err != nil {
@@ -2124,6 +2669,12 @@ func (db *DataStoreMongo) DecommissionDeviceDeployments(ctx context.Context,
 			StorageKeyDeviceDeploymentStatus: model.DeviceDeploymentStatusDecommissioned,
 			StorageKeyDeviceDeploymentActive: false,
 		},
+		"$push": bson.M{
+			StorageKeyDeviceDeploymentStatusHistory: model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusDecommissioned,
+				Timestamp: time.Now(),
+			},
+		},
 	}
 
 	if _, err := collDevs.UpdateMany(ctx, selector, update); err != nil {
@@ -2926,6 +3477,206 @@ func (db *DataStoreMongo) SetStorageSettings(
 	return err
 }
 
+// Per-tenant deployment settings, kept next to the storage settings
+func (db *DataStoreMongo) GetTenantSettings(ctx context.Context) (*model.TenantSettings, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionStorageSettings)
+
+	settings := new(model.TenantSettings)
+	query := bson.M{
+		"_id": StorageKeyTenantSettingsID,
+	}
+	if err := collection.FindOne(ctx, query).Decode(settings); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// SetTenantSettings replaces the tenant settings as a whole: settings which
+// are not set are removed.
+func (db *DataStoreMongo) SetTenantSettings(
+	ctx context.Context,
+	settings *model.TenantSettings,
+) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionStorageSettings)
+
+	filter := bson.M{
+		"_id": StorageKeyTenantSettingsID,
+	}
+	replaceOptions := mopts.Replace()
+	replaceOptions.SetUpsert(true)
+	_, err := collection.ReplaceOne(ctx, filter, settings, replaceOptions)
+
+	return err
+}
+
+func (db *DataStoreMongo) GetRetentionPolicy(
+	ctx context.Context,
+) (*model.RetentionPolicy, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionStorageSettings)
+
+	policy := new(model.RetentionPolicy)
+	query := bson.M{
+		"_id": StorageKeyRetentionPolicyID,
+	}
+	if err := collection.FindOne(ctx, query).Decode(policy); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (db *DataStoreMongo) SetRetentionPolicy(
+	ctx context.Context,
+	policy *model.RetentionPolicy,
+) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionStorageSettings)
+
+	filter := bson.M{
+		"_id": StorageKeyRetentionPolicyID,
+	}
+	replaceOptions := mopts.Replace()
+	replaceOptions.SetUpsert(true)
+	_, err := collection.ReplaceOne(ctx, filter, policy, replaceOptions)
+
+	return err
+}
+
+// Public keys trusted to sign the tenant's artifacts
+
+func (db *DataStoreMongo) InsertPublicKey(ctx context.Context, key *model.PublicKey) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionPublicKeys)
+
+	_, err := collection.InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	}
+	return err
+}
+
+func (db *DataStoreMongo) ListPublicKeys(ctx context.Context) ([]model.PublicKey, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionPublicKeys)
+
+	findOptions := mopts.Find().
+		SetSort(bson.D{{Key: StorageKeyPublicKeyCreated, Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []model.PublicKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (db *DataStoreMongo) GetPublicKey(ctx context.Context, id string) (*model.PublicKey, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionPublicKeys)
+
+	key := new(model.PublicKey)
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(key)
+	if err == mongo.ErrNoDocuments {
+		return nil, store.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (db *DataStoreMongo) DeletePublicKey(ctx context.Context, id string) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionPublicKeys)
+
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	} else if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// Artifact import jobs
+
+func (db *DataStoreMongo) InsertImportJob(ctx context.Context, job *model.ImportJob) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionImportJobs)
+
+	_, err := collection.InsertOne(ctx, job)
+	return err
+}
+
+func (db *DataStoreMongo) GetImportJob(ctx context.Context, id string) (*model.ImportJob, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionImportJobs)
+
+	job := new(model.ImportJob)
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(job)
+	if err == mongo.ErrNoDocuments {
+		return nil, store.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (db *DataStoreMongo) UpdateImportJob(ctx context.Context, job *model.ImportJob) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionImportJobs)
+
+	res, err := collection.ReplaceOne(ctx, bson.M{"_id": job.ID}, job)
+	if err != nil {
+		return err
+	} else if res.MatchedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// FailStaleImportJobs marks as failed the import jobs still pending or
+// processing which were not updated since `updatedBefore`, and returns how
+// many were marked.
+func (db *DataStoreMongo) FailStaleImportJobs(
+	ctx context.Context,
+	updatedBefore time.Time,
+	reason string,
+) (int64, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collection := database.Collection(CollectionImportJobs)
+
+	res, err := collection.UpdateMany(ctx, bson.M{
+		"status": bson.M{"$in": []model.ImportJobStatus{
+			model.ImportJobStatusPending,
+			model.ImportJobStatusProcessing,
+		}},
+		"updated": bson.M{"$lt": updatedBefore},
+	}, bson.M{
+		"$set": bson.M{
+			"status":  model.ImportJobStatusFailed,
+			"error":   reason,
+			"updated": time.Now(),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
 func (db *DataStoreMongo) UpdateDeploymentsWithArtifactName(
 	ctx context.Context,
 	artifactName string,
@@ -2948,6 +3699,157 @@ func (db *DataStoreMongo) UpdateDeploymentsWithArtifactName(
 	return err
 }
 
+// GetArtifactsLastDeployed returns the creation time of the newest
+// deployment of each deployed artifact, indexed by artifact ID.
+func (db *DataStoreMongo) GetArtifactsLastDeployed(
+	ctx context.Context,
+) (map[string]time.Time, error) {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collDpl := database.Collection(CollectionDeployments)
+
+	pipeline := []bson.D{
+		{{Key: "$unwind", Value: "$" + StorageKeyDeploymentArtifacts}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + StorageKeyDeploymentArtifacts},
+			{Key: "last", Value: bson.D{
+				{Key: "$max", Value: "$" + StorageKeyDeploymentCreated},
+			}},
+		}}},
+	}
+	cursor, err := collDpl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		ArtifactID string    `bson:"_id"`
+		Last       time.Time `bson:"last"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	lastDeployed := make(map[string]time.Time, len(results))
+	for _, result := range results {
+		lastDeployed[result.ArtifactID] = result.Last
+	}
+	return lastDeployed, nil
+}
+
+// Artifact objects
+
+func artifactObjectReference(ctx context.Context, imageID string) model.ArtifactObjectReference {
+	ref := model.ArtifactObjectReference{ImageID: imageID}
+	if idty := identity.FromContext(ctx); idty != nil {
+		ref.TenantID = idty.Tenant
+	}
+	return ref
+}
+
+// AddArtifactObjectReference adds the image of the tenant in the context to
+// the references of the artifact object with the given ID, creating the
+// object stored at the given path if it does not exist yet. It returns the
+// artifact object, whose path differs from the given one when the object
+// already existed.
+func (db *DataStoreMongo) AddArtifactObjectReference(
+	ctx context.Context,
+	id string,
+	path string,
+	size int64,
+	imageID string,
+) (*model.ArtifactObject, error) {
+	collObjects := db.client.
+		Database(DatabaseName).
+		Collection(CollectionArtifactObjects)
+	update := bson.D{
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: StorageKeyArtifactObjectPath, Value: path},
+			{Key: StorageKeyArtifactObjectSize, Value: size},
+			{Key: StorageKeyArtifactObjectCreated, Value: time.Now()},
+		}},
+		{Key: "$push", Value: bson.D{
+			{Key: StorageKeyArtifactObjectReferences,
+				Value: artifactObjectReference(ctx, imageID)},
+		}},
+		{Key: "$inc", Value: bson.D{
+			{Key: StorageKeyArtifactObjectRefCount, Value: 1},
+		}},
+	}
+	opts := mopts.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(mopts.After)
+	var (
+		obj model.ArtifactObject
+		err error
+	)
+	for i := 0; i < 2; i++ {
+		err = collObjects.FindOneAndUpdate(ctx,
+			bson.D{{Key: StorageKeyId, Value: id}}, update, opts,
+		).Decode(&obj)
+		// concurrent upserts of the same object: retry the update
+		if !mongo.IsDuplicateKeyError(err) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// RemoveArtifactObjectReference removes the image of the tenant in the
+// context from the references of the artifact object with the given ID and
+// returns the artifact object; it fails with store.ErrNotFound if the image
+// does not reference the object.
+func (db *DataStoreMongo) RemoveArtifactObjectReference(
+	ctx context.Context,
+	id string,
+	imageID string,
+) (*model.ArtifactObject, error) {
+	collObjects := db.client.
+		Database(DatabaseName).
+		Collection(CollectionArtifactObjects)
+	ref := artifactObjectReference(ctx, imageID)
+	filter := bson.D{
+		{Key: StorageKeyId, Value: id},
+		{Key: StorageKeyArtifactObjectReferences, Value: ref},
+	}
+	update := bson.D{
+		{Key: "$pull", Value: bson.D{
+			{Key: StorageKeyArtifactObjectReferences, Value: ref},
+		}},
+		{Key: "$inc", Value: bson.D{
+			{Key: StorageKeyArtifactObjectRefCount, Value: -1},
+		}},
+	}
+	var obj model.ArtifactObject
+	err := collObjects.FindOneAndUpdate(ctx, filter, update,
+		mopts.FindOneAndUpdate().SetReturnDocument(mopts.After),
+	).Decode(&obj)
+	if err == mongo.ErrNoDocuments {
+		return nil, store.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// DeleteArtifactObject deletes the artifact object with the given ID if no
+// image references it; it fails with store.ErrNotFound otherwise.
+func (db *DataStoreMongo) DeleteArtifactObject(ctx context.Context, id string) error {
+	collObjects := db.client.
+		Database(DatabaseName).
+		Collection(CollectionArtifactObjects)
+	res, err := collObjects.DeleteOne(ctx, bson.D{
+		{Key: StorageKeyId, Value: id},
+		{Key: StorageKeyArtifactObjectRefCount, Value: bson.D{{Key: "$lte", Value: 0}}},
+	})
+	if err != nil {
+		return err
+	} else if res.DeletedCount == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
 func (db *DataStoreMongo) GetTenantDbs() ([]string, error) {
 	return migrate.GetTenantDbs(context.Background(), db.client, mstore.IsTenantDb(DbName))
 }
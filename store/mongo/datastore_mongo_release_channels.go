@@ -0,0 +1,106 @@
+// Copyright 2023 Northern.tech AS
+//
+//	Licensed under the Apache License, Version 2.0 (the "License");
+//	you may not use this file except in compliance with the License.
+//	You may obtain a copy of the License at
+//
+//	    http://www.apache.org/licenses/LICENSE-2.0
+//
+//	Unless required by applicable law or agreed to in writing, software
+//	distributed under the License is distributed on an "AS IS" BASIS,
+//	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//	See the License for the specific language governing permissions and
+//	limitations under the License.
+package mongo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+
+	mstore "github.com/mendersoftware/go-lib-micro/store"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+)
+
+// GetReleaseChannels returns the release channels sorted by name, without
+// their promotion history.
+func (db *DataStoreMongo) GetReleaseChannels(
+	ctx context.Context,
+) ([]model.ReleaseChannel, error) {
+	collChannels := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleaseChannels)
+
+	cursor, err := collChannels.Find(ctx, bson.M{},
+		mopts.Find().
+			SetSort(bson.M{StorageKeyReleaseChannelName: 1}).
+			SetProjection(bson.M{StorageKeyReleaseChannelHistory: 0}),
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "mongo: failed to list release channels")
+	}
+	channels := []model.ReleaseChannel{}
+	if err := cursor.All(ctx, &channels); err != nil {
+		return nil, errors.WithMessage(err, "mongo: failed to decode release channels")
+	}
+	return channels, nil
+}
+
+// GetReleaseChannel returns the release channel with the given name.
+func (db *DataStoreMongo) GetReleaseChannel(
+	ctx context.Context,
+	name string,
+) (*model.ReleaseChannel, error) {
+	collChannels := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleaseChannels)
+
+	var channel model.ReleaseChannel
+	err := collChannels.FindOne(ctx,
+		bson.M{StorageKeyReleaseChannelName: name},
+	).Decode(&channel)
+	if err == mongo.ErrNoDocuments {
+		return nil, store.ErrNotFound
+	} else if err != nil {
+		return nil, errors.WithMessage(err, "mongo: failed to get the release channel")
+	}
+	return &channel, nil
+}
+
+// PromoteReleaseChannel points the release channel, created if needed, at
+// the promoted release, and appends the promotion to its history.
+func (db *DataStoreMongo) PromoteReleaseChannel(
+	ctx context.Context,
+	name string,
+	promotion *model.ReleaseChannelPromotion,
+) error {
+	collChannels := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleaseChannels)
+
+	_, err := collChannels.UpdateOne(ctx,
+		bson.M{StorageKeyReleaseChannelName: name},
+		bson.M{
+			mongoOpSet: bson.M{
+				StorageKeyReleaseChannelRelease:  promotion.ReleaseName,
+				StorageKeyReleaseChannelModified: promotion.Promoted,
+			},
+			"$push": bson.M{
+				StorageKeyReleaseChannelHistory: bson.M{
+					"$each":  []*model.ReleaseChannelPromotion{promotion},
+					"$slice": -model.ReleaseChannelHistoryMax,
+				},
+			},
+		},
+		mopts.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return errors.WithMessage(err, "mongo: failed to promote the release channel")
+	}
+	return nil
+}
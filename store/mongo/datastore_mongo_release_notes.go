@@ -0,0 +1,99 @@
+// Copyright 2023 Northern.tech AS
+//
+//	Licensed under the Apache License, Version 2.0 (the "License");
+//	you may not use this file except in compliance with the License.
+//	You may obtain a copy of the License at
+//
+//	    http://www.apache.org/licenses/LICENSE-2.0
+//
+//	Unless required by applicable law or agreed to in writing, software
+//	distributed under the License is distributed on an "AS IS" BASIS,
+//	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//	See the License for the specific language governing permissions and
+//	limitations under the License.
+package mongo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+
+	mstore "github.com/mendersoftware/go-lib-micro/store"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+)
+
+// GetReleaseNotes returns the notes of the release with the given name.
+func (db *DataStoreMongo) GetReleaseNotes(
+	ctx context.Context,
+	releaseName string,
+) (model.Notes, error) {
+	collReleases := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleases)
+
+	var release model.Release
+	err := collReleases.FindOne(ctx,
+		bson.M{StorageKeyReleaseName: releaseName},
+		mopts.FindOne().SetProjection(bson.M{StorageKeyReleaseNotes: 1}),
+	).Decode(&release)
+	if err == mongo.ErrNoDocuments {
+		return "", store.ErrNotFound
+	} else if err != nil {
+		return "", errors.WithMessage(err, "mongo: failed to get the release notes")
+	}
+	return release.Notes, nil
+}
+
+// InsertReleaseNotesRevision appends the revision to the notes history of
+// its release.
+func (db *DataStoreMongo) InsertReleaseNotesRevision(
+	ctx context.Context,
+	revision *model.ReleaseNotesRevision,
+) error {
+	collNotes := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleaseNotes)
+
+	_, err := collNotes.InsertOne(ctx, revision)
+	if err != nil {
+		return errors.WithMessage(err, "mongo: failed to insert the release notes revision")
+	}
+	return nil
+}
+
+// GetReleaseNotesRevisions returns a page of the notes history of the
+// release, newest first, and the total number of revisions.
+func (db *DataStoreMongo) GetReleaseNotesRevisions(
+	ctx context.Context,
+	releaseName string,
+	skip, limit int,
+) ([]model.ReleaseNotesRevision, int, error) {
+	collNotes := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleaseNotes)
+
+	filter := bson.M{StorageKeyReleaseNotesRelease: releaseName}
+	cursor, err := collNotes.Find(ctx, filter,
+		mopts.Find().
+			SetSort(bson.M{StorageKeyReleaseNotesModified: -1}).
+			SetSkip(int64(skip)).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "mongo: failed to list the release notes history")
+	}
+	revisions := []model.ReleaseNotesRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, 0, errors.WithMessage(err, "mongo: failed to decode the release notes history")
+	}
+	count, err := collNotes.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "mongo: failed to count the release notes history")
+	}
+	return revisions, int(count), nil
+}
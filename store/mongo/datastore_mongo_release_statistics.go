@@ -0,0 +1,178 @@
+// Copyright 2023 Northern.tech AS
+//
+//	Licensed under the Apache License, Version 2.0 (the "License");
+//	you may not use this file except in compliance with the License.
+//	You may obtain a copy of the License at
+//
+//	    http://www.apache.org/licenses/LICENSE-2.0
+//
+//	Unless required by applicable law or agreed to in writing, software
+//	distributed under the License is distributed on an "AS IS" BASIS,
+//	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//	See the License for the specific language governing permissions and
+//	limitations under the License.
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+
+	mstore "github.com/mendersoftware/go-lib-micro/store"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+// CountDeploymentsByArtifactName returns the number of deployments of the
+// artifact with the given name.
+func (db *DataStoreMongo) CountDeploymentsByArtifactName(
+	ctx context.Context,
+	artifactName string,
+) (int, error) {
+	collDpl := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionDeployments)
+
+	count, err := collDpl.CountDocuments(ctx, bson.D{
+		{Key: StorageKeyDeploymentArtifactName, Value: artifactName},
+	})
+	return int(count), err
+}
+
+// GetReleaseDeviceDeploymentStats returns the number of device deployments
+// of the release per status, indexed by device type.
+func (db *DataStoreMongo) GetReleaseDeviceDeploymentStats(
+	ctx context.Context,
+	releaseName string,
+) (map[string]model.Stats, error) {
+	collDevs := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionDevices)
+
+	pipeline := []bson.D{
+		{{Key: "$match", Value: bson.D{
+			{Key: StorageKeyDeviceDeploymentAssignedImageName, Value: releaseName},
+			{Key: StorageKeyDeviceDeploymentDeleted, Value: bson.D{
+				{Key: "$exists", Value: false},
+			}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "device_type", Value: "$" + StorageKeyDeviceDeploymentDeviceType},
+				{Key: "status", Value: "$" + StorageKeyDeviceDeploymentStatus},
+			}},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+		}}},
+	}
+	cursor, err := collDevs.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		ID struct {
+			DeviceType string                       `bson:"device_type"`
+			Status     model.DeviceDeploymentStatus `bson:"status"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	stats := make(map[string]model.Stats)
+	for _, result := range results {
+		deviceStats, ok := stats[result.ID.DeviceType]
+		if !ok {
+			deviceStats = model.NewDeviceDeploymentStats()
+			stats[result.ID.DeviceType] = deviceStats
+		}
+		deviceStats.Set(result.ID.Status, result.Count)
+	}
+	return stats, nil
+}
+
+// GetReleaseInstalledDevices returns the number of devices whose latest
+// successful deployment installed the release, indexed by device type.
+// The deployments of the release are matched first, using the release and
+// status index, and a device is counted only when no successful deployment
+// finished after the one installing the release.
+func (db *DataStoreMongo) GetReleaseInstalledDevices(
+	ctx context.Context,
+	releaseName string,
+) (map[string]int, error) {
+	collDevs := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionDevices)
+
+	installedStatus := bson.D{
+		{Key: "$in", Value: []model.DeviceDeploymentStatus{
+			model.DeviceDeploymentStatusSuccess,
+			model.DeviceDeploymentStatusAlreadyInst,
+		}},
+	}
+	notDeleted := bson.D{{Key: "$exists", Value: false}}
+	pipeline := []bson.D{
+		{{Key: "$match", Value: bson.D{
+			{Key: StorageKeyDeviceDeploymentAssignedImageName, Value: releaseName},
+			{Key: StorageKeyDeviceDeploymentStatus, Value: installedStatus},
+			{Key: StorageKeyDeviceDeploymentDeleted, Value: notDeleted},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + StorageKeyDeviceDeploymentDeviceId},
+			{Key: "finished", Value: bson.D{
+				{Key: "$max", Value: "$" + StorageKeyDeviceDeploymentFinished},
+			}},
+			{Key: "device_type", Value: bson.D{
+				{Key: "$first", Value: "$" + StorageKeyDeviceDeploymentDeviceType},
+			}},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: CollectionDevices},
+			{Key: "let", Value: bson.D{
+				{Key: "device", Value: "$_id"},
+				{Key: "finished", Value: "$finished"},
+			}},
+			{Key: "pipeline", Value: []bson.D{
+				{{Key: "$match", Value: bson.D{
+					{Key: StorageKeyDeviceDeploymentStatus, Value: installedStatus},
+					{Key: StorageKeyDeviceDeploymentDeleted, Value: notDeleted},
+					{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+						bson.D{{Key: "$eq", Value: bson.A{
+							"$" + StorageKeyDeviceDeploymentDeviceId, "$$device",
+						}}},
+						bson.D{{Key: "$gt", Value: bson.A{
+							"$" + StorageKeyDeviceDeploymentFinished, "$$finished",
+						}}},
+					}}}},
+				}}},
+				{{Key: "$limit", Value: 1}},
+			}},
+			{Key: "as", Value: "newer"},
+		}}},
+		{{Key: "$match", Value: bson.D{
+			{Key: "newer", Value: bson.D{{Key: "$size", Value: 0}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$device_type"},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+		}}},
+	}
+	cursor, err := collDevs.Aggregate(ctx, pipeline,
+		mopts.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		DeviceType string `bson:"_id"`
+		Count      int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	installed := make(map[string]int, len(results))
+	for _, result := range results {
+		installed[result.DeviceType] = result.Count
+	}
+	return installed, nil
+}
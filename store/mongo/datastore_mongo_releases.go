@@ -15,6 +15,7 @@ package mongo
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -59,6 +60,33 @@ func (db *DataStoreMongo) UpdateReleaseArtifactDescription(
 	return nil
 }
 
+// UpdateReleaseArtifact replaces the artifact in the release with the
+// given name.
+func (db *DataStoreMongo) UpdateReleaseArtifact(
+	ctx context.Context,
+	artifact *model.Image,
+	releaseName string,
+) error {
+	database := db.client.Database(mstore.DbFromContext(ctx, DatabaseName))
+	collReleases := database.Collection(CollectionReleases)
+
+	update := bson.M{
+		"$set": bson.M{
+			StorageKeyReleaseArtifacts + ".$": artifact,
+			StorageKeyReleaseModified:         time.Now(),
+		},
+	}
+	_, err := collReleases.UpdateOne(
+		ctx,
+		bson.M{
+			StorageKeyReleaseName:        releaseName,
+			StorageKeyReleaseArtifactsId: artifact.Id,
+		},
+		update,
+	)
+	return err
+}
+
 func (db *DataStoreMongo) UpdateReleaseArtifacts(
 	ctx context.Context,
 	artifactToAdd *model.Image,
@@ -87,6 +115,12 @@ func (db *DataStoreMongo) UpdateReleaseArtifacts(
 		upsert := true
 		opt.Upsert = &upsert
 		update["$push"] = bson.M{StorageKeyReleaseArtifacts: artifactToAdd}
+		// new releases are approved rather than drafts: uploading an
+		// artifact and deploying it right away, as the clients written
+		// before the release status do, must keep working
+		update["$setOnInsert"] = bson.M{
+			StorageKeyReleaseStatus: model.ReleaseStatusApproved,
+		}
 		update["$inc"] = bson.M{
 			StorageKeyReleaseArtifactsCount: 1,
 		}
@@ -115,12 +149,29 @@ func (db *DataStoreMongo) UpdateReleaseArtifacts(
 	return nil
 }
 
+// releaseTagsFilter matches the releases with all the given tags: flat
+// tags match the releases with the key, whatever its value, and key/value
+// tags the releases with the key set to the value.
+func releaseTagsFilter(tags []string) bson.M {
+	conditions := make(bson.A, len(tags))
+	for i, filter := range tags {
+		tag := model.Tag(filter)
+		condition := bson.M{"key": strings.ToLower(tag.Key())}
+		if value := tag.Value(); value != "" {
+			condition["value"] = value
+		}
+		conditions[i] = bson.M{"$elemMatch": condition}
+	}
+	return bson.M{"$all": conditions}
+}
+
+// ListReleaseTags returns the distinct keys of the release tags.
 func (db *DataStoreMongo) ListReleaseTags(ctx context.Context) (model.Tags, error) {
 	l := log.FromContext(ctx)
 	tagKeys, err := db.client.
 		Database(mstore.DbFromContext(ctx, DatabaseName)).
 		Collection(CollectionReleases).
-		Distinct(ctx, StorageKeyReleaseTags, bson.D{})
+		Distinct(ctx, StorageKeyReleaseTagsKey, bson.D{})
 	if err != nil {
 		return nil, errors.WithMessage(err,
 			"mongo: failed to retrieve distinct tags")
@@ -138,6 +189,28 @@ func (db *DataStoreMongo) ListReleaseTags(ctx context.Context) (model.Tags, erro
 	return ret, err
 }
 
+// ListTaggedReleaseNames returns the names of the releases with at least
+// one tag.
+func (db *DataStoreMongo) ListTaggedReleaseNames(ctx context.Context) ([]string, error) {
+	names, err := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleases).
+		Distinct(ctx, StorageKeyReleaseName, bson.D{
+			{Key: StorageKeyReleaseTags + ".0", Value: bson.D{{Key: "$exists", Value: true}}},
+		})
+	if err != nil {
+		return nil, errors.WithMessage(err,
+			"mongo: failed to retrieve tagged releases")
+	}
+	ret := make([]string, 0, len(names))
+	for _, elem := range names {
+		if name, ok := elem.(string); ok {
+			ret = append(ret, name)
+		}
+	}
+	return ret, nil
+}
+
 func (db *DataStoreMongo) ReplaceReleaseTags(
 	ctx context.Context,
 	releaseName string,
@@ -153,21 +226,8 @@ func (db *DataStoreMongo) ReplaceReleaseTags(
 		Collection(CollectionReleases)
 
 	// Check if added tags will exceed limits
-	if len(tags) > 0 {
-		inUseTags, err := db.ListReleaseTags(ctx)
-		if err != nil {
-			return errors.WithMessage(err, "mongo: failed to count in-use tags")
-		}
-		tagSet := make(map[model.Tag]struct{}, len(inUseTags))
-		for _, tagKey := range inUseTags {
-			tagSet[tagKey] = struct{}{}
-		}
-		for _, tag := range tags {
-			delete(tagSet, tag)
-		}
-		if len(tags)+len(tagSet) > model.TagsMaxUnique {
-			return model.ErrTooManyUniqueTags
-		}
+	if err := db.checkUniqueTagKeys(ctx, tags); err != nil {
+		return err
 	}
 
 	// Update release tags
@@ -185,6 +245,81 @@ func (db *DataStoreMongo) ReplaceReleaseTags(
 	return nil
 }
 
+// checkUniqueTagKeys returns model.ErrTooManyUniqueTags if setting the tags
+// would exceed the maximum number of unique tag keys.
+func (db *DataStoreMongo) checkUniqueTagKeys(ctx context.Context, tags model.Tags) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	inUseKeys, err := db.ListReleaseTags(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "mongo: failed to count in-use tags")
+	}
+	keySet := make(map[string]struct{}, len(inUseKeys))
+	for _, key := range inUseKeys {
+		keySet[string(key)] = struct{}{}
+	}
+	for _, key := range tags.Keys() {
+		delete(keySet, key)
+	}
+	if len(tags)+len(keySet) > model.TagsMaxUnique {
+		return model.ErrTooManyUniqueTags
+	}
+	return nil
+}
+
+// patchReleaseTagsAttempts is the number of times the tags are patched
+// when the tags of the release change concurrently.
+const patchReleaseTagsAttempts = 3
+
+// PatchReleaseTags adds and removes tags of the release; the tags are
+// only updated if they did not change since they were read.
+func (db *DataStoreMongo) PatchReleaseTags(
+	ctx context.Context,
+	releaseName string,
+	patch model.TagsPatch,
+) error {
+	collReleases := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleases)
+
+	for i := 0; i < patchReleaseTagsAttempts; i++ {
+		var release model.Release
+		err := collReleases.FindOne(ctx,
+			bson.M{StorageKeyReleaseName: releaseName},
+			mopts.FindOne().SetProjection(bson.M{StorageKeyReleaseTags: 1}),
+		).Decode(&release)
+		if err == mongo.ErrNoDocuments {
+			return store.ErrNotFound
+		} else if err != nil {
+			return errors.WithMessage(err, "mongo: failed to get release tags")
+		}
+
+		tags := patch.Apply(release.Tags)
+		if err := tags.Validate(); err != nil {
+			return err
+		} else if err := db.checkUniqueTagKeys(ctx, tags); err != nil {
+			return err
+		}
+
+		filter := bson.M{StorageKeyReleaseName: releaseName}
+		if len(release.Tags) > 0 {
+			filter[StorageKeyReleaseTags] = release.Tags
+		} else {
+			filter[StorageKeyReleaseTags] = bson.M{"$in": bson.A{nil, bson.A{}}}
+		}
+		res, err := collReleases.UpdateOne(ctx, filter, bson.M{
+			mongoOpSet: bson.M{StorageKeyReleaseTags: tags},
+		})
+		if err != nil {
+			return errors.WithMessage(err, "mongo: failed to update release tags")
+		} else if res.MatchedCount > 0 {
+			return nil
+		}
+	}
+	return store.ErrConflict
+}
+
 func (db *DataStoreMongo) UpdateRelease(
 	ctx context.Context,
 	releaseName string,
@@ -199,9 +334,31 @@ func (db *DataStoreMongo) UpdateRelease(
 		return errors.Wrap(err, "cant update release due to validation errors")
 	}
 
-	// Update release, at the moment we update only the notes,
-	// it is on purpose that we take only this field explicitly,
+	// Update release, at the moment we update only the notes and the
+	// alias, it is on purpose that we take only these fields explicitly,
 	// once there is a need we can extend
+	set := bson.D{}
+	unset := bson.D{}
+	if release.UpdatesNotes() {
+		set = append(set, bson.E{Key: StorageKeyReleaseNotes, Value: release.Notes})
+	}
+	if release.Alias != nil {
+		if *release.Alias == "" {
+			unset = append(unset, bson.E{Key: StorageKeyReleaseAlias, Value: ""})
+		} else {
+			set = append(set, bson.E{Key: StorageKeyReleaseAlias, Value: *release.Alias})
+		}
+	}
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{Key: mongoOpSet, Value: set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: mongoOpUnset, Value: unset})
+	}
+	if len(update) == 0 {
+		return nil
+	}
 	res, err := collReleases.UpdateOne(
 		ctx,
 		bson.D{
@@ -209,18 +366,11 @@ func (db *DataStoreMongo) UpdateRelease(
 				Key: StorageKeyReleaseName, Value: releaseName,
 			},
 		},
-		bson.D{
-			{
-				Key: mongoOpSet,
-				Value: bson.D{
-					{
-						Key: StorageKeyReleaseNotes, Value: release.Notes,
-					},
-				},
-			},
-		},
+		update,
 	)
-	if err != nil {
+	if mongo.IsDuplicateKeyError(err) {
+		return store.ErrConflict
+	} else if err != nil {
 		return errors.WithMessage(err, "mongo: failed to update release")
 	} else if res.MatchedCount <= 0 {
 		return store.ErrNotFound
@@ -228,6 +378,112 @@ func (db *DataStoreMongo) UpdateRelease(
 	return nil
 }
 
+// GetReleaseStatus returns the status of the release with the given name.
+func (db *DataStoreMongo) GetReleaseStatus(
+	ctx context.Context,
+	releaseName string,
+) (model.ReleaseStatus, error) {
+	collReleases := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleases)
+
+	var release model.Release
+	err := collReleases.FindOne(ctx,
+		bson.M{StorageKeyReleaseName: releaseName},
+		mopts.FindOne().SetProjection(bson.M{StorageKeyReleaseStatus: 1}),
+	).Decode(&release)
+	if err == mongo.ErrNoDocuments {
+		return "", store.ErrNotFound
+	} else if err != nil {
+		return "", errors.WithMessage(err, "mongo: failed to get the release status")
+	}
+	return release.Status, nil
+}
+
+// GetReleaseNameByAlias returns the name of the release with the given
+// alias; store.ErrNotFound is returned if no release has the alias.
+func (db *DataStoreMongo) GetReleaseNameByAlias(
+	ctx context.Context,
+	alias string,
+) (string, error) {
+	collReleases := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleases)
+
+	var release model.Release
+	err := collReleases.FindOne(ctx,
+		bson.M{StorageKeyReleaseAlias: alias},
+		mopts.FindOne().SetProjection(bson.M{StorageKeyReleaseName: 1}),
+	).Decode(&release)
+	if err == mongo.ErrNoDocuments {
+		return "", store.ErrNotFound
+	} else if err != nil {
+		return "", errors.WithMessage(err, "mongo: failed to get the release by alias")
+	}
+	return release.Name, nil
+}
+
+// UpdateReleaseStatus changes the status of the release with the given
+// name, if the current status is one of `from`; store.ErrConflict is
+// returned otherwise.
+func (db *DataStoreMongo) UpdateReleaseStatus(
+	ctx context.Context,
+	releaseName string,
+	from []model.ReleaseStatus,
+	status model.ReleaseStatus,
+) error {
+	collReleases := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleases)
+
+	res, err := collReleases.UpdateOne(ctx,
+		bson.M{
+			StorageKeyReleaseName:   releaseName,
+			StorageKeyReleaseStatus: bson.M{"$in": from},
+		},
+		bson.M{mongoOpSet: bson.M{
+			StorageKeyReleaseStatus:   status,
+			StorageKeyReleaseModified: time.Now(),
+		}},
+	)
+	if err != nil {
+		return errors.WithMessage(err, "mongo: failed to update the release status")
+	} else if res.MatchedCount > 0 {
+		return nil
+	}
+	count, err := collReleases.CountDocuments(ctx,
+		bson.M{StorageKeyReleaseName: releaseName},
+	)
+	if err != nil {
+		return errors.WithMessage(err, "mongo: failed to update the release status")
+	} else if count == 0 {
+		return store.ErrNotFound
+	}
+	return store.ErrConflict
+}
+
+// DeleteReleases deletes the release documents with the given names; the
+// artifacts of the releases are not deleted.
+func (db *DataStoreMongo) DeleteReleases(ctx context.Context, releaseNames []string) error {
+	collReleases := db.client.
+		Database(mstore.DbFromContext(ctx, DatabaseName)).
+		Collection(CollectionReleases)
+
+	_, err := collReleases.DeleteMany(ctx, bson.M{
+		StorageKeyReleaseName: bson.M{"$in": releaseNames},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "mongo: failed to delete releases")
+	}
+	_, err = collReleases.Database().Collection(CollectionReleaseNotes).DeleteMany(ctx, bson.M{
+		StorageKeyReleaseNotesRelease: bson.M{"$in": releaseNames},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "mongo: failed to delete the release notes history")
+	}
+	return nil
+}
+
 // Save the possibly new update types
 func (db *DataStoreMongo) SaveUpdateTypes(ctx context.Context, updateTypes []string) error {
 	database := db.client.Database(DatabaseName)
@@ -25,11 +25,13 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/mendersoftware/deployments/model"
 	"github.com/mendersoftware/deployments/store"
 	"github.com/mendersoftware/go-lib-micro/identity"
 	"github.com/mendersoftware/go-lib-micro/log"
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
 	ctxstore "github.com/mendersoftware/go-lib-micro/store"
 )
 
@@ -1072,6 +1074,95 @@ func TestUpdateUploadIntentStatus(t *testing.T) {
 	})
 }
 
+func TestChunkedUploadIntent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestChunkedUploadIntent in short mode.")
+	}
+	db.Wipe()
+
+	const (
+		artifactID = "00000000-0000-0000-0000-000000000002"
+		tenantID   = "123456789012345678901234"
+	)
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: tenantID,
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+	err := ds.InsertUploadIntent(ctx, &model.UploadLink{
+		ArtifactID: artifactID,
+		Link: model.Link{
+			Expire: time.Now().Add(time.Hour),
+		},
+		Status: model.LinkStatusPending,
+		Chunked: &model.ChunkedUpload{
+			UploadID: "upload",
+			Length:   1024,
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	lock := model.ChunkLock{ID: "lock-1", Expire: time.Now().Add(time.Minute)}
+	err = ds.LockUploadIntentChunk(ctx, artifactID, 0, lock)
+	assert.NoError(t, err)
+
+	// another chunk is being written at offset 0
+	err = ds.LockUploadIntentChunk(ctx, artifactID, 0, model.ChunkLock{
+		ID:     "lock-2",
+		Expire: time.Now().Add(time.Minute),
+	})
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	err = ds.UpdateUploadIntentChunk(ctx, artifactID, "lock-2", 0, 512)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	err = ds.UpdateUploadIntentChunk(ctx, artifactID, lock.ID, 0, 512)
+	assert.NoError(t, err)
+
+	// the chunk at offset 0 was already received
+	err = ds.LockUploadIntentChunk(ctx, artifactID, 0, lock)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	err = ds.UpdateUploadIntentChunk(ctx, artifactID, lock.ID, 0, 512)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	// expired and released locks are taken over
+	err = ds.LockUploadIntentChunk(ctx, artifactID, 512, model.ChunkLock{
+		ID:     "lock-3",
+		Expire: time.Now().Add(-time.Minute),
+	})
+	assert.NoError(t, err)
+	err = ds.LockUploadIntentChunk(ctx, artifactID, 512, model.ChunkLock{
+		ID:     "lock-4",
+		Expire: time.Now().Add(time.Minute),
+	})
+	assert.NoError(t, err)
+	err = ds.UnlockUploadIntentChunk(ctx, artifactID, "lock-4")
+	assert.NoError(t, err)
+
+	link, err := ds.FindUploadLinkByID(ctx, artifactID)
+	if assert.NoError(t, err) && assert.NotNil(t, link.Chunked) {
+		assert.Equal(t, model.ChunkedUpload{
+			UploadID: "upload",
+			Length:   1024,
+			Offset:   512,
+			Chunks:   1,
+		}, *link.Chunked)
+	}
+
+	_, err = ds.FindUploadLinkByID(identity.WithContext(ctx, &identity.Identity{
+		Tenant: "000000000000000000000000",
+	}), artifactID)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	err = ds.UpdateUploadIntentStatus(
+		ctx, artifactID, model.LinkStatusPending, model.LinkStatusProcessing,
+	)
+	assert.NoError(t, err)
+	err = ds.UpdateUploadIntentChunk(ctx, artifactID, "lock-4", 512, 512)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
 func TestFindNewerActiveDeployments(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping TestFindNewerActiveDeployments in short mode.")
@@ -1352,6 +1443,118 @@ func TestSetStorageSettings(t *testing.T) {
 	}
 }
 
+func TestSetTenantSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestSetTenantSettings in short mode.")
+	}
+
+	db.Wipe()
+	ctx := context.Background()
+	ds := NewDataStoreMongoWithClient(db.Client())
+
+	settings, err := ds.GetTenantSettings(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+
+	storageSettings := &model.StorageSettings{
+		Region: "region",
+		Key:    "secretkey",
+		Secret: "secret",
+		Bucket: "bucket",
+	}
+	err = ds.SetStorageSettings(ctx, storageSettings)
+	assert.NoError(t, err)
+
+	expected := &model.TenantSettings{
+		StatusTransitions: model.StatusTransitionsModeEnforce,
+	}
+	err = ds.SetTenantSettings(ctx, expected)
+	assert.NoError(t, err)
+
+	settings, err = ds.GetTenantSettings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, settings)
+
+	err = ds.SetTenantSettings(ctx, &model.TenantSettings{
+		StatusTransitions:      model.StatusTransitionsModeEnforce,
+		RequireSignedArtifacts: true,
+	})
+	assert.NoError(t, err)
+	settings, err = ds.GetTenantSettings(ctx)
+	assert.NoError(t, err)
+	assert.True(t, settings.RequireSignedArtifacts)
+
+	// the settings are replaced: settings left out are reset
+	expected = &model.TenantSettings{
+		StatusTransitions: model.StatusTransitionsModeWarn,
+	}
+	err = ds.SetTenantSettings(ctx, expected)
+	assert.NoError(t, err)
+	settings, err = ds.GetTenantSettings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, settings)
+
+	// the storage settings are kept in the same collection
+	actualStorageSettings, err := ds.GetStorageSettings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, storageSettings, actualStorageSettings)
+}
+
+func TestPublicKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestPublicKeys in short mode.")
+	}
+
+	db.Wipe()
+	ctx := context.Background()
+	ds := NewDataStoreMongoWithClient(db.Client())
+	m := &migration_1_2_21{client: db.Client(), db: DbName}
+	assert.NoError(t, m.Up(migrate.MakeVersion(1, 2, 21)))
+
+	keys, err := ds.ListPublicKeys(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	first := &model.PublicKey{
+		ID:          "1",
+		Name:        "first",
+		Key:         "key-1",
+		Algorithm:   model.PublicKeyAlgorithmECDSA,
+		Fingerprint: "fingerprint-1",
+		Created:     now,
+	}
+	second := &model.PublicKey{
+		ID:          "2",
+		Name:        "second",
+		Key:         "key-2",
+		Algorithm:   model.PublicKeyAlgorithmRSA,
+		Fingerprint: "fingerprint-2",
+		Created:     now.Add(time.Second),
+	}
+	assert.NoError(t, ds.InsertPublicKey(ctx, second))
+	assert.NoError(t, ds.InsertPublicKey(ctx, first))
+
+	duplicate := *first
+	duplicate.ID = "3"
+	err = ds.InsertPublicKey(ctx, &duplicate)
+	assert.ErrorIs(t, err, store.ErrConflict)
+
+	keys, err = ds.ListPublicKeys(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.PublicKey{*first, *second}, keys)
+
+	key, err := ds.GetPublicKey(ctx, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, second, key)
+
+	assert.NoError(t, ds.DeletePublicKey(ctx, "2"))
+	_, err = ds.GetPublicKey(ctx, "2")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	err = ds.DeletePublicKey(ctx, "2")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
 func TestSortDeployments(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping TestSortDeployments in short mode.")
@@ -3328,3 +3531,534 @@ func TestSaveUpdateTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestArtifactObjectReferences(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestArtifactObjectReferences in short mode.")
+	}
+	db.Wipe()
+
+	const objectID = "checksum"
+
+	ctxTenant := func(tenantID string) context.Context {
+		return identity.WithContext(context.Background(), &identity.Identity{
+			Tenant: tenantID,
+		})
+	}
+	ctx1 := ctxTenant("tenant1")
+	ctx2 := ctxTenant("tenant2")
+	ds := NewDataStoreMongoWithClient(db.Client())
+
+	obj, err := ds.AddArtifactObjectReference(ctx1, objectID, "tenant1/image", 10, "image")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "tenant1/image", obj.Path)
+		assert.Equal(t, 1, obj.RefCount)
+	}
+	// the same image ID of another tenant shares the object
+	obj, err = ds.AddArtifactObjectReference(ctx2, objectID, "tenant2/image", 10, "image")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "tenant1/image", obj.Path)
+		assert.Equal(t, 2, obj.RefCount)
+		assert.ElementsMatch(t, []model.ArtifactObjectReference{
+			{TenantID: "tenant1", ImageID: "image"},
+			{TenantID: "tenant2", ImageID: "image"},
+		}, obj.References)
+	}
+
+	err = ds.DeleteArtifactObject(ctx1, objectID)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	obj, err = ds.RemoveArtifactObjectReference(ctx1, objectID, "image")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, obj.RefCount)
+	}
+	_, err = ds.RemoveArtifactObjectReference(ctx1, objectID, "image")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	obj, err = ds.RemoveArtifactObjectReference(ctx2, objectID, "image")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, obj.RefCount)
+		assert.Empty(t, obj.References)
+	}
+	err = ds.DeleteArtifactObject(ctx2, objectID)
+	assert.NoError(t, err)
+	_, err = ds.RemoveArtifactObjectReference(ctx2, objectID, "image")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestDeleteReleases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestDeleteReleases in short mode.")
+	}
+	db.Wipe()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "tenant",
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+	collReleases := db.Client().
+		Database(ctxstore.DbFromContext(ctx, DbName)).
+		Collection(CollectionReleases)
+	_, err := collReleases.InsertMany(ctx, []interface{}{
+		model.Release{Name: "release-1"},
+		model.Release{Name: "release-2"},
+		model.Release{Name: "release-3"},
+	})
+	assert.NoError(t, err)
+
+	err = ds.DeleteReleases(ctx, []string{"release-1", "release-3", "unknown"})
+	assert.NoError(t, err)
+
+	var releases []model.Release
+	cur, err := collReleases.Find(ctx, bson.M{})
+	if assert.NoError(t, err) {
+		assert.NoError(t, cur.All(ctx, &releases))
+	}
+	if assert.Len(t, releases, 1) {
+		assert.Equal(t, "release-2", releases[0].Name)
+	}
+}
+
+func TestUpdateReleaseStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestUpdateReleaseStatus in short mode.")
+	}
+	db.Wipe()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "tenant",
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+	collReleases := db.Client().
+		Database(ctxstore.DbFromContext(ctx, DbName)).
+		Collection(CollectionReleases)
+	_, err := collReleases.InsertOne(ctx, model.Release{
+		Name:   "release-1",
+		Status: model.ReleaseStatusDraft,
+	})
+	assert.NoError(t, err)
+
+	_, err = ds.GetReleaseStatus(ctx, "unknown")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	err = ds.UpdateReleaseStatus(ctx, "unknown",
+		model.ReleaseStatusApproved.StatusesTransitioningTo(),
+		model.ReleaseStatusApproved)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	err = ds.UpdateReleaseStatus(ctx, "release-1",
+		model.ReleaseStatusDeprecated.StatusesTransitioningTo(),
+		model.ReleaseStatusDeprecated)
+	assert.ErrorIs(t, err, store.ErrConflict)
+
+	err = ds.UpdateReleaseStatus(ctx, "release-1",
+		model.ReleaseStatusApproved.StatusesTransitioningTo(),
+		model.ReleaseStatusApproved)
+	assert.NoError(t, err)
+
+	status, err := ds.GetReleaseStatus(ctx, "release-1")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ReleaseStatusApproved, status)
+}
+
+func TestReleaseChannels(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestReleaseChannels in short mode.")
+	}
+	db.Wipe()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "tenant",
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+
+	_, err := ds.GetReleaseChannel(ctx, "stable")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	now := time.Now().UTC().Round(time.Millisecond)
+	promotions := []model.ReleaseChannelPromotion{{
+		ReleaseName: "release-1",
+		UserID:      "user",
+		Promoted:    now,
+	}, {
+		ReleaseName:         "release-2",
+		PreviousReleaseName: "release-1",
+		FromChannel:         "beta",
+		Promoted:            now.Add(time.Minute),
+	}}
+	for i := range promotions {
+		err = ds.PromoteReleaseChannel(ctx, "stable", &promotions[i])
+		assert.NoError(t, err)
+	}
+	err = ds.PromoteReleaseChannel(ctx, "beta", &promotions[1])
+	assert.NoError(t, err)
+
+	channel, err := ds.GetReleaseChannel(ctx, "stable")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &model.ReleaseChannel{
+			Name:        "stable",
+			ReleaseName: "release-2",
+			Modified:    now.Add(time.Minute),
+			History:     promotions,
+		}, channel)
+	}
+
+	channels, err := ds.GetReleaseChannels(ctx)
+	if assert.NoError(t, err) && assert.Len(t, channels, 2) {
+		assert.Equal(t, "beta", channels[0].Name)
+		assert.Equal(t, "stable", channels[1].Name)
+		assert.Empty(t, channels[1].History)
+	}
+}
+
+func TestReleaseKeyValueTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestReleaseKeyValueTags in short mode.")
+	}
+	db.Wipe()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "tenant",
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+	collReleases := db.Client().
+		Database(ctxstore.DbFromContext(ctx, DbName)).
+		Collection(CollectionReleases)
+	_, err := collReleases.InsertMany(ctx, []interface{}{
+		model.Release{Name: "gateway-1"},
+		model.Release{Name: "gateway-2"},
+		model.Release{Name: "sensor-1"},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ds.ReplaceReleaseTags(ctx, "gateway-1",
+		model.Tags{"product=gateway", "fips=true"}))
+	assert.NoError(t, ds.ReplaceReleaseTags(ctx, "gateway-2",
+		model.Tags{"product=gateway", "fips=false", "beta"}))
+	assert.NoError(t, ds.ReplaceReleaseTags(ctx, "sensor-1",
+		model.Tags{"product=sensor"}))
+
+	err = ds.PatchReleaseTags(ctx, "gateway-2", model.TagsPatch{
+		Add:    model.Tags{"fips=true"},
+		Remove: model.Tags{"beta"},
+	})
+	assert.NoError(t, err)
+	err = ds.PatchReleaseTags(ctx, "unknown", model.TagsPatch{
+		Add: model.Tags{"fips=true"},
+	})
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	err = ds.PatchReleaseTags(ctx, "sensor-1", model.TagsPatch{
+		Remove: model.Tags{"product=gateway"},
+	})
+	assert.NoError(t, err)
+
+	findReleases := func(tags ...string) []string {
+		var releases []model.Release
+		cur, err := collReleases.Find(ctx,
+			bson.M{StorageKeyReleaseTags: releaseTagsFilter(tags)},
+			mopts.Find().SetSort(bson.M{StorageKeyReleaseName: 1}))
+		if assert.NoError(t, err) {
+			assert.NoError(t, cur.All(ctx, &releases))
+		}
+		names := make([]string, len(releases))
+		for i, release := range releases {
+			names[i] = release.Name
+		}
+		return names
+	}
+	assert.Equal(t, []string{"gateway-1", "gateway-2", "sensor-1"}, findReleases("product"))
+	assert.Equal(t, []string{"gateway-1", "gateway-2"}, findReleases("Product=gateway"))
+	assert.Equal(t, []string{"gateway-1", "gateway-2"},
+		findReleases("product=gateway", "fips=true"))
+	assert.Empty(t, findReleases("beta"))
+	assert.Equal(t, []string{"sensor-1"}, findReleases("product=sensor"))
+
+	var release model.Release
+	err = collReleases.FindOne(ctx, bson.M{StorageKeyReleaseName: "gateway-2"}).
+		Decode(&release)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.Tags{"product=gateway", "fips=true"}, release.Tags)
+	}
+
+	keys, err := ds.ListReleaseTags(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, model.Tags{"product", "fips"}, keys)
+}
+
+func TestReleaseStatistics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestReleaseStatistics in short mode.")
+	}
+	db.Wipe()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "tenant",
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+	database := db.Client().Database(ctxstore.DbFromContext(ctx, DatabaseName))
+
+	_, err := database.Collection(CollectionDeployments).InsertMany(ctx, []interface{}{
+		bson.M{"_id": "d1", StorageKeyDeploymentArtifactName: "release-1"},
+		bson.M{"_id": "d2", StorageKeyDeploymentArtifactName: "release-1"},
+		bson.M{"_id": "d3", StorageKeyDeploymentArtifactName: "release-2"},
+	})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	newDeviceDeployment := func(
+		id, deviceID, deviceType, release string,
+		status model.DeviceDeploymentStatus,
+		finished time.Duration,
+	) *model.DeviceDeployment {
+		finishedAt := now.Add(finished)
+		return &model.DeviceDeployment{
+			Id:       id,
+			DeviceId: deviceID,
+			Status:   status,
+			Finished: &finishedAt,
+			Image: &model.Image{
+				Id:           release,
+				ArtifactMeta: &model.ArtifactMeta{Name: release},
+			},
+			Request: &model.DeploymentNextRequest{
+				DeviceProvides: &model.InstalledDeviceDeployment{
+					DeviceType: deviceType,
+				},
+			},
+		}
+	}
+	_, err = database.Collection(CollectionDevices).InsertMany(ctx, []interface{}{
+		// device-1 runs release-1
+		newDeviceDeployment("1", "device-1", "rpi4", "release-1",
+			model.DeviceDeploymentStatusSuccess, 0),
+		// device-2 moved from release-1 to release-2
+		newDeviceDeployment("2", "device-2", "rpi4", "release-1",
+			model.DeviceDeploymentStatusSuccess, 0),
+		newDeviceDeployment("3", "device-2", "rpi4", "release-2",
+			model.DeviceDeploymentStatusSuccess, time.Minute),
+		// device-3 failed to move from release-2 to release-1
+		newDeviceDeployment("4", "device-3", "bbb", "release-2",
+			model.DeviceDeploymentStatusAlreadyInst, 0),
+		newDeviceDeployment("5", "device-3", "bbb", "release-1",
+			model.DeviceDeploymentStatusFailure, time.Minute),
+	})
+	assert.NoError(t, err)
+
+	count, err := ds.CountDeploymentsByArtifactName(ctx, "release-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	installed, err := ds.GetReleaseInstalledDevices(ctx, "release-1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"rpi4": 1}, installed)
+
+	installed, err = ds.GetReleaseInstalledDevices(ctx, "release-2")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"rpi4": 1, "bbb": 1}, installed)
+
+	stats, err := ds.GetReleaseDeviceDeploymentStats(ctx, "release-1")
+	if assert.NoError(t, err) && assert.Len(t, stats, 2) {
+		assert.Equal(t, 2, stats["rpi4"][model.DeviceDeploymentStatusSuccessStr])
+		assert.Equal(t, 1, stats["bbb"][model.DeviceDeploymentStatusFailureStr])
+	}
+}
+
+func TestSearchReleasesAndImages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestSearchReleasesAndImages in short mode.")
+	}
+	db.Wipe()
+
+	ctx := context.Background()
+	err := MigrateSingle(ctx, DbName, DbVersion, db.Client(), true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ds := NewDataStoreMongoWithClient(db.Client())
+
+	newImage := func(id, name, description string, provides map[string]string) *model.Image {
+		return &model.Image{
+			Id:        id,
+			ImageMeta: &model.ImageMeta{Description: description},
+			ArtifactMeta: &model.ArtifactMeta{
+				Name:                  name,
+				DeviceTypesCompatible: []string{"rpi4"},
+				Provides:              provides,
+			},
+			Modified: timePtr("2023-09-22T22:00:00+00:00"),
+		}
+	}
+	images := []*model.Image{
+		newImage("6d4f6e27-c3bb-438c-ad9c-d9de30e59d81", "foo-2.1",
+			"security fixes for the foo application",
+			map[string]string{"data-partition.foo.version": "2.1"}),
+		newImage("6d4f6e27-c3bb-438c-ad9c-d9de30e59d82", "foo-3.0",
+			"new foo features",
+			map[string]string{"data-partition.foo.version": "3.0"}),
+		newImage("6d4f6e27-c3bb-438c-ad9c-d9de30e59d83", "rootfs-2.4",
+			"security fixes, security hardening",
+			map[string]string{"rootfs-image.version": "2.4"}),
+	}
+	for _, image := range images {
+		assert.NoError(t, ds.InsertImage(ctx, image))
+		assert.NoError(t, ds.UpdateReleaseArtifacts(ctx, image, nil, image.ArtifactMeta.Name))
+	}
+	assert.NoError(t, ds.UpdateRelease(ctx, "foo-3.0", model.ReleasePatch{
+		Notes: "Security release",
+	}))
+
+	releaseNames := func(filter *model.ReleaseOrImageFilter) []string {
+		releases, count, err := ds.GetReleases(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, len(releases), count)
+		names := make([]string, len(releases))
+		for i, release := range releases {
+			names[i] = release.Name
+		}
+		return names
+	}
+	imageNames := func(filter *model.ReleaseOrImageFilter) []string {
+		images, count, err := ds.ListImages(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, len(images), count)
+		names := make([]string, len(images))
+		for i, image := range images {
+			names[i] = image.ArtifactMeta.Name
+		}
+		return names
+	}
+
+	filter := &model.ReleaseOrImageFilter{
+		Provides: []string{"data-partition.foo.version=2.*"},
+	}
+	assert.Equal(t, []string{"foo-2.1"}, releaseNames(filter))
+	assert.Equal(t, []string{"foo-2.1"}, imageNames(filter))
+
+	filter = &model.ReleaseOrImageFilter{
+		Provides: []string{"data-partition.foo.version"},
+	}
+	assert.Equal(t, []string{"foo-2.1", "foo-3.0"}, releaseNames(filter))
+
+	filter = &model.ReleaseOrImageFilter{
+		Provides: []string{"rootfs-image.version=2.4"},
+	}
+	assert.Equal(t, []string{"rootfs-2.4"}, imageNames(filter))
+
+	// sorted by relevance by default
+	filter = &model.ReleaseOrImageFilter{SearchText: "security"}
+	assert.Equal(t, []string{"rootfs-2.4", "foo-2.1", "foo-3.0"}, releaseNames(filter))
+	assert.Equal(t, []string{"rootfs-2.4", "foo-2.1"}, imageNames(filter))
+
+	filter = &model.ReleaseOrImageFilter{
+		SearchText: "security",
+		Sort:       "name:asc",
+	}
+	assert.Equal(t, []string{"foo-2.1", "foo-3.0", "rootfs-2.4"}, releaseNames(filter))
+}
+
+func TestReleaseAliases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestReleaseAliases in short mode.")
+	}
+	db.Wipe()
+
+	ctx := context.Background()
+	err := MigrateSingle(ctx, DbName, DbVersion, db.Client(), true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ds := NewDataStoreMongoWithClient(db.Client())
+	collReleases := db.Client().
+		Database(ctxstore.DbFromContext(ctx, DbName)).
+		Collection(CollectionReleases)
+	_, err = collReleases.InsertMany(ctx, []interface{}{
+		model.Release{Name: "release-1", Notes: "first"},
+		model.Release{Name: "release-2"},
+	})
+	assert.NoError(t, err)
+
+	_, err = ds.GetReleaseNameByAlias(ctx, "Summer 2023")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	alias := "Summer 2023"
+	err = ds.UpdateRelease(ctx, "release-1", model.ReleasePatch{Alias: &alias})
+	assert.NoError(t, err)
+	err = ds.UpdateRelease(ctx, "release-2", model.ReleasePatch{Alias: &alias})
+	assert.ErrorIs(t, err, store.ErrConflict)
+
+	name, err := ds.GetReleaseNameByAlias(ctx, alias)
+	assert.NoError(t, err)
+	assert.Equal(t, "release-1", name)
+
+	// the notes are left as they are when only the alias is given
+	releases, _, err := ds.GetReleases(ctx, &model.ReleaseOrImageFilter{Name: "summer"})
+	if assert.NoError(t, err) && assert.Len(t, releases, 1) {
+		assert.Equal(t, "release-1", releases[0].Name)
+		assert.Equal(t, alias, releases[0].Alias)
+		assert.Equal(t, model.Notes("first"), releases[0].Notes)
+	}
+
+	noAlias := ""
+	err = ds.UpdateRelease(ctx, "release-1", model.ReleasePatch{Alias: &noAlias})
+	assert.NoError(t, err)
+	_, err = ds.GetReleaseNameByAlias(ctx, alias)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	err = ds.UpdateRelease(ctx, "release-2", model.ReleasePatch{Alias: &alias})
+	assert.NoError(t, err)
+}
+
+func TestReleaseNotesHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestReleaseNotesHistory in short mode.")
+	}
+	db.Wipe()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "tenant",
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+	collReleases := db.Client().
+		Database(ctxstore.DbFromContext(ctx, DbName)).
+		Collection(CollectionReleases)
+	_, err := collReleases.InsertOne(ctx, model.Release{
+		Name:  "release-1",
+		Notes: "# Release 1\n\nSecurity fixes",
+	})
+	assert.NoError(t, err)
+
+	_, err = ds.GetReleaseNotes(ctx, "unknown")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	notes, err := ds.GetReleaseNotes(ctx, "release-1")
+	assert.NoError(t, err)
+	assert.Equal(t, model.Notes("# Release 1\n\nSecurity fixes"), notes)
+
+	now := time.Now().UTC().Round(time.Millisecond)
+	revisions := []model.ReleaseNotesRevision{{
+		ReleaseName: "release-1",
+		Notes:       "# Release 1",
+		UserID:      "user",
+		Modified:    now,
+	}, {
+		ReleaseName: "release-1",
+		Notes:       "# Release 1\n\nSecurity fixes",
+		Modified:    now.Add(time.Minute),
+	}}
+	for i := range revisions {
+		assert.NoError(t, ds.InsertReleaseNotesRevision(ctx, &revisions[i]))
+	}
+
+	history, count, err := ds.GetReleaseNotesRevisions(ctx, "release-1", 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, revisions[1:], history)
+
+	history, _, err = ds.GetReleaseNotesRevisions(ctx, "release-1", 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, revisions[:1], history)
+
+	assert.NoError(t, ds.DeleteReleases(ctx, []string{"release-1"}))
+	history, count, err = ds.GetReleaseNotesRevisions(ctx, "release-1", 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Empty(t, history)
+}
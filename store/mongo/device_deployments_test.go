@@ -338,12 +338,117 @@ func TestUpdateDeviceDeploymentStatus(t *testing.T) {
 					if testCase.InputSubState != "" {
 						assert.Equal(t, testCase.InputSubState, deployment.SubState)
 					}
+
+					// verify the transition was recorded
+					if assert.Len(t, deployment.StatusHistory, 2) {
+						transition := deployment.StatusHistory[1]
+						assert.Equal(t, testCase.InputStatus, transition.Status)
+						assert.Equal(t, testCase.InputSubState, transition.SubState)
+						assert.WithinDuration(t, time.Now(),
+							transition.Timestamp, time.Minute)
+					}
 				}
 			}
 		})
 	}
 }
 
+func TestFindStuckDeviceDeployments(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("skipping TestFindStuckDeviceDeployments in short mode.")
+	}
+
+	const deploymentID = "30b3e62c-9ec2-4312-a7fa-cff24cc7397a"
+	now := time.Now()
+	ago := func(d time.Duration) time.Time {
+		return now.Add(-d)
+	}
+	newDeviceDeployment := func(
+		deviceID string,
+		created time.Time,
+		history ...model.DeviceDeploymentStatusTransition,
+	) *model.DeviceDeployment {
+		dd := model.NewDeviceDeployment(deviceID, deploymentID)
+		dd.Created = &created
+		dd.StatusHistory = history
+		if len(history) > 0 {
+			dd.Status = history[len(history)-1].Status
+		} else {
+			dd.Status = model.DeviceDeploymentStatusDownloading
+		}
+		return dd
+	}
+
+	deviceDeployments := []*model.DeviceDeployment{
+		// downloading for two hours
+		newDeviceDeployment("stuck", ago(3*time.Hour),
+			model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusPending,
+				Timestamp: ago(3 * time.Hour),
+			},
+			model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusDownloading,
+				Timestamp: ago(2 * time.Hour),
+			},
+		),
+		// downloading for ten minutes
+		newDeviceDeployment("in-progress", ago(3*time.Hour),
+			model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusPending,
+				Timestamp: ago(3 * time.Hour),
+			},
+			model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusDownloading,
+				Timestamp: ago(10 * time.Minute),
+			},
+		),
+		// rebooting for two hours
+		newDeviceDeployment("other-status", ago(3*time.Hour),
+			model.DeviceDeploymentStatusTransition{
+				Status:    model.DeviceDeploymentStatusRebooting,
+				Timestamp: ago(2 * time.Hour),
+			},
+		),
+		// no status history recorded
+		newDeviceDeployment("legacy", ago(3*time.Hour)),
+		newDeviceDeployment("legacy-recent", ago(10*time.Minute)),
+	}
+
+	db.Wipe()
+	client := db.Client()
+	store := NewDataStoreMongoWithClient(client)
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "foo",
+	})
+
+	err := store.InsertMany(ctx, deviceDeployments...)
+	assert.NoError(t, err)
+
+	it, err := store.FindStuckDeviceDeployments(ctx,
+		model.DeviceDeploymentStatusDownloading, ago(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer it.Close(ctx)
+
+	var deviceIDs []string
+	for {
+		next, err := it.Next(ctx)
+		assert.NoError(t, err)
+		if !next {
+			break
+		}
+		var dd model.DeviceDeployment
+		err = it.Decode(&dd)
+		assert.NoError(t, err)
+		assert.Equal(t, deploymentID, dd.DeploymentId)
+		assert.Equal(t, model.DeviceDeploymentStatusDownloading, dd.Status)
+		deviceIDs = append(deviceIDs, dd.DeviceId)
+	}
+	assert.ElementsMatch(t, []string{"stuck", "legacy"}, deviceIDs)
+}
+
 func TestUpdateDeviceDeploymentLogAvailability(t *testing.T) {
 
 	if testing.Short() {
@@ -17,6 +17,7 @@ package mongo
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +26,7 @@ import (
 	"github.com/mendersoftware/go-lib-micro/identity"
 
 	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
 )
 
 func TestImagesStorageImageByNameAndDeviceType(t *testing.T) {
@@ -186,6 +188,58 @@ func TestImagesStorageImageByNameAndDeviceType(t *testing.T) {
 	}
 }
 
+func TestImagesByDeviceTypeSmallestFirst(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestImagesByDeviceTypeSmallestFirst in short mode.")
+	}
+	newImage := func(id string, size int64, deviceType string) *model.Image {
+		return &model.Image{
+			Id:        id,
+			ImageMeta: &model.ImageMeta{},
+			ArtifactMeta: &model.ArtifactMeta{
+				Name:                  "release-1",
+				DeviceTypesCompatible: []string{deviceType},
+				Depends:               map[string]interface{}{"checksum": id},
+			},
+			Size: size,
+		}
+	}
+
+	ctx := context.Background()
+	db.Wipe()
+	ds := NewDataStoreMongoWithClient(db.Client())
+	for _, image := range []*model.Image{
+		newImage("large", 20, "rpi4"),
+		newImage("small", 10, "rpi4"),
+		newImage("other", 5, "rpi3"),
+	} {
+		assert.NoError(t, ds.InsertImage(ctx, image))
+	}
+	ids := func(images []*model.Image) []string {
+		ids := make([]string, len(images))
+		for i, image := range images {
+			ids[i] = image.Id
+		}
+		return ids
+	}
+
+	images, err := ds.ImagesByIdsAndDeviceType(ctx,
+		[]string{"large", "small", "other"}, "rpi4")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"small", "large"}, ids(images))
+
+	images, err = ds.ImagesByNameAndDeviceType(ctx, "release-1", "rpi4")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"small", "large"}, ids(images))
+
+	images, err = ds.ImagesByNameAndDeviceType(ctx, "release-1", "beaglebone")
+	assert.NoError(t, err)
+	assert.Empty(t, images)
+
+	_, err = ds.ImagesByIdsAndDeviceType(ctx, nil, "rpi4")
+	assert.EqualError(t, err, ErrImagesStorageInvalidID.Error())
+}
+
 func TestIsArtifactUnique(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping TestIsArtifactUnique in short mode.")
@@ -567,3 +621,152 @@ func TestListImages(t *testing.T) {
 		})
 	}
 }
+
+func TestImageIntegrity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestImageIntegrity in short mode.")
+	}
+	db.Wipe()
+
+	ctx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "123456789012345678901234",
+	})
+	ds := NewDataStoreMongoWithClient(db.Client())
+	image := &model.Image{
+		Id: uuid.NewString(),
+		ArtifactMeta: &model.ArtifactMeta{
+			Name:                  "App1 v1.0",
+			DeviceTypesCompatible: []string{"foo"},
+		},
+		Size: 1024,
+	}
+	if !assert.NoError(t, ds.InsertImage(ctx, image)) {
+		return
+	}
+
+	it, err := ds.FindImages(ctx)
+	if assert.NoError(t, err) {
+		var images []model.Image
+		for next, err := it.Next(ctx); next && err == nil; next, err = it.Next(ctx) {
+			var img model.Image
+			if assert.NoError(t, it.Decode(&img)) {
+				images = append(images, img)
+			}
+		}
+		assert.NoError(t, it.Close(ctx))
+		if assert.Len(t, images, 1) {
+			assert.Equal(t, image.Id, images[0].Id)
+		}
+	}
+
+	found, err := ds.ImageByIdsAndDeviceType(ctx, []string{image.Id}, "foo")
+	assert.NoError(t, err)
+	assert.NotNil(t, found)
+
+	err = ds.SetImageIntegrity(ctx, image.Id, "", &model.ImageIntegrity{
+		Status: model.IntegrityStatusCorrupt,
+		Size:   512,
+	})
+	assert.NoError(t, err)
+
+	// corrupt artifacts are not assigned to devices
+	found, err = ds.ImageByIdsAndDeviceType(ctx, []string{image.Id}, "foo")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+	found, err = ds.ImageByNameAndDeviceType(ctx, "App1 v1.0", "foo")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	err = ds.SetImageIntegrity(ctx, image.Id, "checksum", &model.ImageIntegrity{
+		Status: model.IntegrityStatusOK,
+		Size:   1024,
+	})
+	assert.NoError(t, err)
+	found, err = ds.ImageByIdsAndDeviceType(ctx, []string{image.Id}, "foo")
+	if assert.NoError(t, err) && assert.NotNil(t, found) {
+		assert.Equal(t, "checksum", found.Checksum)
+		assert.True(t, found.Integrity.IsValid())
+	}
+
+	err = ds.SetImageIntegrity(ctx, uuid.NewString(), "", &model.ImageIntegrity{})
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestUpdateImageArtifactMeta(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestUpdateImageArtifactMeta in short mode.")
+	}
+	db.Wipe()
+
+	ctx := context.Background()
+	ds := NewDataStoreMongoWithClient(db.Client())
+	img := &model.Image{
+		Id:        "8a5b1f7c-1bde-4b6e-9a57-4b0c4c9b1e0a",
+		ImageMeta: &model.ImageMeta{},
+		ArtifactMeta: &model.ArtifactMeta{
+			Name:                  "release-1",
+			DeviceTypesCompatible: []string{"rpi4"},
+			Provides: map[string]string{
+				"artifact_name":        "release-1",
+				"rootfs-image.version": "1.0",
+			},
+		},
+	}
+	err := ds.InsertImage(ctx, img)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = ds.UpdateReleaseArtifacts(ctx, img, nil, img.ArtifactMeta.Name)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	change := &model.ArtifactMetaChange{
+		UserID:  "user",
+		Changed: time.Now().UTC().Truncate(time.Millisecond),
+		Before:  img.ArtifactMeta.Dependencies(),
+	}
+	img.ArtifactMeta.Provides["rootfs-image.version"] = "2.0"
+	img.ArtifactMeta.Depends = map[string]interface{}{"artifact_name": "release-0"}
+	change.After = img.ArtifactMeta.Dependencies()
+	err = ds.UpdateImageArtifactMeta(ctx, img.Id, img.ArtifactMeta, change)
+	assert.NoError(t, err)
+
+	found, err := ds.FindImageByID(ctx, img.Id)
+	if assert.NoError(t, err) && assert.NotNil(t, found) {
+		assert.Equal(t, "2.0", found.ArtifactMeta.Provides["rootfs-image.version"])
+		assert.Equal(t, "release-0", found.ArtifactMeta.Depends["artifact_name"])
+		if assert.Len(t, found.ArtifactMetaHistory, 1) {
+			assert.Equal(t, "user", found.ArtifactMetaHistory[0].UserID)
+			assert.Equal(t, "1.0",
+				found.ArtifactMetaHistory[0].Before.Provides["rootfs-image.version"])
+		}
+	}
+	// the provides and depends are reindexed
+	count, err := db.Client().Database(DatabaseName).
+		Collection(CollectionImages).
+		CountDocuments(ctx, bson.D{
+			{Key: model.StorageKeyImageProvidesIdxKey, Value: "rootfs-image.version"},
+			{Key: model.StorageKeyImageProvidesIdxValue, Value: "2.0"},
+			{Key: StorageKeyImageDependsIdx + ".artifact_name", Value: "release-0"},
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = ds.UpdateReleaseArtifact(ctx, found, found.ArtifactMeta.Name)
+	assert.NoError(t, err)
+	var release model.Release
+	err = db.Client().Database(DatabaseName).
+		Collection(CollectionReleases).
+		FindOne(ctx, bson.D{{Key: StorageKeyReleaseName, Value: "release-1"}}).
+		Decode(&release)
+	if assert.NoError(t, err) && assert.Len(t, release.Artifacts, 1) {
+		assert.Equal(t, "2.0",
+			release.Artifacts[0].ArtifactMeta.Provides["rootfs-image.version"])
+	}
+
+	err = ds.UpdateImageArtifactMeta(identity.WithContext(ctx, &identity.Identity{
+		Tenant: "tenant",
+	}), img.Id, img.ArtifactMeta, change)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
+)
+
+func TestImportJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestImportJobs in short mode.")
+	}
+
+	ctx := context.Background()
+	ds := getDb(ctx)
+
+	_, err := ds.GetImportJob(ctx, "1")
+	assert.Equal(t, store.ErrNotFound, err)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	job := &model.ImportJob{
+		ID:      "1",
+		URL:     "https://artifacts.example.com/release-1.mender",
+		Status:  model.ImportJobStatusPending,
+		Created: now,
+		Updated: now,
+	}
+	assert.NoError(t, ds.InsertImportJob(ctx, job))
+
+	found, err := ds.GetImportJob(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, job, found)
+
+	job.Status = model.ImportJobStatusCompleted
+	job.ArtifactID = "1"
+	job.Updated = now.Add(time.Minute)
+	assert.NoError(t, ds.UpdateImportJob(ctx, job))
+
+	found, err = ds.GetImportJob(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, job, found)
+
+	err = ds.UpdateImportJob(ctx, &model.ImportJob{ID: "2"})
+	assert.Equal(t, store.ErrNotFound, err)
+}
+
+func TestFailStaleImportJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestFailStaleImportJobs in short mode.")
+	}
+
+	ctx := context.Background()
+	ds := getDb(ctx)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	jobs := []*model.ImportJob{{
+		ID:      "stale",
+		Status:  model.ImportJobStatusProcessing,
+		Updated: now.Add(-3 * time.Hour),
+	}, {
+		ID:      "stale-pending",
+		Status:  model.ImportJobStatusPending,
+		Updated: now.Add(-3 * time.Hour),
+	}, {
+		ID:      "recent",
+		Status:  model.ImportJobStatusProcessing,
+		Updated: now,
+	}, {
+		ID:         "completed",
+		Status:     model.ImportJobStatusCompleted,
+		ArtifactID: "1",
+		Updated:    now.Add(-3 * time.Hour),
+	}}
+	for _, job := range jobs {
+		assert.NoError(t, ds.InsertImportJob(ctx, job))
+	}
+
+	n, err := ds.FailStaleImportJobs(ctx, now.Add(-2*time.Hour), "import interrupted")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	for id, status := range map[string]model.ImportJobStatus{
+		"stale":         model.ImportJobStatusFailed,
+		"stale-pending": model.ImportJobStatusFailed,
+		"recent":        model.ImportJobStatusProcessing,
+		"completed":     model.ImportJobStatusCompleted,
+	} {
+		job, err := ds.GetImportJob(ctx, id)
+		if assert.NoError(t, err) {
+			assert.Equal(t, status, job.Status, id)
+		}
+	}
+	job, _ := ds.GetImportJob(ctx, "stale")
+	assert.Equal(t, "import interrupted", job.Error)
+}
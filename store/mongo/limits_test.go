@@ -24,6 +24,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/mendersoftware/deployments/model"
+	"github.com/mendersoftware/deployments/store"
 )
 
 // db and test management funcs
@@ -92,3 +93,47 @@ func TestGetLimit(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, lim3OtherTenant, *lim)
 }
+
+func TestStorageUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestStorageUsage in short mode.")
+	}
+
+	dbCtx := identity.WithContext(context.Background(), &identity.Identity{
+		Tenant: "foo",
+	})
+	db := getDb(dbCtx)
+
+	usage, err := db.GetStorageUsage(dbCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), usage)
+
+	// the first artifact creates the usage document
+	err = db.IncrementStorageUsage(dbCtx, 60, 100)
+	assert.NoError(t, err)
+
+	// exceeding the limit leaves the usage unchanged
+	err = db.IncrementStorageUsage(dbCtx, 50, 100)
+	assert.ErrorIs(t, err, store.ErrLimitExceeded)
+	err = db.IncrementStorageUsage(dbCtx, 101, 100)
+	assert.ErrorIs(t, err, store.ErrLimitExceeded)
+
+	err = db.IncrementStorageUsage(dbCtx, 40, 100)
+	assert.NoError(t, err)
+
+	// no limit
+	err = db.IncrementStorageUsage(dbCtx, 50, 0)
+	assert.NoError(t, err)
+
+	err = db.IncrementStorageUsage(dbCtx, -30, 0)
+	assert.NoError(t, err)
+
+	usage, err = db.GetStorageUsage(dbCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(120), usage)
+
+	// usage is tracked per tenant
+	usage, err = db.GetStorageUsage(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), usage)
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+type migration_1_2_22 struct {
+	client *mongo.Client
+	db     string
+}
+
+// Up initializes the storage usage with the total size of the artifacts
+func (m *migration_1_2_22) Up(from migrate.Version) error {
+	ctx := context.Background()
+	database := m.client.Database(m.db)
+
+	cursor, err := database.Collection(CollectionImages).Aggregate(ctx, []bson.M{{
+		"$group": bson.M{
+			"_id":   nil,
+			"usage": bson.M{"$sum": "$" + StorageKeyImageSize},
+		},
+	}})
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.22): failed to compute storage usage: %w", err)
+	}
+	var results []struct {
+		Usage int64 `bson:"usage"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return fmt.Errorf("mongo(1.2.22): failed to compute storage usage: %w", err)
+	}
+	var usage int64
+	if len(results) > 0 {
+		usage = results[0].Usage
+	}
+
+	_, err = database.Collection(CollectionUsage).UpdateOne(ctx,
+		bson.M{"_id": model.LimitStorage},
+		bson.M{"$set": bson.M{StorageKeyUsage: usage}},
+		mopts.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.22): failed to store storage usage: %w", err)
+	}
+
+	return nil
+}
+
+func (m *migration_1_2_22) Version() migrate.Version {
+	return migrate.MakeVersion(1, 2, 22)
+}
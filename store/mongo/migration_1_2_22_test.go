@@ -0,0 +1,64 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func TestMigration_1_2_22(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestMigration_1_2_22 in short mode.")
+	}
+
+	db.Wipe()
+	c := db.Client()
+
+	ctx := context.TODO()
+	ds := NewDataStoreMongoWithClient(c)
+
+	collImages := c.Database(DbName).Collection(CollectionImages)
+	_, err := collImages.InsertMany(ctx, []interface{}{
+		&model.Image{Id: "1", Size: 100},
+		&model.Image{Id: "2", Size: 23},
+	})
+	assert.NoError(t, err)
+
+	mnew := &migration_1_2_22{
+		client: c,
+		db:     DbName,
+	}
+	err = mnew.Up(migrate.MakeVersion(1, 2, 22))
+	assert.NoError(t, err)
+
+	usage, err := ds.GetStorageUsage(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), usage)
+
+	// re-running the migration doesn't count the artifacts twice
+	err = mnew.Up(migrate.MakeVersion(1, 2, 22))
+	assert.NoError(t, err)
+
+	usage, err = ds.GetStorageUsage(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), usage)
+}
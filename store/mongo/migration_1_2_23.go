@@ -0,0 +1,49 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+type migration_1_2_23 struct {
+	client *mongo.Client
+	db     string
+}
+
+// Up approves the releases created before the release statuses
+func (m *migration_1_2_23) Up(from migrate.Version) error {
+	ctx := context.Background()
+
+	_, err := m.client.Database(m.db).Collection(CollectionReleases).UpdateMany(ctx,
+		bson.M{StorageKeyReleaseStatus: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{StorageKeyReleaseStatus: model.ReleaseStatusApproved}},
+	)
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.23): failed to set the release statuses: %w", err)
+	}
+	return nil
+}
+
+func (m *migration_1_2_23) Version() migrate.Version {
+	return migrate.MakeVersion(1, 2, 23)
+}
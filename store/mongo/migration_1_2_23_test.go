@@ -0,0 +1,64 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func TestMigration_1_2_23(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestMigration_1_2_23 in short mode.")
+	}
+
+	db.Wipe()
+	c := db.Client()
+
+	ctx := context.TODO()
+	ds := NewDataStoreMongoWithClient(c)
+
+	collReleases := c.Database(DbName).Collection(CollectionReleases)
+	_, err := collReleases.InsertMany(ctx, []interface{}{
+		bson.M{StorageKeyReleaseName: "release-1"},
+		bson.M{
+			StorageKeyReleaseName:   "release-2",
+			StorageKeyReleaseStatus: model.ReleaseStatusRevoked,
+		},
+	})
+	assert.NoError(t, err)
+
+	mnew := &migration_1_2_23{
+		client: c,
+		db:     DbName,
+	}
+	err = mnew.Up(migrate.MakeVersion(1, 2, 23))
+	assert.NoError(t, err)
+
+	status, err := ds.GetReleaseStatus(ctx, "release-1")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ReleaseStatusApproved, status)
+
+	status, err = ds.GetReleaseStatus(ctx, "release-2")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ReleaseStatusRevoked, status)
+}
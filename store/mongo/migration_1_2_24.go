@@ -0,0 +1,95 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type migration_1_2_24 struct {
+	client *mongo.Client
+	db     string
+}
+
+// Up stores the flat release tags as key/value documents, and replaces the
+// index on the tags with one on their keys and values.
+func (m *migration_1_2_24) Up(from migrate.Version) error {
+	ctx := context.Background()
+	collReleases := m.client.Database(m.db).Collection(CollectionReleases)
+
+	_, err := collReleases.UpdateMany(ctx,
+		bson.M{StorageKeyReleaseTags: bson.M{"$type": "string"}},
+		[]bson.M{{
+			"$set": bson.M{
+				StorageKeyReleaseTags: bson.M{
+					"$map": bson.M{
+						"input": "$" + StorageKeyReleaseTags,
+						"as":    "tag",
+						"in": bson.M{"$cond": bson.A{
+							bson.M{"$eq": bson.A{bson.M{"$type": "$$tag"}, "string"}},
+							bson.M{"key": "$$tag"},
+							"$$tag",
+						}},
+					},
+				},
+			},
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.24): failed to convert the release tags: %w", err)
+	}
+
+	_, err = collReleases.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{
+			Key:   StorageKeyReleaseTagsKey,
+			Value: 1,
+		}, {
+			Key:   StorageKeyReleaseTagsValue,
+			Value: 1,
+		}, {
+			// Sort by modified date by default when querying by tags
+			Key:   StorageKeyReleaseModified,
+			Value: -1,
+		}},
+		Options: mopts.Index().
+			SetName(IndexNameReleaseTagsV2),
+	})
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.24): failed to create index: %w", err)
+	}
+
+	_, err = collReleases.Indexes().DropOne(ctx, IndexNameReleaseTags)
+	var srvErr mongo.ServerError
+	if errors.As(err, &srvErr) {
+		if srvErr.HasErrorCode(errorCodeIndexNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.24): failed to drop index: %w", err)
+	}
+	return nil
+}
+
+func (m *migration_1_2_24) Version() migrate.Version {
+	return migrate.MakeVersion(1, 2, 24)
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func TestMigration_1_2_24(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestMigration_1_2_24 in short mode.")
+	}
+
+	db.Wipe()
+	c := db.Client()
+
+	ctx := context.TODO()
+	collReleases := c.Database(DbName).Collection(CollectionReleases)
+	_, err := collReleases.InsertMany(ctx, []interface{}{
+		bson.M{
+			StorageKeyReleaseName: "release-1",
+			StorageKeyReleaseTags: bson.A{"product-gateway", "fips"},
+		},
+		bson.M{
+			StorageKeyReleaseName: "release-2",
+			StorageKeyReleaseTags: model.Tags{"product=gateway"},
+		},
+		bson.M{StorageKeyReleaseName: "release-3"},
+	})
+	assert.NoError(t, err)
+
+	mold := &migration_1_2_16{
+		client: c,
+		db:     DbName,
+	}
+	err = mold.Up(migrate.MakeVersion(1, 2, 16))
+	assert.NoError(t, err)
+
+	mnew := &migration_1_2_24{
+		client: c,
+		db:     DbName,
+	}
+	err = mnew.Up(migrate.MakeVersion(1, 2, 24))
+	assert.NoError(t, err)
+
+	var releases []bson.M
+	cur, err := collReleases.Find(ctx, bson.M{},
+		mopts.Find().SetSort(bson.M{StorageKeyReleaseName: 1}))
+	if assert.NoError(t, err) {
+		assert.NoError(t, cur.All(ctx, &releases))
+	}
+	if assert.Len(t, releases, 3) {
+		assert.Equal(t, bson.A{
+			bson.M{"key": "product-gateway"},
+			bson.M{"key": "fips"},
+		}, releases[0][StorageKeyReleaseTags])
+		assert.Equal(t, bson.A{
+			bson.M{"key": "product", "value": "gateway"},
+		}, releases[1][StorageKeyReleaseTags])
+		assert.NotContains(t, releases[2], StorageKeyReleaseTags)
+	}
+
+	indexes, err := collReleases.Indexes().ListSpecifications(ctx)
+	assert.NoError(t, err)
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = index.Name
+	}
+	assert.Contains(t, names, IndexNameReleaseTagsV2)
+	assert.NotContains(t, names, IndexNameReleaseTags)
+
+	// running the migration again is a no-op
+	err = mnew.Up(migrate.MakeVersion(1, 2, 24))
+	assert.NoError(t, err)
+}
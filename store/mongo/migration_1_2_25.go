@@ -0,0 +1,80 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+type migration_1_2_25 struct {
+	client *mongo.Client
+	db     string
+}
+
+// Up creates the indexes searching the releases and the artifacts by their
+// provides and by full-text.
+func (m *migration_1_2_25) Up(from migrate.Version) error {
+	ctx := context.Background()
+	database := m.client.Database(m.db)
+
+	_, err := database.Collection(CollectionReleases).Indexes().CreateMany(ctx,
+		[]mongo.IndexModel{{
+			Keys: bson.D{
+				{Key: StorageKeyReleaseNotes, Value: "text"},
+				{Key: StorageKeyReleaseArtifactsDescription, Value: "text"},
+			},
+			Options: mopts.Index().
+				SetName(IndexNameReleaseText),
+		}, {
+			Keys: bson.D{
+				{Key: StorageKeyReleaseArtifacts + "." + model.StorageKeyImageProvidesIdxKey,
+					Value: 1},
+				{Key: StorageKeyReleaseArtifacts + "." + model.StorageKeyImageProvidesIdxValue,
+					Value: 1},
+			},
+			Options: mopts.Index().
+				SetName(IndexNameReleaseProvides),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.25): failed to create release indexes: %w", err)
+	}
+
+	_, err = database.Collection(CollectionImages).Indexes().CreateOne(ctx,
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: StorageKeyImageDescription, Value: "text"},
+			},
+			Options: mopts.Index().
+				SetName(IndexNameImageText),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.25): failed to create artifact index: %w", err)
+	}
+	return nil
+}
+
+func (m *migration_1_2_25) Version() migrate.Version {
+	return migrate.MakeVersion(1, 2, 25)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+)
+
+func TestMigration_1_2_25(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestMigration_1_2_25 in short mode.")
+	}
+
+	db.Wipe()
+	c := db.Client()
+
+	ctx := context.TODO()
+	m := &migration_1_2_25{
+		client: c,
+		db:     DbName,
+	}
+	err := m.Up(migrate.MakeVersion(1, 2, 25))
+	assert.NoError(t, err)
+
+	indexNames := func(coll *mongo.Collection) []string {
+		indexes, err := coll.Indexes().ListSpecifications(ctx)
+		assert.NoError(t, err)
+		names := make([]string, len(indexes))
+		for i, index := range indexes {
+			names[i] = index.Name
+		}
+		return names
+	}
+	database := c.Database(DbName)
+	releaseIndexes := indexNames(database.Collection(CollectionReleases))
+	assert.Contains(t, releaseIndexes, IndexNameReleaseText)
+	assert.Contains(t, releaseIndexes, IndexNameReleaseProvides)
+	assert.Contains(t, indexNames(database.Collection(CollectionImages)), IndexNameImageText)
+
+	// running the migration again is a no-op
+	err = m.Up(migrate.MakeVersion(1, 2, 25))
+	assert.NoError(t, err)
+}
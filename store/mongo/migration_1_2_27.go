@@ -0,0 +1,100 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mopts "go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+type migration_1_2_27 struct {
+	client *mongo.Client
+	db     string
+}
+
+// Up creates the index listing the notes history of a release, newest
+// first, and seeds the history with the current notes of the releases, so
+// that the first edit does not lose them.
+func (m *migration_1_2_27) Up(from migrate.Version) error {
+	ctx := context.Background()
+	database := m.client.Database(m.db)
+
+	_, err := database.Collection(CollectionReleaseNotes).Indexes().CreateOne(ctx,
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: StorageKeyReleaseNotesRelease, Value: 1},
+				{Key: StorageKeyReleaseNotesModified, Value: -1},
+			},
+			Options: mopts.Index().
+				SetName(IndexNameReleaseNotesHistory),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.27): failed to create release notes index: %w", err)
+	}
+
+	cursor, err := database.Collection(CollectionReleases).Find(ctx,
+		bson.M{StorageKeyReleaseNotes: bson.M{"$nin": bson.A{"", nil}}},
+		mopts.Find().SetProjection(bson.M{
+			StorageKeyReleaseNotes:    1,
+			StorageKeyReleaseModified: 1,
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("mongo(1.2.27): failed to list the release notes: %w", err)
+	}
+	defer cursor.Close(ctx)
+	collNotes := database.Collection(CollectionReleaseNotes)
+	for cursor.Next(ctx) {
+		var release model.Release
+		if err := cursor.Decode(&release); err != nil {
+			return fmt.Errorf("mongo(1.2.27): failed to decode the release: %w", err)
+		}
+		modified := time.Now()
+		if release.Modified != nil {
+			modified = *release.Modified
+		}
+		// only seed the releases without history, running the
+		// migration again is a no-op
+		_, err = collNotes.UpdateOne(ctx,
+			bson.M{StorageKeyReleaseNotesRelease: release.Name},
+			bson.M{"$setOnInsert": model.ReleaseNotesRevision{
+				ReleaseName: release.Name,
+				Notes:       release.Notes,
+				Modified:    modified,
+			}},
+			mopts.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("mongo(1.2.27): failed to seed the release notes: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("mongo(1.2.27): failed to list the release notes: %w", err)
+	}
+	return nil
+}
+
+func (m *migration_1_2_27) Version() migrate.Version {
+	return migrate.MakeVersion(1, 2, 27)
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func TestMigration_1_2_27(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestMigration_1_2_27 in short mode.")
+	}
+
+	db.Wipe()
+	c := db.Client()
+
+	ctx := context.TODO()
+	modified := time.Now().UTC().Truncate(time.Millisecond)
+	_, err := c.Database(DbName).Collection(CollectionReleases).InsertMany(ctx, bson.A{
+		model.Release{Name: "with-notes", Notes: "fixes", Modified: &modified},
+		model.Release{Name: "without-notes", Modified: &modified},
+	})
+	assert.NoError(t, err)
+
+	m := &migration_1_2_27{
+		client: c,
+		db:     DbName,
+	}
+	err = m.Up(migrate.MakeVersion(1, 2, 27))
+	assert.NoError(t, err)
+
+	indexes, err := c.Database(DbName).
+		Collection(CollectionReleaseNotes).
+		Indexes().
+		ListSpecifications(ctx)
+	assert.NoError(t, err)
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = index.Name
+	}
+	assert.Contains(t, names, IndexNameReleaseNotesHistory)
+
+	assertRevisions := func() {
+		var revisions []model.ReleaseNotesRevision
+		cursor, err := c.Database(DbName).
+			Collection(CollectionReleaseNotes).
+			Find(ctx, bson.M{})
+		assert.NoError(t, err)
+		assert.NoError(t, cursor.All(ctx, &revisions))
+		assert.Equal(t, []model.ReleaseNotesRevision{{
+			ReleaseName: "with-notes",
+			Notes:       "fixes",
+			Modified:    modified,
+		}}, revisions)
+	}
+	assertRevisions()
+
+	// running the migration again is a no-op
+	err = m.Up(migrate.MakeVersion(1, 2, 27))
+	assert.NoError(t, err)
+	assertRevisions()
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mendersoftware/go-lib-micro/mongo/migrate"
+)
+
+func TestMigration_1_2_28(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestMigration_1_2_28 in short mode.")
+	}
+
+	db.Wipe()
+	c := db.Client()
+
+	ctx := context.TODO()
+	m := &migration_1_2_28{
+		client: c,
+		db:     DbName,
+	}
+	err := m.Up(migrate.MakeVersion(1, 2, 28))
+	assert.NoError(t, err)
+
+	indexes, err := c.Database(DbName).
+		Collection(CollectionDevices).
+		Indexes().
+		ListSpecifications(ctx)
+	assert.NoError(t, err)
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = index.Name
+	}
+	assert.Contains(t, names, IndexDeviceDeploymentReleaseStatusName)
+
+	// running the migration again is a no-op
+	err = m.Up(migrate.MakeVersion(1, 2, 28))
+	assert.NoError(t, err)
+}
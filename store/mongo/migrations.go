@@ -24,8 +24,8 @@ import (
 )
 
 const (
-	DbVersion        = "1.2.19"
-	DbMinimumVersion = "1.2.19"
+	DbVersion        = "1.2.28"
+	DbMinimumVersion = "1.2.28"
 	DbName           = "deployment_service"
 )
 
@@ -150,6 +150,42 @@ func MigrateSingle(ctx context.Context,
 			client: client,
 			db:     db,
 		},
+		&migration_1_2_20{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_21{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_22{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_23{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_24{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_25{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_26{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_27{
+			client: client,
+			db:     db,
+		},
+		&migration_1_2_28{
+			client: client,
+			db:     db,
+		},
 	}
 
 	err = m.Apply(ctx, *ver, migrations)
@@ -0,0 +1,108 @@
+// Copyright 2023 Northern.tech AS
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/mendersoftware/deployments/model"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestRetentionPolicy in short mode.")
+	}
+
+	ctx := context.Background()
+	ds := getDb(ctx)
+
+	policy, err := ds.GetRetentionPolicy(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, policy)
+
+	expected := &model.RetentionPolicy{
+		KeepLastPerDeviceType: 5,
+		KeepTaggedReleases:    true,
+	}
+	assert.NoError(t, ds.SetRetentionPolicy(ctx, expected))
+	policy, err = ds.GetRetentionPolicy(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, policy)
+
+	expected = &model.RetentionPolicy{DeleteUnusedAfterDays: 90}
+	assert.NoError(t, ds.SetRetentionPolicy(ctx, expected))
+	policy, err = ds.GetRetentionPolicy(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, policy)
+}
+
+func TestGetArtifactsLastDeployed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestGetArtifactsLastDeployed in short mode.")
+	}
+
+	ctx := context.Background()
+	ds := getDb(ctx)
+
+	lastDeployed, err := ds.GetArtifactsLastDeployed(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, lastDeployed)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	_, err = ds.client.Database(DbName).
+		Collection(CollectionDeployments).
+		InsertMany(ctx, []interface{}{
+			bson.M{"_id": "1", StorageKeyDeploymentCreated: now.Add(-time.Hour),
+				StorageKeyDeploymentArtifacts: []string{"a1", "a2"}},
+			bson.M{"_id": "2", StorageKeyDeploymentCreated: now,
+				StorageKeyDeploymentArtifacts: []string{"a1"}},
+			bson.M{"_id": "3", StorageKeyDeploymentCreated: now},
+		})
+	assert.NoError(t, err)
+
+	lastDeployed, err = ds.GetArtifactsLastDeployed(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"a1": now,
+		"a2": now.Add(-time.Hour),
+	}, lastDeployed)
+}
+
+func TestListTaggedReleaseNames(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestListTaggedReleaseNames in short mode.")
+	}
+
+	ctx := context.Background()
+	ds := getDb(ctx)
+
+	_, err := ds.client.Database(DbName).
+		Collection(CollectionReleases).
+		InsertMany(ctx, []interface{}{
+			bson.M{"_id": "tagged", StorageKeyReleaseTags: []string{"stable"}},
+			bson.M{"_id": "untagged", StorageKeyReleaseTags: []string{}},
+			bson.M{"_id": "no-tags"},
+		})
+	assert.NoError(t, err)
+
+	names, err := ds.ListTaggedReleaseNames(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tagged"}, names)
+}